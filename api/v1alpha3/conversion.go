@@ -46,6 +46,17 @@ func (r *AWSCluster) ConvertTo(dstRaw conversion.Hub) error {
 		RestoreRootVolume(restored.Status.Bastion.RootVolume, dst.Status.Bastion.RootVolume)
 		restoreNonRootVolumes(restored.Status.Bastion.NonRootVolumes, dst.Status.Bastion.NonRootVolumes)
 	}
+	RestoreNetworkSpec(&restored.Spec.NetworkSpec, &dst.Spec.NetworkSpec)
+	dst.Spec.SecondaryCidrBlock = restored.Spec.SecondaryCidrBlock
+	dst.Spec.Bastion.AMISSMParameterName = restored.Spec.Bastion.AMISSMParameterName
+	if restored.Spec.ControlPlaneLoadBalancer != nil && dst.Spec.ControlPlaneLoadBalancer != nil {
+		dst.Spec.ControlPlaneLoadBalancer.LoadBalancerType = restored.Spec.ControlPlaneLoadBalancer.LoadBalancerType
+		dst.Spec.ControlPlaneLoadBalancer.HealthCheck = restored.Spec.ControlPlaneLoadBalancer.HealthCheck
+	}
+	dst.Spec.ControlPlaneElasticIPs = restored.Spec.ControlPlaneElasticIPs
+	dst.Spec.ControlPlaneDNS = restored.Spec.ControlPlaneDNS
+	dst.Status.Network.ControlPlaneElasticIPs = restored.Status.Network.ControlPlaneElasticIPs
+	dst.Status.Network.ControlPlaneDNSChangeID = restored.Status.Network.ControlPlaneDNSChangeID
 	return nil
 }
 
@@ -94,6 +105,23 @@ func (r *AWSMachine) ConvertTo(dstRaw conversion.Hub) error {
 	RestoreAMIReference(&restored.Spec.AMI, &dst.Spec.AMI)
 	RestoreRootVolume(restored.Spec.RootVolume, dst.Spec.RootVolume)
 	restoreNonRootVolumes(restored.Spec.NonRootVolumes, dst.Spec.NonRootVolumes)
+	dst.Spec.RoleAdditionalPolicies = restored.Spec.RoleAdditionalPolicies
+	dst.Spec.HostID = restored.Spec.HostID
+	dst.Spec.HostResourceGroupArn = restored.Spec.HostResourceGroupArn
+	dst.Spec.TerminationProtection = restored.Spec.TerminationProtection
+	dst.Spec.PlacementGroup = restored.Spec.PlacementGroup
+	dst.Spec.CapacityReservation = restored.Spec.CapacityReservation
+	dst.Spec.IPPrewarming = restored.Spec.IPPrewarming
+	dst.Spec.DetailedMonitoring = restored.Spec.DetailedMonitoring
+	dst.Spec.CloudWatchAgent = restored.Spec.CloudWatchAgent
+	dst.Spec.CPUOptions = restored.Spec.CPUOptions
+	dst.Spec.CreditSpecification = restored.Spec.CreditSpecification
+	dst.Spec.ElasticFabricAdapter = restored.Spec.ElasticFabricAdapter
+	dst.Spec.PrivateIP = restored.Spec.PrivateIP
+	dst.Spec.StoppedInstancePolicy = restored.Spec.StoppedInstancePolicy
+	dst.Spec.Fleet = restored.Spec.Fleet
+	dst.Spec.FallbackInstanceTypes = restored.Spec.FallbackInstanceTypes
+	dst.Spec.UserDataFormat = restored.Spec.UserDataFormat
 	return nil
 }
 
@@ -140,6 +168,23 @@ func (r *AWSMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	RestoreAMIReference(&restored.Spec.Template.Spec.AMI, &dst.Spec.Template.Spec.AMI)
 	RestoreRootVolume(restored.Spec.Template.Spec.RootVolume, dst.Spec.Template.Spec.RootVolume)
 	restoreNonRootVolumes(restored.Spec.Template.Spec.NonRootVolumes, dst.Spec.Template.Spec.NonRootVolumes)
+	dst.Spec.Template.Spec.RoleAdditionalPolicies = restored.Spec.Template.Spec.RoleAdditionalPolicies
+	dst.Spec.Template.Spec.HostID = restored.Spec.Template.Spec.HostID
+	dst.Spec.Template.Spec.HostResourceGroupArn = restored.Spec.Template.Spec.HostResourceGroupArn
+	dst.Spec.Template.Spec.TerminationProtection = restored.Spec.Template.Spec.TerminationProtection
+	dst.Spec.Template.Spec.PlacementGroup = restored.Spec.Template.Spec.PlacementGroup
+	dst.Spec.Template.Spec.CapacityReservation = restored.Spec.Template.Spec.CapacityReservation
+	dst.Spec.Template.Spec.IPPrewarming = restored.Spec.Template.Spec.IPPrewarming
+	dst.Spec.Template.Spec.DetailedMonitoring = restored.Spec.Template.Spec.DetailedMonitoring
+	dst.Spec.Template.Spec.CloudWatchAgent = restored.Spec.Template.Spec.CloudWatchAgent
+	dst.Spec.Template.Spec.CPUOptions = restored.Spec.Template.Spec.CPUOptions
+	dst.Spec.Template.Spec.CreditSpecification = restored.Spec.Template.Spec.CreditSpecification
+	dst.Spec.Template.Spec.ElasticFabricAdapter = restored.Spec.Template.Spec.ElasticFabricAdapter
+	dst.Spec.Template.Spec.PrivateIP = restored.Spec.Template.Spec.PrivateIP
+	dst.Spec.Template.Spec.StoppedInstancePolicy = restored.Spec.Template.Spec.StoppedInstancePolicy
+	dst.Spec.Template.Spec.Fleet = restored.Spec.Template.Spec.Fleet
+	dst.Spec.Template.Spec.FallbackInstanceTypes = restored.Spec.Template.Spec.FallbackInstanceTypes
+	dst.Spec.Template.Spec.UserDataFormat = restored.Spec.Template.Spec.UserDataFormat
 
 	return nil
 }
@@ -286,11 +331,26 @@ func Convert_v1alpha3_AWSClusterStaticIdentitySpec_To_v1alpha4_AWSClusterStaticI
 	return autoConvert_v1alpha3_AWSClusterStaticIdentitySpec_To_v1alpha4_AWSClusterStaticIdentitySpec(in, out, s)
 }
 
+// Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec .
+func Convert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in *v1alpha4.AWSClusterSpec, out *AWSClusterSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSClusterSpec_To_v1alpha3_AWSClusterSpec(in, out, s)
+}
+
 // Convert_v1alpha4_AWSClusterStaticIdentitySpec_To_v1alpha3_AWSClusterStaticIdentitySpec .
 func Convert_v1alpha4_AWSClusterStaticIdentitySpec_To_v1alpha3_AWSClusterStaticIdentitySpec(in *v1alpha4.AWSClusterStaticIdentitySpec, out *AWSClusterStaticIdentitySpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha4_AWSClusterStaticIdentitySpec_To_v1alpha3_AWSClusterStaticIdentitySpec(in, out, s)
 }
 
+// Convert_v1alpha4_Bastion_To_v1alpha3_Bastion .
+func Convert_v1alpha4_Bastion_To_v1alpha3_Bastion(in *v1alpha4.Bastion, out *Bastion, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_Bastion_To_v1alpha3_Bastion(in, out, s)
+}
+
+// Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec .
+func Convert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(in *v1alpha4.AWSLoadBalancerSpec, out *AWSLoadBalancerSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_AWSLoadBalancerSpec_To_v1alpha3_AWSLoadBalancerSpec(in, out, s)
+}
+
 // Convert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec .
 func Convert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in *v1alpha4.AWSMachineSpec, out *AWSMachineSpec, s apiconversion.Scope) error {
 	return autoConvert_v1alpha4_AWSMachineSpec_To_v1alpha3_AWSMachineSpec(in, out, s)
@@ -325,6 +385,16 @@ func restoreInstance(restored, dst *v1alpha4.Instance) {
 		return
 	}
 	dst.VolumeIDs = restored.VolumeIDs
+	dst.PublicIPOnLaunch = restored.PublicIPOnLaunch
+	dst.HostID = restored.HostID
+	dst.HostResourceGroupArn = restored.HostResourceGroupArn
+	dst.TerminationProtection = restored.TerminationProtection
+	dst.PlacementGroupName = restored.PlacementGroupName
+	dst.CapacityReservation = restored.CapacityReservation
+	dst.DetailedMonitoring = restored.DetailedMonitoring
+	dst.CPUOptions = restored.CPUOptions
+	dst.CreditSpecification = restored.CreditSpecification
+	dst.ElasticFabricAdapter = restored.ElasticFabricAdapter
 	RestoreRootVolume(restored.RootVolume, dst.RootVolume)
 	restoreNonRootVolumes(restored.NonRootVolumes, dst.NonRootVolumes)
 }
@@ -341,12 +411,15 @@ func Convert_v1alpha4_AMIReference_To_v1alpha3_AWSResourceReference(in *v1alpha4
 	return nil
 }
 
-// RestoreAMIReference manually restore the EKSOptimizedLookupType for AWSMachine and AWSMachineTemplate
+// RestoreAMIReference manually restore the EKSOptimizedLookupType and SSMParameterName for AWSMachine and AWSMachineTemplate
 func RestoreAMIReference(restored, dst *v1alpha4.AMIReference) {
-	if restored == nil || restored.EKSOptimizedLookupType == nil {
+	if restored == nil {
 		return
 	}
-	dst.EKSOptimizedLookupType = restored.EKSOptimizedLookupType
+	if restored.EKSOptimizedLookupType != nil {
+		dst.EKSOptimizedLookupType = restored.EKSOptimizedLookupType
+	}
+	dst.SSMParameterName = restored.SSMParameterName
 }
 
 // restoreNonRootVolumes manually restores the non-root volumes
@@ -388,3 +461,51 @@ func RestoreRootVolume(restored, dst *v1alpha4.Volume) {
 	dst.Throughput = restored.Throughput
 	return
 }
+
+func Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1alpha4.NetworkSpec, out *NetworkSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in, out, s)
+}
+
+func Convert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(in *v1alpha4.VPCSpec, out *VPCSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_VPCSpec_To_v1alpha3_VPCSpec(in, out, s)
+}
+
+func Convert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(in *v1alpha4.SubnetSpec, out *SubnetSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_SubnetSpec_To_v1alpha3_SubnetSpec(in, out, s)
+}
+
+// RestoreNetworkSpec manually restores the network, VPC and subnet fields that don't exist in v1alpha3.
+func RestoreNetworkSpec(restored, dst *v1alpha4.NetworkSpec) {
+	dst.VPC.IPv6 = restored.VPC.IPv6
+	dst.VPC.NatGatewayTopology = restored.VPC.NatGatewayTopology
+	dst.VPC.Isolated = restored.VPC.Isolated
+	dst.VPC.DHCPOptions = restored.VPC.DHCPOptions
+	dst.AdditionalIngressRules = restored.AdditionalIngressRules
+	dst.PeeringConnections = restored.PeeringConnections
+	dst.NetworkACLRules = restored.NetworkACLRules
+	dst.AllowEFATraffic = restored.AllowEFATraffic
+
+	for i := range dst.Subnets {
+		restoredSubnet := findRestoredSubnet(restored.Subnets, &dst.Subnets[i])
+		if restoredSubnet == nil {
+			continue
+		}
+		dst.Subnets[i].IPv6CidrBlock = restoredSubnet.IPv6CidrBlock
+		dst.Subnets[i].IsIPv6 = restoredSubnet.IsIPv6
+	}
+}
+
+// findRestoredSubnet returns the restored subnet with the same ID, or with the same CIDR block
+// for subnets that aren't created yet, as subnets may have been added or removed since.
+func findRestoredSubnet(restored v1alpha4.Subnets, dst *v1alpha4.SubnetSpec) *v1alpha4.SubnetSpec {
+	for i := range restored {
+		if dst.ID != "" && restored[i].ID == dst.ID {
+			return &restored[i]
+		}
+		if dst.ID == "" && restored[i].ID == "" && restored[i].CidrBlock == dst.CidrBlock {
+			return &restored[i]
+		}
+	}
+
+	return nil
+}
@@ -105,3 +105,28 @@ func TestFuzzyConversion(t *testing.T) {
 		Spoke:  &AWSClusterRoleIdentity{},
 	}))
 }
+
+func TestRestoreNetworkSpecSubnets(t *testing.T) {
+	g := NewWithT(t)
+
+	restored := &v1alpha4.NetworkSpec{
+		Subnets: v1alpha4.Subnets{
+			{ID: "subnet-1", CidrBlock: "10.0.0.0/24", IsIPv6: true, IPv6CidrBlock: "2001:db8:1234:1a00::/64"},
+			{ID: "subnet-2", CidrBlock: "10.0.1.0/24", IsIPv6: true, IPv6CidrBlock: "2001:db8:1234:1a01::/64"},
+		},
+	}
+	// A subnet was added and the subnets reordered through the v1alpha3 API.
+	dst := &v1alpha4.NetworkSpec{
+		Subnets: v1alpha4.Subnets{
+			{CidrBlock: "10.0.2.0/24"},
+			{ID: "subnet-2", CidrBlock: "10.0.1.0/24"},
+			{ID: "subnet-1", CidrBlock: "10.0.0.0/24"},
+		},
+	}
+
+	RestoreNetworkSpec(restored, dst)
+
+	g.Expect(dst.Subnets[0].IsIPv6).To(BeFalse())
+	g.Expect(dst.Subnets[1].IPv6CidrBlock).To(Equal("2001:db8:1234:1a01::/64"))
+	g.Expect(dst.Subnets[2].IPv6CidrBlock).To(Equal("2001:db8:1234:1a00::/64"))
+}
@@ -35,6 +35,12 @@ type AWSClusterSpec struct {
 	// NetworkSpec encapsulates all things related to AWS network.
 	NetworkSpec NetworkSpec `json:"network,omitempty"`
 
+	// SecondaryCidrBlock is an additional CIDR range associated with a managed VPC, from which
+	// a private subnet is carved in every availability zone for pod IPs.
+	// Must be within the 100.64.0.0/10 or 198.19.0.0/16 range.
+	// +optional
+	SecondaryCidrBlock *string `json:"secondaryCidrBlock,omitempty"`
+
 	// The AWS Region the cluster lives in.
 	Region string `json:"region,omitempty"`
 
@@ -55,6 +61,18 @@ type AWSClusterSpec struct {
 	// +optional
 	ControlPlaneLoadBalancer *AWSLoadBalancerSpec `json:"controlPlaneLoadBalancer,omitempty"`
 
+	// ControlPlaneElasticIPs associates an Elastic IP address with each control plane instance,
+	// so the public addresses of the control plane survive instance replacement: addresses left
+	// behind by deleted instances are reused by their replacements, and all of them are released
+	// when the cluster is deleted. Control plane instances must run in public subnets.
+	// +optional
+	ControlPlaneElasticIPs bool `json:"controlPlaneElasticIPs,omitempty"`
+
+	// ControlPlaneDNS creates a Route53 alias record pointing at the API server load balancer,
+	// which is then used as the control plane endpoint instead of the load balancer DNS name.
+	// +optional
+	ControlPlaneDNS *ControlPlaneDNS `json:"controlPlaneDNS,omitempty"`
+
 	// ImageLookupFormat is the AMI naming format to look up machine images when
 	// a machine does not specify an AMI. When set, this will be used for all
 	// cluster machines unless a machine specifies a different ImageLookupOrg.
@@ -142,6 +160,12 @@ type Bastion struct {
 	// the AMI will default to one picked out in public space.
 	// +optional
 	AMI string `json:"ami,omitempty"`
+
+	// AMISSMParameterName is the name of an SSM parameter holding the ID of the AMI to boot the
+	// bastion when AMI isn't set, for example to use a newer Ubuntu release or Amazon Linux.
+	// Defaults to Canonical's parameter for the latest Ubuntu 18.04 AMI.
+	// +optional
+	AMISSMParameterName string `json:"amiSSMParameterName,omitempty"`
 }
 
 // AWSLoadBalancerSpec defines the desired state of an AWS load balancer.
@@ -152,6 +176,15 @@ type AWSLoadBalancerSpec struct {
 	// +optional
 	Scheme *ClassicELBScheme `json:"scheme,omitempty"`
 
+	// LoadBalancerType sets the type of the load balancer (defaults to classic).
+	// A network load balancer (nlb) exposes a static IP address per availability zone and
+	// forwards TCP connections to the API servers, which are health checked on port 6443.
+	// Network load balancers do not support security groups.
+	// +kubebuilder:default=classic
+	// +kubebuilder:validation:Enum=classic;nlb
+	// +optional
+	LoadBalancerType LoadBalancerType `json:"loadBalancerType,omitempty"`
+
 	// CrossZoneLoadBalancing enables the classic ELB cross availability zone balancing.
 	//
 	// With cross-zone load balancing, each load balancer node for your Classic Load Balancer
@@ -171,6 +204,63 @@ type AWSLoadBalancerSpec struct {
 	// This is optional - if not provided new security groups will be created for the load balancer
 	// +optional
 	AdditionalSecurityGroups []string `json:"additionalSecurityGroups,omitempty"`
+
+	// HealthCheck overrides the health check settings used by the load balancer to
+	// decide whether a control plane instance can receive API server traffic.
+	// +optional
+	HealthCheck *AWSLoadBalancerHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// AWSLoadBalancerHealthCheck defines the health check settings of the API server load balancer.
+// Fields that are not set keep their default values.
+type AWSLoadBalancerHealthCheck struct {
+	// IntervalSeconds is the approximate interval, in seconds, between health checks of an individual instance (defaults to 10).
+	// +kubebuilder:validation:Minimum=5
+	// +kubebuilder:validation:Maximum=300
+	// +optional
+	IntervalSeconds *int64 `json:"intervalSeconds,omitempty"`
+
+	// TimeoutSeconds is the amount of time, in seconds, during which no response means a failed health check (defaults to 5).
+	// It must be less than IntervalSeconds and can't be set for network load balancers.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=60
+	// +optional
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+
+	// HealthyThreshold is the number of consecutive successful health checks required
+	// before an unhealthy instance receives traffic again (defaults to 5 for classic
+	// load balancers and 3 for network load balancers).
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	HealthyThreshold *int64 `json:"healthyThreshold,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed health checks required
+	// before an instance stops receiving traffic (defaults to 3).
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	UnhealthyThreshold *int64 `json:"unhealthyThreshold,omitempty"`
+}
+
+// ControlPlaneDNS defines the Route53 record created for the API server load balancer.
+type ControlPlaneDNS struct {
+	// HostedZoneID is the ID of an existing Route53 hosted zone the record is created in.
+	// A private hosted zone is associated with the cluster VPC if it is not already.
+	// Exactly one of hostedZoneID and privateZoneName must be set.
+	// +optional
+	HostedZoneID string `json:"hostedZoneID,omitempty"`
+
+	// PrivateZoneName is the domain name of a private hosted zone to create in the cluster VPC
+	// for the record, for clusters with an internal API server load balancer. The hosted zone is
+	// deleted along with the cluster.
+	// +optional
+	PrivateZoneName string `json:"privateZoneName,omitempty"`
+
+	// Name is the fully qualified domain name of the record.
+	// Defaults to api.<cluster name>.<hosted zone domain name>.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 // AWSClusterStatus defines the observed state of AWSCluster
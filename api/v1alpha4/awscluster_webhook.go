@@ -18,6 +18,7 @@ package v1alpha4
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -42,6 +43,13 @@ func (r *AWSCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-awscluster,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,versions=v1alpha4,name=validation.awscluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 // +kubebuilder:webhook:verbs=create;update,path=/mutate-infrastructure-cluster-x-k8s-io-v1alpha4-awscluster,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,versions=v1alpha4,name=default.awscluster.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
+const (
+	// defaultHealthCheckIntervalSeconds and defaultHealthCheckTimeoutSeconds are the health check settings of
+	// the API server load balancer when spec.controlPlaneLoadBalancer.healthCheck doesn't override them.
+	defaultHealthCheckIntervalSeconds = 10
+	defaultHealthCheckTimeoutSeconds  = 5
+)
+
 var (
 	_ webhook.Validator = &AWSCluster{}
 	_ webhook.Defaulter = &AWSCluster{}
@@ -53,6 +61,30 @@ func (r *AWSCluster) ValidateCreate() error {
 
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
 	allErrs = append(allErrs, r.validateSSHKeyName()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.ValidatePeeringConnections(r.Spec.SecondaryCidrBlock)...)
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancer()...)
+	allErrs = append(allErrs, r.validateControlPlaneDNS()...)
+	allErrs = append(allErrs, r.validateIsolatedNetwork()...)
+	allErrs = append(allErrs, r.validateControlPlaneElasticIPs()...)
+	allErrs = append(allErrs, r.validateSecondaryCidrBlock()...)
+
+	// The VPC ID of a managed VPC is only filled in once the VPC is created.
+	if r.Spec.SecondaryCidrBlock != nil && r.Spec.NetworkSpec.VPC.ID != "" {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "secondaryCidrBlock"), "subnets can only be carved from a secondary CIDR block of a managed VPC"),
+		)
+	}
+	if r.Spec.NetworkSpec.VPC.DHCPOptions != nil && r.Spec.NetworkSpec.VPC.ID != "" {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "network", "vpc", "dhcpOptions"), "DHCP options can only be set for a managed VPC"),
+		)
+	}
+	if len(r.Spec.NetworkSpec.NetworkACLRules) > 0 && r.Spec.NetworkSpec.VPC.ID != "" {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "network", "networkACLRules"), "network ACLs can only be managed for a managed VPC"),
+		)
+	}
 
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -91,6 +123,13 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
 					r.Spec.ControlPlaneLoadBalancer.Scheme, "field is immutable, default value was set to Internet-facing"),
 			)
 		}
+		// If old type was nil, the only value accepted here is the default value: classic
+		if newLoadBalancer.LoadBalancerType != "" && newLoadBalancer.LoadBalancerType != LoadBalancerTypeClassic {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "loadBalancerType"),
+					r.Spec.ControlPlaneLoadBalancer.LoadBalancerType, "field is immutable, default value was set to classic"),
+			)
+		}
 	} else {
 		// If old scheme was not nil, the new scheme should be the same.
 		existingLoadBalancer := oldC.Spec.ControlPlaneLoadBalancer.DeepCopy()
@@ -100,6 +139,19 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
 					r.Spec.ControlPlaneLoadBalancer.Scheme, "field is immutable"),
 			)
 		}
+		if existingLoadBalancer.LoadBalancerType != newLoadBalancer.LoadBalancerType {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "loadBalancerType"),
+					r.Spec.ControlPlaneLoadBalancer.LoadBalancerType, "field is immutable"),
+			)
+		}
+	}
+
+	// The record name becomes the control plane endpoint, which cannot change once set.
+	if !reflect.DeepEqual(oldC.Spec.ControlPlaneDNS, r.Spec.ControlPlaneDNS) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "controlPlaneDNS"), r.Spec.ControlPlaneDNS, "field is immutable"),
+		)
 	}
 
 	if !reflect.DeepEqual(oldC.Spec.ControlPlaneEndpoint, clusterv1.APIEndpoint{}) &&
@@ -122,6 +174,41 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
 		}
 	}
 
+	if oldC.Spec.NetworkSpec.VPC.Isolated != r.Spec.NetworkSpec.VPC.Isolated {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "isolated"), r.Spec.NetworkSpec.VPC.Isolated, "field is immutable"),
+		)
+	}
+
+	// The IPv6 CIDR block is only associated when the VPC is created.
+	if oldC.Spec.NetworkSpec.VPC.IsIPv6Enabled() != r.Spec.NetworkSpec.VPC.IsIPv6Enabled() {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "ipv6"), r.Spec.NetworkSpec.VPC.IPv6, "field is immutable"),
+		)
+	}
+
+	// Subnets have already been carved from the secondary CIDR block.
+	if !reflect.DeepEqual(oldC.Spec.SecondaryCidrBlock, r.Spec.SecondaryCidrBlock) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "secondaryCidrBlock"), r.Spec.SecondaryCidrBlock, "field is immutable"),
+		)
+	}
+
+	// Switching topologies would leave the NAT gateways of the old topology behind.
+	if oldC.Spec.NetworkSpec.VPC.IsSingleNatGateway() != r.Spec.NetworkSpec.VPC.IsSingleNatGateway() {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "natGatewayTopology"), r.Spec.NetworkSpec.VPC.NatGatewayTopology, "field is immutable"),
+		)
+	}
+
+	// Removing the DHCP options would leave the VPC associated with the cluster's options set.
+	if oldC.Spec.NetworkSpec.VPC.DHCPOptions != nil && r.Spec.NetworkSpec.VPC.DHCPOptions == nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "dhcpOptions"),
+				r.Spec.NetworkSpec.VPC.DHCPOptions, "field cannot be set to nil"),
+		)
+	}
+
 	// If a identityRef is already set, do not allow removal of it.
 	if oldC.Spec.IdentityRef != nil && r.Spec.IdentityRef == nil {
 		allErrs = append(allErrs,
@@ -138,6 +225,13 @@ func (r *AWSCluster) ValidateUpdate(old runtime.Object) error {
 	}
 
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.ValidatePeeringConnections(r.Spec.SecondaryCidrBlock)...)
+	allErrs = append(allErrs, r.validateControlPlaneLoadBalancer()...)
+	allErrs = append(allErrs, r.validateControlPlaneDNS()...)
+	allErrs = append(allErrs, r.validateIsolatedNetwork()...)
+	allErrs = append(allErrs, r.validateControlPlaneElasticIPs()...)
+	allErrs = append(allErrs, r.validateSecondaryCidrBlock()...)
 
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -151,6 +245,161 @@ func (r *AWSCluster) validateSSHKeyName() field.ErrorList {
 	return validateSSHKeyName(r.Spec.SSHKeyName)
 }
 
+func (r *AWSCluster) validateControlPlaneLoadBalancer() field.ErrorList {
+	var allErrs field.ErrorList
+
+	lb := r.Spec.ControlPlaneLoadBalancer
+	if lb == nil {
+		return allErrs
+	}
+
+	if hc := lb.HealthCheck; hc != nil && (hc.IntervalSeconds != nil || hc.TimeoutSeconds != nil) {
+		interval, timeout := int64(defaultHealthCheckIntervalSeconds), int64(defaultHealthCheckTimeoutSeconds)
+		if hc.IntervalSeconds != nil {
+			interval = *hc.IntervalSeconds
+		}
+		if hc.TimeoutSeconds != nil {
+			timeout = *hc.TimeoutSeconds
+		}
+
+		switch {
+		case lb.LoadBalancerType == LoadBalancerTypeNLB && hc.TimeoutSeconds != nil:
+			allErrs = append(allErrs,
+				field.Forbidden(field.NewPath("spec", "controlPlaneLoadBalancer", "healthCheck", "timeoutSeconds"),
+					"network load balancers do not support a health check timeout"),
+			)
+		case lb.LoadBalancerType != LoadBalancerTypeNLB && timeout >= interval:
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "healthCheck", "timeoutSeconds"),
+					timeout, fmt.Sprintf("must be less than intervalSeconds (%d)", interval)),
+			)
+		}
+	}
+
+	if lb.LoadBalancerType != LoadBalancerTypeNLB {
+		return allErrs
+	}
+
+	if len(lb.AdditionalSecurityGroups) > 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "controlPlaneLoadBalancer", "additionalSecurityGroups"),
+				"network load balancers do not support security groups"),
+		)
+	}
+	return allErrs
+}
+
+func (r *AWSCluster) validateControlPlaneDNS() field.ErrorList {
+	var allErrs field.ErrorList
+
+	dns := r.Spec.ControlPlaneDNS
+	if dns == nil {
+		return allErrs
+	}
+
+	fldPath := field.NewPath("spec", "controlPlaneDNS")
+	switch {
+	case dns.HostedZoneID == "" && dns.PrivateZoneName == "":
+		allErrs = append(allErrs, field.Required(fldPath, "one of hostedZoneID or privateZoneName must be set"))
+	case dns.HostedZoneID != "" && dns.PrivateZoneName != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("privateZoneName"), "cannot be set together with hostedZoneID"))
+	case dns.PrivateZoneName != "":
+		lb := r.Spec.ControlPlaneLoadBalancer
+		if lb == nil || lb.Scheme == nil || *lb.Scheme != ClassicELBSchemeInternal {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("privateZoneName"), "requires an internal control plane load balancer"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateIsolatedNetwork rejects the cluster settings that expose the cluster to the internet
+// when the VPC has no route to it.
+func (r *AWSCluster) validateIsolatedNetwork() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !r.Spec.NetworkSpec.VPC.IsIsolated() {
+		return allErrs
+	}
+
+	scheme := ClassicELBSchemeInternetFacing
+	if lb := r.Spec.ControlPlaneLoadBalancer; lb != nil && lb.Scheme != nil {
+		scheme = *lb.Scheme
+	}
+	if scheme != ClassicELBSchemeInternal {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "controlPlaneLoadBalancer", "scheme"), scheme, "must be internal for an isolated VPC"),
+		)
+	}
+	if r.Spec.Bastion.Enabled {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "bastion", "enabled"), "a bastion host cannot be used with an isolated VPC"),
+		)
+	}
+	if r.Spec.ControlPlaneElasticIPs {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "controlPlaneElasticIPs"), "elastic IPs cannot be used with an isolated VPC"),
+		)
+	}
+
+	return allErrs
+}
+
+// validateControlPlaneElasticIPs checks that control plane instances can be placed in a public subnet, as Elastic IPs
+// can't route traffic to instances in private subnets. Only the subnets of a managed VPC are known to be public or not.
+func (r *AWSCluster) validateControlPlaneElasticIPs() field.ErrorList {
+	var allErrs field.ErrorList
+
+	subnets := r.Spec.NetworkSpec.Subnets
+	if !r.Spec.ControlPlaneElasticIPs || r.Spec.NetworkSpec.VPC.ID != "" || len(subnets) == 0 {
+		return allErrs
+	}
+
+	if len(subnets.FilterPublic()) == 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(field.NewPath("spec", "controlPlaneElasticIPs"), "elastic IPs require control plane instances in a public subnet"),
+		)
+	}
+
+	return allErrs
+}
+
+// validateSecondaryCidrBlock applies the same restrictions as the secondary CIDR block of an
+// AWSManagedControlPlane.
+func (r *AWSCluster) validateSecondaryCidrBlock() field.ErrorList {
+	var allErrs field.ErrorList
+
+	if r.Spec.SecondaryCidrBlock == nil {
+		return allErrs
+	}
+
+	fldPath := field.NewPath("spec", "secondaryCidrBlock")
+	_, ipv4Net, err := net.ParseCIDR(*r.Spec.SecondaryCidrBlock)
+	if err != nil || ipv4Net.IP.To4() == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, *r.Spec.SecondaryCidrBlock, "must be valid CIDR range"))
+		return allErrs
+	}
+
+	prefix, _ := ipv4Net.Mask.Size()
+	if prefix < 16 || prefix > 28 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *r.Spec.SecondaryCidrBlock, "CIDR block sizes must be between a /16 netmask and /28 netmask"))
+	}
+
+	inRange := false
+	for _, validRange := range []string{"100.64.0.0/10", "198.19.0.0/16"} {
+		_, validNet, _ := net.ParseCIDR(validRange)
+		validPrefix, _ := validNet.Mask.Size()
+		if validNet.Contains(ipv4Net.IP) && prefix >= validPrefix {
+			inRange = true
+		}
+	}
+	if !inRange {
+		allErrs = append(allErrs, field.Invalid(fldPath, *r.Spec.SecondaryCidrBlock, "must be within the 100.64.0.0/10 or 198.19.0.0/16 range"))
+	}
+
+	return allErrs
+}
+
 func SetDefaultsAWSClusterSpec(s *AWSClusterSpec) {
 	SetDefaults_Bastion(&s.Bastion)
 	SetDefaults_NetworkSpec(&s.NetworkSpec)
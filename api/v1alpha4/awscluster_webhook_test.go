@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
 )
@@ -42,6 +43,534 @@ func TestAWSCluster_ValidateCreate(t *testing.T) {
 		wantErr bool
 	}{
 		// The SSHKeyName tests were moved to sshkeyname_test.go
+		{
+			name: "accepts a valid VPC CIDR block",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							CidrBlock: "10.1.0.0/16",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "accepts ipv6 subnets in a VPC with ipv6",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{IPv6: &IPv6{}},
+						Subnets: Subnets{
+							{AvailabilityZone: "us-east-1a", CidrBlock: "10.0.0.0/24", IsIPv6: true},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects ipv6 subnets in a managed VPC without ipv6",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{AvailabilityZone: "us-east-1a", CidrBlock: "10.0.0.0/24", IsIPv6: true},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects ipv6 subnet cidr blocks in a managed VPC without ipv6",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{AvailabilityZone: "us-east-1a", CidrBlock: "10.0.0.0/24", IPv6CidrBlock: "2001:db8:1234:1a01::/64"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects an invalid VPC CIDR block",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							CidrBlock: "10.1.0.0/33",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects an additional ingress rule with an invalid CIDR block",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						AdditionalIngressRules: map[SecurityGroupRole]IngressRules{
+							SecurityGroupNode: {
+								{
+									Description: "Corporate NodePorts",
+									Protocol:    SecurityGroupProtocolTCP,
+									FromPort:    30000,
+									ToPort:      32767,
+									CidrBlocks:  []string{"192.168.0.0"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects additional security groups on a network load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType:         LoadBalancerTypeNLB,
+						AdditionalSecurityGroups: []string{"sg-00001"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a health check timeout that is not less than the interval",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						HealthCheck: &AWSLoadBalancerHealthCheck{
+							IntervalSeconds: pointer.Int64(10),
+							TimeoutSeconds:  pointer.Int64(10),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a health check timeout that is not less than the default interval",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						HealthCheck: &AWSLoadBalancerHealthCheck{
+							TimeoutSeconds: pointer.Int64(15),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a health check interval that is not greater than the default timeout",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						HealthCheck: &AWSLoadBalancerHealthCheck{
+							IntervalSeconds: pointer.Int64(5),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a health check timeout for a network load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeNLB,
+						HealthCheck: &AWSLoadBalancerHealthCheck{
+							TimeoutSeconds: pointer.Int64(3),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts a short health check interval for a network load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeNLB,
+						HealthCheck: &AWSLoadBalancerHealthCheck{
+							IntervalSeconds: pointer.Int64(5),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "accepts a tuned health check",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						HealthCheck: &AWSLoadBalancerHealthCheck{
+							IntervalSeconds:    pointer.Int64(5),
+							TimeoutSeconds:     pointer.Int64(3),
+							HealthyThreshold:   pointer.Int64(2),
+							UnhealthyThreshold: pointer.Int64(2),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects a control plane DNS record without a hosted zone",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneDNS: &ControlPlaneDNS{
+						Name: "api.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a private hosted zone for an internet-facing load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneDNS: &ControlPlaneDNS{
+						PrivateZoneName: "internal.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts a private hosted zone for an internal load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Scheme: &ClassicELBSchemeInternal,
+					},
+					ControlPlaneDNS: &ControlPlaneDNS{
+						PrivateZoneName: "internal.example.com",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "accepts an isolated VPC with an internal load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{Isolated: true},
+					},
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Scheme: &ClassicELBSchemeInternal,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects an isolated VPC with an internet-facing load balancer",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{Isolated: true},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects an isolated VPC with a bastion host",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{Isolated: true},
+					},
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Scheme: &ClassicELBSchemeInternal,
+					},
+					Bastion: Bastion{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a peering connection with an invalid CIDR block",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						PeeringConnections: []VPCPeeringConnection{
+							{PeerVPCID: "vpc-shared", PeerCidrBlocks: []string{"172.16.0.0"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a peering connection routing the default route",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						PeeringConnections: []VPCPeeringConnection{
+							{PeerVPCID: "vpc-shared", PeerCidrBlocks: []string{"0.0.0.0/0"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a peering connection overlapping with the VPC CIDR block",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{CidrBlock: "10.0.0.0/16"},
+						PeeringConnections: []VPCPeeringConnection{
+							{PeerVPCID: "vpc-shared", PeerCidrBlocks: []string{"10.0.128.0/24"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a peering connection overlapping with the secondary CIDR block",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("100.64.0.0/16"),
+					NetworkSpec: NetworkSpec{
+						PeeringConnections: []VPCPeeringConnection{
+							{PeerVPCID: "vpc-shared", PeerCidrBlocks: []string{"100.64.0.0/10"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts a peering connection to another network",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("100.64.0.0/16"),
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{CidrBlock: "10.0.0.0/16"},
+						PeeringConnections: []VPCPeeringConnection{
+							{PeerVPCID: "vpc-shared", PeerCidrBlocks: []string{"172.16.0.0/16"}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects an isolated VPC with public subnets",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{Isolated: true},
+						Subnets: Subnets{
+							{CidrBlock: "10.0.0.0/24", IsPublic: true},
+						},
+					},
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Scheme: &ClassicELBSchemeInternal,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts a secondary CIDR block in the carrier-grade NAT range",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("100.64.0.0/16"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects a secondary CIDR block outside of the allowed ranges",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("10.1.0.0/16"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a secondary CIDR block with an unmanaged VPC",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{ID: "vpc-existing"},
+					},
+					SecondaryCidrBlock: pointer.String("100.64.0.0/16"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts DHCP options with a domain name and name servers",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							DHCPOptions: &DHCPOptions{
+								DomainName:        "corp.example.com",
+								DomainNameServers: []string{"10.1.0.2", AmazonProvidedDNS},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects empty DHCP options",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							DHCPOptions: &DHCPOptions{},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects DHCP options with a name server that isn't an IPv4 address",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							DHCPOptions: &DHCPOptions{
+								DomainNameServers: []string{"dns.corp.example.com"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects DHCP options with an unmanaged VPC",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							ID: "vpc-existing",
+							DHCPOptions: &DHCPOptions{
+								DomainName: "corp.example.com",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts network ACL rules",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetworkACLRules: []NetworkACLRule{
+							{RuleNumber: 100, Action: NetworkACLRuleActionAllow, Protocol: SecurityGroupProtocolTCP, CidrBlock: "10.0.0.0/8", FromPort: 1024, ToPort: 65535},
+							{RuleNumber: 100, Egress: true, Action: NetworkACLRuleActionAllow, Protocol: SecurityGroupProtocolAll, CidrBlock: "0.0.0.0/0"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects network ACL rules with the same number and direction",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetworkACLRules: []NetworkACLRule{
+							{RuleNumber: 100, Action: NetworkACLRuleActionAllow, Protocol: SecurityGroupProtocolAll, CidrBlock: "10.0.0.0/8"},
+							{RuleNumber: 100, Action: NetworkACLRuleActionDeny, Protocol: SecurityGroupProtocolAll, CidrBlock: "0.0.0.0/0"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a network ACL rule with ports for a protocol without ports",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						NetworkACLRules: []NetworkACLRule{
+							{RuleNumber: 100, Action: NetworkACLRuleActionAllow, Protocol: SecurityGroupProtocolICMP, CidrBlock: "10.0.0.0/8", FromPort: 8, ToPort: 8},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects network ACL rules with an unmanaged VPC",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{ID: "vpc-existing"},
+						NetworkACLRules: []NetworkACLRule{
+							{RuleNumber: 100, Action: NetworkACLRuleActionAllow, Protocol: SecurityGroupProtocolAll, CidrBlock: "10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects a secondary CIDR block larger than a /16",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("100.64.0.0/10"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts control plane elastic IPs with a public subnet",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneElasticIPs: true,
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{CidrBlock: "10.0.0.0/24", AvailabilityZone: "us-east-1a", IsPublic: true},
+							{CidrBlock: "10.0.1.0/24", AvailabilityZone: "us-east-1a"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects control plane elastic IPs without a public subnet",
+			cluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneElasticIPs: true,
+					NetworkSpec: NetworkSpec{
+						Subnets: Subnets{
+							{CidrBlock: "10.0.1.0/24", AvailabilityZone: "us-east-1a"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -79,6 +608,38 @@ func TestAWSCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "ipv6 cannot be enabled on an existing cluster",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{IPv6: &IPv6{}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ipv6 cidr block can be filled in once the VPC is created",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{IPv6: &IPv6{}},
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{IPv6: &IPv6{CidrBlock: "2001:db8:1234:1a00::/56"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "controlPlaneLoadBalancer scheme is immutable",
 			oldCluster: &AWSCluster{
@@ -125,6 +686,24 @@ func TestAWSCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "controlPlaneLoadBalancer type is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeClassic,
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						LoadBalancerType: LoadBalancerTypeNLB,
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "controlPlaneLoadBalancer crossZoneLoadBalancer is mutable",
 			oldCluster: &AWSCluster{
@@ -180,6 +759,121 @@ func TestAWSCluster_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "controlPlaneDNS is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneDNS: &ControlPlaneDNS{
+						HostedZoneID: "Z0123456789",
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneDNS: &ControlPlaneDNS{
+						HostedZoneID: "Z0123456789",
+						Name:         "api.example.com",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "isolated is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Scheme: &ClassicELBSchemeInternal,
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{Isolated: true},
+					},
+					ControlPlaneLoadBalancer: &AWSLoadBalancerSpec{
+						Scheme: &ClassicELBSchemeInternal,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "natGatewayTopology is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{NatGatewayTopology: &NatGatewayTopologySingle},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "natGatewayTopology can be set to the default",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{NatGatewayTopology: &NatGatewayTopologyPerAZ},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "secondaryCidrBlock is immutable",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("100.64.0.0/16"),
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("100.65.0.0/16"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "secondaryCidrBlock is allowed once the managed VPC is created",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					SecondaryCidrBlock: pointer.String("100.64.0.0/16"),
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{ID: "vpc-managed"},
+					},
+					SecondaryCidrBlock: pointer.String("100.64.0.0/16"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "dhcpOptions cannot be removed",
+			oldCluster: &AWSCluster{
+				Spec: AWSClusterSpec{
+					NetworkSpec: NetworkSpec{
+						VPC: VPCSpec{
+							DHCPOptions: &DHCPOptions{DomainName: "corp.example.com"},
+						},
+					},
+				},
+			},
+			newCluster: &AWSCluster{
+				Spec: AWSClusterSpec{},
+			},
+			wantErr: true,
+		},
 		{
 			name: "removal of externally managed annotation is not allowed",
 			oldCluster: &AWSCluster{
@@ -513,3 +1207,52 @@ func TestAWSCluster_DefaultAllowedCIDRBlocks(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateClusterNetwork(t *testing.T) {
+	tests := []struct {
+		name           string
+		vpcCidrBlock   string
+		clusterNetwork *clusterv1.ClusterNetwork
+		wantErr        bool
+	}{
+		{
+			name:         "no cluster network",
+			vpcCidrBlock: "10.0.0.0/16",
+		},
+		{
+			name:         "pod and service cidr blocks outside the vpc",
+			vpcCidrBlock: "10.0.0.0/16",
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Pods:     &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.96.0.0/12"}},
+			},
+		},
+		{
+			name:         "pod cidr block contains the vpc",
+			vpcCidrBlock: "10.0.0.0/16",
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Pods: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.0.0.0/8"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:         "service cidr block inside the vpc",
+			vpcCidrBlock: "10.0.0.0/16",
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				Services: &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.0.128.0/20"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := ValidateClusterNetwork(tt.vpcCidrBlock, tt.clusterNetwork)
+			if tt.wantErr {
+				g.Expect(errs).NotTo(BeEmpty())
+			} else {
+				g.Expect(errs).To(BeEmpty())
+			}
+		})
+	}
+}
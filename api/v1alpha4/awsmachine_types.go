@@ -39,6 +39,29 @@ var (
 	SecretBackendSecretsManager = SecretBackend("secrets-manager")
 )
 
+// UserDataFormat defines the format of the bootstrap data passed to an instance as user data.
+type UserDataFormat string
+
+var (
+	// UserDataFormatCloudConfig defines bootstrap data consumed by cloud-init.
+	UserDataFormatCloudConfig = UserDataFormat("cloud-config")
+
+	// UserDataFormatIgnition defines bootstrap data consumed by Ignition, which is used instead of
+	// cloud-init by distributions such as Flatcar Container Linux.
+	UserDataFormatIgnition = UserDataFormat("ignition")
+)
+
+// StoppedInstancePolicy defines what is done when the instance of a machine is found stopped.
+type StoppedInstancePolicy string
+
+var (
+	// StoppedInstancePolicyRestart starts the instance again.
+	StoppedInstancePolicyRestart = StoppedInstancePolicy("Restart")
+
+	// StoppedInstancePolicyReplace fails the machine, so that a MachineHealthCheck replaces it.
+	StoppedInstancePolicyReplace = StoppedInstancePolicy("Replace")
+)
+
 // AWSMachineSpec defines the desired state of AWSMachine
 type AWSMachineSpec struct {
 	// ProviderID is the unique identifier as specified by the cloud provider.
@@ -74,6 +97,14 @@ type AWSMachineSpec struct {
 	// InstanceType is the type of instance to create. Example: m4.xlarge
 	InstanceType string `json:"instanceType,omitempty"`
 
+	// FallbackInstanceTypes is an ordered list of instance types to launch the instance with
+	// if EC2 has insufficient capacity for InstanceType, or if InstanceType is not offered in
+	// the availability zone. Instance types not offered in the availability zone are skipped.
+	// The instance types must support the same architecture as InstanceType, as the AMI is
+	// looked up for InstanceType only.
+	// +optional
+	FallbackInstanceTypes []string `json:"fallbackInstanceTypes,omitempty"`
+
 	// AdditionalTags is an optional set of tags to add to an instance, in addition to the ones added by default by the
 	// AWS provider. If both the AWSCluster and the AWSMachine specify the same tag name with different values, the
 	// AWSMachine's value takes precedence.
@@ -84,6 +115,14 @@ type AWSMachineSpec struct {
 	// +optional
 	IAMInstanceProfile string `json:"iamInstanceProfile,omitempty"`
 
+	// RoleAdditionalPolicies allows you to attach additional managed policies, given by their ARNs,
+	// to the role of the IAM instance profile. You must enable the EC2EnableIAM
+	// feature flag for these to be attached. They are only attached to roles the controller
+	// created for the instance profile, other roles are never modified. Only the policies listed
+	// in the controller's --role-additional-policies-allow-list flag are allowed.
+	// +optional
+	RoleAdditionalPolicies []string `json:"roleAdditionalPolicies,omitempty"`
+
 	// PublicIP specifies whether the instance should get a public IP.
 	// Precedence for this setting is as follows:
 	// 1. This field if set
@@ -92,6 +131,12 @@ type AWSMachineSpec struct {
 	// +optional
 	PublicIP *bool `json:"publicIP,omitempty"`
 
+	// PrivateIP is the private IPv4 address to assign to the instance. It must be an available
+	// address of the subnet the instance is launched into, other than the addresses AWS reserves.
+	// Cannot be set in templates, nor together with networkInterfaces.
+	// +optional
+	PrivateIP *string `json:"privateIP,omitempty"`
+
 	// AdditionalSecurityGroups is an array of references to security groups that should be applied to the
 	// instance. These security groups would be set in addition to any security groups defined
 	// at the cluster level or in the actuator. It is possible to specify either IDs of Filters. Using Filters
@@ -134,6 +179,14 @@ type AWSMachineSpec struct {
 	// +optional
 	UncompressedUserData *bool `json:"uncompressedUserData,omitempty"`
 
+	// UserDataFormat is the format of the bootstrap data, either cloud-config or ignition.
+	// Defaults to cloud-config. Ignition bootstrap data is passed to the instance as is: it is
+	// neither gzip-compressed nor stored in a secure secrets backend, so cloudInit.secureSecretsBackend
+	// cannot be set when using ignition.
+	// +optional
+	// +kubebuilder:validation:Enum:=cloud-config;ignition
+	UserDataFormat UserDataFormat `json:"userDataFormat,omitempty"`
+
 	// CloudInit defines options related to the bootstrapping systems where
 	// CloudInit is used.
 	// +optional
@@ -147,6 +200,89 @@ type AWSMachineSpec struct {
 	// +optional
 	// +kubebuilder:validation:Enum:=default;dedicated;host
 	Tenancy string `json:"tenancy,omitempty"`
+
+	// HostID is the ID of the Dedicated Host the instance is launched on.
+	// Only valid when tenancy is "host".
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// HostResourceGroupArn is the ARN of the host resource group in which to launch the instance.
+	// Only valid when tenancy is "host", and cannot be used together with hostID.
+	// +optional
+	HostResourceGroupArn string `json:"hostResourceGroupArn,omitempty"`
+
+	// TerminationProtection enables termination protection on the instance, so that it
+	// can't be terminated through the EC2 API until the protection is lifted. The protection
+	// is lifted automatically when the machine is deleted. Spot instances don't support
+	// termination protection, so it can't be enabled together with spotMarketOptions.
+	// +optional
+	TerminationProtection *bool `json:"terminationProtection,omitempty"`
+
+	// PlacementGroup is the placement group the instance is launched into.
+	// The placement group is created if it does not exist yet.
+	// +optional
+	PlacementGroup *PlacementGroup `json:"placementGroup,omitempty"`
+
+	// CapacityReservation configures whether the instance is launched into a
+	// Capacity Reservation, so that it consumes pre-purchased capacity.
+	// Cannot be used together with spotMarketOptions.
+	// +optional
+	CapacityReservation *CapacityReservation `json:"capacityReservation,omitempty"`
+
+	// IPPrewarming attaches additional network interfaces and secondary private IPv4 addresses
+	// to the instance at launch, so that the Amazon VPC CNI can assign pod IPs right away instead
+	// of attaching network interfaces while pods are being scheduled.
+	// Cannot be used together with networkInterfaces, and additional network interfaces cannot be
+	// used together with publicIP set to true.
+	// +optional
+	IPPrewarming *IPPrewarming `json:"ipPrewarming,omitempty"`
+
+	// DetailedMonitoring enables detailed monitoring of the instance, which sends its metrics
+	// to CloudWatch every minute instead of every five minutes, at an additional cost.
+	// +optional
+	DetailedMonitoring bool `json:"detailedMonitoring,omitempty"`
+
+	// CloudWatchAgent installs the CloudWatch agent on the instance, to collect its memory and disk
+	// metrics and ship the kubelet and containerd logs to CloudWatch Logs. The role of the instance
+	// profile needs the permissions of the CloudWatchAgentServerPolicy managed policy, which is
+	// attached to it when the EC2EnableIAM feature flag is enabled and the controller created the role.
+	// The agent is downloaded from the S3 bucket of the cluster region. Cannot be used together with
+	// the ignition user data format or Bottlerocket.
+	// +optional
+	CloudWatchAgent *CloudWatchAgent `json:"cloudWatchAgent,omitempty"`
+
+	// CPUOptions sets the number of CPU cores and threads per core of the instance, for example to
+	// disable multithreading or to reduce the number of cores visible to licensed software.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// CreditSpecification is the credit option for CPU usage of burstable performance instances.
+	// Only valid for T instance types, including the fallback instance types.
+	// +optional
+	CreditSpecification *CreditSpecification `json:"creditSpecification,omitempty"`
+
+	// ElasticFabricAdapter launches the instance with an Elastic Fabric Adapter as its primary
+	// network interface, for HPC and machine learning workloads using MPI or NCCL. The instance
+	// type must support EFA, and network.allowEFATraffic must be set on the cluster.
+	// Cannot be used together with networkInterfaces.
+	// +optional
+	ElasticFabricAdapter bool `json:"elasticFabricAdapter,omitempty"`
+
+	// Fleet launches the instance through an EC2 Fleet of type instant instead of a single RunInstances
+	// call, so that EC2 chooses its instance type and subnet among several combinations. Spot instances
+	// are requested from the fleet when spotMarketOptions is set. Cannot be used together with
+	// fallbackInstanceTypes, networkInterfaces, publicIP, privateIP, ipPrewarming, elasticFabricAdapter
+	// or capacityReservation, as those depend on a single instance type or subnet.
+	// +optional
+	Fleet *Fleet `json:"fleet,omitempty"`
+
+	// StoppedInstancePolicy is what is done when the instance is found stopped, e.g. after it was
+	// stopped from the console. Restart starts it again, waiting longer between attempts when it
+	// keeps stopping; stopped spot instances are left for EC2 to start again. Replace fails the
+	// machine, so that a MachineHealthCheck replaces it. By default, the instance is left stopped.
+	// +kubebuilder:validation:Enum=Restart;Replace
+	// +optional
+	StoppedInstancePolicy StoppedInstancePolicy `json:"stoppedInstancePolicy,omitempty"`
 }
 
 // CloudInit defines options related to the bootstrapping systems where
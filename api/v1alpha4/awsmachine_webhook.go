@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -54,6 +55,24 @@ func (r *AWSMachine) ValidateCreate() error {
 	allErrs = append(allErrs, r.validateNonRootVolumes()...)
 	allErrs = append(allErrs, r.validateSSHKeyName()...)
 	allErrs = append(allErrs, r.validateAdditionalSecurityGroups()...)
+	allErrs = append(allErrs, r.validateImageLookupFormat()...)
+	allErrs = append(allErrs, validateHostPlacement(r.Spec.Tenancy, r.Spec.HostID, r.Spec.HostResourceGroupArn, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateCapacityReservation(r.Spec.CapacityReservation, r.Spec.SpotMarketOptions, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateTerminationProtection(r.Spec.TerminationProtection, r.Spec.SpotMarketOptions, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateIPPrewarming(r.Spec.IPPrewarming, r.Spec.NetworkInterfaces, r.Spec.PublicIP, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateCreditSpecification(r.Spec.CreditSpecification, r.Spec.InstanceType, r.Spec.FallbackInstanceTypes, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateElasticFabricAdapter(r.Spec.ElasticFabricAdapter, r.Spec.NetworkInterfaces, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validatePrivateIP(r.Spec.PrivateIP, r.Spec.NetworkInterfaces, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateInstanceTypes(r.Spec.InstanceType, r.Spec.FallbackInstanceTypes, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateFleet(&r.Spec, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateAMIReference(r.Spec.AMI, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateSubnetReference(r.Spec.Subnet, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateRoleAdditionalPolicies(r.Spec.RoleAdditionalPolicies, r.Spec.IAMInstanceProfile, field.NewPath("spec"))...)
+	allErrs = append(allErrs, validateBottlerocket(r.Spec.AMI, r.Spec.CloudInit, r.Spec.UserDataFormat, r.Spec.CloudWatchAgent, field.NewPath("spec"))...)
+
+	if r.Spec.PlacementGroup != nil {
+		allErrs = append(allErrs, r.Spec.PlacementGroup.Validate(field.NewPath("spec", "placementGroup"))...)
+	}
 
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
@@ -135,6 +154,14 @@ func (r *AWSMachine) validateCloudInitSecret() field.ErrorList {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudInit", "secretCount"), "must be set together with spec.CloudInit.SecretPrefix"))
 	}
 
+	if r.Spec.UserDataFormat == UserDataFormatIgnition && r.Spec.CloudInit.SecureSecretsBackend != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudInit", "secureSecretsBackend"), "cannot be set if spec.userDataFormat is ignition"))
+	}
+
+	if r.Spec.UserDataFormat == UserDataFormatIgnition && r.Spec.CloudWatchAgent != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudWatchAgent"), "cannot be set if spec.userDataFormat is ignition"))
+	}
+
 	return allErrs
 }
 
@@ -168,8 +195,9 @@ func (r *AWSMachine) validateRootVolume() field.ErrorList {
 func (r *AWSMachine) validateNonRootVolumes() field.ErrorList {
 	var allErrs field.ErrorList
 
+	deviceNames := sets.NewString()
 	for _, volume := range r.Spec.NonRootVolumes {
-		if VolumeTypesProvisioned.Has(string(r.Spec.RootVolume.Type)) && volume.IOPS == 0 {
+		if VolumeTypesProvisioned.Has(string(volume.Type)) && volume.IOPS == 0 {
 			allErrs = append(allErrs, field.Required(field.NewPath("spec.nonRootVolumes.iops"), "iops required if type is 'io1' or 'io2'"))
 		}
 
@@ -184,7 +212,10 @@ func (r *AWSMachine) validateNonRootVolumes() field.ErrorList {
 
 		if volume.DeviceName == "" {
 			allErrs = append(allErrs, field.Required(field.NewPath("spec.nonRootVolumes.deviceName"), "non root volume should have device name"))
+		} else if deviceNames.Has(volume.DeviceName) {
+			allErrs = append(allErrs, field.Duplicate(field.NewPath("spec.nonRootVolumes.deviceName"), volume.DeviceName))
 		}
+		deviceNames.Insert(volume.DeviceName)
 	}
 
 	return allErrs
@@ -196,9 +227,10 @@ func (r *AWSMachine) ValidateDelete() error {
 }
 
 // Default implements webhook.Defaulter such that an empty CloudInit will be defined with a default
-// SecureSecretsBackend as SecretBackendSecretsManager iff InsecureSkipSecretsManager is unset.
+// SecureSecretsBackend as SecretBackendSecretsManager iff InsecureSkipSecretsManager is unset
+// and the user data isn't in the Ignition format.
 func (r *AWSMachine) Default() {
-	if !r.Spec.CloudInit.InsecureSkipSecretsManager && r.Spec.CloudInit.SecureSecretsBackend == "" {
+	if !r.Spec.CloudInit.InsecureSkipSecretsManager && r.Spec.CloudInit.SecureSecretsBackend == "" && r.Spec.UserDataFormat != UserDataFormatIgnition {
 		r.Spec.CloudInit.SecureSecretsBackend = SecretBackendSecretsManager
 	}
 }
@@ -217,3 +249,7 @@ func (r *AWSMachine) validateAdditionalSecurityGroups() field.ErrorList {
 func (r *AWSMachine) validateSSHKeyName() field.ErrorList {
 	return validateSSHKeyName(r.Spec.SSHKeyName)
 }
+
+func (r *AWSMachine) validateImageLookupFormat() field.ErrorList {
+	return validateImageLookupFormat(r.Spec.ImageLookupFormat, field.NewPath("spec"))
+}
@@ -37,6 +37,8 @@ func TestMachineDefault(t *testing.T) {
 }
 
 func TestAWSMachine_Create(t *testing.T) {
+	bottlerocket := Bottlerocket
+
 	tests := []struct {
 		name    string
 		machine *AWSMachine
@@ -125,6 +127,39 @@ func TestAWSMachine_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "allow non root volumes without a root volume",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{
+							DeviceName: "/dev/sdb",
+							Size:       50,
+							Type:       "gp3",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ensure non root volumes have unique device names",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NonRootVolumes: []Volume{
+						{
+							DeviceName: "/dev/sdb",
+							Size:       50,
+						},
+						{
+							DeviceName: "/dev/sdb",
+							Size:       100,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "ensure non root volume throughput is nonnegative",
 			machine: &AWSMachine{
@@ -138,6 +173,24 @@ func TestAWSMachine_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "image lookup format may reference base OS and kubernetes version",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ImageLookupFormat: "custom-{{.BaseOS}}-?{{.K8sVersion}}-*",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "image lookup format must be a valid template",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					ImageLookupFormat: "custom-{{.BaseOS-*",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "additional security groups may have id",
 			machine: &AWSMachine{
@@ -188,6 +241,526 @@ func TestAWSMachine_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "placement group partition count is allowed with the partition strategy",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PlacementGroup: &PlacementGroup{
+						Name:           "example",
+						Strategy:       PlacementGroupStrategyPartition,
+						PartitionCount: pointer.Int64(3),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "placement group partition count is forbidden with other strategies",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PlacementGroup: &PlacementGroup{
+						Name:           "example",
+						Strategy:       PlacementGroupStrategySpread,
+						PartitionCount: pointer.Int64(3),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dedicated host id is allowed with host tenancy",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Tenancy: "host",
+					HostID:  "h-0123456789abcdef0",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "dedicated host id is forbidden without host tenancy",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Tenancy: "dedicated",
+					HostID:  "h-0123456789abcdef0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dedicated host id and host resource group can't both be set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Tenancy:              "host",
+					HostID:               "h-0123456789abcdef0",
+					HostResourceGroupArn: "arn:aws:resource-groups:us-east-1:123456789012:group/hosts",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "secure secrets backend is forbidden with ignition user data format",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					UserDataFormat: UserDataFormatIgnition,
+					CloudInit: CloudInit{
+						SecureSecretsBackend: SecretBackendSecretsManager,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cloudwatch agent is allowed with cloud-config user data format",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CloudWatchAgent: &CloudWatchAgent{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cloudwatch agent is forbidden with ignition user data format",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					UserDataFormat:  UserDataFormatIgnition,
+					CloudWatchAgent: &CloudWatchAgent{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "capacity reservation id is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CapacityReservation: &CapacityReservation{
+						ID: aws.String("cr-0123456789abcdef0"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "capacity reservation id and preference can't both be set",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					CapacityReservation: &CapacityReservation{
+						ID:         aws.String("cr-0123456789abcdef0"),
+						Preference: CapacityReservationPreferenceOpen,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "capacity reservation is forbidden with spot market options",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions: &SpotMarketOptions{},
+					CapacityReservation: &CapacityReservation{
+						Preference: CapacityReservationPreferenceOpen,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "termination protection is forbidden with spot market options",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions:     &SpotMarketOptions{},
+					TerminationProtection: aws.Bool(true),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "disabled termination protection is allowed with spot market options",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					SpotMarketOptions:     &SpotMarketOptions{},
+					TerminationProtection: aws.Bool(false),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ip prewarming is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					IPPrewarming: &IPPrewarming{
+						AdditionalNetworkInterfaces: 1,
+						SecondaryPrivateIPCount:     9,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ip prewarming is forbidden with network interfaces",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NetworkInterfaces: []string{"eni-0123456789abcdef0"},
+					IPPrewarming: &IPPrewarming{
+						SecondaryPrivateIPCount: 9,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "additional network interfaces are allowed with public ip disabled",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PublicIP: aws.Bool(false),
+					IPPrewarming: &IPPrewarming{
+						AdditionalNetworkInterfaces: 1,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "additional network interfaces are forbidden with public ip",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PublicIP: aws.Bool(true),
+					IPPrewarming: &IPPrewarming{
+						AdditionalNetworkInterfaces: 1,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "credit specification is allowed for burstable instance types",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:          "t3.medium",
+					FallbackInstanceTypes: []string{"t3a.medium"},
+					CreditSpecification: &CreditSpecification{
+						CPUCredits: CPUCreditsUnlimited,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "credit specification is forbidden for other instance types",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5.large",
+					CreditSpecification: &CreditSpecification{
+						CPUCredits: CPUCreditsUnlimited,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "credit specification is forbidden for other fallback instance types",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:          "t3.large",
+					FallbackInstanceTypes: []string{"m5.large"},
+					CreditSpecification: &CreditSpecification{
+						CPUCredits: CPUCreditsStandard,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "elastic fabric adapter is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:         "p4d.24xlarge",
+					ElasticFabricAdapter: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "elastic fabric adapter is forbidden with network interfaces",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NetworkInterfaces:    []string{"eni-0123456789abcdef0"},
+					ElasticFabricAdapter: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fleet is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5.large",
+					Fleet: &Fleet{
+						Overrides: []FleetOverride{
+							{SubnetID: "subnet-1"},
+							{InstanceType: "m5a.large", SubnetID: "subnet-2"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "capacity optimized fleet is allowed for spot instances",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:      "m5.large",
+					SpotMarketOptions: &SpotMarketOptions{},
+					Fleet: &Fleet{
+						Overrides:          []FleetOverride{{InstanceType: "m5a.large"}},
+						AllocationStrategy: FleetAllocationStrategyCapacityOptimized,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "capacity optimized fleet is forbidden for on-demand instances",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5.large",
+					Fleet: &Fleet{
+						Overrides:          []FleetOverride{{InstanceType: "m5a.large"}},
+						AllocationStrategy: FleetAllocationStrategyCapacityOptimized,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fleet is forbidden with fallback instance types",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:          "m5.large",
+					FallbackInstanceTypes: []string{"m5a.large"},
+					Fleet: &Fleet{
+						Overrides: []FleetOverride{{InstanceType: "m5n.large"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed fleet instance type is forbidden",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5.large",
+					Fleet: &Fleet{
+						Overrides: []FleetOverride{{InstanceType: "m5alarge"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed instance type is forbidden",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType: "m5large",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed fallback instance type is forbidden",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					InstanceType:          "m5.large",
+					FallbackInstanceTypes: []string{"M5A.Large"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ami id is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI: AMIReference{ID: aws.String("ami-0123456789abcdef0")},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ami resolved from an ssm parameter is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI: AMIReference{ID: aws.String("resolve:ssm:/my-org/images/node")},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ami ssm parameter name is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI: AMIReference{SSMParameterName: aws.String("/aws/service/canonical/ubuntu/server/20.04/stable/current/amd64/hvm/ebs-gp2/ami-id")},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ami id and ssm parameter name together are forbidden",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI: AMIReference{
+						ID:               aws.String("ami-0123456789abcdef0"),
+						SSMParameterName: aws.String("/my-org/images/node"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed ami id is forbidden",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI: AMIReference{ID: aws.String("ubuntu-20.04")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed subnet id is forbidden",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					Subnet: &AWSResourceReference{ID: aws.String("my-subnet")},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "private ip is allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PrivateIP: aws.String("10.0.1.10"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "private ip must be an IPv4 address",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					PrivateIP: aws.String("fd00::10"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "private ip is forbidden with network interfaces",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					NetworkInterfaces: []string{"eni-0123456789abcdef0"},
+					PrivateIP:         aws.String("10.0.1.10"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bottlerocket is allowed without secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI:       AMIReference{EKSOptimizedLookupType: &bottlerocket},
+					CloudInit: CloudInit{InsecureSkipSecretsManager: true},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bottlerocket is forbidden with secrets manager",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI: AMIReference{EKSOptimizedLookupType: &bottlerocket},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bottlerocket is forbidden with ignition user data format",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI:            AMIReference{EKSOptimizedLookupType: &bottlerocket},
+					CloudInit:      CloudInit{InsecureSkipSecretsManager: true},
+					UserDataFormat: UserDataFormatIgnition,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bottlerocket is forbidden with the cloudwatch agent",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					AMI:             AMIReference{EKSOptimizedLookupType: &bottlerocket},
+					CloudInit:       CloudInit{InsecureSkipSecretsManager: true},
+					CloudWatchAgent: &CloudWatchAgent{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "role additional policies are allowed",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					IAMInstanceProfile:     "workers",
+					RoleAdditionalPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess", "arn:aws:iam::123456789012:policy/team/ecr-pull"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "role additional policies require an instance profile",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					RoleAdditionalPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "role additional policies must be managed policy arns",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					IAMInstanceProfile:     "workers",
+					RoleAdditionalPolicies: []string{"AmazonS3ReadOnlyAccess"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "role additional policies can't be role arns",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					IAMInstanceProfile:     "workers",
+					RoleAdditionalPolicies: []string{"arn:aws:iam::123456789012:role/admin"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "role additional policies must be allowed by the controller",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					IAMInstanceProfile:     "workers",
+					RoleAdditionalPolicies: []string{"arn:aws:iam::aws:policy/AdministratorAccess"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "role additional policies must be unique",
+			machine: &AWSMachine{
+				Spec: AWSMachineSpec{
+					IAMInstanceProfile:     "workers",
+					RoleAdditionalPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess", "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -298,6 +871,7 @@ func TestAWSMachine_SecretsBackend(t *testing.T) {
 	tests := []struct {
 		name                   string
 		cloudInit              CloudInit
+		userDataFormat         UserDataFormat
 		expectedSecretsBackend string
 	}{
 		{
@@ -315,6 +889,12 @@ func TestAWSMachine_SecretsBackend(t *testing.T) {
 			cloudInit:              CloudInit{InsecureSkipSecretsManager: true},
 			expectedSecretsBackend: "",
 		},
+		{
+			name:                   "with ignition user data format",
+			cloudInit:              CloudInit{InsecureSkipSecretsManager: false},
+			userDataFormat:         UserDataFormatIgnition,
+			expectedSecretsBackend: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -326,6 +906,7 @@ func TestAWSMachine_SecretsBackend(t *testing.T) {
 				Namespace:    "default",
 			}
 			machine.Spec.CloudInit = tt.cloudInit
+			machine.Spec.UserDataFormat = tt.userDataFormat
 			if err := testEnv.Create(ctx, machine); err != nil {
 				t.Errorf("failed to create machine: %v", err)
 			}
@@ -106,13 +106,44 @@ func (r *AWSMachineTemplate) ValidateCreate() error {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudInit", "secretCount"), "cannot be set in templates"))
 	}
 
+	if spec.UserDataFormat == UserDataFormatIgnition && spec.CloudInit.SecureSecretsBackend != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "cloudInit", "secureSecretsBackend"), "cannot be set if spec.template.spec.userDataFormat is ignition"))
+	}
+
+	if spec.UserDataFormat == UserDataFormatIgnition && spec.CloudWatchAgent != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "cloudWatchAgent"), "cannot be set if spec.template.spec.userDataFormat is ignition"))
+	}
+
 	if spec.ProviderID != nil {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "providerID"), "cannot be set in templates"))
 	}
 
+	// A private IP address can only be assigned to one instance at a time.
+	if spec.PrivateIP != nil {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "template", "spec", "privateIP"), "cannot be set in templates"))
+	}
+
 	allErrs = append(allErrs, r.validateRootVolume()...)
 	allErrs = append(allErrs, r.validateNonRootVolumes()...)
 
+	allErrs = append(allErrs, validateImageLookupFormat(spec.ImageLookupFormat, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateHostPlacement(spec.Tenancy, spec.HostID, spec.HostResourceGroupArn, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateCapacityReservation(spec.CapacityReservation, spec.SpotMarketOptions, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateTerminationProtection(spec.TerminationProtection, spec.SpotMarketOptions, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateIPPrewarming(spec.IPPrewarming, spec.NetworkInterfaces, spec.PublicIP, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateCreditSpecification(spec.CreditSpecification, spec.InstanceType, spec.FallbackInstanceTypes, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateElasticFabricAdapter(spec.ElasticFabricAdapter, spec.NetworkInterfaces, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateInstanceTypes(spec.InstanceType, spec.FallbackInstanceTypes, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateFleet(&spec, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateAMIReference(spec.AMI, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateSubnetReference(spec.Subnet, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateRoleAdditionalPolicies(spec.RoleAdditionalPolicies, spec.IAMInstanceProfile, field.NewPath("spec", "template", "spec"))...)
+	allErrs = append(allErrs, validateBottlerocket(spec.AMI, spec.CloudInit, spec.UserDataFormat, spec.CloudWatchAgent, field.NewPath("spec", "template", "spec"))...)
+
+	if spec.PlacementGroup != nil {
+		allErrs = append(allErrs, spec.PlacementGroup.Validate(field.NewPath("spec", "template", "spec", "placementGroup"))...)
+	}
+
 	return aggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, allErrs)
 }
 
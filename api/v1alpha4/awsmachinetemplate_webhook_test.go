@@ -25,6 +25,8 @@ import (
 )
 
 func TestAWSMachineTemplateValidateCreate(t *testing.T) {
+	bottlerocket := Bottlerocket
+
 	tests := []struct {
 		name          string
 		inputTemplate *AWSMachineTemplate
@@ -44,6 +46,34 @@ func TestAWSMachineTemplateValidateCreate(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "don't allow privateIP",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							PrivateIP: pointer.StringPtr("10.0.1.10"),
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "don't allow malformed AMI ID",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							AMI: AMIReference{ID: pointer.StringPtr("ubuntu-20.04")},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
 		{
 			name: "don't allow secretARN",
 			inputTemplate: &AWSMachineTemplate{
@@ -60,6 +90,113 @@ func TestAWSMachineTemplateValidateCreate(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "don't allow invalid image lookup format",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							ImageLookupFormat: "custom-{{.K8sVersion",
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "don't allow bottlerocket with secrets manager",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							AMI: AMIReference{EKSOptimizedLookupType: &bottlerocket},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "don't allow bottlerocket with the cloudwatch agent",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							AMI:             AMIReference{EKSOptimizedLookupType: &bottlerocket},
+							CloudInit:       CloudInit{InsecureSkipSecretsManager: true},
+							CloudWatchAgent: &CloudWatchAgent{},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "don't allow malformed role additional policy ARN",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							IAMInstanceProfile:     "workers",
+							RoleAdditionalPolicies: []string{"arn:aws:s3:::my-bucket"},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "don't allow role additional policies the controller doesn't allow",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							IAMInstanceProfile:     "workers",
+							RoleAdditionalPolicies: []string{"arn:aws:iam::aws:policy/AdministratorAccess"},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "allow role additional policies the controller allows",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							IAMInstanceProfile:     "workers",
+							RoleAdditionalPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+						},
+					},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "don't allow a fleet together with network interfaces",
+			inputTemplate: &AWSMachineTemplate{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: AWSMachineTemplateSpec{
+					Template: AWSMachineTemplateResource{
+						Spec: AWSMachineSpec{
+							InstanceType:      "m5.large",
+							NetworkInterfaces: []string{"eni-0123456789abcdef0"},
+							Fleet: &Fleet{
+								Overrides: []FleetOverride{{InstanceType: "m5a.large"}},
+							},
+						},
+					},
+				},
+			},
+			wantError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -43,6 +43,9 @@ const (
 	VpcCreationStartedReason = "VpcCreationStarted"
 	// VpcReconciliationFailedReason used when errors occur during VPC reconciliation.
 	VpcReconciliationFailedReason = "VpcReconciliationFailed"
+	// VpcCidrBlockOverlapsClusterNetworkReason used when the CIDR block of a managed VPC overlaps with the pod or
+	// service CIDR blocks of the Cluster. The spec has to be fixed before the VPC can be created.
+	VpcCidrBlockOverlapsClusterNetworkReason = "VpcCidrBlockOverlapsClusterNetwork"
 )
 
 const (
@@ -60,6 +63,14 @@ const (
 	InternetGatewayFailedReason = "InternetGatewayFailed"
 )
 
+const (
+	// EgressOnlyInternetGatewayReadyCondition reports on the successful reconciliation of egress only internet gateways.
+	// Only applicable to managed clusters with IPv6 enabled.
+	EgressOnlyInternetGatewayReadyCondition clusterv1.ConditionType = "EgressOnlyInternetGatewayReady"
+	// EgressOnlyInternetGatewayFailedReason used when errors occur during egress only internet gateway reconciliation.
+	EgressOnlyInternetGatewayFailedReason = "EgressOnlyInternetGatewayFailed"
+)
+
 const (
 	// NatGatewaysReadyCondition reports successful reconciliation of NAT gateways.
 	// Only applicable to managed clusters.
@@ -78,6 +89,14 @@ const (
 	RouteTableReconciliationFailedReason = "RouteTableReconciliationFailed"
 )
 
+const (
+	// DHCPOptionsReadyCondition reports successful reconciliation of the DHCP options set of the VPC.
+	// Only applicable to managed clusters.
+	DHCPOptionsReadyCondition clusterv1.ConditionType = "DHCPOptionsReady"
+	// DHCPOptionsReconciliationFailedReason used when any errors occur during reconciliation of the DHCP options set.
+	DHCPOptionsReconciliationFailedReason = "DHCPOptionsReconciliationFailed"
+)
+
 const (
 	// SecondaryCidrsReadyCondition reports successful reconciliation of secondary CIDR blocks.
 	// Only applicable to managed clusters.
@@ -86,6 +105,36 @@ const (
 	SecondaryCidrReconciliationFailedReason = "SecondaryCidrReconciliationFailed"
 )
 
+const (
+	// VPCEndpointsReadyCondition reports successful reconciliation of the VPC endpoints of an isolated VPC.
+	// Only applicable to managed clusters.
+	VPCEndpointsReadyCondition clusterv1.ConditionType = "VPCEndpointsReady"
+	// VPCEndpointsReconciliationFailedReason used when any errors occur during reconciliation of VPC endpoints.
+	VPCEndpointsReconciliationFailedReason = "VPCEndpointsReconciliationFailed"
+)
+
+const (
+	// VPCPeeringConnectionsReadyCondition reports successful reconciliation of VPC peering connections and their routes.
+	// Only applicable to managed clusters.
+	VPCPeeringConnectionsReadyCondition clusterv1.ConditionType = "VPCPeeringConnectionsReady"
+	// VPCPeeringConnectionsReconciliationFailedReason used when any errors occur during reconciliation of VPC peering connections.
+	VPCPeeringConnectionsReconciliationFailedReason = "VPCPeeringConnectionsReconciliationFailed"
+	// VPCPeeringConnectionsNotActiveReason used while a VPC peering connection waits to be accepted by the peer
+	// or to be provisioned.
+	VPCPeeringConnectionsNotActiveReason = "VPCPeeringConnectionsNotActive"
+	// VPCPeeringRoutesConflictReason used when a CIDR block to route through a VPC peering connection is
+	// already routed through another target in a route table of the cluster.
+	VPCPeeringRoutesConflictReason = "VPCPeeringRoutesConflict"
+)
+
+const (
+	// NetworkACLsReadyCondition reports successful reconciliation of the network ACL of the cluster subnets.
+	// Only applicable to managed clusters.
+	NetworkACLsReadyCondition clusterv1.ConditionType = "NetworkACLsReady"
+	// NetworkACLsReconciliationFailedReason used when any errors occur during reconciliation of network ACLs.
+	NetworkACLsReconciliationFailedReason = "NetworkACLsReconciliationFailed"
+)
+
 const (
 	// ClusterSecurityGroupsReadyCondition reports successful reconciliation of security groups.
 	ClusterSecurityGroupsReadyCondition clusterv1.ConditionType = "ClusterSecurityGroupsReady"
@@ -114,6 +163,24 @@ const (
 	LoadBalancerFailedReason = "LoadBalancerFailed"
 )
 
+const (
+	// ControlPlaneDNSReadyCondition reports on whether the Route53 record of the control plane endpoint was
+	// successfully reconciled. Clusters without controlPlaneDNS skip this condition.
+	ControlPlaneDNSReadyCondition clusterv1.ConditionType = "ControlPlaneDNSReady"
+	// ControlPlaneDNSFailedReason used when an error occurs during control plane DNS record reconciliation.
+	ControlPlaneDNSFailedReason = "ControlPlaneDNSFailed"
+	// WaitForDNSPropagationReason used while waiting for the control plane DNS record to propagate to all Route53 name servers.
+	WaitForDNSPropagationReason = "WaitForDNSPropagation"
+)
+
+const (
+	// ControlPlaneElasticIPsReadyCondition reports on whether the Elastic IP addresses of the control plane instances
+	// were successfully reconciled. Clusters without controlPlaneElasticIPs skip this condition.
+	ControlPlaneElasticIPsReadyCondition clusterv1.ConditionType = "ControlPlaneElasticIPsReady"
+	// ControlPlaneElasticIPsFailedReason used when an error occurs during control plane Elastic IP reconciliation.
+	ControlPlaneElasticIPsFailedReason = "ControlPlaneElasticIPsFailed"
+)
+
 const (
 	// InstanceReadyCondition reports on current status of the EC2 instance. Ready indicates the instance is in a Running state.
 	InstanceReadyCondition clusterv1.ConditionType = "InstanceReady"
@@ -124,6 +191,8 @@ const (
 	InstanceTerminatedReason = "InstanceTerminated"
 	// InstanceStoppedReason instance is in a stopped state.
 	InstanceStoppedReason = "InstanceStopped"
+	// InstanceSystemStatusImpairedReason instance is failing its system status checks.
+	InstanceSystemStatusImpairedReason = "InstanceSystemStatusImpaired"
 	// InstanceNotReadyReason used when the instance is in a pending state.
 	InstanceNotReadyReason = "InstanceNotReady"
 	// InstanceProvisionStartedReason set when the provisioning of an instance started.
@@ -156,3 +225,12 @@ const (
 	// ELBDetachFailedReason used when a control plane node fails to detach from an ELB.
 	ELBDetachFailedReason = "ELBDetachFailed"
 )
+
+const (
+	// AMIUpToDateCondition reports whether the instance runs the AMI new instances of the machine are launched with.
+	// Only set when the AMIUpdateDetection feature gate is enabled, for machines whose AMI isn't pinned by ID.
+	AMIUpToDateCondition clusterv1.ConditionType = "AMIUpToDate"
+
+	// NewerAMIAvailableReason used when new instances of the machine are launched with a newer AMI than the instance runs.
+	NewerAMIAvailableReason = "NewerAMIAvailable"
+)
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const roleAdditionalPoliciesWebhookPath = "/validate-infrastructure-cluster-x-k8s-io-v1alpha4-roleadditionalpolicies"
+
+// +kubebuilder:webhook:verbs=create,path=/validate-infrastructure-cluster-x-k8s-io-v1alpha4-roleadditionalpolicies,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=awsmachines;awsmachinetemplates,versions=v1alpha4,name=roleadditionalpolicies.awsmachine.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+
+// RoleAdditionalPoliciesWebhook rejects AWSMachines and AWSMachineTemplates requesting managed policies
+// that aren't allowed to be attached to the roles of their instance profiles. Any other policy is rejected,
+// as whoever can create machines could otherwise grant them any permission, e.g. with AdministratorAccess.
+// +kubebuilder:object:generate=false
+type RoleAdditionalPoliciesWebhook struct {
+	// Allowed holds the ARNs of the managed policies machines may attach to the roles of their instance
+	// profiles. It is set from the --role-additional-policies-allow-list flag of the controller.
+	Allowed sets.String
+
+	decoder *admission.Decoder
+}
+
+var (
+	_ admission.Handler         = &RoleAdditionalPoliciesWebhook{}
+	_ admission.DecoderInjector = &RoleAdditionalPoliciesWebhook{}
+)
+
+// SetupWebhookWithManager registers the webhook with the webhook server of the manager.
+func (w *RoleAdditionalPoliciesWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(roleAdditionalPoliciesWebhookPath, &webhook.Admission{Handler: w})
+	return nil
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (w *RoleAdditionalPoliciesWebhook) InjectDecoder(d *admission.Decoder) error {
+	w.decoder = d
+	return nil
+}
+
+// Handle implements admission.Handler.
+func (w *RoleAdditionalPoliciesWebhook) Handle(_ context.Context, req admission.Request) admission.Response {
+	var (
+		roleAdditionalPolicies []string
+		fldPath                *field.Path
+	)
+
+	switch req.Kind.Kind {
+	case "AWSMachine":
+		machine := &AWSMachine{}
+		if err := w.decoder.Decode(req, machine); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		roleAdditionalPolicies = machine.Spec.RoleAdditionalPolicies
+		fldPath = field.NewPath("spec", "roleAdditionalPolicies")
+	case "AWSMachineTemplate":
+		template := &AWSMachineTemplate{}
+		if err := w.decoder.Decode(req, template); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		roleAdditionalPolicies = template.Spec.Template.Spec.RoleAdditionalPolicies
+		fldPath = field.NewPath("spec", "template", "spec", "roleAdditionalPolicies")
+	default:
+		return admission.Allowed("")
+	}
+
+	var allErrs field.ErrorList
+	for i, policyARN := range roleAdditionalPolicies {
+		if !w.Allowed.Has(policyARN) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i), fmt.Sprintf("policy %q is not allowed by the controller's --role-additional-policies-allow-list", policyARN)))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		status := apierrors.NewInvalid(schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}, req.Name, allErrs).ErrStatus
+		return admission.Response{
+			AdmissionResponse: admissionv1.AdmissionResponse{
+				Allowed: false,
+				Result:  &status,
+			},
+		}
+	}
+
+	return admission.Allowed("")
+}
@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/cluster-api-provider-aws/test/helpers"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -69,6 +70,12 @@ func setup() {
 	if err := (&AWSMachine{}).SetupWebhookWithManager(testEnv); err != nil {
 		panic(fmt.Sprintf("Unable to setup AWSMachine webhook: %v", err))
 	}
+	roleAdditionalPolicies := &RoleAdditionalPoliciesWebhook{
+		Allowed: sets.NewString("arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess", "arn:aws:iam::123456789012:policy/team/ecr-pull"),
+	}
+	if err := roleAdditionalPolicies.SetupWebhookWithManager(testEnv); err != nil {
+		panic(fmt.Sprintf("Unable to setup RoleAdditionalPolicies webhook: %v", err))
+	}
 	if err := (&AWSMachineTemplate{}).SetupWebhookWithManager(testEnv); err != nil {
 		panic(fmt.Sprintf("Unable to setup AWSMachineTemplate webhook: %v", err))
 	}
@@ -129,6 +129,9 @@ const (
 	// PrivateRoleTagValue describes the value for the private role.
 	PrivateRoleTagValue = "private"
 
+	// InstanceProfileRoleTagValue describes the value for the role of IAM roles created for instance profiles.
+	InstanceProfileRoleTagValue = "instance-profile"
+
 	// MachineNameTagKey is the key for machine name.
 	MachineNameTagKey = "MachineName"
 )
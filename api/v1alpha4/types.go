@@ -57,10 +57,22 @@ type AMIReference struct {
 	// +optional
 	ID *string `json:"id,omitempty"`
 
-	// EKSOptimizedLookupType If specified, will look up an EKS Optimized image in SSM Parameter store
-	// +kubebuilder:validation:Enum:=AmazonLinux;AmazonLinuxGPU
+	// EKSOptimizedLookupType If specified, will look up an EKS Optimized image in SSM Parameter store.
+	// Defaults to AmazonLinuxGPU for x86_64 instance types with NVIDIA GPUs, and to AmazonLinux otherwise.
+	// AmazonLinuxGPU is not available for arm64 instance types.
+	// AWSMachines using Bottlerocket must set cloudInit.insecureSkipSecretsManager, as Bottlerocket
+	// doesn't run cloud-init.
+	// EKS Optimized images are only looked up for machines of EKS clusters. Machines of AWSClusters
+	// default to the CAPA images, which don't ship GPU drivers, so GPU instances need a custom image.
+	// +kubebuilder:validation:Enum:=AmazonLinux;AmazonLinuxGPU;Bottlerocket
 	// +optional
 	EKSOptimizedLookupType *EKSAMILookupType `json:"eksLookupType,omitempty"`
+
+	// SSMParameterName is the name of an SSM parameter holding the ID of the AMI to use, such as
+	// the parameters Canonical and Amazon publish for the latest Ubuntu and Amazon Linux AMIs.
+	// Takes precedence over the AMI lookup, the image must be able to bootstrap Kubernetes.
+	// +optional
+	SSMParameterName *string `json:"ssmParameterName,omitempty"`
 }
 
 // AWSMachineTemplateResource describes the data needed to create am AWSMachine from a template
@@ -95,6 +107,15 @@ type NetworkStatus struct {
 
 	// APIServerELB is the Kubernetes api server classic load balancer.
 	APIServerELB ClassicELB `json:"apiServerElb,omitempty"`
+
+	// ControlPlaneElasticIPs are the allocation IDs of the Elastic IP addresses allocated for control plane instances.
+	// +optional
+	ControlPlaneElasticIPs []string `json:"controlPlaneElasticIPs,omitempty"`
+
+	// ControlPlaneDNSChangeID is the ID of the pending Route53 change to the control plane DNS record.
+	// It is cleared once the change has propagated to all Route53 name servers.
+	// +optional
+	ControlPlaneDNSChangeID string `json:"controlPlaneDNSChangeID,omitempty"`
 }
 
 // ClassicELBScheme defines the scheme of a classic load balancer.
@@ -114,6 +135,17 @@ func (e ClassicELBScheme) String() string {
 	return string(e)
 }
 
+// LoadBalancerType defines the type of load balancer used for the API server.
+type LoadBalancerType string
+
+var (
+	// LoadBalancerTypeClassic defines an AWS Classic ELB.
+	LoadBalancerTypeClassic = LoadBalancerType("classic")
+
+	// LoadBalancerTypeNLB defines an AWS Network Load Balancer.
+	LoadBalancerTypeNLB = LoadBalancerType("nlb")
+)
+
 // ClassicELBProtocol defines listener protocols for a classic load balancer.
 type ClassicELBProtocol string
 
@@ -204,6 +236,17 @@ var (
 	AZSelectionSchemeRandom = AZSelectionScheme("Random")
 )
 
+// NatGatewayTopology defines how NAT gateways are provisioned for private subnets.
+type NatGatewayTopology string
+
+var (
+	// NatGatewayTopologyPerAZ will create a NAT gateway in every availability zone.
+	NatGatewayTopologyPerAZ = NatGatewayTopology("PerAZ")
+
+	// NatGatewayTopologySingle will create a single NAT gateway shared by all availability zones.
+	NatGatewayTopologySingle = NatGatewayTopology("Single")
+)
+
 // NetworkSpec encapsulates all things related to AWS network.
 type NetworkSpec struct {
 	// VPC configuration.
@@ -222,6 +265,94 @@ type NetworkSpec struct {
 	// This is optional - if not provided new security groups will be created for the cluster
 	// +optional
 	SecurityGroupOverrides map[SecurityGroupRole]string `json:"securityGroupOverrides,omitempty"`
+
+	// AdditionalIngressRules is an optional set of ingress rules, keyed by security group role,
+	// to add to the security groups managed by the provider, e.g. to allow NodePort traffic from
+	// a corporate network. Rules are not applied to overridden security groups.
+	// +optional
+	AdditionalIngressRules map[SecurityGroupRole]IngressRules `json:"additionalIngressRules,omitempty"`
+
+	// PeeringConnections is an optional list of VPCs to peer a managed VPC with. Routes to the
+	// peer VPCs are added to the route tables of the cluster once a connection is active.
+	// +optional
+	PeeringConnections []VPCPeeringConnection `json:"peeringConnections,omitempty"`
+
+	// NetworkACLRules is an optional list of rules of a network ACL that is created in a managed VPC
+	// and associated with all the subnets of the cluster, on top of the security groups.
+	// Network ACLs are stateless: the return traffic of every allowed connection must be allowed too.
+	// +optional
+	NetworkACLRules []NetworkACLRule `json:"networkACLRules,omitempty"`
+
+	// AllowEFATraffic allows all traffic between the instances sharing a node security group, as
+	// required by the Elastic Fabric Adapters of the machines with elasticFabricAdapter set.
+	// +optional
+	AllowEFATraffic bool `json:"allowEFATraffic,omitempty"`
+}
+
+// NetworkACLRuleAction defines whether a network ACL rule allows or denies the traffic it matches.
+type NetworkACLRuleAction string
+
+var (
+	// NetworkACLRuleActionAllow allows the traffic matched by the rule.
+	NetworkACLRuleActionAllow = NetworkACLRuleAction("allow")
+
+	// NetworkACLRuleActionDeny denies the traffic matched by the rule.
+	NetworkACLRuleActionDeny = NetworkACLRuleAction("deny")
+)
+
+// NetworkACLRule defines a rule of the network ACL of the cluster subnets.
+type NetworkACLRule struct {
+	// RuleNumber orders the evaluation of the rules of a direction, lowest first.
+	// The first rule that matches the traffic applies.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32766
+	RuleNumber int64 `json:"ruleNumber"`
+
+	// Egress applies the rule to the traffic leaving the subnets instead of the traffic entering them.
+	// +optional
+	Egress bool `json:"egress,omitempty"`
+
+	// Action is whether the rule allows or denies the traffic.
+	// +kubebuilder:validation:Enum=allow;deny
+	Action NetworkACLRuleAction `json:"action"`
+
+	// Protocol is the protocol of the traffic. "-1" matches all protocols and "icmp" all ICMP types.
+	// +kubebuilder:validation:Enum="-1";tcp;udp;icmp
+	Protocol SecurityGroupProtocol `json:"protocol"`
+
+	// CidrBlock is the IPv4 CIDR block the traffic comes from, or goes to for an egress rule.
+	CidrBlock string `json:"cidrBlock"`
+
+	// FromPort is the start of the port range of a tcp or udp rule.
+	// +optional
+	FromPort int64 `json:"fromPort,omitempty"`
+
+	// ToPort is the end of the port range of a tcp or udp rule.
+	// +optional
+	ToPort int64 `json:"toPort,omitempty"`
+}
+
+// VPCPeeringConnection configures a peering connection between the cluster VPC and another VPC.
+type VPCPeeringConnection struct {
+	// PeerVPCID is the id of the VPC to peer with.
+	PeerVPCID string `json:"peerVpcId"`
+
+	// PeerOwnerID is the id of the AWS account that owns the peer VPC.
+	// Defaults to the account of the cluster. Connections to a VPC of another account
+	// must be accepted by that account.
+	// +optional
+	PeerOwnerID string `json:"peerOwnerId,omitempty"`
+
+	// PeerRegion is the region of the peer VPC. Defaults to the region of the cluster.
+	// Connections to a VPC of another region must be accepted from that region.
+	// +optional
+	PeerRegion string `json:"peerRegion,omitempty"`
+
+	// PeerCidrBlocks are the CIDR blocks routed through the connection.
+	// Defaults to all the CIDR blocks of the peer VPC. Cannot contain 0.0.0.0/0 nor overlap with
+	// the CIDR blocks of the cluster VPC.
+	// +optional
+	PeerCidrBlocks []string `json:"peerCidrBlocks,omitempty"`
 }
 
 // VPCSpec configures an AWS VPC.
@@ -233,6 +364,12 @@ type VPCSpec struct {
 	// Defaults to 10.0.0.0/16.
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// IPv6 contains the IPv6 settings for the VPC. Setting this field on a managed
+	// VPC requests an Amazon-provided IPv6 CIDR block and configures the network as dual-stack.
+	// It can only be set when the cluster is created.
+	// +optional
+	IPv6 *IPv6 `json:"ipv6,omitempty"`
+
 	// InternetGatewayID is the id of the internet gateway associated with the VPC.
 	// +optional
 	InternetGatewayID *string `json:"internetGatewayId,omitempty"`
@@ -256,6 +393,55 @@ type VPCSpec struct {
 	// +kubebuilder:default=Ordered
 	// +kubebuilder:validation:Enum=Ordered;Random
 	AvailabilityZoneSelection *AZSelectionScheme `json:"availabilityZoneSelection,omitempty"`
+
+	// NatGatewayTopology specifies how NAT gateways are provisioned for the private subnets
+	// of a managed VPC. There are 2 topologies:
+	// PerAZ - creates a NAT gateway in every availability zone that has a public subnet
+	// Single - creates one NAT gateway shared by all private subnets
+	// Defaults to PerAZ
+	// +optional
+	// +kubebuilder:validation:Enum=PerAZ;Single
+	NatGatewayTopology *NatGatewayTopology `json:"natGatewayTopology,omitempty"`
+
+	// Isolated creates a managed VPC without any route to the internet: no internet gateway,
+	// NAT gateways or public subnets are created. Instances reach the AWS APIs they depend on
+	// through VPC endpoints, which are created along with the VPC.
+	// +optional
+	Isolated bool `json:"isolated,omitempty"`
+
+	// DHCPOptions configures the DHCP options set associated with a managed VPC, which sets
+	// the search domain and the name servers in the resolv.conf of instances.
+	// +optional
+	DHCPOptions *DHCPOptions `json:"dhcpOptions,omitempty"`
+}
+
+// AmazonProvidedDNS is the name of the DNS server provided by Amazon in DHCP options sets.
+const AmazonProvidedDNS = "AmazonProvidedDNS"
+
+// DHCPOptions defines the DHCP options set associated with a managed VPC.
+type DHCPOptions struct {
+	// DomainName is the domain name instances use to complete unqualified host names.
+	// Defaults to the internal domain of the region.
+	// +optional
+	DomainName string `json:"domainName,omitempty"`
+
+	// DomainNameServers are the IPv4 addresses of the DNS servers instances use, or
+	// AmazonProvidedDNS for the Amazon provided DNS server.
+	// Defaults to AmazonProvidedDNS.
+	// +optional
+	// +kubebuilder:validation:MaxItems=4
+	DomainNameServers []string `json:"domainNameServers,omitempty"`
+}
+
+// IPv6 configures the IPv6 settings of a VPC.
+type IPv6 struct {
+	// CidrBlock is the IPv6 CIDR block provided by Amazon when the VPC has IPv6 enabled.
+	// +optional
+	CidrBlock string `json:"cidrBlock,omitempty"`
+
+	// EgressOnlyInternetGatewayID is the id of the egress-only internet gateway associated with an IPv6 enabled VPC.
+	// +optional
+	EgressOnlyInternetGatewayID *string `json:"egressOnlyInternetGatewayId,omitempty"`
 }
 
 // String returns a string representation of the VPC.
@@ -273,6 +459,21 @@ func (v *VPCSpec) IsManaged(clusterName string) bool {
 	return !v.IsUnmanaged(clusterName)
 }
 
+// IsSingleNatGateway returns true if all private subnets should share a single NAT gateway.
+func (v *VPCSpec) IsSingleNatGateway() bool {
+	return v.NatGatewayTopology != nil && *v.NatGatewayTopology == NatGatewayTopologySingle
+}
+
+// IsIsolated returns true if the VPC has no route to the internet.
+func (v *VPCSpec) IsIsolated() bool {
+	return v.Isolated
+}
+
+// IsIPv6Enabled returns true if the VPC is configured as dual-stack.
+func (v *VPCSpec) IsIPv6Enabled() bool {
+	return v.IPv6 != nil
+}
+
 // SubnetSpec configures an AWS Subnet.
 type SubnetSpec struct {
 	// ID defines a unique identifier to reference this resource.
@@ -281,6 +482,10 @@ type SubnetSpec struct {
 	// CidrBlock is the CIDR block to be used when the provider creates a managed VPC.
 	CidrBlock string `json:"cidrBlock,omitempty"`
 
+	// IPv6CidrBlock is the IPv6 CIDR block to be used when the provider creates a managed VPC with IPv6 enabled.
+	// +optional
+	IPv6CidrBlock string `json:"ipv6CidrBlock,omitempty"`
+
 	// AvailabilityZone defines the availability zone to use for this subnet in the cluster's region.
 	AvailabilityZone string `json:"availabilityZone,omitempty"`
 
@@ -288,6 +493,10 @@ type SubnetSpec struct {
 	// +optional
 	IsPublic bool `json:"isPublic"`
 
+	// IsIPv6 defines the subnet as an IPv6 subnet. A subnet is IPv6 when it is associated with a VPC that has IPv6 enabled.
+	// +optional
+	IsIPv6 bool `json:"isIpv6,omitempty"`
+
 	// RouteTableID is the routing table id associated with the subnet.
 	// +optional
 	RouteTableID *string `json:"routeTableId,omitempty"`
@@ -306,6 +515,11 @@ func (s *SubnetSpec) String() string {
 	return fmt.Sprintf("id=%s/az=%s/public=%v", s.ID, s.AvailabilityZone, s.IsPublic)
 }
 
+// IsSecondary returns true if the subnet was carved from the secondary CIDR block of the VPC for pods.
+func (s *SubnetSpec) IsSecondary() bool {
+	return s.Tags[NameAWSSubnetAssociation] == SecondarySubnetTagValue
+}
+
 // Subnets is a slice of Subnet.
 type Subnets []SubnetSpec
 
@@ -371,6 +585,17 @@ func (s Subnets) FilterPublic() (res Subnets) {
 	return
 }
 
+// FilterPrimary returns a slice containing all subnets except those carved from the secondary CIDR block
+// for pods, which machines and load balancers must not be placed in.
+func (s Subnets) FilterPrimary() (res Subnets) {
+	for i := range s {
+		if !s[i].IsSecondary() {
+			res = append(res, s[i])
+		}
+	}
+	return
+}
+
 // FilterByZone returns a slice containing all subnets that live in the availability zone specified.
 func (s Subnets) FilterByZone(zone string) (res Subnets) {
 	for _, x := range s {
@@ -694,9 +919,50 @@ type Instance struct {
 	// +optional
 	Tenancy string `json:"tenancy,omitempty"`
 
+	// HostID is the ID of the Dedicated Host the instance runs on.
+	// +optional
+	HostID string `json:"hostID,omitempty"`
+
+	// HostResourceGroupArn is the ARN of the host resource group the instance was launched in.
+	// +optional
+	HostResourceGroupArn string `json:"hostResourceGroupArn,omitempty"`
+
 	// IDs of the instance's volumes
 	// +optional
 	VolumeIDs []string `json:"volumeIDs,omitempty"`
+
+	// PublicIPOnLaunch specifies whether a public IPv4 address is associated with the instance
+	// when it is launched, overriding the default of its subnet.
+	// +optional
+	PublicIPOnLaunch *bool `json:"publicIPOnLaunch,omitempty"`
+
+	// TerminationProtection specifies whether termination protection is enabled when the instance is launched.
+	// +optional
+	TerminationProtection bool `json:"terminationProtection,omitempty"`
+
+	// PlacementGroupName is the name of the placement group the instance runs in.
+	// +optional
+	PlacementGroupName string `json:"placementGroupName,omitempty"`
+
+	// CapacityReservation is the Capacity Reservation configuration of the instance.
+	// +optional
+	CapacityReservation *CapacityReservation `json:"capacityReservation,omitempty"`
+
+	// DetailedMonitoring specifies whether detailed monitoring is enabled for the instance.
+	// +optional
+	DetailedMonitoring bool `json:"detailedMonitoring,omitempty"`
+
+	// CPUOptions is the CPU configuration of the instance.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// CreditSpecification is the credit option for CPU usage the instance is launched with.
+	// +optional
+	CreditSpecification *CreditSpecification `json:"creditSpecification,omitempty"`
+
+	// ElasticFabricAdapter specifies whether the primary network interface of the instance is an Elastic Fabric Adapter.
+	// +optional
+	ElasticFabricAdapter bool `json:"elasticFabricAdapter,omitempty"`
 }
 
 // Volume encapsulates the configuration options for the storage device
@@ -773,6 +1039,174 @@ type SpotMarketOptions struct {
 	MaxPrice *string `json:"maxPrice,omitempty"`
 }
 
+// FleetAllocationStrategy describes how EC2 Fleet chooses the instance type and subnet of an instance.
+type FleetAllocationStrategy string
+
+var (
+	// FleetAllocationStrategyLowestPrice launches the instance with the cheapest combination that has capacity.
+	FleetAllocationStrategyLowestPrice = FleetAllocationStrategy("lowest-price")
+
+	// FleetAllocationStrategyCapacityOptimized launches the spot instance in the capacity pool with the most
+	// spare capacity, which is the least likely to be interrupted.
+	FleetAllocationStrategyCapacityOptimized = FleetAllocationStrategy("capacity-optimized")
+)
+
+// Fleet defines the instance type and subnet combinations EC2 Fleet chooses from to launch an instance.
+type Fleet struct {
+	// Overrides are the instance type and subnet combinations the instance may be launched with.
+	// +kubebuilder:validation:MinItems=1
+	Overrides []FleetOverride `json:"overrides"`
+
+	// AllocationStrategy is how EC2 Fleet chooses among the overrides. lowest-price picks the cheapest
+	// combination that has capacity. capacity-optimized picks the spot capacity pool least likely to be
+	// interrupted, and requires spotMarketOptions. Defaults to lowest-price.
+	// +kubebuilder:validation:Enum=lowest-price;capacity-optimized
+	// +optional
+	AllocationStrategy FleetAllocationStrategy `json:"allocationStrategy,omitempty"`
+}
+
+// FleetOverride is an instance type and subnet combination an instance may be launched with.
+type FleetOverride struct {
+	// InstanceType is the type of instance to launch. Defaults to the instanceType of the machine.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// SubnetID is the ID of the subnet to launch the instance into, in the VPC of the cluster.
+	// Defaults to the subnet the machine would be launched into without a fleet.
+	// +optional
+	SubnetID string `json:"subnetID,omitempty"`
+}
+
+// PlacementGroupStrategy describes how instances are placed within a placement group.
+// See: https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/placement-groups.html
+type PlacementGroupStrategy string
+
+var (
+	// PlacementGroupStrategyCluster packs instances close together inside an availability zone.
+	PlacementGroupStrategyCluster = PlacementGroupStrategy("cluster")
+
+	// PlacementGroupStrategySpread places each instance on distinct underlying hardware.
+	PlacementGroupStrategySpread = PlacementGroupStrategy("spread")
+
+	// PlacementGroupStrategyPartition spreads instances across logical partitions
+	// that do not share underlying hardware.
+	PlacementGroupStrategyPartition = PlacementGroupStrategy("partition")
+)
+
+// PlacementGroup defines the placement group an instance is launched into.
+type PlacementGroup struct {
+	// Name is the name of the placement group.
+	// +kubebuilder:validation:MinLength:=1
+	// +kubebuilder:validation:MaxLength:=255
+	Name string `json:"name"`
+
+	// Strategy is the placement strategy used when the placement group is created.
+	// +optional
+	// +kubebuilder:default=cluster
+	// +kubebuilder:validation:Enum:=cluster;spread;partition
+	Strategy PlacementGroupStrategy `json:"strategy,omitempty"`
+
+	// PartitionCount is the number of partitions of a placement group with the partition strategy.
+	// +optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=7
+	PartitionCount *int64 `json:"partitionCount,omitempty"`
+}
+
+// CapacityReservationPreference describes whether an instance can run in any open Capacity Reservation.
+// See: https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/ec2-capacity-reservations.html
+type CapacityReservationPreference string
+
+var (
+	// CapacityReservationPreferenceOpen runs the instance in any open Capacity Reservation
+	// with matching attributes, falling back to On-Demand capacity.
+	CapacityReservationPreferenceOpen = CapacityReservationPreference("open")
+
+	// CapacityReservationPreferenceNone runs the instance in On-Demand capacity only.
+	CapacityReservationPreferenceNone = CapacityReservationPreference("none")
+)
+
+// CapacityReservation defines the Capacity Reservation an instance is launched into.
+type CapacityReservation struct {
+	// ID is the ID of the Capacity Reservation the instance is launched into.
+	// Cannot be used together with preference.
+	// +optional
+	ID *string `json:"id,omitempty"`
+
+	// Preference indicates whether the instance can run in any open Capacity Reservation
+	// with matching attributes. Cannot be used together with id.
+	// +optional
+	// +kubebuilder:validation:Enum:=open;none
+	Preference CapacityReservationPreference `json:"preference,omitempty"`
+}
+
+// IPPrewarming defines the network interfaces and secondary private IPv4 addresses attached to an
+// instance at launch. The network interfaces are created in the subnet and with the security groups
+// of the instance, and are deleted when the instance is terminated.
+type IPPrewarming struct {
+	// AdditionalNetworkInterfaces is the number of network interfaces to attach in addition
+	// to the primary one. The instance type must support that many network interfaces.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=14
+	AdditionalNetworkInterfaces int64 `json:"additionalNetworkInterfaces,omitempty"`
+
+	// SecondaryPrivateIPCount is the number of secondary private IPv4 addresses to assign to
+	// each network interface, including the primary one. The instance type must support that
+	// many IPv4 addresses per network interface.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=49
+	SecondaryPrivateIPCount int64 `json:"secondaryPrivateIPCount,omitempty"`
+}
+
+// CPUOptions defines the number of CPU cores and threads per core of an instance.
+// The instance type must support the given number of cores and threads per core.
+type CPUOptions struct {
+	// CoreCount is the number of CPU cores of the instance.
+	// +kubebuilder:validation:Minimum=1
+	CoreCount int64 `json:"coreCount"`
+
+	// ThreadsPerCore is the number of threads per CPU core. Set it to 1 to disable multithreading.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2
+	ThreadsPerCore int64 `json:"threadsPerCore"`
+}
+
+// CPUCredits describes how a burstable performance instance is charged for its CPU usage.
+type CPUCredits string
+
+const (
+	// CPUCreditsStandard throttles the instance to its baseline CPU performance once it has spent its CPU credits.
+	CPUCreditsStandard = CPUCredits("standard")
+
+	// CPUCreditsUnlimited lets the instance burst above its baseline CPU performance once it has spent
+	// its CPU credits, for an additional charge.
+	CPUCreditsUnlimited = CPUCredits("unlimited")
+)
+
+// CreditSpecification defines the credit option for CPU usage of a burstable performance instance.
+type CreditSpecification struct {
+	// CPUCredits is the credit option for CPU usage of the instance.
+	// +kubebuilder:validation:Enum:=standard;unlimited
+	CPUCredits CPUCredits `json:"cpuCredits"`
+}
+
+// CloudWatchAgent defines how the CloudWatch agent installed on an instance is configured.
+type CloudWatchAgent struct {
+	// LogGroupName is the CloudWatch Logs log group the kubelet and containerd logs are shipped to.
+	// Defaults to /cluster-api/<cluster name>.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[\.\-_/#A-Za-z0-9]{1,512}$`
+	LogGroupName string `json:"logGroupName,omitempty"`
+
+	// MetricsCollectionInterval is how often, in seconds, the memory and disk metrics are collected.
+	// Defaults to 60.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MetricsCollectionInterval int64 `json:"metricsCollectionInterval,omitempty"`
+}
+
 // EKSAMILookupType specifies which AWS AMI to use for a AWSMachine and AWSMachinePool.
 type EKSAMILookupType string
 
@@ -781,4 +1215,6 @@ const (
 	AmazonLinux EKSAMILookupType = "AmazonLinux"
 	// AmazonLinuxGPU is the AmazonLinux GPU AMI type.
 	AmazonLinuxGPU EKSAMILookupType = "AmazonLinuxGPU"
+	// Bottlerocket is the Bottlerocket OS AMI type, which requires bootstrap data in its TOML settings format.
+	Bottlerocket EKSAMILookupType = "Bottlerocket"
 )
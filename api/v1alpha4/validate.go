@@ -20,14 +20,28 @@ import (
 	"fmt"
 	"net"
 	"regexp"
+	"strings"
+	"text/template"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
 var (
 	sshKeyValidNameRegex = regexp.MustCompile(`^[[:graph:]]+([[:print:]]*[[:graph:]]+)*$`)
+
+	burstableInstanceTypeRegex = regexp.MustCompile(`^t[0-9][a-z]*\.`)
+
+	instanceTypeRegex = regexp.MustCompile(`^[a-z][a-z0-9-]*\.[a-z0-9-]+$`)
+	amiIDRegex        = regexp.MustCompile(`^ami-([0-9a-f]{8}|[0-9a-f]{17})$`)
+	subnetIDRegex     = regexp.MustCompile(`^subnet-([0-9a-f]{8}|[0-9a-f]{17})$`)
+
+	managedPolicyARNRegex = regexp.MustCompile(`^arn:aws(-cn|-us-gov|-iso|-iso-b)?:iam::(aws|[0-9]{12}):policy/[\w+=,.@/-]+$`)
 )
 
+// ssmParameterImagePrefix prefixes an image ID RunInstances resolves from an SSM parameter.
+const ssmParameterImagePrefix = "resolve:ssm:"
+
 // Validate will validate the bastion fields.
 func (b *Bastion) Validate() []*field.Error {
 	var errs field.ErrorList
@@ -49,6 +63,522 @@ func (b *Bastion) Validate() []*field.Error {
 	return errs
 }
 
+// Validate will validate the network fields.
+func (n *NetworkSpec) Validate() []*field.Error {
+	var errs field.ErrorList
+
+	if n.VPC.CidrBlock != "" {
+		if _, _, err := net.ParseCIDR(n.VPC.CidrBlock); err != nil {
+			errs = append(errs,
+				field.Invalid(field.NewPath("spec", "network", "vpc", "cidrBlock"), n.VPC.CidrBlock, "must be a valid CIDR block"),
+			)
+		}
+	}
+
+	for role, rules := range n.AdditionalIngressRules {
+		rulesPath := field.NewPath("spec", "network", "additionalIngressRules").Key(string(role))
+		for i, rule := range rules {
+			if len(rule.CidrBlocks) > 0 && len(rule.SourceSecurityGroupIDs) > 0 {
+				errs = append(errs,
+					field.Forbidden(rulesPath.Index(i), "cidrBlocks and sourceSecurityGroupIds cannot both be set"),
+				)
+			}
+			for j, cidr := range rule.CidrBlocks {
+				if _, _, err := net.ParseCIDR(cidr); err != nil {
+					errs = append(errs,
+						field.Invalid(rulesPath.Index(i).Child("cidrBlocks").Index(j), cidr, "must be a valid CIDR block"),
+					)
+				}
+			}
+		}
+	}
+
+	for i, peering := range n.PeeringConnections {
+		peeringPath := field.NewPath("spec", "network", "peeringConnections").Index(i)
+		if peering.PeerVPCID == "" {
+			errs = append(errs,
+				field.Required(peeringPath.Child("peerVpcId"), "must be set"),
+			)
+		}
+		for j, cidr := range peering.PeerCidrBlocks {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				errs = append(errs,
+					field.Invalid(peeringPath.Child("peerCidrBlocks").Index(j), cidr, "must be a valid CIDR block"),
+				)
+			}
+		}
+	}
+
+	// Subnets of a VPC created by the controller can only be IPv6 if the VPC gets an IPv6 CIDR block.
+	if n.VPC.ID == "" && !n.VPC.IsIPv6Enabled() {
+		for i, subnet := range n.Subnets {
+			subnetPath := field.NewPath("spec", "network", "subnets").Index(i)
+			if subnet.IsIPv6 {
+				errs = append(errs,
+					field.Forbidden(subnetPath.Child("isIpv6"), "cannot be set unless spec.network.vpc.ipv6 is set"),
+				)
+			}
+			if subnet.IPv6CidrBlock != "" {
+				errs = append(errs,
+					field.Forbidden(subnetPath.Child("ipv6CidrBlock"), "cannot be set unless spec.network.vpc.ipv6 is set"),
+				)
+			}
+		}
+	}
+
+	type ruleKey struct {
+		egress     bool
+		ruleNumber int64
+	}
+	ruleNumbers := map[ruleKey]bool{}
+	for i, rule := range n.NetworkACLRules {
+		rulePath := field.NewPath("spec", "network", "networkACLRules").Index(i)
+		key := ruleKey{egress: rule.Egress, ruleNumber: rule.RuleNumber}
+		if ruleNumbers[key] {
+			errs = append(errs,
+				field.Duplicate(rulePath.Child("ruleNumber"), rule.RuleNumber),
+			)
+		}
+		ruleNumbers[key] = true
+		if ip, _, err := net.ParseCIDR(rule.CidrBlock); err != nil || ip.To4() == nil {
+			errs = append(errs,
+				field.Invalid(rulePath.Child("cidrBlock"), rule.CidrBlock, "must be a valid IPv4 CIDR block"),
+			)
+		}
+		switch rule.Protocol {
+		case SecurityGroupProtocolTCP, SecurityGroupProtocolUDP:
+			if rule.FromPort < 0 || rule.ToPort > 65535 || rule.FromPort > rule.ToPort {
+				errs = append(errs,
+					field.Invalid(rulePath, fmt.Sprintf("%d-%d", rule.FromPort, rule.ToPort), "must be a port range between 0 and 65535"),
+				)
+			}
+		default:
+			if rule.FromPort != 0 || rule.ToPort != 0 {
+				errs = append(errs,
+					field.Forbidden(rulePath, "ports can only be set for tcp and udp rules"),
+				)
+			}
+		}
+	}
+
+	if n.VPC.DHCPOptions != nil {
+		errs = append(errs, n.VPC.DHCPOptions.validate(field.NewPath("spec", "network", "vpc", "dhcpOptions"))...)
+	}
+
+	if n.VPC.IsIsolated() {
+		errs = append(errs, n.validateIsolated()...)
+	}
+	return errs
+}
+
+// ValidatePeeringConnections will validate the CIDR blocks routed through the VPC peering connections.
+// They can't take over the default route, nor the local routes of the VPC CIDR block and of its
+// secondary CIDR block, which is set outside of the network spec.
+func (n *NetworkSpec) ValidatePeeringConnections(secondaryCidrBlock *string) field.ErrorList {
+	var errs field.ErrorList
+
+	var vpcCidrBlocks []string
+	if n.VPC.CidrBlock != "" {
+		vpcCidrBlocks = append(vpcCidrBlocks, n.VPC.CidrBlock)
+	}
+	if secondaryCidrBlock != nil {
+		vpcCidrBlocks = append(vpcCidrBlocks, *secondaryCidrBlock)
+	}
+
+	for i, peering := range n.PeeringConnections {
+		cidrBlocksPath := field.NewPath("spec", "network", "peeringConnections").Index(i).Child("peerCidrBlocks")
+		for j, cidr := range peering.PeerCidrBlocks {
+			_, peerNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				continue
+			}
+			if ones, _ := peerNet.Mask.Size(); ones == 0 {
+				errs = append(errs,
+					field.Invalid(cidrBlocksPath.Index(j), cidr, "cannot route the default route through a peering connection"),
+				)
+				continue
+			}
+			for _, vpcCidrBlock := range vpcCidrBlocks {
+				_, vpcNet, err := net.ParseCIDR(vpcCidrBlock)
+				if err != nil {
+					continue
+				}
+				if vpcNet.Contains(peerNet.IP) || peerNet.Contains(vpcNet.IP) {
+					errs = append(errs,
+						field.Invalid(cidrBlocksPath.Index(j), cidr, fmt.Sprintf("must not overlap with the VPC CIDR block %s", vpcCidrBlock)),
+					)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// ValidateClusterNetwork will validate the VPC CIDR block against the pod and service CIDR blocks of the owning
+// Cluster. The webhook can't look up the Cluster, so it's run by the controller before it creates a managed VPC.
+func ValidateClusterNetwork(vpcCidrBlock string, clusterNetwork *clusterv1.ClusterNetwork) field.ErrorList {
+	var errs field.ErrorList
+
+	if clusterNetwork == nil {
+		return errs
+	}
+	_, vpcNet, err := net.ParseCIDR(vpcCidrBlock)
+	if err != nil {
+		return append(errs, field.Invalid(field.NewPath("spec", "network", "vpc", "cidrBlock"), vpcCidrBlock, "must be a valid CIDR block"))
+	}
+
+	check := func(path *field.Path, ranges *clusterv1.NetworkRanges) {
+		if ranges == nil {
+			return
+		}
+		for i, cidr := range ranges.CIDRBlocks {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				continue
+			}
+			if vpcNet.Contains(ipNet.IP) || ipNet.Contains(vpcNet.IP) {
+				errs = append(errs,
+					field.Invalid(path.Child("cidrBlocks").Index(i), cidr, fmt.Sprintf("must not overlap with the VPC CIDR block %s", vpcCidrBlock)),
+				)
+			}
+		}
+	}
+	check(field.NewPath("spec", "clusterNetwork", "pods"), clusterNetwork.Pods)
+	check(field.NewPath("spec", "clusterNetwork", "services"), clusterNetwork.Services)
+
+	return errs
+}
+
+func (o *DHCPOptions) validate(fldPath *field.Path) []*field.Error {
+	var errs field.ErrorList
+
+	if o.DomainName == "" && len(o.DomainNameServers) == 0 {
+		errs = append(errs,
+			field.Required(fldPath, "one of domainName or domainNameServers must be set"),
+		)
+	}
+	for i, server := range o.DomainNameServers {
+		if server == AmazonProvidedDNS {
+			continue
+		}
+		if ip := net.ParseIP(server); ip == nil || ip.To4() == nil {
+			errs = append(errs,
+				field.Invalid(fldPath.Child("domainNameServers").Index(i), server, fmt.Sprintf("must be an IPv4 address or %s", AmazonProvidedDNS)),
+			)
+		}
+	}
+	return errs
+}
+
+// validateIsolated rejects the settings that require a route to the internet.
+func (n *NetworkSpec) validateIsolated() []*field.Error {
+	var errs field.ErrorList
+
+	vpcPath := field.NewPath("spec", "network", "vpc")
+	if n.VPC.NatGatewayTopology != nil {
+		errs = append(errs,
+			field.Forbidden(vpcPath.Child("natGatewayTopology"), "cannot be set for an isolated VPC"),
+		)
+	}
+	if n.VPC.IsIPv6Enabled() {
+		errs = append(errs,
+			field.Forbidden(vpcPath.Child("ipv6"), "cannot be set for an isolated VPC"),
+		)
+	}
+	for i, subnet := range n.Subnets {
+		if subnet.IsPublic {
+			errs = append(errs,
+				field.Forbidden(field.NewPath("spec", "network", "subnets").Index(i).Child("isPublic"), "public subnets cannot be used with an isolated VPC"),
+			)
+		}
+	}
+	return errs
+}
+
+// Validate will validate the placement group fields.
+func (p *PlacementGroup) Validate(fldPath *field.Path) []*field.Error {
+	var errs field.ErrorList
+
+	if p.PartitionCount != nil && p.Strategy != PlacementGroupStrategyPartition {
+		errs = append(errs,
+			field.Forbidden(fldPath.Child("partitionCount"), "can only be set if strategy is 'partition'"),
+		)
+	}
+	return errs
+}
+
+func validateHostPlacement(tenancy, hostID, hostResourceGroupArn string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if tenancy != "host" {
+		if hostID != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("hostID"), "can only be set if tenancy is 'host'"))
+		}
+		if hostResourceGroupArn != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("hostResourceGroupArn"), "can only be set if tenancy is 'host'"))
+		}
+	}
+
+	if hostID != "" && hostResourceGroupArn != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("hostResourceGroupArn"), "cannot be set together with hostID"))
+	}
+
+	return allErrs
+}
+
+func validateCapacityReservation(capacityReservation *CapacityReservation, spotMarketOptions *SpotMarketOptions, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if capacityReservation == nil {
+		return allErrs
+	}
+
+	if spotMarketOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("capacityReservation"), "cannot be set together with spotMarketOptions"))
+	}
+
+	if capacityReservation.ID != nil && capacityReservation.Preference != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("capacityReservation", "preference"), "cannot be set together with id"))
+	}
+
+	return allErrs
+}
+
+func validateTerminationProtection(terminationProtection *bool, spotMarketOptions *SpotMarketOptions, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if terminationProtection != nil && *terminationProtection && spotMarketOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("terminationProtection"), "cannot be enabled together with spotMarketOptions"))
+	}
+
+	return allErrs
+}
+
+func validateIPPrewarming(ipPrewarming *IPPrewarming, networkInterfaces []string, publicIP *bool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if ipPrewarming == nil {
+		return allErrs
+	}
+
+	if len(networkInterfaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ipPrewarming"), "cannot be set together with networkInterfaces"))
+	}
+
+	// EC2 only associates a public IP address with instances launched with a single network interface.
+	if ipPrewarming.AdditionalNetworkInterfaces > 0 && publicIP != nil && *publicIP {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ipPrewarming", "additionalNetworkInterfaces"), "cannot be set together with publicIP"))
+	}
+
+	return allErrs
+}
+
+func validatePrivateIP(privateIP *string, networkInterfaces []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if privateIP == nil {
+		return allErrs
+	}
+
+	if ip := net.ParseIP(*privateIP); ip == nil || ip.To4() == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("privateIP"), *privateIP, "must be a valid IPv4 address"))
+	}
+
+	if len(networkInterfaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("privateIP"), "cannot be set together with networkInterfaces"))
+	}
+
+	return allErrs
+}
+
+func validateElasticFabricAdapter(elasticFabricAdapter bool, networkInterfaces []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if elasticFabricAdapter && len(networkInterfaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("elasticFabricAdapter"), "cannot be set together with networkInterfaces"))
+	}
+
+	return allErrs
+}
+
+func validateCreditSpecification(creditSpecification *CreditSpecification, instanceType string, fallbackInstanceTypes []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if creditSpecification == nil {
+		return allErrs
+	}
+
+	for _, t := range append([]string{instanceType}, fallbackInstanceTypes...) {
+		if t != "" && !burstableInstanceTypeRegex.MatchString(t) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("creditSpecification"), fmt.Sprintf("cannot be set for instance type %q, which isn't a burstable performance instance type", t)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateInstanceTypes, validateAMIReference and validateSubnetReference only check the shape of the IDs.
+// The webhooks run without AWS credentials or the AWSCluster of the machine, so they can't tell whether an
+// instance type is offered, whether a subnet is in the failure domain of the machine, or how large the
+// snapshot of an AMI is. The machine controller checks those before RunInstances: CreateInstance rejects a
+// subnet outside the failure domain, and checkRootVolume a root volume smaller than the AMI snapshot.
+func validateInstanceTypes(instanceType string, fallbackInstanceTypes []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if instanceType != "" && !instanceTypeRegex.MatchString(instanceType) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("instanceType"), instanceType, "must be an EC2 instance type, e.g. m5.large"))
+	}
+
+	for i, t := range fallbackInstanceTypes {
+		if !instanceTypeRegex.MatchString(t) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("fallbackInstanceTypes").Index(i), t, "must be an EC2 instance type, e.g. m5.large"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateFleet checks that the fleet only varies what EC2 Fleet can override, the instance type and the subnet.
+func validateFleet(spec *AWSMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Fleet == nil {
+		return allErrs
+	}
+
+	fleetPath := fldPath.Child("fleet")
+	for _, other := range []struct {
+		name string
+		set  bool
+	}{
+		{"fallbackInstanceTypes", len(spec.FallbackInstanceTypes) > 0},
+		{"networkInterfaces", len(spec.NetworkInterfaces) > 0},
+		{"publicIP", spec.PublicIP != nil},
+		{"privateIP", spec.PrivateIP != nil},
+		{"ipPrewarming", spec.IPPrewarming != nil},
+		{"elasticFabricAdapter", spec.ElasticFabricAdapter},
+		{"capacityReservation", spec.CapacityReservation != nil},
+	} {
+		if other.set {
+			allErrs = append(allErrs, field.Forbidden(fleetPath, fmt.Sprintf("cannot be set together with %s", other.name)))
+		}
+	}
+
+	if spec.Fleet.AllocationStrategy == FleetAllocationStrategyCapacityOptimized && spec.SpotMarketOptions == nil {
+		allErrs = append(allErrs, field.Forbidden(fleetPath.Child("allocationStrategy"), "capacity-optimized requires spotMarketOptions"))
+	}
+
+	for i, override := range spec.Fleet.Overrides {
+		if override.InstanceType == "" {
+			continue
+		}
+		if !instanceTypeRegex.MatchString(override.InstanceType) {
+			allErrs = append(allErrs, field.Invalid(fleetPath.Child("overrides").Index(i).Child("instanceType"), override.InstanceType, "must be an EC2 instance type, e.g. m5.large"))
+		} else if spec.CreditSpecification != nil && !burstableInstanceTypeRegex.MatchString(override.InstanceType) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("creditSpecification"), fmt.Sprintf("cannot be set for instance type %q, which isn't a burstable performance instance type", override.InstanceType)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateAMIReference(ami AMIReference, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if ami.ID != nil && ami.SSMParameterName != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ami", "ssmParameterName"), "cannot be set together with spec.ami.id"))
+	}
+
+	if ami.ID == nil || strings.HasPrefix(*ami.ID, ssmParameterImagePrefix) {
+		return allErrs
+	}
+
+	if !amiIDRegex.MatchString(*ami.ID) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ami", "id"), *ami.ID, "must be an AMI ID, e.g. ami-0123456789abcdef0"))
+	}
+
+	return allErrs
+}
+
+func validateSubnetReference(subnet *AWSResourceReference, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if subnet == nil || subnet.ID == nil {
+		return allErrs
+	}
+
+	if !subnetIDRegex.MatchString(*subnet.ID) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnet", "id"), *subnet.ID, "must be a subnet ID, e.g. subnet-0123456789abcdef0"))
+	}
+
+	return allErrs
+}
+
+// validateBottlerocket rejects the user data options Bottlerocket can't handle. Bottlerocket reads its
+// TOML settings directly from the user data, it runs neither cloud-init nor Ignition.
+func validateBottlerocket(ami AMIReference, cloudInit CloudInit, userDataFormat UserDataFormat, cloudWatchAgent *CloudWatchAgent, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if ami.EKSOptimizedLookupType == nil || *ami.EKSOptimizedLookupType != Bottlerocket {
+		return allErrs
+	}
+
+	if !cloudInit.InsecureSkipSecretsManager {
+		allErrs = append(allErrs, field.Required(fldPath.Child("cloudInit", "insecureSkipSecretsManager"), "must be true if ami.eksLookupType is Bottlerocket"))
+	}
+
+	if userDataFormat == UserDataFormatIgnition {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("userDataFormat"), "cannot be ignition if ami.eksLookupType is Bottlerocket"))
+	}
+
+	// Bottlerocket has no shell to run the script installing the CloudWatch agent.
+	if cloudWatchAgent != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("cloudWatchAgent"), "cannot be set if ami.eksLookupType is Bottlerocket"))
+	}
+
+	return allErrs
+}
+
+func validateRoleAdditionalPolicies(roleAdditionalPolicies []string, iamInstanceProfile string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(roleAdditionalPolicies) == 0 {
+		return allErrs
+	}
+
+	if iamInstanceProfile == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("iamInstanceProfile"), "must be set if roleAdditionalPolicies is set"))
+	}
+
+	seen := make(map[string]struct{}, len(roleAdditionalPolicies))
+	for i, policyARN := range roleAdditionalPolicies {
+		if !managedPolicyARNRegex.MatchString(policyARN) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("roleAdditionalPolicies").Index(i), policyARN, "must be the ARN of a managed policy, e.g. arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"))
+			continue
+		}
+		if _, ok := seen[policyARN]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("roleAdditionalPolicies").Index(i), policyARN))
+		}
+		seen[policyARN] = struct{}{}
+	}
+
+	return allErrs
+}
+
+func validateImageLookupFormat(imageLookupFormat string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if imageLookupFormat == "" {
+		return allErrs
+	}
+
+	if _, err := template.New("amiName").Parse(imageLookupFormat); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("imageLookupFormat"), imageLookupFormat, err.Error()))
+	}
+
+	return allErrs
+}
+
 func validateSSHKeyName(sshKeyName *string) field.ErrorList {
 	var allErrs field.ErrorList
 	switch {
@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha3
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/cluster-api-provider-aws/bootstrap/eks/api/v1alpha4"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
@@ -25,14 +27,31 @@ import (
 func (r *EKSConfig) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.EKSConfig)
 
-	return Convert_v1alpha3_EKSConfig_To_v1alpha4_EKSConfig(r, dst, nil)
+	if err := Convert_v1alpha3_EKSConfig_To_v1alpha4_EKSConfig(r, dst, nil); err != nil {
+		return err
+	}
+	// Manually restore data.
+	restored := &v1alpha4.EKSConfig{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.ContainerRuntime = restored.Spec.ContainerRuntime
+	dst.Spec.PreBootstrapCommands = restored.Spec.PreBootstrapCommands
+	dst.Spec.PostBootstrapCommands = restored.Spec.PostBootstrapCommands
+	dst.Spec.Bottlerocket = restored.Spec.Bottlerocket
+	return nil
 }
 
 // ConvertFrom converts the v1alpha4 EKSConfig receiver to a v1alpha3 EKSConfig.
 func (r *EKSConfig) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.EKSConfig)
 
-	return Convert_v1alpha4_EKSConfig_To_v1alpha3_EKSConfig(src, r, nil)
+	if err := Convert_v1alpha4_EKSConfig_To_v1alpha3_EKSConfig(src, r, nil); err != nil {
+		return err
+	}
+	// Preserve Hub data on down-conversion.
+	return utilconversion.MarshalData(src, r)
 }
 
 // ConvertTo converts the v1alpha3 EKSConfigList receiver to a v1alpha4 EKSConfigList.
@@ -53,14 +72,31 @@ func (r *EKSConfigList) ConvertFrom(srcRaw conversion.Hub) error {
 func (r *EKSConfigTemplate) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*v1alpha4.EKSConfigTemplate)
 
-	return Convert_v1alpha3_EKSConfigTemplate_To_v1alpha4_EKSConfigTemplate(r, dst, nil)
+	if err := Convert_v1alpha3_EKSConfigTemplate_To_v1alpha4_EKSConfigTemplate(r, dst, nil); err != nil {
+		return err
+	}
+	// Manually restore data.
+	restored := &v1alpha4.EKSConfigTemplate{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.Template.Spec.ContainerRuntime = restored.Spec.Template.Spec.ContainerRuntime
+	dst.Spec.Template.Spec.PreBootstrapCommands = restored.Spec.Template.Spec.PreBootstrapCommands
+	dst.Spec.Template.Spec.PostBootstrapCommands = restored.Spec.Template.Spec.PostBootstrapCommands
+	dst.Spec.Template.Spec.Bottlerocket = restored.Spec.Template.Spec.Bottlerocket
+	return nil
 }
 
 // ConvertFrom converts the v1alpha4 EKSConfigTemplate receiver to a v1alpha3 EKSConfigTemplate.
 func (r *EKSConfigTemplate) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*v1alpha4.EKSConfigTemplate)
 
-	return Convert_v1alpha4_EKSConfigTemplate_To_v1alpha3_EKSConfigTemplate(src, r, nil)
+	if err := Convert_v1alpha4_EKSConfigTemplate_To_v1alpha3_EKSConfigTemplate(src, r, nil); err != nil {
+		return err
+	}
+	// Preserve Hub data on down-conversion.
+	return utilconversion.MarshalData(src, r)
 }
 
 // ConvertTo converts the v1alpha3 EKSConfigTemplateList receiver to a v1alpha4 EKSConfigTemplateList.
@@ -76,3 +112,8 @@ func (r *EKSConfigTemplateList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1alpha4_EKSConfigTemplateList_To_v1alpha3_EKSConfigTemplateList(src, r, nil)
 }
+
+// Convert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec converts a v1alpha4 EKSConfigSpec to a v1alpha3 EKSConfigSpec.
+func Convert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec(in *v1alpha4.EKSConfigSpec, out *EKSConfigSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec(in, out, s)
+}
@@ -62,11 +62,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha4.EKSConfigSpec)(nil), (*EKSConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec(a.(*v1alpha4.EKSConfigSpec), b.(*EKSConfigSpec), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*EKSConfigStatus)(nil), (*v1alpha4.EKSConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_EKSConfigStatus_To_v1alpha4_EKSConfigStatus(a.(*EKSConfigStatus), b.(*v1alpha4.EKSConfigStatus), scope)
 	}); err != nil {
@@ -117,6 +112,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha4.EKSConfigSpec)(nil), (*EKSConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec(a.(*v1alpha4.EKSConfigSpec), b.(*EKSConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -154,7 +154,17 @@ func Convert_v1alpha4_EKSConfig_To_v1alpha3_EKSConfig(in *v1alpha4.EKSConfig, ou
 
 func autoConvert_v1alpha3_EKSConfigList_To_v1alpha4_EKSConfigList(in *EKSConfigList, out *v1alpha4.EKSConfigList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha4.EKSConfig)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.EKSConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_EKSConfig_To_v1alpha4_EKSConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -165,7 +175,17 @@ func Convert_v1alpha3_EKSConfigList_To_v1alpha4_EKSConfigList(in *EKSConfigList,
 
 func autoConvert_v1alpha4_EKSConfigList_To_v1alpha3_EKSConfigList(in *v1alpha4.EKSConfigList, out *EKSConfigList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]EKSConfig)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EKSConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_EKSConfig_To_v1alpha3_EKSConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -186,14 +206,13 @@ func Convert_v1alpha3_EKSConfigSpec_To_v1alpha4_EKSConfigSpec(in *EKSConfigSpec,
 
 func autoConvert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec(in *v1alpha4.EKSConfigSpec, out *EKSConfigSpec, s conversion.Scope) error {
 	out.KubeletExtraArgs = *(*map[string]string)(unsafe.Pointer(&in.KubeletExtraArgs))
+	// WARNING: in.ContainerRuntime requires manual conversion: does not exist in peer-type
+	// WARNING: in.PreBootstrapCommands requires manual conversion: does not exist in peer-type
+	// WARNING: in.PostBootstrapCommands requires manual conversion: does not exist in peer-type
+	// WARNING: in.Bottlerocket requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec is an autogenerated conversion function.
-func Convert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec(in *v1alpha4.EKSConfigSpec, out *EKSConfigSpec, s conversion.Scope) error {
-	return autoConvert_v1alpha4_EKSConfigSpec_To_v1alpha3_EKSConfigSpec(in, out, s)
-}
-
 func autoConvert_v1alpha3_EKSConfigStatus_To_v1alpha4_EKSConfigStatus(in *EKSConfigStatus, out *v1alpha4.EKSConfigStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
 	out.DataSecretName = (*string)(unsafe.Pointer(in.DataSecretName))
@@ -252,7 +271,17 @@ func Convert_v1alpha4_EKSConfigTemplate_To_v1alpha3_EKSConfigTemplate(in *v1alph
 
 func autoConvert_v1alpha3_EKSConfigTemplateList_To_v1alpha4_EKSConfigTemplateList(in *EKSConfigTemplateList, out *v1alpha4.EKSConfigTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha4.EKSConfigTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.EKSConfigTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_EKSConfigTemplate_To_v1alpha4_EKSConfigTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -263,7 +292,17 @@ func Convert_v1alpha3_EKSConfigTemplateList_To_v1alpha4_EKSConfigTemplateList(in
 
 func autoConvert_v1alpha4_EKSConfigTemplateList_To_v1alpha3_EKSConfigTemplateList(in *v1alpha4.EKSConfigTemplateList, out *EKSConfigTemplateList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]EKSConfigTemplate)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EKSConfigTemplate, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_EKSConfigTemplate_To_v1alpha3_EKSConfigTemplate(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
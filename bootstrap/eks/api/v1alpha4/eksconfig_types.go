@@ -29,6 +29,51 @@ type EKSConfigSpec struct {
 	// Passes the kubelet args into the EKS bootstrap script
 	// +optional
 	KubeletExtraArgs map[string]string `json:"kubeletExtraArgs,omitempty"`
+
+	// ContainerRuntime selects the container runtime the EKS bootstrap script configures on the node.
+	// Defaults to the runtime of the AMI when not set.
+	// +kubebuilder:validation:Enum=dockerd;containerd
+	// +optional
+	ContainerRuntime ContainerRuntime `json:"containerRuntime,omitempty"`
+
+	// PreBootstrapCommands specifies extra commands to run before the EKS bootstrap script.
+	// +optional
+	PreBootstrapCommands []string `json:"preBootstrapCommands,omitempty"`
+
+	// PostBootstrapCommands specifies extra commands to run after the EKS bootstrap script.
+	// +optional
+	PostBootstrapCommands []string `json:"postBootstrapCommands,omitempty"`
+
+	// Bottlerocket generates bootstrap data for nodes running Bottlerocket OS, in its TOML settings
+	// format, instead of calling the bootstrap script of the EKS optimized Amazon Linux AMI.
+	// Cannot be used together with kubeletExtraArgs, preBootstrapCommands or postBootstrapCommands.
+	// +optional
+	Bottlerocket *Bottlerocket `json:"bottlerocket,omitempty"`
+}
+
+// ContainerRuntime is a container runtime supported by the EKS bootstrap script.
+type ContainerRuntime string
+
+const (
+	// ContainerRuntimeDockerd configures the node to use Docker.
+	ContainerRuntimeDockerd = ContainerRuntime("dockerd")
+
+	// ContainerRuntimeContainerd configures the node to use containerd.
+	ContainerRuntimeContainerd = ContainerRuntime("containerd")
+)
+
+// Bottlerocket defines the host containers settings of Bottlerocket OS nodes.
+// See: https://github.com/bottlerocket-os/bottlerocket#host-containers-settings
+type Bottlerocket struct {
+	// EnableAdminContainer enables the admin host container, which gives privileged access to
+	// the node. It is reached through the control host container.
+	// +optional
+	EnableAdminContainer bool `json:"enableAdminContainer,omitempty"`
+
+	// DisableControlContainer disables the control host container, which runs the AWS SSM agent
+	// used to access the node with AWS Systems Manager Session Manager.
+	// +optional
+	DisableControlContainer bool `json:"disableControlContainer,omitempty"`
 }
 
 // EKSConfigStatus defines the observed state of EKSConfig
@@ -17,7 +17,9 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -29,20 +31,51 @@ func (r *EKSConfig) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		Complete()
 }
 
-// +kubebuilder:webhook:verbs=create;update,path=/validate-bootstrap-cluster-x-k8s-io-v1alpha4-eksconfig,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=bootstrap.cluster.x-k8s.io,resources=eksconfig,versions=v1alpha4,name=validation.eksconfigs.bootstrap.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
-// +kubebuilder:webhook:verbs=create;update,path=/mutate-bootstrap-cluster-x-k8s-io-v1alpha4-eksconfig,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=bootstrap.cluster.x-k8s.io,resources=eksconfig,versions=v1alpha4,name=default.eksconfigs.bootstrap.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/validate-bootstrap-cluster-x-k8s-io-v1alpha4-eksconfig,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=bootstrap.cluster.x-k8s.io,resources=eksconfigs,versions=v1alpha4,name=validation.eksconfigs.bootstrap.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-bootstrap-cluster-x-k8s-io-v1alpha4-eksconfig,mutating=true,failurePolicy=fail,matchPolicy=Equivalent,groups=bootstrap.cluster.x-k8s.io,resources=eksconfigs,versions=v1alpha4,name=default.eksconfigs.bootstrap.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1beta1
 
 var _ webhook.Defaulter = &EKSConfig{}
 var _ webhook.Validator = &EKSConfig{}
 
 // ValidateCreate will do any extra validation when creating a EKSConfig.
 func (r *EKSConfig) ValidateCreate() error {
-	return nil
+	return r.validate()
 }
 
 // ValidateUpdate will do any extra validation when updating a EKSConfig.
 func (r *EKSConfig) ValidateUpdate(old runtime.Object) error {
-	return nil
+	return r.validate()
+}
+
+func (r *EKSConfig) validate() error {
+	allErrs := r.Spec.validate(field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("EKSConfig").GroupKind(), r.Name, allErrs)
+}
+
+func (s *EKSConfigSpec) validate(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if s.Bottlerocket != nil && len(s.KubeletExtraArgs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("kubeletExtraArgs"), "cannot be set together with bottlerocket"))
+	}
+
+	if s.Bottlerocket != nil && len(s.PreBootstrapCommands) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("preBootstrapCommands"), "cannot be set together with bottlerocket"))
+	}
+
+	if s.Bottlerocket != nil && len(s.PostBootstrapCommands) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("postBootstrapCommands"), "cannot be set together with bottlerocket"))
+	}
+
+	if s.Bottlerocket != nil && s.ContainerRuntime == ContainerRuntimeDockerd {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("containerRuntime"), "bottlerocket nodes only support containerd"))
+	}
+
+	return allErrs
 }
 
 // ValidateDelete allows you to add any extra validation when deleting.
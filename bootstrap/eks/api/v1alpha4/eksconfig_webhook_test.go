@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEKSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    EKSConfigSpec
+		wantErr bool
+	}{
+		{
+			name: "bootstrap script options are allowed",
+			spec: EKSConfigSpec{
+				KubeletExtraArgs:      map[string]string{"node-labels": "role=worker"},
+				ContainerRuntime:      ContainerRuntimeDockerd,
+				PreBootstrapCommands:  []string{"echo pre"},
+				PostBootstrapCommands: []string{"echo post"},
+			},
+		},
+		{
+			name: "bottlerocket is allowed",
+			spec: EKSConfigSpec{
+				Bottlerocket: &Bottlerocket{EnableAdminContainer: true},
+			},
+		},
+		{
+			name: "bottlerocket with containerd is allowed",
+			spec: EKSConfigSpec{
+				Bottlerocket:     &Bottlerocket{},
+				ContainerRuntime: ContainerRuntimeContainerd,
+			},
+		},
+		{
+			name: "bottlerocket with kubeletExtraArgs is rejected",
+			spec: EKSConfigSpec{
+				Bottlerocket:     &Bottlerocket{},
+				KubeletExtraArgs: map[string]string{"node-labels": "role=worker"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bottlerocket with preBootstrapCommands is rejected",
+			spec: EKSConfigSpec{
+				Bottlerocket:         &Bottlerocket{},
+				PreBootstrapCommands: []string{"echo pre"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bottlerocket with postBootstrapCommands is rejected",
+			spec: EKSConfigSpec{
+				Bottlerocket:          &Bottlerocket{},
+				PostBootstrapCommands: []string{"echo post"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bottlerocket with dockerd is rejected",
+			spec: EKSConfigSpec{
+				Bottlerocket:     &Bottlerocket{},
+				ContainerRuntime: ContainerRuntimeDockerd,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config := &EKSConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			if tt.wantErr {
+				g.Expect(config.ValidateCreate()).NotTo(Succeed())
+				g.Expect(config.ValidateUpdate(config.DeepCopy())).NotTo(Succeed())
+			} else {
+				g.Expect(config.ValidateCreate()).To(Succeed())
+				g.Expect(config.ValidateUpdate(config.DeepCopy())).To(Succeed())
+			}
+		})
+	}
+}
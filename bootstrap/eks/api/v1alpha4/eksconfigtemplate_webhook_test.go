@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEKSConfigTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    EKSConfigSpec
+		wantErr string
+	}{
+		{
+			name: "bottlerocket is allowed",
+			spec: EKSConfigSpec{
+				Bottlerocket: &Bottlerocket{DisableControlContainer: true},
+			},
+		},
+		{
+			name: "bottlerocket with kubeletExtraArgs is rejected",
+			spec: EKSConfigSpec{
+				Bottlerocket:     &Bottlerocket{},
+				KubeletExtraArgs: map[string]string{"node-labels": "role=worker"},
+			},
+			wantErr: "spec.template.spec.kubeletExtraArgs",
+		},
+		{
+			name: "bottlerocket with dockerd is rejected",
+			spec: EKSConfigSpec{
+				Bottlerocket:     &Bottlerocket{},
+				ContainerRuntime: ContainerRuntimeDockerd,
+			},
+			wantErr: "spec.template.spec.containerRuntime",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			template := &EKSConfigTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default"},
+				Spec: EKSConfigTemplateSpec{
+					Template: EKSConfigTemplateResource{Spec: tt.spec},
+				},
+			}
+
+			if tt.wantErr != "" {
+				g.Expect(template.ValidateCreate()).To(MatchError(ContainSubstring(tt.wantErr)))
+				g.Expect(template.ValidateUpdate(template.DeepCopy())).To(MatchError(ContainSubstring(tt.wantErr)))
+			} else {
+				g.Expect(template.ValidateCreate()).To(Succeed())
+				g.Expect(template.ValidateUpdate(template.DeepCopy())).To(Succeed())
+			}
+		})
+	}
+}
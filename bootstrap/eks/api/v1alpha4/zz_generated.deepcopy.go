@@ -25,6 +25,21 @@ import (
 	apiv1alpha4 "sigs.k8s.io/cluster-api/api/v1alpha4"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bottlerocket) DeepCopyInto(out *Bottlerocket) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bottlerocket.
+func (in *Bottlerocket) DeepCopy() *Bottlerocket {
+	if in == nil {
+		return nil
+	}
+	out := new(Bottlerocket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EKSConfig) DeepCopyInto(out *EKSConfig) {
 	*out = *in
@@ -94,6 +109,21 @@ func (in *EKSConfigSpec) DeepCopyInto(out *EKSConfigSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.PreBootstrapCommands != nil {
+		in, out := &in.PreBootstrapCommands, &out.PreBootstrapCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostBootstrapCommands != nil {
+		in, out := &in.PostBootstrapCommands, &out.PostBootstrapCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bottlerocket != nil {
+		in, out := &in.Bottlerocket, &out.Bottlerocket
+		*out = new(Bottlerocket)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EKSConfigSpec.
@@ -19,6 +19,7 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/pointer"
 	bootstrapv1 "sigs.k8s.io/cluster-api-provider-aws/bootstrap/eks/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/bootstrap/eks/internal/userdata"
@@ -37,6 +39,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -186,12 +189,22 @@ func (r *EKSConfigReconciler) joinWorker(ctx context.Context, cluster *clusterv1
 	log.Info("Generating userdata")
 
 	// generate userdata
-	userDataScript, err := userdata.NewNode(&userdata.NodeInput{
-		// AWSManagedControlPlane webhooks default and validate EKSClusterName
-		ClusterName: controlPlane.Spec.EKSClusterName,
+	var userDataScript []byte
+	var err error
+	if config.Spec.Bottlerocket != nil {
+		userDataScript, err = r.newBottlerocketNode(ctx, cluster, controlPlane, config.Spec.Bottlerocket)
+	} else {
+		userDataScript, err = userdata.NewNode(&userdata.NodeInput{
+			// AWSManagedControlPlane webhooks default and validate EKSClusterName
+			ClusterName: controlPlane.Spec.EKSClusterName,
 
-		KubeletExtraArgs: config.Spec.KubeletExtraArgs,
-	})
+			KubeletExtraArgs: config.Spec.KubeletExtraArgs,
+			ContainerRuntime: string(config.Spec.ContainerRuntime),
+
+			PreBootstrapCommands:  config.Spec.PreBootstrapCommands,
+			PostBootstrapCommands: config.Spec.PostBootstrapCommands,
+		})
+	}
 	if err != nil {
 		log.Error(err, "Failed to create a worker join configuration")
 		conditions.MarkFalse(config, bootstrapv1.DataSecretAvailableCondition, bootstrapv1.DataSecretGenerationFailedReason, clusterv1.ConditionSeverityWarning, "")
@@ -208,6 +221,34 @@ func (r *EKSConfigReconciler) joinWorker(ctx context.Context, cluster *clusterv1
 	return ctrl.Result{}, nil
 }
 
+// newBottlerocketNode generates the user data of a Bottlerocket node. Unlike the bootstrap script of the
+// EKS optimized AMI, Bottlerocket doesn't look up the API server endpoint and CA of the cluster, so they
+// are read from the cluster's kubeconfig.
+func (r *EKSConfigReconciler) newBottlerocketNode(ctx context.Context, cluster *clusterv1.Cluster, controlPlane *ekscontrolplanev1.AWSManagedControlPlane, bottlerocket *bootstrapv1.Bottlerocket) ([]byte, error) {
+	data, err := kubeconfig.FromSecret(ctx, r.Client, util.ObjectKey(cluster))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get kubeconfig secret")
+	}
+
+	cfg, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+	}
+
+	kubeCluster, ok := cfg.Clusters[controlPlane.Spec.EKSClusterName]
+	if !ok {
+		return nil, errors.Errorf("cluster %q not found in kubeconfig", controlPlane.Spec.EKSClusterName)
+	}
+
+	return userdata.NewBottlerocketNode(&userdata.BottlerocketNodeInput{
+		ClusterName:             controlPlane.Spec.EKSClusterName,
+		APIServerEndpoint:       kubeCluster.Server,
+		B64ClusterCA:            base64.StdEncoding.EncodeToString(kubeCluster.CertificateAuthorityData),
+		EnableAdminContainer:    bottlerocket.EnableAdminContainer,
+		DisableControlContainer: bottlerocket.DisableControlContainer,
+	})
+}
+
 func (r *EKSConfigReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, option controller.Options) error {
 	b := ctrl.NewControllerManagedBy(mgr).
 		For(&bootstrapv1.EKSConfig{}).
@@ -18,11 +18,21 @@ package controllers
 
 import (
 	"context"
+	"encoding/base64"
 	"testing"
 
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	bootstrapv1 "sigs.k8s.io/cluster-api-provider-aws/bootstrap/eks/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/bootstrap/eks/internal/userdata"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -69,3 +79,84 @@ func TestEKSConfigReconciler_ReturnEarlyIfClusterControlPlaneNotInitialized(t *t
 		g.Expect(err).NotTo(HaveOccurred())
 	}).Should(Succeed())
 }
+
+func TestEKSConfigReconciler_NewBottlerocketNode(t *testing.T) {
+	amcp := newAMCP("test-cluster")
+	cluster := newCluster(amcp.Name)
+	bottlerocket := &bootstrapv1.Bottlerocket{EnableAdminContainer: true}
+
+	tests := []struct {
+		name        string
+		kubeconfig  *clientcmdapi.Config
+		expectedErr string
+	}{
+		{
+			name: "reads the endpoint and CA of the EKS cluster from the kubeconfig",
+			kubeconfig: &clientcmdapi.Config{
+				Clusters: map[string]*clientcmdapi.Cluster{
+					amcp.Spec.EKSClusterName: {
+						Server:                   "https://eks.example.com",
+						CertificateAuthorityData: []byte("ca-data"),
+					},
+				},
+			},
+		},
+		{
+			name: "fails when the EKS cluster is not in the kubeconfig",
+			kubeconfig: &clientcmdapi.Config{
+				Clusters: map[string]*clientcmdapi.Cluster{
+					"other-cluster": {
+						Server: "https://other.example.com",
+					},
+				},
+			},
+			expectedErr: "not found in kubeconfig",
+		},
+		{
+			name:        "fails when the kubeconfig secret does not exist",
+			expectedErr: "failed to get kubeconfig secret",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			clientBuilder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.kubeconfig != nil {
+				data, err := clientcmd.Write(*tt.kubeconfig)
+				g.Expect(err).NotTo(HaveOccurred())
+				clientBuilder = clientBuilder.WithObjects(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      secret.Name(cluster.Name, secret.Kubeconfig),
+						Namespace: cluster.Namespace,
+					},
+					Data: map[string][]byte{
+						secret.KubeconfigDataName: data,
+					},
+				})
+			}
+
+			reconciler := EKSConfigReconciler{
+				Client: clientBuilder.Build(),
+			}
+
+			userData, err := reconciler.newBottlerocketNode(context.Background(), cluster, amcp, bottlerocket)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			expectedUserData, err := userdata.NewBottlerocketNode(&userdata.BottlerocketNodeInput{
+				ClusterName:          amcp.Spec.EKSClusterName,
+				APIServerEndpoint:    "https://eks.example.com",
+				B64ClusterCA:         base64.StdEncoding.EncodeToString([]byte("ca-data")),
+				EnableAdminContainer: true,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(userData)).To(Equal(string(expectedUserData)))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+const (
+	bottlerocketNodeUserData = `[settings.kubernetes]
+cluster-name = {{printf "%q" .ClusterName}}
+api-server = {{printf "%q" .APIServerEndpoint}}
+cluster-certificate = {{printf "%q" .B64ClusterCA}}
+
+[settings.host-containers.admin]
+enabled = {{.EnableAdminContainer}}
+
+[settings.host-containers.control]
+enabled = {{not .DisableControlContainer}}
+`
+)
+
+// BottlerocketNodeInput defines the context to generate the user data of a Bottlerocket node.
+type BottlerocketNodeInput struct {
+	ClusterName       string
+	APIServerEndpoint string
+	// B64ClusterCA is the base64 encoded PEM certificate of the cluster CA.
+	B64ClusterCA string
+
+	EnableAdminContainer    bool
+	DisableControlContainer bool
+}
+
+// NewBottlerocketNode returns the TOML settings to be used as user data on a Bottlerocket node instance.
+func NewBottlerocketNode(input *BottlerocketNodeInput) ([]byte, error) {
+	t, err := template.New("BottlerocketNode").Parse(bottlerocketNodeUserData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BottlerocketNode template: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, input); err != nil {
+		return nil, fmt.Errorf("failed to generate BottlerocketNode template: %w", err)
+	}
+
+	return out.Bytes(), nil
+}
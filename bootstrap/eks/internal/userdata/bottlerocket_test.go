@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+)
+
+func TestNewBottlerocketNode(t *testing.T) {
+	format.TruncatedDiff = false
+	g := NewWithT(t)
+
+	tests := []struct {
+		name          string
+		input         *BottlerocketNodeInput
+		expectedBytes []byte
+	}{
+		{
+			name: "success case",
+			input: &BottlerocketNodeInput{
+				ClusterName:       "test-cluster",
+				APIServerEndpoint: "https://test-cluster.eks.amazonaws.com",
+				B64ClusterCA:      "Y2VydGlmaWNhdGU=",
+			},
+			expectedBytes: []byte(`[settings.kubernetes]
+cluster-name = "test-cluster"
+api-server = "https://test-cluster.eks.amazonaws.com"
+cluster-certificate = "Y2VydGlmaWNhdGU="
+
+[settings.host-containers.admin]
+enabled = false
+
+[settings.host-containers.control]
+enabled = true
+`),
+		},
+		{
+			name: "with admin container enabled and control container disabled",
+			input: &BottlerocketNodeInput{
+				ClusterName:             "test-cluster",
+				APIServerEndpoint:       "https://test-cluster.eks.amazonaws.com",
+				B64ClusterCA:            "Y2VydGlmaWNhdGU=",
+				EnableAdminContainer:    true,
+				DisableControlContainer: true,
+			},
+			expectedBytes: []byte(`[settings.kubernetes]
+cluster-name = "test-cluster"
+api-server = "https://test-cluster.eks.amazonaws.com"
+cluster-certificate = "Y2VydGlmaWNhdGU="
+
+[settings.host-containers.admin]
+enabled = true
+
+[settings.host-containers.control]
+enabled = false
+`),
+		},
+	}
+	for _, testcase := range tests {
+		t.Run(testcase.name, func(t *testing.T) {
+			bytes, err := NewBottlerocketNode(testcase.input)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(bytes)).To(Equal(string(testcase.expectedBytes)))
+		})
+	}
+}
@@ -24,7 +24,13 @@ import (
 
 const (
 	nodeUserData = `#!/bin/bash
-/etc/eks/bootstrap.sh {{.ClusterName}} {{- template "args" .KubeletExtraArgs }}
+{{- range .PreBootstrapCommands }}
+{{ . }}
+{{- end }}
+/etc/eks/bootstrap.sh {{.ClusterName}} {{- template "args" .KubeletExtraArgs }} {{- if .ContainerRuntime }} --container-runtime {{.ContainerRuntime}}{{ end }}
+{{- range .PostBootstrapCommands }}
+{{ . }}
+{{- end }}
 `
 )
 
@@ -32,6 +38,10 @@ const (
 type NodeInput struct {
 	ClusterName      string
 	KubeletExtraArgs map[string]string
+	ContainerRuntime string
+
+	PreBootstrapCommands  []string
+	PostBootstrapCommands []string
 }
 
 // NewNode returns the user data string to be used on a node instance.
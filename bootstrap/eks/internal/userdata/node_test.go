@@ -61,6 +61,36 @@ func TestNewNode(t *testing.T) {
 			},
 			expectedBytes: []byte(`#!/bin/bash
 /etc/eks/bootstrap.sh test-cluster --kubelet-extra-args '--foo=bar --pizza-topping=pepperoni'
+`),
+			expectErr: false,
+		},
+		{
+			name: "with container runtime",
+			args: args{
+				input: &NodeInput{
+					ClusterName:      "test-cluster",
+					ContainerRuntime: "containerd",
+				},
+			},
+			expectedBytes: []byte(`#!/bin/bash
+/etc/eks/bootstrap.sh test-cluster --container-runtime containerd
+`),
+			expectErr: false,
+		},
+		{
+			name: "with pre and post bootstrap commands",
+			args: args{
+				input: &NodeInput{
+					ClusterName:           "test-cluster",
+					PreBootstrapCommands:  []string{"echo pre", "yum install -y jq"},
+					PostBootstrapCommands: []string{"echo post"},
+				},
+			},
+			expectedBytes: []byte(`#!/bin/bash
+echo pre
+yum install -y jq
+/etc/eks/bootstrap.sh test-cluster
+echo post
 `),
 			expectErr: false,
 		},
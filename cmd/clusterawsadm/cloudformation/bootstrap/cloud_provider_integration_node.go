@@ -19,6 +19,7 @@ package bootstrap
 import (
 	"github.com/awslabs/goformation/v4/cloudformation"
 	"sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
 )
 
 func (t Template) cloudProviderNodeAwsRoles() []string {
@@ -33,26 +34,6 @@ func (t Template) cloudProviderNodeAwsRoles() []string {
 	return roles
 }
 
-// From https://github.com/kubernetes/cloud-provider-aws
 func (t Template) cloudProviderNodeAwsPolicy() *v1alpha4.PolicyDocument {
-	return &v1alpha4.PolicyDocument{
-		Version: v1alpha4.CurrentVersion,
-		Statement: []v1alpha4.StatementEntry{
-			{
-				Effect:   v1alpha4.EffectAllow,
-				Resource: v1alpha4.Resources{v1alpha4.Any},
-				Action: v1alpha4.Actions{
-					"ec2:DescribeInstances",
-					"ec2:DescribeRegions",
-					"ecr:GetAuthorizationToken",
-					"ecr:BatchCheckLayerAvailability",
-					"ecr:GetDownloadUrlForLayer",
-					"ecr:GetRepositoryPolicy",
-					"ecr:DescribeRepositories",
-					"ecr:ListImages",
-					"ecr:BatchGetImage",
-				},
-			},
-		},
-	}
+	return iam.NodeCloudProviderPolicy()
 }
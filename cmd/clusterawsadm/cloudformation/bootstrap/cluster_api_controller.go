@@ -80,50 +80,85 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 			Effect:   infrav1.EffectAllow,
 			Resource: infrav1.Resources{infrav1.Any},
 			Action: infrav1.Actions{
+				"ec2:AcceptVpcPeeringConnection",
 				"ec2:AllocateAddress",
+				"ec2:AssociateAddress",
+				"ec2:AssociateDhcpOptions",
 				"ec2:AssociateRouteTable",
+				"ec2:AssociateVpcCidrBlock",
 				"ec2:AttachInternetGateway",
 				"ec2:AuthorizeSecurityGroupIngress",
+				"ec2:CreateDhcpOptions",
 				"ec2:CreateInternetGateway",
+				"ec2:CreateEgressOnlyInternetGateway",
 				"ec2:CreateNatGateway",
+				"ec2:CreateNetworkAcl",
+				"ec2:CreateNetworkAclEntry",
+				"ec2:CreatePlacementGroup",
 				"ec2:CreateRoute",
 				"ec2:CreateRouteTable",
 				"ec2:CreateSecurityGroup",
 				"ec2:CreateSubnet",
 				"ec2:CreateTags",
 				"ec2:CreateVpc",
+				"ec2:CreateVpcEndpoint",
+				"ec2:CreateVpcPeeringConnection",
 				"ec2:ModifyVpcAttribute",
+				"ec2:DeleteDhcpOptions",
 				"ec2:DeleteInternetGateway",
+				"ec2:DeleteEgressOnlyInternetGateway",
 				"ec2:DeleteNatGateway",
+				"ec2:DeleteNetworkInterface",
+				"ec2:DeleteNetworkAcl",
+				"ec2:DeleteNetworkAclEntry",
+				"ec2:DeletePlacementGroup",
+				"ec2:DeleteRoute",
 				"ec2:DeleteRouteTable",
 				"ec2:DeleteSecurityGroup",
 				"ec2:DeleteSubnet",
 				"ec2:DeleteTags",
 				"ec2:DeleteVpc",
+				"ec2:DeleteVpcEndpoints",
+				"ec2:DeleteVpcPeeringConnection",
 				"ec2:DescribeAccountAttributes",
 				"ec2:DescribeAddresses",
 				"ec2:DescribeAvailabilityZones",
+				"ec2:DescribeDhcpOptions",
 				"ec2:DescribeInstances",
+				"ec2:DescribeInstanceStatus",
+				"ec2:DescribeInstanceTypes",
+				"ec2:DescribeInstanceTypeOfferings",
 				"ec2:DescribeInternetGateways",
+				"ec2:DescribeEgressOnlyInternetGateways",
 				"ec2:DescribeImages",
 				"ec2:DescribeNatGateways",
+				"ec2:DescribeNetworkAcls",
 				"ec2:DescribeNetworkInterfaces",
 				"ec2:DescribeNetworkInterfaceAttribute",
+				"ec2:DescribePlacementGroups",
 				"ec2:DescribeRouteTables",
 				"ec2:DescribeSecurityGroups",
 				"ec2:DescribeSubnets",
 				"ec2:DescribeVpcs",
+				"ec2:DescribeVpcEndpoints",
+				"ec2:DescribeVpcPeeringConnections",
 				"ec2:DescribeVpcAttribute",
 				"ec2:DescribeVolumes",
 				"ec2:DetachInternetGateway",
 				"ec2:DisassociateRouteTable",
 				"ec2:DisassociateAddress",
+				"ec2:DisassociateVpcCidrBlock",
 				"ec2:ModifyInstanceAttribute",
 				"ec2:ModifyNetworkInterfaceAttribute",
 				"ec2:ModifySubnetAttribute",
 				"ec2:ReleaseAddress",
+				"ec2:ReplaceNetworkAclAssociation",
+				"ec2:ReplaceNetworkAclEntry",
+				"ec2:ReplaceRoute",
+				"ec2:RevokeSecurityGroupEgress",
 				"ec2:RevokeSecurityGroupIngress",
 				"ec2:RunInstances",
+				"ec2:StartInstances",
 				"ec2:TerminateInstances",
 				"tag:GetResources",
 				"elasticloadbalancing:AddTags",
@@ -138,8 +173,19 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"elasticloadbalancing:RegisterInstancesWithLoadBalancer",
 				"elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
 				"elasticloadbalancing:RemoveTags",
+				"elasticloadbalancing:CreateTargetGroup",
+				"elasticloadbalancing:DeleteTargetGroup",
+				"elasticloadbalancing:DescribeTargetGroups",
+				"elasticloadbalancing:ModifyTargetGroup",
+				"elasticloadbalancing:ModifyTargetGroupAttributes",
+				"elasticloadbalancing:DescribeTargetHealth",
+				"elasticloadbalancing:RegisterTargets",
+				"elasticloadbalancing:DeregisterTargets",
+				"elasticloadbalancing:CreateListener",
+				"elasticloadbalancing:DescribeListeners",
 				"autoscaling:DescribeAutoScalingGroups",
 				"autoscaling:DescribeInstanceRefreshes",
+				"ec2:CreateFleet",
 				"ec2:CreateLaunchTemplate",
 				"ec2:CreateLaunchTemplateVersion",
 				"ec2:DescribeLaunchTemplates",
@@ -147,6 +193,8 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"ec2:DeleteLaunchTemplate",
 				"ec2:DeleteLaunchTemplateVersions",
 				"ec2:DescribeKeyPairs",
+				"route53:CreateHostedZone",
+				"route53:ListHostedZonesByVPC",
 			},
 		},
 		{
@@ -163,6 +211,23 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"autoscaling:DeleteTags",
 			},
 		},
+		{
+			Effect: infrav1.EffectAllow,
+			Resource: infrav1.Resources{
+				"arn:*:route53:::hostedzone/*",
+				"arn:*:route53:::change/*",
+			},
+			Action: infrav1.Actions{
+				"route53:AssociateVPCWithHostedZone",
+				"route53:ChangeResourceRecordSets",
+				"route53:ChangeTagsForResource",
+				"route53:DeleteHostedZone",
+				"route53:GetChange",
+				"route53:GetHostedZone",
+				"route53:ListResourceRecordSets",
+				"route53:ListTagsForResource",
+			},
+		},
 		{
 			Effect: infrav1.EffectAllow,
 			Resource: infrav1.Resources{
@@ -199,6 +264,18 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				infrav1.StringLike: map[string]string{"iam:AWSServiceName": "spot.amazonaws.com"},
 			},
 		},
+		{
+			Effect: infrav1.EffectAllow,
+			Action: infrav1.Actions{
+				"iam:CreateServiceLinkedRole",
+			},
+			Resource: infrav1.Resources{
+				"arn:*:iam::*:role/aws-service-role/ec2fleet.amazonaws.com/AWSServiceRoleForEC2Fleet",
+			},
+			Condition: infrav1.Conditions{
+				infrav1.StringLike: map[string]string{"iam:AWSServiceName": "ec2fleet.amazonaws.com"},
+			},
+		},
 		{
 			Effect:   infrav1.EffectAllow,
 			Resource: t.allowedEC2InstanceProfiles(),
@@ -206,6 +283,16 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 				"iam:PassRole",
 			},
 		},
+		{
+			Effect: infrav1.EffectAllow,
+			Resource: infrav1.Resources{
+				"arn:*:ssm:*:*:parameter/aws/service/*",
+				"arn:*:ssm:*:*:parameter/cluster-api-provider-aws/*",
+			},
+			Action: infrav1.Actions{
+				"ssm:GetParameter",
+			},
+		},
 	}
 	for _, secureSecretBackend := range t.Spec.SecureSecretsBackends {
 		switch secureSecretBackend {
@@ -240,16 +327,6 @@ func (t Template) ControllersPolicy() *infrav1.PolicyDocument {
 			"iam:GetRole",
 			"iam:ListAttachedRolePolicies",
 		}
-		statement = append(statement, infrav1.StatementEntry{
-			Effect: infrav1.EffectAllow,
-			Resource: infrav1.Resources{
-				"arn:*:ssm:*:*:parameter/aws/service/eks/optimized-ami/*",
-			},
-			Action: infrav1.Actions{
-				"ssm:GetParameter",
-			},
-		})
-
 		statement = append(statement, infrav1.StatementEntry{
 			Effect: infrav1.EffectAllow,
 			Action: infrav1.Actions{
@@ -18,51 +18,9 @@ package bootstrap
 
 import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
 )
 
-func (t Template) secretPolicy(secureSecretsBackend infrav1.SecretBackend) infrav1.StatementEntry {
-	switch secureSecretsBackend {
-	case infrav1.SecretBackendSecretsManager:
-		return infrav1.StatementEntry{
-			Effect: infrav1.EffectAllow,
-			Resource: infrav1.Resources{
-				"arn:*:secretsmanager:*:*:secret:aws.cluster.x-k8s.io/*",
-			},
-			Action: infrav1.Actions{
-				"secretsmanager:DeleteSecret",
-				"secretsmanager:GetSecretValue",
-			},
-		}
-	case infrav1.SecretBackendSSMParameterStore:
-		return infrav1.StatementEntry{
-			Effect: infrav1.EffectAllow,
-			Resource: infrav1.Resources{
-				"arn:*:ssm:*:*:parameter/cluster.x-k8s.io/*",
-			},
-			Action: infrav1.Actions{
-				"ssm:DeleteParameter",
-				"ssm:GetParameter",
-			},
-		}
-	}
-	return infrav1.StatementEntry{}
-}
-
-func (t Template) sessionManagerPolicy() infrav1.StatementEntry {
-	return infrav1.StatementEntry{
-		Effect:   infrav1.EffectAllow,
-		Resource: infrav1.Resources{infrav1.Any},
-		Action: infrav1.Actions{
-			"ssm:UpdateInstanceInformation",
-			"ssmmessages:CreateControlChannel",
-			"ssmmessages:CreateDataChannel",
-			"ssmmessages:OpenControlChannel",
-			"ssmmessages:OpenDataChannel",
-			"s3:GetEncryptionConfiguration",
-		},
-	}
-}
-
 func (t Template) nodeManagedPolicies() []string {
 	policies := t.Spec.Nodes.ExtraPolicyAttachments
 
@@ -81,19 +39,7 @@ func (t Template) nodeManagedPolicies() []string {
 }
 
 func (t Template) nodePolicy() *infrav1.PolicyDocument {
-	policyDocument := t.cloudProviderNodeAwsPolicy()
-	for _, secureSecretsBackend := range t.Spec.SecureSecretsBackends {
-		policyDocument.Statement = append(
-			policyDocument.Statement,
-			t.secretPolicy(secureSecretsBackend),
-		)
-	}
-	policyDocument.Statement = append(
-		policyDocument.Statement,
-		t.sessionManagerPolicy(),
-	)
-
-	return policyDocument
+	return iam.NodeInstanceProfilePolicy(t.Spec.SecureSecretsBackends...)
 }
 
 func (t Template) generateAWSManagedPolicyARN(name string) string {
@@ -30,11 +30,14 @@ import (
 	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/feature"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/gc"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/network"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/route53"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/securitygroup"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/cluster-api/util"
@@ -53,12 +56,32 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+const (
+	// OrphanedResourcesSweepAnnotation is the key for the AWSCluster object annotation which controls
+	// the deletion, when the cluster is deleted, of the AWS resources tagged as owned by the cluster
+	// that its status doesn't track. Set it to OrphanedResourcesSweepDryRun to only list them.
+	OrphanedResourcesSweepAnnotation = "sigs.k8s.io/cluster-api-provider-aws-orphaned-resources-sweep"
+
+	// OrphanedResourcesSweepDryRun makes the sweep log the orphaned resources it finds instead of
+	// deleting them.
+	OrphanedResourcesSweepDryRun = "dry-run"
+
+	// DryRunAnnotation is the key for the AWSCluster object annotation which makes the AWS clients of the
+	// AWSCluster and AWSMachine controllers log the mutating calls they would make for the cluster instead
+	// of making them, as the --dry-run flag does for all clusters.
+	DryRunAnnotation = "sigs.k8s.io/cluster-api-provider-aws-dry-run"
+)
+
 // AWSClusterReconciler reconciles a AwsCluster object.
 type AWSClusterReconciler struct {
 	client.Client
 	Recorder         record.EventRecorder
 	Endpoints        []scope.ServiceEndpoint
 	WatchFilterValue string
+
+	// DryRun makes the AWS clients of all clusters log the mutating calls they would make instead of
+	// making them, set from the --dry-run flag.
+	DryRun bool
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch;create;update;patch;delete
@@ -123,6 +146,7 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		AWSCluster:     awsCluster,
 		ControllerName: "awscluster",
 		Endpoints:      r.Endpoints,
+		DryRun:         isDryRun(r.DryRun, awsCluster),
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -137,11 +161,30 @@ func (r *AWSClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	// Handle deleted clusters
 	if !awsCluster.DeletionTimestamp.IsZero() {
-		return reconcileDelete(clusterScope)
+		return dryRunResult(reconcileDelete(clusterScope))
 	}
 
 	// Handle non-deleted clusters
-	return reconcileNormal(clusterScope)
+	return dryRunResult(reconcileNormal(clusterScope))
+}
+
+// isDryRun returns whether the AWS clients of the cluster log the mutating calls they would make instead of
+// making them, because of the --dry-run flag or of the DryRunAnnotation of the AWSCluster.
+func isDryRun(dryRun bool, awsCluster *infrav1.AWSCluster) bool {
+	if dryRun {
+		return true
+	}
+	_, ok := awsCluster.GetAnnotations()[DryRunAnnotation]
+	return ok
+}
+
+// dryRunResult ends a reconciliation stopped at the first mutating AWS call of a cluster in dry-run mode
+// without an error: the call was logged, and retrying would stop at the same call until something changes.
+func dryRunResult(result reconcile.Result, err error) (reconcile.Result, error) {
+	if awserrors.IsDryRunOperation(errors.Cause(err)) {
+		return reconcile.Result{}, nil
+	}
+	return result, err
 }
 
 // TODO(ncdc): should this be a function on ClusterScope?
@@ -150,6 +193,7 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 
 	ec2svc := ec2.NewService(clusterScope)
 	elbsvc := elb.NewService(clusterScope)
+	route53Svc := route53.NewService(clusterScope)
 	networkSvc := network.NewService(clusterScope)
 	sgService := securitygroup.NewService(clusterScope)
 
@@ -161,6 +205,18 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		}
 	}
 
+	// The record must go first, it is only deleted while it still points at the load balancer.
+	if err := route53Svc.DeleteControlPlaneDNS(); err != nil {
+		clusterScope.Error(err, "error deleting control plane DNS record")
+		return reconcile.Result{}, err
+	}
+
+	// Orphaned instances and load balancers would keep the security groups and the network in use.
+	if err := deleteOrphanedResources(clusterScope, gc.NewService(clusterScope)); err != nil {
+		clusterScope.Error(err, "error deleting orphaned resources")
+		return reconcile.Result{}, err
+	}
+
 	if err := elbsvc.DeleteLoadbalancers(); err != nil {
 		clusterScope.Error(err, "error deleting load balancer")
 		return reconcile.Result{}, err
@@ -171,6 +227,11 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		return reconcile.Result{}, err
 	}
 
+	if err := ec2svc.DeletePlacementGroups(); err != nil {
+		clusterScope.Error(err, "error deleting placement groups")
+		return reconcile.Result{}, err
+	}
+
 	if err := sgService.DeleteSecurityGroups(); err != nil {
 		clusterScope.Error(err, "error deleting security groups")
 		return reconcile.Result{}, err
@@ -187,6 +248,38 @@ func reconcileDelete(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 	return reconcile.Result{}, nil
 }
 
+// deleteOrphanedResources deletes the resources tagged as owned by the cluster that its status has lost
+// track of, or only logs them when the AWSCluster has the OrphanedResourcesSweepAnnotation set to dry-run.
+func deleteOrphanedResources(clusterScope *scope.ClusterScope, gcSvc *gc.Service) error {
+	if clusterScope.AWSCluster.GetAnnotations()[OrphanedResourcesSweepAnnotation] != OrphanedResourcesSweepDryRun {
+		return gcSvc.DeleteOrphans()
+	}
+
+	orphans, err := gcSvc.ListOrphans()
+	if err != nil {
+		return err
+	}
+	if len(orphans) > 0 {
+		clusterScope.Info("Leaving orphaned resources in place in dry-run mode", "resources", orphans)
+	}
+	return nil
+}
+
+// validateClusterNetwork makes sure a managed VPC that is yet to be created doesn't overlap with the pod and service
+// CIDR blocks of the Cluster.
+func validateClusterNetwork(clusterScope *scope.ClusterScope) error {
+	vpc := clusterScope.VPC()
+	if vpc.ID != "" {
+		return nil
+	}
+
+	cidrBlock := vpc.CidrBlock
+	if cidrBlock == "" {
+		cidrBlock = network.DefaultVPCCidr
+	}
+	return infrav1.ValidateClusterNetwork(cidrBlock, clusterScope.Cluster.Spec.ClusterNetwork).ToAggregate()
+}
+
 // TODO(ncdc): should this be a function on ClusterScope?
 func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error) {
 	clusterScope.Info("Reconciling AWSCluster")
@@ -205,6 +298,13 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 	networkSvc := network.NewService(clusterScope)
 	sgService := securitygroup.NewService(clusterScope)
 
+	if err := validateClusterNetwork(clusterScope); err != nil {
+		// Retrying can't fix an overlapping CIDR block, so don't requeue: the next change to the spec triggers a new reconcile.
+		clusterScope.Error(err, "invalid cluster network")
+		conditions.MarkFalse(awsCluster, infrav1.VpcReadyCondition, infrav1.VpcCidrBlockOverlapsClusterNetworkReason, clusterv1.ConditionSeverityError, err.Error())
+		return reconcile.Result{}, nil
+	}
+
 	if err := networkSvc.ReconcileNetwork(); err != nil {
 		clusterScope.Error(err, "failed to reconcile network")
 		return reconcile.Result{}, err
@@ -227,8 +327,18 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 		return reconcile.Result{}, err
 	}
 
+	if err := ec2Service.ReconcileControlPlaneElasticIPs(); err != nil {
+		clusterScope.Error(err, "failed to reconcile control plane elastic IPs")
+		conditions.MarkFalse(awsCluster, infrav1.ControlPlaneElasticIPsReadyCondition, infrav1.ControlPlaneElasticIPsFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return reconcile.Result{}, err
+	}
+	if clusterScope.ControlPlaneElasticIPs() {
+		conditions.MarkTrue(awsCluster, infrav1.ControlPlaneElasticIPsReadyCondition)
+	}
+
 	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
 		instancestateSvc := instancestate.NewService(clusterScope)
+		instancestateSvc.RebalanceRecommendations = feature.Gates.Enabled(feature.SpotRebalanceRecommendation)
 		if err := instancestateSvc.ReconcileEC2Events(); err != nil {
 			// non fatal error, so we continue
 			clusterScope.Error(err, "non-fatal: failed to set up EventBridge")
@@ -254,12 +364,29 @@ func reconcileNormal(clusterScope *scope.ClusterScope) (reconcile.Result, error)
 	}
 	conditions.MarkTrue(awsCluster, infrav1.LoadBalancerReadyCondition)
 
+	endpointHost := awsCluster.Status.Network.APIServerELB.DNSName
+	if awsCluster.Spec.ControlPlaneDNS != nil {
+		recordName, inSync, err := route53.NewService(clusterScope).ReconcileControlPlaneDNS()
+		if err != nil {
+			clusterScope.Error(err, "failed to reconcile control plane DNS record")
+			conditions.MarkFalse(awsCluster, infrav1.ControlPlaneDNSReadyCondition, infrav1.ControlPlaneDNSFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return reconcile.Result{}, err
+		}
+		if !inSync {
+			conditions.MarkFalse(awsCluster, infrav1.ControlPlaneDNSReadyCondition, infrav1.WaitForDNSPropagationReason, clusterv1.ConditionSeverityInfo, "")
+			clusterScope.Info("Waiting on control plane DNS record to propagate", "name", recordName)
+			return reconcile.Result{RequeueAfter: 15 * time.Second}, nil
+		}
+		conditions.MarkTrue(awsCluster, infrav1.ControlPlaneDNSReadyCondition)
+		endpointHost = recordName
+	}
+
 	awsCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{
-		Host: awsCluster.Status.Network.APIServerELB.DNSName,
+		Host: endpointHost,
 		Port: clusterScope.APIServerPort(),
 	}
 
-	for _, subnet := range clusterScope.Subnets().FilterPrivate() {
+	for _, subnet := range clusterScope.Subnets().FilterPrimary().FilterPrivate() {
 		found := false
 		for _, az := range awsCluster.Status.Network.APIServerELB.AvailabilityZones {
 			if az == subnet.AvailabilityZone {
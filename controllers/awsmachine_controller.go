@@ -20,13 +20,17 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	ekscontrolplanev1 "sigs.k8s.io/cluster-api-provider-aws/controlplane/eks/api/v1alpha4"
@@ -36,6 +40,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/iam"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/secretsmanager"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ssm"
@@ -70,11 +75,42 @@ type AWSMachineReconciler struct {
 	SSMServiceFactory            func(cloud.ClusterScoper) services.SecretInterface
 	Endpoints                    []scope.ServiceEndpoint
 	WatchFilterValue             string
+
+	// AMILookupInterval is how long the AMI looked up to detect AMI updates is reused by the machines
+	// sharing the same lookup parameters. Defaults to 10 minutes, the default sync period.
+	AMILookupInterval time.Duration
+
+	// AllowedRoleAdditionalPolicies holds the ARNs of the managed policies machines may attach to the roles
+	// of their instance profiles, set from the --role-additional-policies-allow-list flag.
+	AllowedRoleAdditionalPolicies sets.String
+
+	// DryRun makes the AWS clients of all clusters log the mutating calls they would make instead of
+	// making them, set from the --dry-run flag.
+	DryRun bool
+
+	resolvedAMIs *cache.LRUExpireCache
+	startBackoff *flowcontrol.Backoff
 }
 
 const (
 	// AWSManagedControlPlaneRefKind is the string value indicating that a cluster is AWS managed.
 	AWSManagedControlPlaneRefKind = "AWSManagedControlPlane"
+
+	// defaultAMILookupInterval is the default for AMILookupInterval.
+	defaultAMILookupInterval = 10 * time.Minute
+
+	resolvedAMICacheSize = 1024
+
+	// startBackoffInitial and startBackoffMax bound how long the controller waits before starting a stopped
+	// instance again, so that an instance that keeps stopping, or can't be started, isn't started every reconcile.
+	startBackoffInitial = time.Minute
+	startBackoffMax     = 30 * time.Minute
+
+	// cloudWatchAgentServerPolicy is the managed policy granting the CloudWatch agent the permissions it needs.
+	cloudWatchAgentServerPolicy = "arn:aws:iam::aws:policy/CloudWatchAgentServerPolicy"
+
+	// defaultCloudWatchAgentMetricsCollectionInterval is how often, in seconds, the CloudWatch agent collects metrics by default.
+	defaultCloudWatchAgentMetricsCollectionInterval = 60
 )
 
 func (r *AWSMachineReconciler) getEC2Service(scope scope.EC2Scope) services.EC2MachineInterface {
@@ -82,7 +118,7 @@ func (r *AWSMachineReconciler) getEC2Service(scope scope.EC2Scope) services.EC2M
 		return r.ec2ServiceFactory(scope)
 	}
 
-	return ec2.NewService(scope)
+	return ec2.NewCachedService(scope)
 }
 
 func (r *AWSMachineReconciler) getSecretsManagerService(scope cloud.ClusterScoper) services.SecretInterface {
@@ -194,10 +230,10 @@ func (r *AWSMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return r.reconcileNormal(ctx, machineScope, infraScope, infraScope, nil)
 	case *scope.ClusterScope:
 		if !awsMachine.ObjectMeta.DeletionTimestamp.IsZero() {
-			return r.reconcileDelete(machineScope, infraScope, infraScope, infraScope)
+			return dryRunResult(r.reconcileDelete(machineScope, infraScope, infraScope, infraScope))
 		}
 
-		return r.reconcileNormal(ctx, machineScope, infraScope, infraScope, infraScope)
+		return dryRunResult(r.reconcileNormal(ctx, machineScope, infraScope, infraScope, infraScope))
 	default:
 		return ctrl.Result{}, errors.New("infraCluster has unknown type")
 	}
@@ -207,6 +243,12 @@ func (r *AWSMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 	log := ctrl.LoggerFrom(ctx)
 	AWSClusterToAWSMachines := r.AWSClusterToAWSMachines(log)
 
+	if r.AMILookupInterval == 0 {
+		r.AMILookupInterval = defaultAMILookupInterval
+	}
+	r.resolvedAMIs = cache.NewLRUExpireCache(resolvedAMICacheSize)
+	r.startBackoff = flowcontrol.NewBackOff(startBackoffInitial, startBackoffMax)
+
 	controller, err := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
 		For(&infrav1.AWSMachine{}).
@@ -421,6 +463,7 @@ func (r *AWSMachineReconciler) reconcileDelete(machineScope *scope.MachineScope,
 	}
 
 	// Instance is deleted so remove the finalizer.
+	r.startBackoff.DeleteEntry(instance.ID)
 	controllerutil.RemoveFinalizer(machineScope.AWSMachine, infrav1.MachineFinalizer)
 
 	return ctrl.Result{}, nil
@@ -542,6 +585,7 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		machineScope.Info("EC2 instance state changed", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
 	}
 
+	var result ctrl.Result
 	switch instance.State {
 	case infrav1.InstanceStatePending:
 		machineScope.SetNotReady()
@@ -549,13 +593,31 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 	case infrav1.InstanceStateStopping, infrav1.InstanceStateStopped:
 		machineScope.SetNotReady()
 		conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceStoppedReason, clusterv1.ConditionSeverityError, "")
+
+		if instance.State == infrav1.InstanceStateStopped {
+			switch machineScope.AWSMachine.Spec.StoppedInstancePolicy {
+			case infrav1.StoppedInstancePolicyRestart:
+				if result, err = r.restartStoppedInstance(ec2svc, machineScope, instance); err != nil {
+					return ctrl.Result{}, err
+				}
+			case infrav1.StoppedInstancePolicyReplace:
+				r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceStopped", "EC2 instance %q is stopped, failing the machine for it to be replaced", instance.ID)
+				machineScope.SetFailureReason(capierrors.UpdateMachineError)
+				machineScope.SetFailureMessage(errors.Errorf("EC2 instance %q is stopped", instance.ID))
+			}
+		}
 	case infrav1.InstanceStateRunning:
-		machineScope.SetReady()
-		conditions.MarkTrue(machineScope.AWSMachine, infrav1.InstanceReadyCondition)
+		if feature.Gates.Enabled(feature.InstanceStatusCheck) {
+			r.reconcileInstanceSystemStatus(ec2svc, machineScope, instance)
+		} else {
+			machineScope.SetReady()
+			conditions.MarkTrue(machineScope.AWSMachine, infrav1.InstanceReadyCondition)
+		}
 	case infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated:
 		machineScope.SetNotReady()
 		machineScope.Info("Unexpected EC2 instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
 		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceUnexpectedTermination", "Unexpected EC2 instance termination")
+		r.startBackoff.DeleteEntry(instance.ID)
 		conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceTerminatedReason, clusterv1.ConditionSeverityError, "")
 	default:
 		machineScope.SetNotReady()
@@ -566,6 +628,10 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 		conditions.MarkUnknown(machineScope.AWSMachine, infrav1.InstanceReadyCondition, "", "")
 	}
 
+	if instance.State == infrav1.InstanceStateRunning && feature.Gates.Enabled(feature.AMIUpdateDetection) {
+		r.reconcileAMIUpToDate(ec2svc, machineScope, instance)
+	}
+
 	// reconcile the deletion of the bootstrap data secret now that we have updated instance state
 	if deleteSecretErr := r.deleteEncryptedBootstrapDataSecret(machineScope, clusterScope); err != nil {
 		r.Log.Error(deleteSecretErr, "unable to delete secrets")
@@ -579,14 +645,17 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 
 	// tasks that can take place during all known instance states
 	if machineScope.InstanceIsInKnownState() {
-		_, err = r.ensureTags(ec2svc, machineScope.AWSMachine, machineScope.GetInstanceID(), machineScope.AdditionalTags())
+		desiredTags := ec2svc.DesiredInstanceTags(machineScope)
+		instanceTags, volumeTags := r.currentTags(ec2svc, machineScope, instance)
+
+		_, err = r.ensureTags(ec2svc, machineScope.AWSMachine, machineScope.GetInstanceID(), machineScope.AdditionalTags(), desiredTags, instanceTags)
 		if err != nil {
 			machineScope.Error(err, "failed to ensure tags")
 			return ctrl.Result{}, err
 		}
 
 		if instance != nil {
-			r.ensureStorageTags(ec2svc, instance, machineScope.AWSMachine)
+			r.ensureStorageTags(ec2svc, instance, machineScope.AWSMachine, desiredTags, volumeTags)
 		}
 
 		if err := r.reconcileLBAttachment(machineScope, elbScope, instance); err != nil {
@@ -613,8 +682,40 @@ func (r *AWSMachineReconciler) reconcileNormal(_ context.Context, machineScope *
 			return ctrl.Result{}, err
 		}
 		conditions.MarkTrue(machineScope.AWSMachine, infrav1.SecurityGroupsReadyCondition)
+
+		// Elastic IPs can only be associated with instances that have finished launching.
+		if machineScope.IsControlPlane() && ec2Scope.ControlPlaneElasticIPs() && instance.State == infrav1.InstanceStateRunning {
+			if err := ec2svc.AssociateControlPlaneElasticIP(instance); err != nil {
+				machineScope.Error(err, "failed to associate elastic IP")
+				return ctrl.Result{}, err
+			}
+		}
 	}
 
+	return result, nil
+}
+
+// restartStoppedInstance starts a stopped instance again, backing off between the attempts for the instance.
+// Stopped spot instances are left alone: EC2 starts them again once there is spot capacity. The attempts of an
+// instance are forgotten once it's terminated, or once it has stayed up for twice the maximum backoff.
+func (r *AWSMachineReconciler) restartStoppedInstance(ec2svc services.EC2MachineInterface, machineScope *scope.MachineScope, instance *infrav1.Instance) (ctrl.Result, error) {
+	if machineScope.AWSMachine.Spec.SpotMarketOptions != nil {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	if r.startBackoff.IsInBackOffSinceUpdate(instance.ID, now) {
+		machineScope.Info("Waiting before starting the stopped EC2 instance again", "instance-id", instance.ID)
+		return ctrl.Result{RequeueAfter: r.startBackoff.Get(instance.ID)}, nil
+	}
+	r.startBackoff.Next(instance.ID, now)
+	r.startBackoff.GC()
+
+	if err := ec2svc.StartInstance(instance.ID); err != nil {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "FailedStart", "Failed to start stopped EC2 instance: %v", err)
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "SuccessfulStart", "Started stopped EC2 instance %q", instance.ID)
 	return ctrl.Result{}, nil
 }
 
@@ -655,6 +756,114 @@ func (r *AWSMachineReconciler) deleteEncryptedBootstrapDataSecret(machineScope *
 	return nil
 }
 
+// reconcileInstanceSystemStatus marks a running instance ready unless it fails its EC2 system status checks,
+// e.g. after a hardware failure. A single impaired sample doesn't fail the machine: the checks can recover, and
+// replacing the machine is left to a MachineHealthCheck once its Node stays NotReady. When the status can't be
+// described, the instance keeps the readiness it was last given, and the status is described again on the
+// next reconcile.
+func (r *AWSMachineReconciler) reconcileInstanceSystemStatus(ec2svc services.EC2MachineInterface, machineScope *scope.MachineScope, instance *infrav1.Instance) {
+	wasImpaired := conditions.GetReason(machineScope.AWSMachine, infrav1.InstanceReadyCondition) == infrav1.InstanceSystemStatusImpairedReason
+
+	impaired, err := ec2svc.InstanceSystemStatusImpaired(instance.ID)
+	if err != nil {
+		machineScope.Error(err, "failed to get instance system status")
+		impaired = wasImpaired
+	}
+
+	if impaired {
+		if !wasImpaired {
+			machineScope.Info("EC2 instance is failing its system status checks", "instance-id", instance.ID)
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InstanceSystemStatusImpaired", "EC2 instance is failing its system status checks")
+		}
+		machineScope.SetNotReady()
+		conditions.MarkFalse(machineScope.AWSMachine, infrav1.InstanceReadyCondition, infrav1.InstanceSystemStatusImpairedReason, clusterv1.ConditionSeverityWarning, "EC2 instance %q is failing its system status checks", instance.ID)
+		return
+	}
+
+	if wasImpaired {
+		machineScope.Info("EC2 instance passes its system status checks again", "instance-id", instance.ID)
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "InstanceSystemStatusRecovered", "EC2 instance passes its system status checks again")
+	}
+	machineScope.SetReady()
+	conditions.MarkTrue(machineScope.AWSMachine, infrav1.InstanceReadyCondition)
+}
+
+// reconcileAMIUpToDate reports whether the instance runs the AMI new instances of the machine are launched with,
+// so that machines can be rolled onto newly published AMIs. AMIs pinned by ID never change, so they aren't checked.
+// Replacing the machines is left to the MachineDeployment they belong to.
+func (r *AWSMachineReconciler) reconcileAMIUpToDate(ec2svc services.EC2MachineInterface, machineScope *scope.MachineScope, instance *infrav1.Instance) {
+	if machineScope.AWSMachine.Spec.AMI.ID != nil {
+		return
+	}
+
+	imageID, err := r.resolveInstanceAMI(ec2svc, machineScope)
+	if err != nil {
+		// The running instance isn't affected, the AMI is looked up again on the next reconcile.
+		machineScope.Error(err, "failed to look up the AMI of new instances")
+		return
+	}
+
+	if imageID == instance.ImageID {
+		conditions.MarkTrue(machineScope.AWSMachine, infrav1.AMIUpToDateCondition)
+		return
+	}
+
+	if !conditions.IsFalse(machineScope.AWSMachine, infrav1.AMIUpToDateCondition) {
+		r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeNormal, "NewerAMIAvailable", "EC2 instance runs AMI %q, new instances are launched with AMI %q", instance.ImageID, imageID)
+	}
+	conditions.MarkFalse(machineScope.AWSMachine, infrav1.AMIUpToDateCondition, infrav1.NewerAMIAvailableReason, clusterv1.ConditionSeverityInfo,
+		"EC2 instance runs AMI %s, new instances are launched with AMI %s", instance.ImageID, imageID)
+}
+
+// resolveInstanceAMI looks up the AMI new instances of the machine are launched with. Published AMIs change
+// seldom, so the lookup is shared with the machines using the same lookup parameters for AMILookupInterval.
+func (r *AWSMachineReconciler) resolveInstanceAMI(ec2svc services.EC2MachineInterface, machineScope *scope.MachineScope) (string, error) {
+	if r.resolvedAMIs == nil {
+		return ec2svc.DiscoverInstanceAMI(machineScope)
+	}
+
+	key := amiLookupKey(machineScope)
+	if imageID, ok := r.resolvedAMIs.Get(key); ok {
+		return imageID.(string), nil
+	}
+
+	imageID, err := ec2svc.DiscoverInstanceAMI(machineScope)
+	if err != nil {
+		return "", err
+	}
+
+	r.resolvedAMIs.Add(key, imageID, r.AMILookupInterval)
+	return imageID, nil
+}
+
+// amiLookupKey returns the parameters the AMI of new instances of the machine is looked up with.
+func amiLookupKey(machineScope *scope.MachineScope) string {
+	spec := machineScope.AWSMachine.Spec
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s/%s/%s/%s/%s/%s/%s/%t/%s",
+		machineScope.Namespace(),
+		machineScope.InfraCluster.InfraClusterName(),
+		machineScope.InfraCluster.Region(),
+		aws.StringValue(spec.AMI.SSMParameterName),
+		spec.ImageLookupFormat,
+		spec.ImageLookupOrg,
+		spec.ImageLookupBaseOS,
+		machineScope.InfraCluster.ImageLookupFormat(),
+		machineScope.InfraCluster.ImageLookupOrg(),
+		machineScope.InfraCluster.ImageLookupBaseOS(),
+		eksAMILookupType(spec.AMI.EKSOptimizedLookupType),
+		spec.InstanceType,
+		machineScope.IsEKSManaged(),
+		aws.StringValue(machineScope.Machine.Spec.Version),
+	)
+}
+
+func eksAMILookupType(amiType *infrav1.EKSAMILookupType) string {
+	if amiType == nil {
+		return ""
+	}
+	return string(*amiType)
+}
+
 func (r *AWSMachineReconciler) createInstance(ec2svc services.EC2MachineInterface, machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper) (*infrav1.Instance, error) {
 	machineScope.Info("Creating EC2 instance")
 
@@ -663,6 +872,12 @@ func (r *AWSMachineReconciler) createInstance(ec2svc services.EC2MachineInterfac
 		return nil, errors.Wrapf(userDataErr, "failed to resolve userdata")
 	}
 
+	if feature.Gates.Enabled(feature.EC2EnableIAM) && machineScope.AWSMachine.Spec.IAMInstanceProfile != "" {
+		if err := r.reconcileInstanceProfile(machineScope, clusterScope); err != nil {
+			return nil, err
+		}
+	}
+
 	instance, err := ec2svc.CreateInstance(machineScope, userData)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create AWSMachine instance")
@@ -671,6 +886,36 @@ func (r *AWSMachineReconciler) createInstance(ec2svc services.EC2MachineInterfac
 	return instance, nil
 }
 
+// reconcileInstanceProfile creates the instance profile named by the AWSMachine if it doesn't exist,
+// granting it the same policies clusterawsadm grants to the control plane or nodes roles,
+// and attaches the additional policies requested by the AWSMachine to its role.
+func (r *AWSMachineReconciler) reconcileInstanceProfile(machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper) error {
+	policy := iam.NodeInstanceProfilePolicy(machineScope.SecureSecretsBackend())
+	if machineScope.IsControlPlane() {
+		policy = iam.ControlPlaneInstanceProfilePolicy(machineScope.SecureSecretsBackend())
+	}
+
+	// The webhook rejects policies that aren't allowed, but the allow-list may have shrunk since the machine was created.
+	var additionalPolicies []string
+	for _, policyARN := range machineScope.AWSMachine.Spec.RoleAdditionalPolicies {
+		if !r.AllowedRoleAdditionalPolicies.Has(policyARN) {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "SkippedAttachRolePolicy", "Not attaching policy %q, which is not allowed by the controller", policyARN)
+			continue
+		}
+		additionalPolicies = append(additionalPolicies, policyARN)
+	}
+	if machineScope.AWSMachine.Spec.CloudWatchAgent != nil {
+		additionalPolicies = append([]string{cloudWatchAgentServerPolicy}, additionalPolicies...)
+	}
+
+	iamSvc := iam.NewService(clusterScope)
+	if err := iamSvc.ReconcileInstanceProfile(machineScope.AWSMachine.Spec.IAMInstanceProfile, policy, additionalPolicies); err != nil {
+		return errors.Wrapf(err, "failed to reconcile instance profile %q", machineScope.AWSMachine.Spec.IAMInstanceProfile)
+	}
+
+	return nil
+}
+
 func (r *AWSMachineReconciler) resolveUserData(machineScope *scope.MachineScope, clusterScope cloud.ClusterScoper) ([]byte, error) {
 	userData, err := machineScope.GetRawBootstrapData()
 	if err != nil {
@@ -678,6 +923,32 @@ func (r *AWSMachineReconciler) resolveUserData(machineScope *scope.MachineScope,
 		return nil, err
 	}
 
+	// Ignition configs are passed to the instance as is, Ignition can't fetch them from a secrets backend.
+	if machineScope.UseIgnition() {
+		if err := userdata.ValidateIgnition(userData); err != nil {
+			r.Recorder.Eventf(machineScope.AWSMachine, corev1.EventTypeWarning, "InvalidBootstrapData", err.Error())
+			return nil, err
+		}
+		return userData, nil
+	}
+
+	if agent := machineScope.AWSMachine.Spec.CloudWatchAgent; agent != nil {
+		input := &userdata.CloudWatchAgentInput{
+			LogGroupName:              agent.LogGroupName,
+			MetricsCollectionInterval: agent.MetricsCollectionInterval,
+			Region:                    clusterScope.Region(),
+		}
+		if input.LogGroupName == "" {
+			input.LogGroupName = "/cluster-api/" + clusterScope.Name()
+		}
+		if input.MetricsCollectionInterval == 0 {
+			input.MetricsCollectionInterval = defaultCloudWatchAgentMetricsCollectionInterval
+		}
+		if userData, err = userdata.AddCloudWatchAgent(userData, input); err != nil {
+			return nil, errors.Wrap(err, "failed to add the CloudWatch agent to the bootstrap data")
+		}
+	}
+
 	if !machineScope.UseSecretsManager() {
 		return userData, nil
 	}
@@ -897,6 +1168,7 @@ func (r *AWSMachineReconciler) getInfraCluster(ctx context.Context, log logr.Log
 		Cluster:        cluster,
 		AWSCluster:     awsCluster,
 		ControllerName: "awsmachine",
+		DryRun:         isDryRun(r.DryRun, awsCluster),
 	})
 	if err != nil {
 		return nil, err
@@ -919,20 +1191,27 @@ func (r *AWSMachineReconciler) indexAWSMachineByInstanceID(o client.Object) []st
 	return nil
 }
 
-func (r *AWSMachineReconciler) ensureStorageTags(ec2svc services.EC2MachineInterface, instance *infrav1.Instance, machine *infrav1.AWSMachine) {
+// ensureStorageTags applies the additional tags of the machine to the volumes of the instance. The desired tags
+// removed or changed out-of-band on the volumes the instance was launched with, found in current, are restored.
+func (r *AWSMachineReconciler) ensureStorageTags(ec2svc services.EC2MachineInterface, instance *infrav1.Instance, machine *infrav1.AWSMachine, desired infrav1.Tags, current map[string]infrav1.Tags) {
 	annotations, err := r.machineAnnotationJSON(machine, VolumeTagsLastAppliedAnnotation)
 	if err != nil {
 		r.Log.Error(err, "Failed to fetch the annotations for volume tags")
 	}
 	for _, volumeID := range instance.VolumeIDs {
+		var drifted infrav1.Tags
+		if tags, ok := current[volumeID]; ok {
+			drifted = desired.Difference(tags)
+		}
+
 		if subAnnotation, ok := annotations[volumeID].(map[string]interface{}); ok {
-			newAnnotation, err := r.ensureVolumeTags(ec2svc, aws.String(volumeID), subAnnotation, machine.Spec.AdditionalTags)
+			newAnnotation, err := r.ensureVolumeTags(ec2svc, aws.String(volumeID), subAnnotation, machine.Spec.AdditionalTags, drifted)
 			if err != nil {
 				r.Log.Error(err, "Failed to fetch the changed volume tags in EC2 instance")
 			}
 			annotations[volumeID] = newAnnotation
 		} else {
-			newAnnotation, err := r.ensureVolumeTags(ec2svc, aws.String(volumeID), make(map[string]interface{}), machine.Spec.AdditionalTags)
+			newAnnotation, err := r.ensureVolumeTags(ec2svc, aws.String(volumeID), make(map[string]interface{}), machine.Spec.AdditionalTags, drifted)
 			if err != nil {
 				r.Log.Error(err, "Failed to fetch the changed volume tags in EC2 instance")
 			}
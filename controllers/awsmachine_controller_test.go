@@ -18,9 +18,12 @@ package controllers
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
@@ -32,7 +35,9 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	"k8s.io/utils/pointer"
@@ -143,6 +148,9 @@ func TestAWSMachineReconciler(t *testing.T) {
 		ec2Svc = mock_services.NewMockEC2MachineInterface(mockCtrl)
 		secretSvc = mock_services.NewMockSecretInterface(mockCtrl)
 
+		ec2Svc.EXPECT().DesiredInstanceTags(gomock.Any()).Return(nil).AnyTimes()
+		ec2Svc.EXPECT().GetLaunchResourceTags(gomock.Any(), gomock.Any()).Return(nil, nil, nil).AnyTimes()
+
 		// If your test hangs for 9 minutes, increase the value here to the number of events during a reconciliation loop
 		recorder = record.NewFakeRecorder(2)
 
@@ -153,8 +161,9 @@ func TestAWSMachineReconciler(t *testing.T) {
 			secretsManagerServiceFactory: func(cloud.ClusterScoper) services.SecretInterface {
 				return secretSvc
 			},
-			Recorder: recorder,
-			Log:      klogr.New(),
+			Recorder:     recorder,
+			Log:          klogr.New(),
+			startBackoff: flowcontrol.NewBackOff(startBackoffInitial, startBackoffMax),
 		}
 	}
 	teardown := func(t *testing.T, g *WithT) {
@@ -401,6 +410,7 @@ func TestAWSMachineReconciler(t *testing.T) {
 
 					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs)
 					expectConditions(g, ms.AWSMachine, []conditionAssertion{{conditionType: infrav1.SecurityGroupsReadyCondition, status: corev1.ConditionTrue}})
+					g.Eventually(recorder.Events).Should(Receive(ContainSubstring("SuccessfulUpdateSecurityGroups")))
 				})
 
 				t.Run("should not tag anything if there's not tags", func(t *testing.T) {
@@ -515,6 +525,100 @@ func TestAWSMachineReconciler(t *testing.T) {
 					expectConditions(g, ms.AWSMachine, []conditionAssertion{{infrav1.InstanceReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityError, infrav1.InstanceStoppedReason}})
 				})
 
+				t.Run("should start a stopped instance with the Restart policy", func(t *testing.T) {
+					g := NewWithT(t)
+					awsMachine := getAWSMachine()
+					awsMachine.Spec.StoppedInstancePolicy = infrav1.StoppedInstancePolicyRestart
+					setup(awsMachine, t, g)
+					defer teardown(t, g)
+					instanceCreate(t, g)
+					getCoreSecurityGroups(t, g)
+
+					instance.State = infrav1.InstanceStateStopped
+					ec2Svc.EXPECT().StartInstance("myMachine").Return(nil)
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs)
+					g.Expect(err).To(BeNil())
+					g.Expect(ms.AWSMachine.Status.Ready).To(Equal(false))
+					g.Expect(ms.AWSMachine.Status.FailureReason).To(BeNil())
+					g.Eventually(recorder.Events).Should(Receive(ContainSubstring("SuccessfulStart")))
+					expectConditions(g, ms.AWSMachine, []conditionAssertion{{infrav1.InstanceReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityError, infrav1.InstanceStoppedReason}})
+				})
+
+				t.Run("should return an error when a stopped instance can't be started with the Restart policy", func(t *testing.T) {
+					g := NewWithT(t)
+					awsMachine := getAWSMachine()
+					awsMachine.Spec.StoppedInstancePolicy = infrav1.StoppedInstancePolicyRestart
+					setup(awsMachine, t, g)
+					defer teardown(t, g)
+					instanceCreate(t, g)
+
+					instance.State = infrav1.InstanceStateStopped
+					expectedErr := errors.New("insufficient capacity")
+					secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+					ec2Svc.EXPECT().StartInstance("myMachine").Return(expectedErr)
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs)
+					g.Expect(errors.Cause(err)).To(MatchError(expectedErr))
+					g.Expect(ms.AWSMachine.Status.Ready).To(Equal(false))
+					g.Expect(ms.AWSMachine.Status.FailureReason).To(BeNil())
+					g.Eventually(recorder.Events).Should(Receive(ContainSubstring("FailedStart")))
+					expectConditions(g, ms.AWSMachine, []conditionAssertion{{infrav1.InstanceReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityError, infrav1.InstanceStoppedReason}})
+				})
+
+				t.Run("should back off from starting a stopped instance again with the Restart policy", func(t *testing.T) {
+					g := NewWithT(t)
+					awsMachine := getAWSMachine()
+					awsMachine.Spec.StoppedInstancePolicy = infrav1.StoppedInstancePolicyRestart
+					setup(awsMachine, t, g)
+					defer teardown(t, g)
+
+					instance := &infrav1.Instance{ID: "myMachine", State: infrav1.InstanceStateStopped}
+					ec2Svc.EXPECT().StartInstance("myMachine").Return(nil).Times(1)
+					result, err := reconciler.restartStoppedInstance(ec2Svc, ms, instance)
+					g.Expect(err).To(BeNil())
+					g.Expect(result.IsZero()).To(BeTrue())
+					g.Eventually(recorder.Events).Should(Receive(ContainSubstring("SuccessfulStart")))
+
+					// The instance stopped again right away.
+					result, err = reconciler.restartStoppedInstance(ec2Svc, ms, instance)
+					g.Expect(err).To(BeNil())
+					g.Expect(result.RequeueAfter).To(Equal(startBackoffInitial))
+				})
+
+				t.Run("should leave a stopped spot instance alone with the Restart policy", func(t *testing.T) {
+					g := NewWithT(t)
+					awsMachine := getAWSMachine()
+					awsMachine.Spec.StoppedInstancePolicy = infrav1.StoppedInstancePolicyRestart
+					awsMachine.Spec.SpotMarketOptions = &infrav1.SpotMarketOptions{}
+					setup(awsMachine, t, g)
+					defer teardown(t, g)
+					instanceCreate(t, g)
+					getCoreSecurityGroups(t, g)
+
+					instance.State = infrav1.InstanceStateStopped
+					ec2Svc.EXPECT().StartInstance(gomock.Any()).Times(0)
+					_, err := reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs)
+					g.Expect(err).To(BeNil())
+					g.Expect(ms.AWSMachine.Status.Ready).To(Equal(false))
+				})
+
+				t.Run("should fail the machine of a stopped instance with the Replace policy", func(t *testing.T) {
+					g := NewWithT(t)
+					awsMachine := getAWSMachine()
+					awsMachine.Spec.StoppedInstancePolicy = infrav1.StoppedInstancePolicyReplace
+					setup(awsMachine, t, g)
+					defer teardown(t, g)
+					instanceCreate(t, g)
+					getCoreSecurityGroups(t, g)
+
+					instance.State = infrav1.InstanceStateStopped
+					ec2Svc.EXPECT().StartInstance(gomock.Any()).Times(0)
+					_, _ = reconciler.reconcileNormal(context.Background(), ms, cs, cs, cs)
+					g.Expect(ms.AWSMachine.Status.Ready).To(Equal(false))
+					g.Expect(ms.AWSMachine.Status.FailureReason).To(PointTo(Equal(capierrors.UpdateMachineError)))
+					g.Expect(ms.AWSMachine.Status.FailureMessage).To(PointTo(Equal("EC2 instance \"myMachine\" is stopped")))
+					g.Eventually(recorder.Events).Should(Receive(ContainSubstring("InstanceStopped")))
+				})
+
 				t.Run("should then set instance to running and ready once it is restarted", func(t *testing.T) {
 					g := NewWithT(t)
 					awsMachine := getAWSMachine()
@@ -795,6 +899,292 @@ func TestAWSMachineReconciler(t *testing.T) {
 		})
 	})
 
+	t.Run("Checking the instance system status", func(t *testing.T) {
+		instance := &infrav1.Instance{
+			ID:    "myMachine",
+			State: infrav1.InstanceStateRunning,
+		}
+
+		t.Run("should mark the machine ready when the system status checks pass", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			ec2Svc.EXPECT().InstanceSystemStatusImpaired("myMachine").Return(false, nil)
+
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(ms.AWSMachine.Status.Ready).To(Equal(true))
+			g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.InstanceReadyCondition)).To(BeTrue())
+			g.Expect(recorder.Events).NotTo(Receive())
+		})
+
+		t.Run("should mark the machine not ready without failing it when the instance is impaired", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			ec2Svc.EXPECT().InstanceSystemStatusImpaired("myMachine").Return(true, nil).Times(2)
+
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(ms.AWSMachine.Status.Ready).To(Equal(false))
+			g.Expect(ms.AWSMachine.Status.FailureReason).To(BeNil())
+			g.Expect(ms.AWSMachine.Status.FailureMessage).To(BeNil())
+			expectConditions(g, ms.AWSMachine, []conditionAssertion{{infrav1.InstanceReadyCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityWarning, infrav1.InstanceSystemStatusImpairedReason}})
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("InstanceSystemStatusImpaired")))
+
+			// The event is only recorded when the instance becomes impaired.
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(recorder.Events).NotTo(Receive())
+		})
+
+		t.Run("should mark the machine ready again when the instance recovers", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			gomock.InOrder(
+				ec2Svc.EXPECT().InstanceSystemStatusImpaired("myMachine").Return(true, nil),
+				ec2Svc.EXPECT().InstanceSystemStatusImpaired("myMachine").Return(false, nil),
+			)
+
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("InstanceSystemStatusImpaired")))
+
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(ms.AWSMachine.Status.Ready).To(Equal(true))
+			g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.InstanceReadyCondition)).To(BeTrue())
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("InstanceSystemStatusRecovered")))
+		})
+
+		t.Run("should keep the readiness of the machine when the system status can't be described", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			gomock.InOrder(
+				ec2Svc.EXPECT().InstanceSystemStatusImpaired("myMachine").Return(false, errors.New("throttled")),
+				ec2Svc.EXPECT().InstanceSystemStatusImpaired("myMachine").Return(true, nil),
+				ec2Svc.EXPECT().InstanceSystemStatusImpaired("myMachine").Return(false, errors.New("throttled")),
+			)
+
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(ms.AWSMachine.Status.Ready).To(Equal(true))
+
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("InstanceSystemStatusImpaired")))
+
+			reconciler.reconcileInstanceSystemStatus(ec2Svc, ms, instance)
+			g.Expect(ms.AWSMachine.Status.Ready).To(Equal(false))
+			g.Expect(conditions.GetReason(ms.AWSMachine, infrav1.InstanceReadyCondition)).To(Equal(infrav1.InstanceSystemStatusImpairedReason))
+			g.Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+
+	t.Run("Restoring tags changed out-of-band", func(t *testing.T) {
+		desired := infrav1.Tags{
+			"Name": "test",
+			"sigs.k8s.io/cluster-api-provider-aws/role": "node",
+			"team": "platform",
+		}
+
+		// withTags returns the desired tags with the given tags changed.
+		withTags := func(changed infrav1.Tags) infrav1.Tags {
+			tags := desired.DeepCopy()
+			for k, v := range changed {
+				tags[k] = v
+			}
+			return tags
+		}
+
+		t.Run("should restore the tags on the instance and its primary network interface", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			g.Expect(reconciler.updateMachineAnnotationJSON(ms.AWSMachine, TagsLastAppliedAnnotation, map[string]interface{}{"team": "platform"})).To(Succeed())
+
+			ec2Svc.EXPECT().UpdateResourceTags(PointsTo("myMachine"), map[string]string{"Name": "test"}, map[string]string{}).Return(nil)
+			ec2Svc.EXPECT().UpdateResourceTags(PointsTo("eni-primary"), map[string]string{"team": "platform"}, map[string]string{}).Return(nil)
+
+			current := map[string]infrav1.Tags{
+				"myMachine":   withTags(infrav1.Tags{"Name": "renamed", "unmanaged": "kept"}),
+				"eni-primary": withTags(infrav1.Tags{"team": "infra"}),
+			}
+			changed, err := reconciler.ensureTags(ec2Svc, ms.AWSMachine, pointer.StringPtr("myMachine"), map[string]string{"team": "platform"}, desired, current)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(changed).To(BeTrue())
+		})
+
+		t.Run("should remove the additional tags removed from the machine from its primary network interface", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			g.Expect(reconciler.updateMachineAnnotationJSON(ms.AWSMachine, TagsLastAppliedAnnotation, map[string]interface{}{"team": "platform", "cost-center": "42"})).To(Succeed())
+
+			ec2Svc.EXPECT().UpdateResourceTags(PointsTo("myMachine"), map[string]string{}, map[string]string{"cost-center": "42"}).Return(nil)
+			ec2Svc.EXPECT().UpdateResourceTags(PointsTo("eni-primary"), map[string]string{}, map[string]string{"cost-center": "42"}).Return(nil)
+
+			current := map[string]infrav1.Tags{
+				"myMachine":   withTags(infrav1.Tags{"cost-center": "42"}),
+				"eni-primary": withTags(infrav1.Tags{"cost-center": "42"}),
+			}
+			_, err := reconciler.ensureTags(ec2Svc, ms.AWSMachine, pointer.StringPtr("myMachine"), map[string]string{"team": "platform"}, desired, current)
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+
+		t.Run("should restore the tags on the volumes the instance was launched with only", func(t *testing.T) {
+			g := NewWithT(t)
+			awsMachine := getAWSMachine()
+			awsMachine.Spec.AdditionalTags = infrav1.Tags{"team": "platform"}
+			setup(awsMachine, t, g)
+			defer teardown(t, g)
+
+			g.Expect(reconciler.updateMachineAnnotationJSON(ms.AWSMachine, VolumeTagsLastAppliedAnnotation, map[string]interface{}{
+				"vol-root": map[string]interface{}{"team": "platform"},
+				"vol-pv":   map[string]interface{}{"team": "platform"},
+			})).To(Succeed())
+
+			ec2Svc.EXPECT().UpdateResourceTags(PointsTo("vol-root"), map[string]string{"team": "platform"}, map[string]string{}).Return(nil)
+
+			instance := &infrav1.Instance{ID: "myMachine", VolumeIDs: []string{"vol-root", "vol-pv"}}
+			current := map[string]infrav1.Tags{
+				"vol-root": withTags(infrav1.Tags{"team": "infra"}),
+			}
+			reconciler.ensureStorageTags(ec2Svc, instance, ms.AWSMachine, desired, current)
+		})
+	})
+
+	t.Run("Reporting AMI updates", func(t *testing.T) {
+		instance := &infrav1.Instance{
+			ID:      "myMachine",
+			State:   infrav1.InstanceStateRunning,
+			ImageID: "ami-old",
+		}
+
+		t.Run("should not look up an AMI pinned by ID", func(t *testing.T) {
+			g := NewWithT(t)
+			awsMachine := getAWSMachine()
+			awsMachine.Spec.AMI.ID = pointer.StringPtr("ami-old")
+			setup(awsMachine, t, g)
+			defer teardown(t, g)
+
+			reconciler.reconcileAMIUpToDate(ec2Svc, ms, instance)
+			g.Expect(conditions.Get(ms.AWSMachine, infrav1.AMIUpToDateCondition)).To(BeNil())
+		})
+
+		t.Run("should mark the AMI up to date when new instances use the same AMI", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			ec2Svc.EXPECT().DiscoverInstanceAMI(gomock.Any()).Return("ami-old", nil)
+
+			reconciler.reconcileAMIUpToDate(ec2Svc, ms, instance)
+			g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.AMIUpToDateCondition)).To(BeTrue())
+		})
+
+		t.Run("should report a newer AMI", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			ec2Svc.EXPECT().DiscoverInstanceAMI(gomock.Any()).Return("ami-new", nil).Times(2)
+
+			reconciler.reconcileAMIUpToDate(ec2Svc, ms, instance)
+			expectConditions(g, ms.AWSMachine, []conditionAssertion{{infrav1.AMIUpToDateCondition, corev1.ConditionFalse, clusterv1.ConditionSeverityInfo, infrav1.NewerAMIAvailableReason}})
+			g.Expect(conditions.GetMessage(ms.AWSMachine, infrav1.AMIUpToDateCondition)).To(ContainSubstring("ami-new"))
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("NewerAMIAvailable")))
+
+			// The event is only recorded once.
+			reconciler.reconcileAMIUpToDate(ec2Svc, ms, instance)
+			g.Expect(recorder.Events).NotTo(Receive())
+		})
+
+		t.Run("should reuse the AMI looked up for the same lookup parameters", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			reconciler.AMILookupInterval = time.Minute
+			reconciler.resolvedAMIs = cache.NewLRUExpireCache(10)
+			ec2Svc.EXPECT().DiscoverInstanceAMI(gomock.Any()).Return("ami-new", nil).Times(1)
+
+			reconciler.reconcileAMIUpToDate(ec2Svc, ms, instance)
+			reconciler.reconcileAMIUpToDate(ec2Svc, ms, instance)
+			g.Expect(conditions.IsFalse(ms.AWSMachine, infrav1.AMIUpToDateCondition)).To(BeTrue())
+		})
+
+		t.Run("should leave the condition alone when the AMI lookup fails", func(t *testing.T) {
+			g := NewWithT(t)
+			setup(getAWSMachine(), t, g)
+			defer teardown(t, g)
+
+			conditions.MarkTrue(ms.AWSMachine, infrav1.AMIUpToDateCondition)
+			ec2Svc.EXPECT().DiscoverInstanceAMI(gomock.Any()).Return("", errors.New("throttled"))
+
+			reconciler.reconcileAMIUpToDate(ec2Svc, ms, instance)
+			g.Expect(conditions.IsTrue(ms.AWSMachine, infrav1.AMIUpToDateCondition)).To(BeTrue())
+		})
+	})
+
+	t.Run("Resolving the user data", func(t *testing.T) {
+		t.Run("should pass the bootstrap data as is without the CloudWatch agent", func(t *testing.T) {
+			g := NewWithT(t)
+			awsMachine := getAWSMachine()
+			awsMachine.Spec.CloudInit = infrav1.CloudInit{InsecureSkipSecretsManager: true}
+			setup(awsMachine, t, g)
+			defer teardown(t, g)
+
+			userData, err := reconciler.resolveUserData(ms, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(string(userData)).To(Equal("shell-script"))
+		})
+
+		t.Run("should add the CloudWatch agent script to the bootstrap data", func(t *testing.T) {
+			g := NewWithT(t)
+			awsMachine := getAWSMachine()
+			awsMachine.Spec.CloudInit = infrav1.CloudInit{InsecureSkipSecretsManager: true}
+			awsMachine.Spec.CloudWatchAgent = &infrav1.CloudWatchAgent{LogGroupName: "/cluster-api/test"}
+			setup(awsMachine, t, g)
+			defer teardown(t, g)
+			cs.AWSCluster.Spec.Region = "eu-west-2"
+
+			userData, err := reconciler.resolveUserData(ms, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(string(userData)).To(ContainSubstring("Content-Type: multipart/mixed"))
+			g.Expect(string(userData)).To(ContainSubstring("shell-script"))
+			g.Expect(string(userData)).To(ContainSubstring("CLOUDWATCH_AGENT_URL=https://amazoncloudwatch-agent-eu-west-2.s3.eu-west-2.amazonaws.com"))
+			g.Expect(string(userData)).To(ContainSubstring(`"log_group_name": "/cluster-api/test"`))
+			g.Expect(string(userData)).To(ContainSubstring(`"metrics_collection_interval": 60`))
+		})
+
+		t.Run("should store the bootstrap data with the CloudWatch agent in the secrets backend", func(t *testing.T) {
+			g := NewWithT(t)
+			awsMachine := getAWSMachine()
+			awsMachine.Spec.CloudWatchAgent = &infrav1.CloudWatchAgent{}
+			setup(awsMachine, t, g)
+			defer teardown(t, g)
+
+			var stored []byte
+			secretSvc.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *scope.MachineScope, data []byte) (string, int32, error) {
+				stored = data
+				return "test/secret", int32(1), nil
+			}).Times(1)
+			secretSvc.EXPECT().UserData(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("fetch-secret"), nil).Times(1)
+
+			userData, err := reconciler.resolveUserData(ms, cs)
+			g.Expect(err).To(BeNil())
+			g.Expect(string(userData)).To(Equal("fetch-secret"))
+
+			gz, err := gzip.NewReader(bytes.NewReader(stored))
+			g.Expect(err).To(BeNil())
+			decompressed, err := ioutil.ReadAll(gz)
+			g.Expect(err).To(BeNil())
+			g.Expect(string(decompressed)).To(ContainSubstring("amazon-cloudwatch-agent-ctl"))
+		})
+	})
+
 	t.Run("Deleting an AWSMachine", func(t *testing.T) {
 		finalizer := func(t *testing.T, g *WithT) {
 			ms.AWSMachine.Finalizers = []string{
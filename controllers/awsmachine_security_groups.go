@@ -19,9 +19,11 @@ package controllers
 import (
 	"sort"
 
+	corev1 "k8s.io/api/core/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	service "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2"
 )
 
 const (
@@ -49,7 +51,7 @@ func (r *AWSMachineReconciler) ensureSecurityGroups(ec2svc service.EC2MachineInt
 		return false, err
 	}
 
-	additionalSecurityGroupsIDs, err := r.getAdditionalSecurityGroupsIDs(ec2svc, additional)
+	additionalSecurityGroupsIDs, err := ec2.GetAdditionalSecurityGroupsIDs(ec2svc, additional)
 	if err != nil {
 		return false, nil // nolint:nilerr
 	}
@@ -60,8 +62,10 @@ func (r *AWSMachineReconciler) ensureSecurityGroups(ec2svc service.EC2MachineInt
 	}
 
 	if err := ec2svc.UpdateInstanceSecurityGroups(*scope.GetInstanceID(), ids); err != nil {
+		r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeWarning, "FailedUpdateSecurityGroups", "Failed to update security groups of instance %q: %v", *scope.GetInstanceID(), err)
 		return false, err
 	}
+	r.Recorder.Eventf(scope.AWSMachine, corev1.EventTypeNormal, "SuccessfulUpdateSecurityGroups", "Updated security groups of instance %q to %v", *scope.GetInstanceID(), ids)
 
 	// Build and store annotation.
 	newAnnotation := make(map[string]interface{}, len(additionalSecurityGroupsIDs))
@@ -121,24 +125,3 @@ func (r *AWSMachineReconciler) securityGroupsChanged(annotation map[string]inter
 
 	return false, res
 }
-
-func (r *AWSMachineReconciler) getAdditionalSecurityGroupsIDs(ec2svc service.EC2MachineInterface, securitygroups []infrav1.AWSResourceReference) ([]string, error) {
-	additionalSecurityGroupsIDs := []string{}
-
-	for _, sg := range securitygroups {
-		if sg.ID != nil {
-			additionalSecurityGroupsIDs = append(additionalSecurityGroupsIDs, *sg.ID)
-		}
-
-		if sg.Filters != nil {
-			id, err := ec2svc.GetFilteredSecurityGroupID(sg)
-			if err != nil {
-				return nil, err
-			}
-
-			additionalSecurityGroupsIDs = append(additionalSecurityGroupsIDs, id)
-		}
-	}
-
-	return additionalSecurityGroupsIDs, nil
-}
@@ -17,7 +17,10 @@ limitations under the License.
 package controllers
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
+
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	service "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
 )
 
@@ -39,7 +42,10 @@ const (
 // Returns bool, error
 // Bool indicates if changes were made or not, allowing the caller to decide
 // if the machine should be updated.
-func (r *AWSMachineReconciler) ensureTags(svc service.EC2MachineInterface, machine *infrav1.AWSMachine, instanceID *string, additionalTags map[string]string) (bool, error) {
+// The desired tags removed or changed out-of-band on the instance, or on its
+// primary network interface, are restored. current holds the tags found on
+// them by resource ID, and none are restored on the resources missing from it.
+func (r *AWSMachineReconciler) ensureTags(svc service.EC2MachineInterface, machine *infrav1.AWSMachine, instanceID *string, additionalTags map[string]string, desired infrav1.Tags, current map[string]infrav1.Tags) (bool, error) {
 	annotation, err := r.machineAnnotationJSON(machine, TagsLastAppliedAnnotation)
 	if err != nil {
 		return false, err
@@ -50,12 +56,40 @@ func (r *AWSMachineReconciler) ensureTags(svc service.EC2MachineInterface, machi
 	// moment we send everything, even if only a single tag was created or
 	// updated.
 	changed, created, deleted, newAnnotation := r.tagsChanged(annotation, additionalTags)
-	if changed {
+
+	restored := false
+	for resourceID, tags := range current {
+		drifted := desired.Difference(tags)
+		if len(drifted) > 0 {
+			r.Log.V(2).Info("Restoring drifted tags", "resource-id", resourceID, "tags", drifted)
+			restored = true
+		}
+
+		if resourceID == aws.StringValue(instanceID) {
+			for k, v := range drifted {
+				created[k] = v
+			}
+			continue
+		}
+
+		// The network interface only gets the tags of the machine by being
+		// restored, the additional tags removed from the machine are removed
+		// from it as well.
+		if len(drifted) > 0 || len(deleted) > 0 {
+			if err := svc.UpdateResourceTags(aws.String(resourceID), drifted, deleted); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if changed || len(created) > 0 {
 		err = svc.UpdateResourceTags(instanceID, created, deleted)
 		if err != nil {
 			return false, err
 		}
+	}
 
+	if changed {
 		// We also need to update the annotation if anything changed.
 		err = r.updateMachineAnnotationJSON(machine, TagsLastAppliedAnnotation, newAnnotation)
 		if err != nil {
@@ -63,18 +97,22 @@ func (r *AWSMachineReconciler) ensureTags(svc service.EC2MachineInterface, machi
 		}
 	}
 
-	return changed, nil
+	return changed || restored, nil
 }
 
 // Ensure that the tags of the volumes in the machine are correct
 // Returns tags which are being created/updated/deleted and error.
-func (r *AWSMachineReconciler) ensureVolumeTags(svc service.EC2MachineInterface, volumeID *string, annotation map[string]interface{}, additionalTags map[string]string) (map[string]interface{}, error) {
+// The drifted tags, removed or changed out-of-band, are restored as well.
+func (r *AWSMachineReconciler) ensureVolumeTags(svc service.EC2MachineInterface, volumeID *string, annotation map[string]interface{}, additionalTags map[string]string, drifted infrav1.Tags) (map[string]interface{}, error) {
 	// Check if the volume tags were changed. If they were, update them.
 	// It would be possible here to only send new/updated tags, but for the
 	// moment we send everything, even if only a single tag was created or
 	// updated.
 	changed, created, deleted, subAnnotation := r.tagsChanged(annotation, additionalTags)
-	if changed {
+	for k, v := range drifted {
+		created[k] = v
+	}
+	if changed || len(drifted) > 0 {
 		err := svc.UpdateResourceTags(volumeID, created, deleted)
 		if err != nil {
 			return nil, err
@@ -84,6 +122,29 @@ func (r *AWSMachineReconciler) ensureVolumeTags(svc service.EC2MachineInterface,
 	return subAnnotation, nil
 }
 
+// currentTags returns the tags found on the instance and on its primary
+// network interface, and those found on the volumes it was launched with, by
+// resource ID, for their drift to be restored. Failing to look up those of
+// the network interface and volumes is only logged, they're checked again on
+// the next reconcile.
+func (r *AWSMachineReconciler) currentTags(svc service.EC2MachineInterface, machineScope *scope.MachineScope, instance *infrav1.Instance) (map[string]infrav1.Tags, map[string]infrav1.Tags) {
+	if instance == nil {
+		return nil, nil
+	}
+
+	volumeTags, networkInterfaceTags, err := svc.GetLaunchResourceTags(instance.ID, machineScope.AWSMachine.Spec.NonRootVolumes)
+	if err != nil {
+		machineScope.Error(err, "failed to get the tags of the launch volumes and network interface")
+	}
+
+	instanceTags := map[string]infrav1.Tags{instance.ID: instance.Tags}
+	for id, tags := range networkInterfaceTags {
+		instanceTags[id] = tags
+	}
+
+	return instanceTags, volumeTags
+}
+
 // tagsChanged determines which tags to delete and which to add.
 func (r *AWSMachineReconciler) tagsChanged(annotation map[string]interface{}, src map[string]string) (bool, map[string]string, map[string]string, map[string]interface{}) {
 	// Bool tracking if we found any changed state.
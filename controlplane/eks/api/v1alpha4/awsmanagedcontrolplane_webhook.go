@@ -89,6 +89,8 @@ func (r *AWSManagedControlPlane) ValidateCreate() error {
 
 	allErrs = append(allErrs, r.validateEKSVersion(nil)...)
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.ValidatePeeringConnections(r.Spec.SecondaryCidrBlock)...)
 	allErrs = append(allErrs, r.validateIAMAuthConfig()...)
 	allErrs = append(allErrs, r.validateSecondaryCIDR()...)
 	allErrs = append(allErrs, r.validateEKSAddons()...)
@@ -120,6 +122,8 @@ func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) error {
 	allErrs = append(allErrs, r.validateEKSClusterNameSame(oldAWSManagedControlplane)...)
 	allErrs = append(allErrs, r.validateEKSVersion(oldAWSManagedControlplane)...)
 	allErrs = append(allErrs, r.Spec.Bastion.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.Validate()...)
+	allErrs = append(allErrs, r.Spec.NetworkSpec.ValidatePeeringConnections(r.Spec.SecondaryCidrBlock)...)
 	allErrs = append(allErrs, r.validateIAMAuthConfig()...)
 	allErrs = append(allErrs, r.validateSecondaryCIDR()...)
 	allErrs = append(allErrs, r.validateEKSAddons()...)
@@ -145,6 +149,28 @@ func (r *AWSManagedControlPlane) ValidateUpdate(old runtime.Object) error {
 		)
 	}
 
+	// The IPv6 CIDR block is only associated when the VPC is created.
+	if oldAWSManagedControlplane.Spec.NetworkSpec.VPC.IsIPv6Enabled() != r.Spec.NetworkSpec.VPC.IsIPv6Enabled() {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "ipv6"), r.Spec.NetworkSpec.VPC.IPv6, "field is immutable"),
+		)
+	}
+
+	// Switching topologies would leave the NAT gateways of the old topology behind.
+	if oldAWSManagedControlplane.Spec.NetworkSpec.VPC.IsSingleNatGateway() != r.Spec.NetworkSpec.VPC.IsSingleNatGateway() {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "natGatewayTopology"), r.Spec.NetworkSpec.VPC.NatGatewayTopology, "field is immutable"),
+		)
+	}
+
+	// Removing the DHCP options would leave the VPC associated with the cluster's options set.
+	if oldAWSManagedControlplane.Spec.NetworkSpec.VPC.DHCPOptions != nil && r.Spec.NetworkSpec.VPC.DHCPOptions == nil {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "network", "vpc", "dhcpOptions"),
+				r.Spec.NetworkSpec.VPC.DHCPOptions, "field cannot be set to nil"),
+		)
+	}
+
 	// If a identityRef is already set, do not allow removal of it.
 	if oldAWSManagedControlplane.Spec.IdentityRef != nil && r.Spec.IdentityRef == nil {
 		allErrs = append(allErrs,
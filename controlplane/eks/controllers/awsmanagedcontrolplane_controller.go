@@ -237,7 +237,7 @@ func (r *AWSManagedControlPlaneReconciler) reconcileNormal(ctx context.Context,
 	}
 	conditions.MarkTrue(awsManagedControlPlane, controlplanev1.IAMAuthenticatorConfiguredCondition)
 
-	for _, subnet := range managedScope.Subnets().FilterPrivate() {
+	for _, subnet := range managedScope.Subnets().FilterPrimary().FilterPrivate() {
 		managedScope.SetFailureDomain(subnet.AvailabilityZone, clusterv1.FailureDomainSpec{
 			ControlPlane: true,
 		})
@@ -27,10 +27,12 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
+	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -50,10 +52,15 @@ type AwsInstanceStateReconciler struct {
 	queueURLs         sync.Map
 	Endpoints         []scope.ServiceEndpoint
 	WatchFilterValue  string
+
+	// HandleRebalanceRecommendations replaces the Machines of spot instances at elevated risk of interruption,
+	// rather than only the ones about to be interrupted.
+	HandleRebalanceRecommendations bool
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=awsmachines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch;delete
 
 func (r *AwsInstanceStateReconciler) getSQSService(region string) (sqsiface.SQSAPI, error) {
 	if r.sqsServiceFactory != nil {
@@ -166,9 +173,25 @@ func (r *AwsInstanceStateReconciler) watchQueuesForInstanceEvents() {
 	}
 }
 
-// processMessage triggers a reconcile on an AWSMachine if its EC2 instance state changed.
+// processMessage triggers a reconcile on an AWSMachine if its EC2 instance state changed,
+// and replaces the Machine of a spot instance that is about to be interrupted. Rebalance recommendations
+// only lead to a replacement if HandleRebalanceRecommendations is set, as the instance may well keep running.
 func (r *AwsInstanceStateReconciler) processMessage(ctx context.Context, msg message) {
-	if msg.Source != "aws.ec2" || msg.DetailType != instancestate.Ec2StateChangeNotification || msg.MessageDetail == nil {
+	if msg.Source != "aws.ec2" || msg.MessageDetail == nil {
+		return
+	}
+
+	switch msg.DetailType {
+	case instancestate.Ec2SpotInterruptionWarning:
+		r.replaceSpotMachine(ctx, msg)
+		return
+	case instancestate.Ec2RebalanceRecommendation:
+		if r.HandleRebalanceRecommendations {
+			r.replaceSpotMachine(ctx, msg)
+		}
+		return
+	case instancestate.Ec2StateChangeNotification:
+	default:
 		return
 	}
 
@@ -205,6 +228,40 @@ func (r *AwsInstanceStateReconciler) processMessage(ctx context.Context, msg mes
 	}
 }
 
+// replaceSpotMachine deletes the Machine of a spot instance that is about to be interrupted, so that Cluster API
+// drains its Node and the owning MachineSet creates a replacement before the instance is reclaimed.
+// Machines without a controller are left alone, as deleting them would remove capacity for good, and so are
+// control plane Machines, whose replacement is up to the control plane provider.
+func (r *AwsInstanceStateReconciler) replaceSpotMachine(ctx context.Context, msg message) {
+	awsMachines := &infrav1.AWSMachineList{}
+	if err := r.List(ctx, awsMachines, client.MatchingFields{controllers.InstanceIDIndex: msg.MessageDetail.InstanceID}); err != nil {
+		r.Log.Error(err, "unable to list machines by instance ID", "instanceID", msg.MessageDetail.InstanceID)
+		return
+	}
+	if len(awsMachines.Items) == 0 {
+		return
+	}
+
+	awsMachine := awsMachines.Items[0]
+	if !awsMachine.ObjectMeta.DeletionTimestamp.IsZero() {
+		return
+	}
+
+	machine, err := util.GetOwnerMachine(ctx, r.Client, awsMachine.ObjectMeta)
+	if err != nil {
+		r.Log.Error(err, "unable to get owner machine", "awsMachine", awsMachine.Name)
+		return
+	}
+	if machine == nil || !machine.ObjectMeta.DeletionTimestamp.IsZero() || util.IsControlPlaneMachine(machine) || metav1.GetControllerOf(machine) == nil {
+		return
+	}
+
+	r.Log.Info("Replacing machine of interrupted spot instance", "machine", machine.Name, "instanceID", msg.MessageDetail.InstanceID, "notification", msg.DetailType)
+	if err := r.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+		r.Log.Error(err, "unable to delete machine", "machine", machine.Name)
+	}
+}
+
 // getQueueURL retrieves the SQS queue URL for a given cluster.
 func (r *AwsInstanceStateReconciler) getQueueURL(cluster *infrav1.AWSCluster) (string, error) {
 	sqsSvs, err := r.getSQSService(cluster.Spec.Region)
@@ -28,12 +28,15 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/golang/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/controllers"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/instancestate/mock_sqsiface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -167,6 +170,142 @@ func TestAWSInstanceStateController(t *testing.T) {
 			return val == "shutting-down"
 		}, 10*time.Second).Should(Equal(true))
 	})
+
+	t.Run("should replace machines of spot instances about to be interrupted", func(t *testing.T) {
+		machineSetOwner := metav1.OwnerReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "MachineSet",
+			Name:       "machine-set",
+			UID:        "machine-set-uid",
+			Controller: pointer.BoolPtr(true),
+		}
+
+		testCases := []struct {
+			name                           string
+			detailType                     string
+			handleRebalanceRecommendations bool
+			controlPlane                   bool
+			owners                         []metav1.OwnerReference
+			expectDeleted                  bool
+		}{
+			{
+				name:          "worker machine is deleted on an interruption warning",
+				detailType:    instancestate.Ec2SpotInterruptionWarning,
+				owners:        []metav1.OwnerReference{machineSetOwner},
+				expectDeleted: true,
+			},
+			{
+				name:          "worker machine is kept on a rebalance recommendation by default",
+				detailType:    instancestate.Ec2RebalanceRecommendation,
+				owners:        []metav1.OwnerReference{machineSetOwner},
+				expectDeleted: false,
+			},
+			{
+				name:                           "worker machine is deleted on a rebalance recommendation if enabled",
+				detailType:                     instancestate.Ec2RebalanceRecommendation,
+				handleRebalanceRecommendations: true,
+				owners:                         []metav1.OwnerReference{machineSetOwner},
+				expectDeleted:                  true,
+			},
+			{
+				name:          "control plane machine is kept on an interruption warning",
+				detailType:    instancestate.Ec2SpotInterruptionWarning,
+				controlPlane:  true,
+				owners:        []metav1.OwnerReference{machineSetOwner},
+				expectDeleted: false,
+			},
+			{
+				name:          "machine without a controller is kept on an interruption warning",
+				detailType:    instancestate.Ec2SpotInterruptionWarning,
+				expectDeleted: false,
+			},
+		}
+
+		for i, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				g := NewWithT(t)
+				instanceID := fmt.Sprintf("i-spot-instance-%d", i)
+
+				machine := &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            fmt.Sprintf("spot-machine-%d", i),
+						Namespace:       "default",
+						Labels:          map[string]string{},
+						OwnerReferences: tc.owners,
+					},
+					Spec: clusterv1.MachineSpec{
+						ClusterName: "aws-cluster-1",
+					},
+				}
+				if tc.controlPlane {
+					machine.Labels[clusterv1.MachineControlPlaneLabelName] = ""
+				}
+				persistObject(g, machine)
+
+				awsMachine := &infrav1.AWSMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("spot-aws-machine-%d", i),
+						Namespace: "default",
+						OwnerReferences: []metav1.OwnerReference{{
+							APIVersion: clusterv1.GroupVersion.String(),
+							Kind:       "Machine",
+							Name:       machine.Name,
+							UID:        machine.UID,
+						}},
+					},
+					Spec: infrav1.AWSMachineSpec{
+						InstanceID: pointer.StringPtr(instanceID),
+					},
+				}
+				persistObject(g, awsMachine)
+
+				// Wait for the cache to index the AWSMachine before handling the message.
+				g.Eventually(func() int {
+					awsMachines := &infrav1.AWSMachineList{}
+					_ = k8sClient.List(context.TODO(), awsMachines, client.MatchingFields{controllers.InstanceIDIndex: instanceID})
+					return len(awsMachines.Items)
+				}, 10*time.Second).Should(Equal(1))
+
+				r := &AwsInstanceStateReconciler{
+					Client:                         k8sClient,
+					Log:                            ctrl.Log.WithName("controllers").WithName("AWSInstanceState"),
+					HandleRebalanceRecommendations: tc.handleRebalanceRecommendations,
+				}
+				r.processMessage(context.TODO(), message{
+					Source:        "aws.ec2",
+					DetailType:    tc.detailType,
+					MessageDetail: &messageDetail{InstanceID: instanceID},
+				})
+
+				key := types.NamespacedName{Namespace: machine.Namespace, Name: machine.Name}
+				if tc.expectDeleted {
+					g.Eventually(func() bool {
+						return apierrors.IsNotFound(k8sClient.Get(context.TODO(), key, &clusterv1.Machine{}))
+					}, 10*time.Second).Should(BeTrue())
+				} else {
+					g.Consistently(func() error {
+						return k8sClient.Get(context.TODO(), key, &clusterv1.Machine{})
+					}, 2*time.Second).Should(Succeed())
+				}
+			})
+		}
+	})
+
+	t.Run("should ignore messages about unknown instances", func(t *testing.T) {
+		r := &AwsInstanceStateReconciler{
+			Client:                         k8sClient,
+			Log:                            ctrl.Log.WithName("controllers").WithName("AWSInstanceState"),
+			HandleRebalanceRecommendations: true,
+		}
+		for _, detailType := range []string{instancestate.Ec2StateChangeNotification, instancestate.Ec2SpotInterruptionWarning, instancestate.Ec2RebalanceRecommendation} {
+			r.processMessage(context.TODO(), message{
+				Source:        "aws.ec2",
+				DetailType:    detailType,
+				MessageDetail: &messageDetail{InstanceID: "i-unknown", State: infrav1.InstanceStateShuttingDown},
+			})
+		}
+		r.processMessage(context.TODO(), message{Source: "aws.ec2", DetailType: instancestate.Ec2SpotInterruptionWarning})
+	})
 }
 
 const messageBodyJSON = `{
@@ -58,6 +58,27 @@ const (
 	// alpha: v0.7?
 	EventBridgeInstanceState featuregate.Feature = "EventBridgeInstanceState"
 
+	// SpotRebalanceRecommendation will replace the Machines of spot instances at elevated risk of interruption,
+	// in addition to the ones about to be interrupted. Requires EventBridgeInstanceState.
+	// owner: @geetikabatra
+	// alpha: v0.7
+	SpotRebalanceRecommendation featuregate.Feature = "SpotRebalanceRecommendation"
+
+	// EC2EnableIAM will create the IAM instance profiles named by AWSMachines if they don't exist
+	// owner: @geetikabatra
+	// alpha: v0.7
+	EC2EnableIAM featuregate.Feature = "EC2EnableIAM"
+
+	// AMIUpdateDetection will report the AWSMachines whose instance doesn't run the AMI new instances are launched with
+	// owner: @geetikabatra
+	// alpha: v0.7
+	AMIUpdateDetection featuregate.Feature = "AMIUpdateDetection"
+
+	// InstanceStatusCheck will mark the AWSMachines whose instance fails its EC2 system status checks not ready
+	// owner: @geetikabatra
+	// alpha: v0.7
+	InstanceStatusCheck featuregate.Feature = "InstanceStatusCheck"
+
 	// AutoControllerIdentityCreator will create AWSClusterControllerIdentity instance that allows all namespaces to use it.
 	// owner: @sedefsavas
 	// alpha: v0.6
@@ -77,6 +98,10 @@ var defaultCAPAFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	EKSAllowAddRoles:              {Default: false, PreRelease: featuregate.Beta},
 	EKSFargate:                    {Default: false, PreRelease: featuregate.Alpha},
 	EventBridgeInstanceState:      {Default: false, PreRelease: featuregate.Alpha},
+	SpotRebalanceRecommendation:   {Default: false, PreRelease: featuregate.Alpha},
+	EC2EnableIAM:                  {Default: false, PreRelease: featuregate.Alpha},
+	AMIUpdateDetection:            {Default: false, PreRelease: featuregate.Alpha},
+	InstanceStatusCheck:           {Default: false, PreRelease: featuregate.Alpha},
 	MachinePool:                   {Default: false, PreRelease: featuregate.Alpha},
 	AutoControllerIdentityCreator: {Default: true, PreRelease: featuregate.Alpha},
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	cgrecord "k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -98,6 +99,8 @@ var (
 	webhookCertDir           string
 	healthAddr               string
 	serviceEndpoints         string
+	roleAdditionalPolicies   []string
+	dryRun                   bool
 
 	errEKSInvalidFlags = errors.New("invalid EKS flag combination")
 )
@@ -156,6 +159,8 @@ func main() {
 
 	setupLog.V(1).Info(fmt.Sprintf("feature gates: %+v\n", feature.Gates))
 
+	allowedRoleAdditionalPolicies := sets.NewString(roleAdditionalPolicies...)
+
 	// Parse service endpoints.
 	AWSServiceEndpoints, err := endpoints.ParseFlag(serviceEndpoints)
 	if err != nil {
@@ -164,11 +169,14 @@ func main() {
 	}
 
 	if err = (&controllers.AWSMachineReconciler{
-		Client:           mgr.GetClient(),
-		Log:              ctrl.Log.WithName("controllers").WithName("AWSMachine"),
-		Recorder:         mgr.GetEventRecorderFor("awsmachine-controller"),
-		Endpoints:        AWSServiceEndpoints,
-		WatchFilterValue: watchFilterValue,
+		Client:                        mgr.GetClient(),
+		Log:                           ctrl.Log.WithName("controllers").WithName("AWSMachine"),
+		Recorder:                      mgr.GetEventRecorderFor("awsmachine-controller"),
+		Endpoints:                     AWSServiceEndpoints,
+		WatchFilterValue:              watchFilterValue,
+		AMILookupInterval:             syncPeriod,
+		AllowedRoleAdditionalPolicies: allowedRoleAdditionalPolicies,
+		DryRun:                        dryRun,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: awsMachineConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSMachine")
 		os.Exit(1)
@@ -178,6 +186,7 @@ func main() {
 		Recorder:         mgr.GetEventRecorderFor("awscluster-controller"),
 		Endpoints:        AWSServiceEndpoints,
 		WatchFilterValue: watchFilterValue,
+		DryRun:           dryRun,
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: awsClusterConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSCluster")
 		os.Exit(1)
@@ -224,6 +233,10 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AWSMachineList")
 		os.Exit(1)
 	}
+	if err = (&infrav1alpha4.RoleAdditionalPoliciesWebhook{Allowed: allowedRoleAdditionalPolicies}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "RoleAdditionalPolicies")
+		os.Exit(1)
+	}
 	if err = (&infrav1alpha4.AWSClusterControllerIdentityList{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AWSClusterControllerIdentityList")
 		os.Exit(1)
@@ -353,10 +366,11 @@ func enableGates(ctx context.Context, mgr ctrl.Manager, awsServiceEndpoints []sc
 	if feature.Gates.Enabled(feature.EventBridgeInstanceState) {
 		setupLog.Info("EventBridge notifications enabled. enabling AWSInstanceStateController")
 		if err := (&instancestate.AwsInstanceStateReconciler{
-			Client:           mgr.GetClient(),
-			Log:              ctrl.Log.WithName("controllers").WithName("AWSInstanceStateController"),
-			Endpoints:        awsServiceEndpoints,
-			WatchFilterValue: watchFilterValue,
+			Client:                         mgr.GetClient(),
+			Log:                            ctrl.Log.WithName("controllers").WithName("AWSInstanceStateController"),
+			Endpoints:                      awsServiceEndpoints,
+			WatchFilterValue:               watchFilterValue,
+			HandleRebalanceRecommendations: feature.Gates.Enabled(feature.SpotRebalanceRecommendation),
 		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: instanceStateConcurrency}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AWSInstanceStateController")
 			os.Exit(1)
@@ -463,5 +477,17 @@ func initFlags(fs *pflag.FlagSet) {
 		fmt.Sprintf("Label value that the controller watches to reconcile cluster-api objects. Label key is always %s. If unspecified, the controller watches for all cluster-api objects.", clusterv1.WatchLabel),
 	)
 
+	fs.StringSliceVar(&roleAdditionalPolicies,
+		"role-additional-policies-allow-list",
+		nil,
+		"Comma-separated ARNs of the managed policies AWSMachines may attach to the roles of their instance profiles with spec.roleAdditionalPolicies. No policy is allowed if unspecified.",
+	)
+
+	fs.BoolVar(&dryRun,
+		"dry-run",
+		false,
+		fmt.Sprintf("Log the mutating AWS API calls the AWSCluster and AWSMachine controllers would make, with their input, instead of making them. A single cluster can be put in dry-run mode with the %s annotation on its AWSCluster.", controllers.DryRunAnnotation),
+	)
+
 	feature.MutableGates.AddFlag(fs)
 }
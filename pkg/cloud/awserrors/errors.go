@@ -29,6 +29,7 @@ const (
 	InUseIPAddress             = "InvalidIPAddress.InUse"
 	GroupNotFound              = "InvalidGroup.NotFound"
 	PermissionNotFound         = "InvalidPermission.NotFound"
+	PermissionDuplicate        = "InvalidPermission.Duplicate"
 	VPCNotFound                = "InvalidVpcID.NotFound"
 	SubnetNotFound             = "InvalidSubnetID.NotFound"
 	InternetGatewayNotFound    = "InvalidInternetGatewayID.NotFound"
@@ -42,8 +43,13 @@ const (
 	AssociationIDNotFound      = "InvalidAssociationID.NotFound"
 	InvalidInstanceID          = "InvalidInstanceID.NotFound"
 	LaunchTemplateNameNotFound = "InvalidLaunchTemplateName.NotFoundException"
+	LaunchTemplateNameExists   = "InvalidLaunchTemplateName.AlreadyExistsException"
 	ResourceExists             = "ResourceExistsException"
 	NoCredentialProviders      = "NoCredentialProviders"
+	PlacementGroupNotFound     = "InvalidPlacementGroup.Unknown"
+	InsufficientCapacity       = "InsufficientInstanceCapacity"
+	OperationNotPermitted      = "OperationNotPermitted"
+	DryRunOperation            = "DryRunOperation"
 )
 
 var _ error = &EC2Error{}
@@ -100,6 +106,14 @@ func IsResourceExists(err error) bool {
 	return false
 }
 
+// IsLaunchTemplateNameExists returns true if a launch template with the same name already exists.
+func IsLaunchTemplateNameExists(err error) bool {
+	if code, ok := Code(err); ok {
+		return code == LaunchTemplateNameExists
+	}
+	return false
+}
+
 // NewFailedDependency returns an error which indicates that a dependency failure status.
 func NewFailedDependency(msg string) error {
 	return &EC2Error{
@@ -126,6 +140,30 @@ func IsConflict(err error) bool {
 	return ReasonForError(err) == http.StatusConflict
 }
 
+// IsInsufficientCapacity returns true if EC2 doesn't have enough capacity for the requested instance type.
+func IsInsufficientCapacity(err error) bool {
+	if code, ok := Code(err); ok {
+		return code == InsufficientCapacity
+	}
+	return false
+}
+
+// IsOperationNotPermitted returns true if EC2 refused the operation, e.g. terminating an instance with termination protection.
+func IsOperationNotPermitted(err error) bool {
+	if code, ok := Code(err); ok {
+		return code == OperationNotPermitted
+	}
+	return false
+}
+
+// IsDryRunOperation returns true if a mutating call was skipped because the cluster is in dry-run mode.
+func IsDryRunOperation(err error) bool {
+	if code, ok := Code(err); ok {
+		return code == DryRunOperation
+	}
+	return false
+}
+
 // IsSDKError returns true if the error is of type awserr.Error.
 func IsSDKError(err error) (ok bool) {
 	_, ok = err.(awserr.Error)
@@ -144,6 +182,8 @@ func IsInvalidNotFoundError(err error) bool {
 			return true
 		case LaunchTemplateNameNotFound:
 			return true
+		case PlacementGroupNotFound:
+			return true
 		}
 	}
 
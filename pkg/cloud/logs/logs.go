@@ -18,10 +18,12 @@ package logs
 
 import (
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/go-logr/logr"
 )
 
 const (
+	logWithRequestID  = 4
 	logWithHTTPHeader = 9
 	logWithHTTPBody   = 10
 )
@@ -39,6 +41,23 @@ func GetAWSLogLevel(logger logr.Logger) aws.LogLevelType {
 	return aws.LogOff
 }
 
+// LogRequest returns a request handler logging every AWS API call with its request ID, which
+// identifies the call in CloudTrail and in AWS support cases.
+func LogRequest(logger logr.Logger) func(r *request.Request) {
+	return func(r *request.Request) {
+		log := logger.V(logWithRequestID)
+		if !log.Enabled() {
+			return
+		}
+
+		keysAndValues := []interface{}{"service", r.ClientInfo.ServiceID, "operation", r.Operation.Name, "request-id", r.RequestID}
+		if r.Error != nil {
+			keysAndValues = append(keysAndValues, "error", r.Error.Error())
+		}
+		log.Info("AWS API call completed", keysAndValues...)
+	}
+}
+
 // NewWrapLogr will create an AWS Logger wrapper.
 func NewWrapLogr(logger logr.Logger) aws.Logger {
 	return &logrWrapper{
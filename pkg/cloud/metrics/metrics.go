@@ -34,6 +34,7 @@ const (
 	metricRequestCountKey    = "api_requests_total"
 	metricRequestDurationKey = "api_request_duration_seconds"
 	metricAPICallRetries     = "api_call_retries"
+	metricThrottledCountKey  = "api_requests_throttled_total"
 	metricServiceLabel       = "service"
 	metricRegionLabel        = "region"
 	metricOperationLabel     = "operation"
@@ -59,12 +60,18 @@ var (
 		Help:      "Number of retries made against an AWS API",
 		Buckets:   []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 	}, []string{metricControllerLabel, metricServiceLabel, metricRegionLabel, metricOperationLabel})
+	awsThrottledRequestCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: metricAWSSubsystem,
+		Name:      metricThrottledCountKey,
+		Help:      "Total number of AWS requests rejected because of throttling",
+	}, []string{metricControllerLabel, metricServiceLabel, metricRegionLabel, metricOperationLabel})
 )
 
 func init() {
 	metrics.Registry.MustRegister(awsRequestCount)
 	metrics.Registry.MustRegister(awsRequestDurationSeconds)
 	metrics.Registry.MustRegister(awsCallRetries)
+	metrics.Registry.MustRegister(awsThrottledRequestCount)
 }
 
 // CaptureRequestMetrics will monitor and capture request metrics.
@@ -88,6 +95,9 @@ func CaptureRequestMetrics(controller string) func(r *request.Request) {
 		awsRequestCount.WithLabelValues(controller, service, region, operation, statusCode, errorCode).Inc()
 		awsRequestDurationSeconds.WithLabelValues(controller, service, region, operation).Observe(duration.Seconds())
 		awsCallRetries.WithLabelValues(controller, service, region, operation).Observe(float64(r.RetryCount))
+		if r.Error != nil && r.IsErrorThrottle() {
+			awsThrottledRequestCount.WithLabelValues(controller, service, region, operation).Inc()
+		}
 	}
 }
 
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestCaptureRequestMetricsCountsThrottledRequests(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+		expected   float64
+	}{
+		{
+			name:       "throttled request is counted",
+			err:        awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil),
+			statusCode: http.StatusServiceUnavailable,
+			expected:   1,
+		},
+		{
+			name:       "other failures are not counted",
+			err:        awserr.New("InvalidParameterValue", "Invalid value", nil),
+			statusCode: http.StatusBadRequest,
+			expected:   0,
+		},
+		{
+			name:       "successful request is not counted",
+			statusCode: http.StatusOK,
+			expected:   0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Each case uses its own controller label, so that the counters don't add up across cases.
+			controller := "test-" + tc.name
+			req := &request.Request{
+				Config:       aws.Config{Region: aws.String("us-east-1")},
+				ClientInfo:   metadata.ClientInfo{Endpoint: "https://ec2.us-east-1.amazonaws.com"},
+				Operation:    &request.Operation{Name: "DescribeInstances"},
+				AttemptTime:  time.Now(),
+				Error:        tc.err,
+				HTTPResponse: &http.Response{StatusCode: tc.statusCode},
+			}
+
+			CaptureRequestMetrics(controller)(req)
+
+			metric := &dto.Metric{}
+			g.Expect(awsThrottledRequestCount.WithLabelValues(controller, "ec2", "us-east-1", "DescribeInstances").Write(metric)).To(Succeed())
+			g.Expect(metric.GetCounter().GetValue()).To(Equal(tc.expected))
+		})
+	}
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package scope
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
 	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
@@ -28,12 +32,16 @@ import (
 	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/aws/aws-sdk-go/service/elb"
 	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/aws/aws-sdk-go/service/eventbridge"
 	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -47,8 +55,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
 	awslogs "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/logs"
 	awsmetrics "sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/metrics"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
 	"sigs.k8s.io/cluster-api-provider-aws/version"
 )
@@ -57,16 +67,25 @@ import (
 func NewASGClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) autoscalingiface.AutoScalingAPI {
 	asgClient := autoscaling.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	asgClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	asgClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
+	if session.ServiceLimiter(autoscaling.ServiceID) != nil {
+		asgClient.Handlers.Sign.PushFront(session.ServiceLimiter(autoscaling.ServiceID).LimitRequest)
+	}
 	asgClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	if session.ServiceLimiter(autoscaling.ServiceID) != nil {
+		asgClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(autoscaling.ServiceID).ReviewResponse)
+	}
 	asgClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	asgClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return asgClient
 }
 
 // NewEC2Client creates a new EC2 API client for a given session.
 func NewEC2Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) ec2iface.EC2API {
-	ec2Client := ec2.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	ec2Client := ec2.New(session.Session(), throttle.WithRetryer(aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger))))
 	ec2Client.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	ec2Client.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
 	if session.ServiceLimiter(ec2.ServiceID) != nil {
 		ec2Client.Handlers.Sign.PushFront(session.ServiceLimiter(ec2.ServiceID).LimitRequest)
 	}
@@ -75,18 +94,35 @@ func NewEC2Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 		ec2Client.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(ec2.ServiceID).ReviewResponse)
 	}
 	ec2Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	ec2Client.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return ec2Client
 }
 
 // NewELBClient creates a new ELB API client for a given session.
 func NewELBClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) elbiface.ELBAPI {
-	elbClient := elb.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	elbClient := elb.New(session.Session(), throttle.WithRetryer(aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger))))
 	elbClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	elbClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
 	elbClient.Handlers.Sign.PushFront(session.ServiceLimiter(elb.ServiceID).LimitRequest)
 	elbClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	elbClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(elb.ServiceID).ReviewResponse)
 	elbClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	elbClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
+
+	return elbClient
+}
+
+// NewELBv2Client creates a new ELB v2 API client for a given session.
+func NewELBv2Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) elbv2iface.ELBV2API {
+	elbClient := elbv2.New(session.Session(), throttle.WithRetryer(aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger))))
+	elbClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	elbClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
+	elbClient.Handlers.Sign.PushFront(session.ServiceLimiter(elbv2.ServiceID).LimitRequest)
+	elbClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	elbClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(elbv2.ServiceID).ReviewResponse)
+	elbClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	elbClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return elbClient
 }
@@ -95,6 +131,7 @@ func NewELBClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 func NewEventBridgeClient(scopeUser cloud.ScopeUsage, session cloud.Session, target runtime.Object) eventbridgeiface.EventBridgeAPI {
 	eventBridgeClient := eventbridge.New(session.Session())
 	eventBridgeClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	eventBridgeClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
 	eventBridgeClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	eventBridgeClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
 
@@ -105,6 +142,7 @@ func NewEventBridgeClient(scopeUser cloud.ScopeUsage, session cloud.Session, tar
 func NewSQSClient(scopeUser cloud.ScopeUsage, session cloud.Session, target runtime.Object) sqsiface.SQSAPI {
 	SQSClient := sqs.New(session.Session())
 	SQSClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	SQSClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
 	SQSClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	SQSClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
 
@@ -124,10 +162,12 @@ func NewGlobalSQSClient(scopeUser cloud.ScopeUsage, session cloud.Session) sqsif
 func NewResourgeTaggingClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI {
 	resourceTagging := resourcegroupstaggingapi.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	resourceTagging.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	resourceTagging.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
 	resourceTagging.Handlers.Sign.PushFront(session.ServiceLimiter(resourceTagging.ServiceID).LimitRequest)
 	resourceTagging.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	resourceTagging.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(resourceTagging.ServiceID).ReviewResponse)
 	resourceTagging.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	resourceTagging.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return resourceTagging
 }
@@ -136,10 +176,12 @@ func NewResourgeTaggingClient(scopeUser cloud.ScopeUsage, session cloud.Session,
 func NewSecretsManagerClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) secretsmanageriface.SecretsManagerAPI {
 	secretsClient := secretsmanager.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	secretsClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	secretsClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
 	secretsClient.Handlers.Sign.PushFront(session.ServiceLimiter(secretsClient.ServiceID).LimitRequest)
 	secretsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	secretsClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(secretsClient.ServiceID).ReviewResponse)
 	secretsClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	secretsClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return secretsClient
 }
@@ -148,8 +190,16 @@ func NewSecretsManagerClient(scopeUser cloud.ScopeUsage, session cloud.Session,
 func NewEKSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) eksiface.EKSAPI {
 	eksClient := eks.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	eksClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	eksClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
+	if session.ServiceLimiter(eks.ServiceID) != nil {
+		eksClient.Handlers.Sign.PushFront(session.ServiceLimiter(eks.ServiceID).LimitRequest)
+	}
 	eksClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	if session.ServiceLimiter(eks.ServiceID) != nil {
+		eksClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(eks.ServiceID).ReviewResponse)
+	}
 	eksClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	eksClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return eksClient
 }
@@ -158,18 +208,46 @@ func NewEKSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 func NewIAMClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) iamiface.IAMAPI {
 	iamClient := iam.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	iamClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	iamClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
+	if session.ServiceLimiter(iam.ServiceID) != nil {
+		iamClient.Handlers.Sign.PushFront(session.ServiceLimiter(iam.ServiceID).LimitRequest)
+	}
 	iamClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	if session.ServiceLimiter(iam.ServiceID) != nil {
+		iamClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(iam.ServiceID).ReviewResponse)
+	}
 	iamClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	iamClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return iamClient
 }
 
+// NewRoute53Client creates a new Route53 API client for a given session.
+func NewRoute53Client(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) route53iface.Route53API {
+	route53Client := route53.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
+	route53Client.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	route53Client.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
+	if session.ServiceLimiter(route53.ServiceID) != nil {
+		route53Client.Handlers.Sign.PushFront(session.ServiceLimiter(route53.ServiceID).LimitRequest)
+	}
+	route53Client.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	if session.ServiceLimiter(route53.ServiceID) != nil {
+		route53Client.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(route53.ServiceID).ReviewResponse)
+	}
+	route53Client.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	route53Client.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
+
+	return route53Client
+}
+
 // NewSTSClient creates a new STS API client for a given session.
 func NewSTSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) stsiface.STSAPI {
 	stsClient := sts.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	stsClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	stsClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
 	stsClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
 	stsClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	stsClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return stsClient
 }
@@ -178,8 +256,16 @@ func NewSTSClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr
 func NewSSMClient(scopeUser cloud.ScopeUsage, session cloud.Session, logger logr.Logger, target runtime.Object) ssmiface.SSMAPI {
 	ssmClient := ssm.New(session.Session(), aws.NewConfig().WithLogLevel(awslogs.GetAWSLogLevel(logger)).WithLogger(awslogs.NewWrapLogr(logger)))
 	ssmClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+	ssmClient.Handlers.Validate.PushFrontNamed(getDryRunHandler(session))
+	if session.ServiceLimiter(ssm.ServiceID) != nil {
+		ssmClient.Handlers.Sign.PushFront(session.ServiceLimiter(ssm.ServiceID).LimitRequest)
+	}
 	ssmClient.Handlers.CompleteAttempt.PushFront(awsmetrics.CaptureRequestMetrics(scopeUser.ControllerName()))
+	if session.ServiceLimiter(ssm.ServiceID) != nil {
+		ssmClient.Handlers.CompleteAttempt.PushFront(session.ServiceLimiter(ssm.ServiceID).ReviewResponse)
+	}
 	ssmClient.Handlers.Complete.PushBack(recordAWSPermissionsIssue(target))
+	ssmClient.Handlers.Complete.PushBack(awslogs.LogRequest(logger))
 
 	return ssmClient
 }
@@ -195,6 +281,69 @@ func recordAWSPermissionsIssue(target runtime.Object) func(r *request.Request) {
 	}
 }
 
+// dryRunner is implemented by the scopes whose AWS clients can log the mutating calls they would make
+// instead of making them.
+type dryRunner interface {
+	logr.Logger
+	DryRun() bool
+}
+
+// readOnlyOperationPrefixes are the prefixes of the names of the AWS API operations that don't change anything.
+var readOnlyOperationPrefixes = []string{"Describe", "Get", "List"}
+
+// getDryRunHandler fails the mutating calls of the clients of a scope in dry-run mode before they are sent,
+// and logs them with their input instead. The DryRunOperation error stops the reconciliation at the first
+// change it would make.
+func getDryRunHandler(session cloud.Session) request.NamedHandler {
+	return request.NamedHandler{
+		Name: "capa/dry-run",
+		Fn: func(r *request.Request) {
+			dryRunScope, ok := session.(dryRunner)
+			if !ok || !dryRunScope.DryRun() {
+				return
+			}
+			for _, prefix := range readOnlyOperationPrefixes {
+				if strings.HasPrefix(r.Operation.Name, prefix) {
+					return
+				}
+			}
+
+			dryRunScope.Info("Dry run, skipping mutating AWS API call", "aws-service", r.ClientInfo.ServiceID, "operation", r.Operation.Name, "input", dryRunInput(r.Params))
+			r.Error = awserr.New(awserrors.DryRunOperation, fmt.Sprintf("dry run, %s %s was not called", r.ClientInfo.ServiceID, r.Operation.Name), nil)
+		},
+	}
+}
+
+// dryRunInput returns the input of a call skipped in dry-run mode for the logs, without the user data
+// and secret values, which may hold the bootstrap credentials of the instances.
+func dryRunInput(params interface{}) string {
+	const redacted = "<redacted>"
+
+	switch input := awsutil.CopyOf(params).(type) {
+	case *ec2.RunInstancesInput:
+		input.UserData = aws.String(redacted)
+		return input.String()
+	case *ec2.CreateLaunchTemplateInput:
+		if input.LaunchTemplateData != nil {
+			input.LaunchTemplateData.UserData = aws.String(redacted)
+		}
+		return input.String()
+	case *ec2.CreateLaunchTemplateVersionInput:
+		if input.LaunchTemplateData != nil {
+			input.LaunchTemplateData.UserData = aws.String(redacted)
+		}
+		return input.String()
+	case *secretsmanager.CreateSecretInput:
+		input.SecretString = aws.String(redacted)
+		return input.String()
+	case *ssm.PutParameterInput:
+		input.Value = aws.String(redacted)
+		return input.String()
+	}
+
+	return awsutil.Prettify(params)
+}
+
 func getUserAgentHandler() request.NamedHandler {
 	return request.NamedHandler{
 		Name: "capa/user-agent",
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsclient "github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/klog/v2/klogr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/throttle"
+)
+
+type dryRunTestScope struct {
+	logr.Logger
+	session awsclient.ConfigProvider
+	dryRun  bool
+}
+
+func (s *dryRunTestScope) Session() awsclient.ConfigProvider              { return s.session }
+func (s *dryRunTestScope) ServiceLimiter(string) *throttle.ServiceLimiter { return nil }
+func (s *dryRunTestScope) ControllerName() string                         { return "test" }
+func (s *dryRunTestScope) DryRun() bool                                   { return s.dryRun }
+
+func TestDryRunHandler(t *testing.T) {
+	testCases := []struct {
+		name             string
+		dryRun           bool
+		call             func(client *ec2.EC2) error
+		expectDryRun     bool
+		expectedRequests int
+	}{
+		{
+			name:   "mutating call is skipped in dry-run mode",
+			dryRun: true,
+			call: func(client *ec2.EC2) error {
+				_, err := client.CreateVpc(&ec2.CreateVpcInput{CidrBlock: aws.String("10.0.0.0/16")})
+				return err
+			},
+			expectDryRun:     true,
+			expectedRequests: 0,
+		},
+		{
+			name:   "read-only call is made in dry-run mode",
+			dryRun: true,
+			call: func(client *ec2.EC2) error {
+				_, err := client.DescribeVpcs(&ec2.DescribeVpcsInput{})
+				return err
+			},
+			expectedRequests: 1,
+		},
+		{
+			name: "mutating call is made outside of dry-run mode",
+			call: func(client *ec2.EC2) error {
+				_, err := client.CreateVpc(&ec2.CreateVpcInput{CidrBlock: aws.String("10.0.0.0/16")})
+				return err
+			},
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				_, _ = w.Write([]byte(`<Response xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"></Response>`))
+			}))
+			defer server.Close()
+
+			sess, err := session.NewSession(&aws.Config{
+				Region:      aws.String("us-east-1"),
+				Endpoint:    aws.String(server.URL),
+				Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			s := &dryRunTestScope{Logger: klogr.New(), session: sess, dryRun: tc.dryRun}
+			client := NewEC2Client(s, s, s, &infrav1.AWSCluster{}).(*ec2.EC2)
+
+			err = tc.call(client)
+			if tc.expectDryRun {
+				g.Expect(awserrors.IsDryRunOperation(err)).To(BeTrue())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(requests).To(Equal(tc.expectedRequests))
+		})
+	}
+}
+
+func TestDryRunInput(t *testing.T) {
+	g := NewWithT(t)
+
+	input := &ec2.RunInstancesInput{
+		SubnetId: aws.String("subnet-1"),
+		UserData: aws.String("secret-bootstrap-data"),
+	}
+
+	logged := dryRunInput(input)
+	g.Expect(logged).To(ContainSubstring("subnet-1"))
+	g.Expect(strings.Contains(logged, "secret-bootstrap-data")).To(BeFalse())
+	g.Expect(aws.StringValue(input.UserData)).To(Equal("secret-bootstrap-data"))
+}
@@ -42,6 +42,9 @@ type ClusterScopeParams struct {
 	ControllerName string
 	Endpoints      []ServiceEndpoint
 	Session        awsclient.ConfigProvider
+
+	// DryRun makes the AWS clients of the cluster log the mutating calls they would make instead of making them.
+	DryRun bool
 }
 
 // NewClusterScope creates a new Scope from the supplied parameters.
@@ -59,11 +62,12 @@ func NewClusterScope(params ClusterScopeParams) (*ClusterScope, error) {
 	}
 
 	clusterScope := &ClusterScope{
-		Logger:         params.Logger,
+		Logger:         params.Logger.WithValues("region", params.AWSCluster.Spec.Region),
 		client:         params.Client,
 		Cluster:        params.Cluster,
 		AWSCluster:     params.AWSCluster,
 		controllerName: params.ControllerName,
+		dryRun:         params.DryRun,
 	}
 
 	session, serviceLimiters, err := sessionForClusterWithRegion(params.Client, clusterScope, params.AWSCluster.Spec.Region, params.Endpoints, params.Logger)
@@ -95,6 +99,12 @@ type ClusterScope struct {
 	session         awsclient.ConfigProvider
 	serviceLimiters throttle.ServiceLimiters
 	controllerName  string
+	dryRun          bool
+}
+
+// DryRun returns whether the AWS clients of the cluster log the mutating calls they would make instead of making them.
+func (s *ClusterScope) DryRun() bool {
+	return s.dryRun
 }
 
 // Network returns the cluster network object.
@@ -112,6 +122,16 @@ func (s *ClusterScope) Subnets() infrav1.Subnets {
 	return s.AWSCluster.Spec.NetworkSpec.Subnets
 }
 
+// PeeringConnections returns the VPC peering connections of the cluster.
+func (s *ClusterScope) PeeringConnections() []infrav1.VPCPeeringConnection {
+	return s.AWSCluster.Spec.NetworkSpec.PeeringConnections
+}
+
+// NetworkACLRules returns the rules of the network ACL of the cluster subnets.
+func (s *ClusterScope) NetworkACLRules() []infrav1.NetworkACLRule {
+	return s.AWSCluster.Spec.NetworkSpec.NetworkACLRules
+}
+
 // IdentityRef returns the cluster identityRef.
 func (s *ClusterScope) IdentityRef() *infrav1.AWSIdentityReference {
 	return s.AWSCluster.Spec.IdentityRef
@@ -135,14 +155,24 @@ func (s *ClusterScope) SecurityGroupOverrides() map[infrav1.SecurityGroupRole]st
 	return s.AWSCluster.Spec.NetworkSpec.SecurityGroupOverrides
 }
 
+// AdditionalIngressRules returns the additional ingress rules for the cluster security groups.
+func (s *ClusterScope) AdditionalIngressRules() map[infrav1.SecurityGroupRole]infrav1.IngressRules {
+	return s.AWSCluster.Spec.NetworkSpec.AdditionalIngressRules
+}
+
+// AllowEFATraffic returns whether the node security groups allow all traffic between their instances.
+func (s *ClusterScope) AllowEFATraffic() bool {
+	return s.AWSCluster.Spec.NetworkSpec.AllowEFATraffic
+}
+
 // SecurityGroups returns the cluster security groups as a map, it creates the map if empty.
 func (s *ClusterScope) SecurityGroups() map[infrav1.SecurityGroupRole]infrav1.SecurityGroup {
 	return s.AWSCluster.Status.Network.SecurityGroups
 }
 
-// SecondaryCidrBlock is currently unimplemented for non-managed clusters.
+// SecondaryCidrBlock returns the optional secondary CIDR block to use for pod IPs.
 func (s *ClusterScope) SecondaryCidrBlock() *string {
-	return nil
+	return s.AWSCluster.Spec.SecondaryCidrBlock
 }
 
 // Name returns the CAPI cluster name.
@@ -209,14 +239,39 @@ func (s *ClusterScope) PatchObject() error {
 	}
 
 	if s.VPC().IsManaged(s.Name()) {
-		applicableConditions = append(applicableConditions,
-			infrav1.InternetGatewayReadyCondition,
-			infrav1.NatGatewaysReadyCondition,
-			infrav1.RouteTablesReadyCondition)
+		applicableConditions = append(applicableConditions, infrav1.RouteTablesReadyCondition)
+
+		if s.VPC().IsIsolated() {
+			applicableConditions = append(applicableConditions, infrav1.VPCEndpointsReadyCondition)
+		} else {
+			applicableConditions = append(applicableConditions,
+				infrav1.InternetGatewayReadyCondition,
+				infrav1.NatGatewaysReadyCondition)
+		}
 
 		if s.AWSCluster.Spec.Bastion.Enabled {
 			applicableConditions = append(applicableConditions, infrav1.BastionHostReadyCondition)
 		}
+
+		if len(s.AWSCluster.Spec.NetworkSpec.PeeringConnections) > 0 {
+			applicableConditions = append(applicableConditions, infrav1.VPCPeeringConnectionsReadyCondition)
+		}
+
+		if s.VPC().DHCPOptions != nil {
+			applicableConditions = append(applicableConditions, infrav1.DHCPOptionsReadyCondition)
+		}
+
+		if len(s.AWSCluster.Spec.NetworkSpec.NetworkACLRules) > 0 {
+			applicableConditions = append(applicableConditions, infrav1.NetworkACLsReadyCondition)
+		}
+	}
+
+	if s.AWSCluster.Spec.ControlPlaneDNS != nil {
+		applicableConditions = append(applicableConditions, infrav1.ControlPlaneDNSReadyCondition)
+	}
+
+	if s.AWSCluster.Spec.ControlPlaneElasticIPs {
+		applicableConditions = append(applicableConditions, infrav1.ControlPlaneElasticIPsReadyCondition)
 	}
 
 	conditions.SetSummary(s.AWSCluster,
@@ -235,9 +290,15 @@ func (s *ClusterScope) PatchObject() error {
 			infrav1.InternetGatewayReadyCondition,
 			infrav1.NatGatewaysReadyCondition,
 			infrav1.RouteTablesReadyCondition,
+			infrav1.VPCEndpointsReadyCondition,
+			infrav1.VPCPeeringConnectionsReadyCondition,
+			infrav1.DHCPOptionsReadyCondition,
+			infrav1.NetworkACLsReadyCondition,
 			infrav1.ClusterSecurityGroupsReadyCondition,
 			infrav1.BastionHostReadyCondition,
 			infrav1.LoadBalancerReadyCondition,
+			infrav1.ControlPlaneDNSReadyCondition,
+			infrav1.ControlPlaneElasticIPsReadyCondition,
 			infrav1.PrincipalUsageAllowedCondition,
 		}})
 }
@@ -325,3 +386,13 @@ func (s *ClusterScope) ImageLookupOrg() string {
 func (s *ClusterScope) ImageLookupBaseOS() string {
 	return s.AWSCluster.Spec.ImageLookupBaseOS
 }
+
+// ControlPlaneElasticIPs returns whether Elastic IPs should be associated with control plane instances.
+func (s *ClusterScope) ControlPlaneElasticIPs() bool {
+	return s.AWSCluster.Spec.ControlPlaneElasticIPs
+}
+
+// ControlPlaneDNS returns the Route53 record to create for the API server load balancer.
+func (s *ClusterScope) ControlPlaneDNS() *infrav1.ControlPlaneDNS {
+	return s.AWSCluster.Spec.ControlPlaneDNS
+}
@@ -191,7 +191,12 @@ func (m *MachineScope) SetAnnotation(key, value string) {
 // UseSecretsManager returns the computed value of whether or not
 // userdata should be stored using AWS Secrets Manager.
 func (m *MachineScope) UseSecretsManager() bool {
-	return !m.AWSMachine.Spec.CloudInit.InsecureSkipSecretsManager
+	return !m.AWSMachine.Spec.CloudInit.InsecureSkipSecretsManager && !m.UseIgnition()
+}
+
+// UseIgnition returns whether or not the bootstrap data is an Ignition config.
+func (m *MachineScope) UseIgnition() bool {
+	return m.AWSMachine.Spec.UserDataFormat == infrav1.UserDataFormatIgnition
 }
 
 // SecureSecretsBackend returns the chosen secret backend.
@@ -202,6 +207,9 @@ func (m *MachineScope) SecureSecretsBackend() infrav1.SecretBackend {
 // UserDataIsUncompressed returns the computed value of whether or not
 // userdata should be compressed using gzip.
 func (m *MachineScope) UserDataIsUncompressed() bool {
+	if m.UseIgnition() {
+		return true
+	}
 	return m.AWSMachine.Spec.UncompressedUserData != nil && *m.AWSMachine.Spec.UncompressedUserData
 }
 
@@ -296,6 +304,7 @@ func (m *MachineScope) PatchObject() error {
 			infrav1.InstanceReadyCondition,
 			infrav1.SecurityGroupsReadyCondition,
 			infrav1.ELBAttachedCondition,
+			infrav1.AMIUpToDateCondition,
 		}})
 }
 
@@ -181,6 +181,21 @@ func TestUseSecretsManagerTrue(t *testing.T) {
 	}
 }
 
+func TestUseSecretsManagerFalseWithIgnition(t *testing.T) {
+	scope, err := setupMachineScope()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope.AWSMachine.Spec.UserDataFormat = infrav1.UserDataFormatIgnition
+
+	if scope.UseSecretsManager() {
+		t.Fatalf("UseSecretsManager should be false")
+	}
+	if !scope.UserDataIsUncompressed() {
+		t.Fatalf("UserDataIsUncompressed should be true")
+	}
+}
+
 func TestGetSecretARNDefaultIsNil(t *testing.T) {
 	scope, err := setupMachineScope()
 	if err != nil {
@@ -78,7 +78,7 @@ func NewManagedControlPlaneScope(params ManagedControlPlaneScopeParams) (*Manage
 	}
 
 	managedScope := &ManagedControlPlaneScope{
-		Logger:               params.Logger,
+		Logger:               params.Logger.WithValues("region", params.ControlPlane.Spec.Region),
 		Client:               params.Client,
 		Cluster:              params.Cluster,
 		ControlPlane:         params.ControlPlane,
@@ -148,6 +148,16 @@ func (s *ManagedControlPlaneScope) VPC() *infrav1.VPCSpec {
 	return &s.ControlPlane.Spec.NetworkSpec.VPC
 }
 
+// PeeringConnections returns the VPC peering connections of the control plane.
+func (s *ManagedControlPlaneScope) PeeringConnections() []infrav1.VPCPeeringConnection {
+	return s.ControlPlane.Spec.NetworkSpec.PeeringConnections
+}
+
+// NetworkACLRules returns the rules of the network ACL of the control plane subnets.
+func (s *ManagedControlPlaneScope) NetworkACLRules() []infrav1.NetworkACLRule {
+	return s.ControlPlane.Spec.NetworkSpec.NetworkACLRules
+}
+
 // ServiceLimiter returns the AWS SDK session. Used for creating clients.
 func (s *ManagedControlPlaneScope) ServiceLimiter(service string) *throttle.ServiceLimiter {
 	if sl, ok := s.serviceLimiters[service]; ok {
@@ -194,6 +204,21 @@ func (s *ManagedControlPlaneScope) SecurityGroupOverrides() map[infrav1.Security
 	return s.ControlPlane.Spec.NetworkSpec.SecurityGroupOverrides
 }
 
+// AdditionalIngressRules returns the additional ingress rules for the control plane security groups.
+func (s *ManagedControlPlaneScope) AdditionalIngressRules() map[infrav1.SecurityGroupRole]infrav1.IngressRules {
+	return s.ControlPlane.Spec.NetworkSpec.AdditionalIngressRules
+}
+
+// AllowEFATraffic returns whether the node security groups allow all traffic between their instances.
+func (s *ManagedControlPlaneScope) AllowEFATraffic() bool {
+	return s.ControlPlane.Spec.NetworkSpec.AllowEFATraffic
+}
+
+// ControlPlaneLoadBalancer returns nil as the EKS control plane is not fronted by a load balancer managed by the provider.
+func (s *ManagedControlPlaneScope) ControlPlaneLoadBalancer() *infrav1.AWSLoadBalancerSpec {
+	return nil
+}
+
 // Name returns the CAPI cluster name.
 func (s *ManagedControlPlaneScope) Name() string {
 	return s.Cluster.Name
@@ -232,6 +257,10 @@ func (s *ManagedControlPlaneScope) PatchObject() error {
 			infrav1.InternetGatewayReadyCondition,
 			infrav1.NatGatewaysReadyCondition,
 			infrav1.RouteTablesReadyCondition,
+			infrav1.VPCEndpointsReadyCondition,
+			infrav1.VPCPeeringConnectionsReadyCondition,
+			infrav1.DHCPOptionsReadyCondition,
+			infrav1.NetworkACLsReadyCondition,
 			infrav1.BastionHostReadyCondition,
 			ekscontrolplanev1.EKSControlPlaneCreatingCondition,
 			ekscontrolplanev1.EKSControlPlaneReadyCondition,
@@ -338,6 +367,11 @@ func (s *ManagedControlPlaneScope) ImageLookupBaseOS() string {
 	return s.ControlPlane.Spec.ImageLookupBaseOS
 }
 
+// ControlPlaneElasticIPs returns false as EKS control planes have no instances to associate Elastic IPs with.
+func (s *ManagedControlPlaneScope) ControlPlaneElasticIPs() bool {
+	return false
+}
+
 // IAMAuthConfig returns the IAM authenticator config. The returned value will never be nil.
 func (s *ManagedControlPlaneScope) IAMAuthConfig() *ekscontrolplanev1.IAMAuthenticatorConfig {
 	if s.ControlPlane.Spec.IAMAuthenticatorConfig == nil {
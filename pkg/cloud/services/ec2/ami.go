@@ -58,8 +58,26 @@ const (
 	// EKS AMI ID SSM Parameter name.
 	eksAmiSSMParameterFormat = "/aws/service/eks/optimized-ami/%s/amazon-linux-2/recommended/image_id"
 
+	// EKS arm64 AMI ID SSM Parameter name.
+	eksArm64AmiSSMParameterFormat = "/aws/service/eks/optimized-ami/%s/amazon-linux-2-arm64/recommended/image_id"
+
 	// EKS GPU AMI ID SSM Parameter name.
 	eksGPUAmiSSMParameterFormat = "/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/image_id"
+
+	// EKS Bottlerocket AMI ID SSM Parameter name, for the Kubernetes version and architecture.
+	eksBottlerocketAmiSSMParameterFormat = "/aws/service/bottlerocket/aws-k8s-%s/%s/latest/image_id"
+
+	// Amd64ArchitectureTag is the reference AWS uses for x86_64 architecture images.
+	Amd64ArchitectureTag = "x86_64"
+
+	// Arm64ArchitectureTag is the reference AWS uses for arm64 architecture images.
+	Arm64ArchitectureTag = "arm64"
+
+	// nvidiaGPUManufacturer is the manufacturer EC2 reports for NVIDIA GPUs.
+	nvidiaGPUManufacturer = "NVIDIA"
+
+	// Canonical's published SSM Parameter name for the bastion's Ubuntu AMI ID.
+	bastionAmiSSMParameter = "/aws/service/canonical/ubuntu/server/18.04/stable/current/amd64/hvm/ebs-gp2/ami-id"
 )
 
 // AMILookup contains the parameters used to template AMI names used for lookup.
@@ -88,7 +106,8 @@ func GenerateAmiName(amiNameFormat, baseOS, kubernetesVersion string) (string, e
 }
 
 // DefaultAMILookup will do a default AMI lookup.
-func DefaultAMILookup(ec2Client ec2iface.EC2API, ownerID, baseOS, kubernetesVersion, amiNameFormat string) (*ec2.Image, error) {
+// An empty architecture looks up x86_64 images.
+func DefaultAMILookup(ec2Client ec2iface.EC2API, ownerID, baseOS, kubernetesVersion, architecture, amiNameFormat string) (*ec2.Image, error) {
 	if amiNameFormat == "" {
 		amiNameFormat = DefaultAmiNameFormat
 	}
@@ -98,6 +117,9 @@ func DefaultAMILookup(ec2Client ec2iface.EC2API, ownerID, baseOS, kubernetesVers
 	if baseOS == "" {
 		baseOS = defaultMachineAMILookupBaseOS
 	}
+	if architecture == "" {
+		architecture = Amd64ArchitectureTag
+	}
 
 	amiName, err := GenerateAmiName(amiNameFormat, baseOS, kubernetesVersion)
 	if err != nil {
@@ -115,7 +137,7 @@ func DefaultAMILookup(ec2Client ec2iface.EC2API, ownerID, baseOS, kubernetesVers
 			},
 			{
 				Name:   aws.String("architecture"),
-				Values: []*string{aws.String("x86_64")},
+				Values: []*string{aws.String(architecture)},
 			},
 			{
 				Name:   aws.String("state"),
@@ -144,10 +166,10 @@ func DefaultAMILookup(ec2Client ec2iface.EC2API, ownerID, baseOS, kubernetesVers
 }
 
 // defaultAMIIDLookup returns the default AMI based on region.
-func (s *Service) defaultAMIIDLookup(amiNameFormat, ownerID, baseOS, kubernetesVersion string) (string, error) {
-	latestImage, err := DefaultAMILookup(s.EC2Client, ownerID, baseOS, kubernetesVersion, amiNameFormat)
+func (s *Service) defaultAMIIDLookup(amiNameFormat, ownerID, baseOS, architecture, kubernetesVersion string) (string, error) {
+	latestImage, err := DefaultAMILookup(s.EC2Client, ownerID, baseOS, kubernetesVersion, architecture, amiNameFormat)
 	if err != nil {
-		record.Eventf(s.scope.InfraCluster(), "FailedDescribeImages", "Failed to find ami for OS=%s and Kubernetes-version=%s: %v", baseOS, kubernetesVersion, err)
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeImages", "Failed to find ami for OS=%s, architecture=%s and Kubernetes-version=%s: %v", baseOS, architecture, kubernetesVersion, err)
 		return "", errors.Wrapf(err, "failed to find ami")
 	}
 
@@ -155,6 +177,121 @@ func (s *Service) defaultAMIIDLookup(amiNameFormat, ownerID, baseOS, kubernetesV
 	return aws.StringValue(latestImage.ImageId), nil
 }
 
+// describeInstanceType returns the description of an instance type.
+func (s *Service) describeInstanceType(instanceType string) (*ec2.InstanceTypeInfo, error) {
+	input := &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	}
+
+	out, err := s.EC2Client.DescribeInstanceTypes(input)
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeInstanceTypes", "Failed to describe instance type %q: %v", instanceType, err)
+		return nil, errors.Wrapf(err, "failed to describe instance type %q", instanceType)
+	}
+
+	if len(out.InstanceTypes) == 0 {
+		return nil, errors.Errorf("instance type %q not found", instanceType)
+	}
+
+	return out.InstanceTypes[0], nil
+}
+
+// pickArchitectureForInstanceType returns the image architecture to look up
+// for an instance type, preferring x86_64 when both are supported.
+func (s *Service) pickArchitectureForInstanceType(instanceType string) (string, error) {
+	info, err := s.describeInstanceType(instanceType)
+	if err != nil {
+		return "", err
+	}
+
+	return architectureForInstanceType(instanceType, info)
+}
+
+func architectureForInstanceType(instanceType string, info *ec2.InstanceTypeInfo) (string, error) {
+	if info.ProcessorInfo == nil {
+		return "", errors.Errorf("instance type %q has no processor info", instanceType)
+	}
+
+	supportedArchs := aws.StringValueSlice(info.ProcessorInfo.SupportedArchitectures)
+	for _, arch := range supportedArchs {
+		if arch == ec2.ArchitectureTypeX8664 {
+			return Amd64ArchitectureTag, nil
+		}
+	}
+	for _, arch := range supportedArchs {
+		if arch == ec2.ArchitectureTypeArm64 {
+			return Arm64ArchitectureTag, nil
+		}
+	}
+
+	return "", errors.Errorf("instance type %q has no supported architecture, got %v", instanceType, supportedArchs)
+}
+
+// eksAMILookupForInstanceType looks up the EKS optimized AMI matching the architecture of an
+// instance type, describing the instance type once.
+func (s *Service) eksAMILookupForInstanceType(kubernetesVersion, instanceType string, amiType *v1alpha4.EKSAMILookupType) (string, error) {
+	info, err := s.describeInstanceType(instanceType)
+	if err != nil {
+		return "", err
+	}
+
+	architecture, err := architectureForInstanceType(instanceType, info)
+	if err != nil {
+		return "", err
+	}
+
+	return s.eksAMILookup(kubernetesVersion, architecture, pickEKSAMILookupType(info, architecture, amiType))
+}
+
+// defaultAMIIDLookupForInstanceType looks up the AMI matching the architecture of an instance type,
+// describing the instance type once. The default CAPA images don't ship GPU drivers, so looking them up
+// for an instance type with NVIDIA GPUs is warned about.
+func (s *Service) defaultAMIIDLookupForInstanceType(amiNameFormat, ownerID, baseOS, instanceType, kubernetesVersion string) (string, error) {
+	info, err := s.describeInstanceType(instanceType)
+	if err != nil {
+		return "", err
+	}
+
+	architecture, err := architectureForInstanceType(instanceType, info)
+	if err != nil {
+		return "", err
+	}
+
+	if ownerID == "" && hasNVIDIAGPU(info) {
+		s.scope.Info("Looking up a default AMI without NVIDIA drivers for a GPU instance type", "instance-type", instanceType)
+		record.Warnf(s.scope.InfraCluster(), "GPUDriversMissing", "The default AMIs have no NVIDIA drivers, instance type %q needs an AMI ID or an imageLookupOrg with GPU images", instanceType)
+	}
+
+	return s.defaultAMIIDLookup(amiNameFormat, ownerID, baseOS, architecture, kubernetesVersion)
+}
+
+// pickEKSAMILookupType returns the EKS optimized AMI type to look up for an instance
+// type when none is requested, so that x86_64 instances with NVIDIA GPUs get the accelerated
+// AMI shipping the NVIDIA drivers. There is no accelerated AMI for arm64, so Graviton
+// instances with NVIDIA GPUs get the standard arm64 AMI.
+func pickEKSAMILookupType(info *ec2.InstanceTypeInfo, architecture string, amiType *v1alpha4.EKSAMILookupType) *v1alpha4.EKSAMILookupType {
+	if amiType != nil || architecture != Amd64ArchitectureTag || !hasNVIDIAGPU(info) {
+		return amiType
+	}
+
+	gpuAMIType := v1alpha4.AmazonLinuxGPU
+	return &gpuAMIType
+}
+
+// hasNVIDIAGPU returns whether an instance type has NVIDIA GPUs.
+func hasNVIDIAGPU(info *ec2.InstanceTypeInfo) bool {
+	if info.GpuInfo == nil {
+		return false
+	}
+	for _, gpu := range info.GpuInfo.Gpus {
+		if aws.StringValue(gpu.Manufacturer) == nvidiaGPUManufacturer {
+			return true
+		}
+	}
+
+	return false
+}
+
 type images []*ec2.Image
 
 // Len is the number of elements in the collection.
@@ -188,6 +325,45 @@ func GetLatestImage(imgs []*ec2.Image) (*ec2.Image, error) {
 	return imgs[len(imgs)-1], nil
 }
 
+// bastionAMILookup returns the AMI held by the bastion's SSM parameter, Canonical's latest
+// Ubuntu AMI by default. Only the default parameter falls back to the static per-region
+// list if it cannot be read; a configured parameter that cannot be read is an error.
+func (s *Service) bastionAMILookup() (string, error) {
+	paramName := s.scope.Bastion().AMISSMParameterName
+	if paramName != "" {
+		return s.ssmAMILookup(paramName)
+	}
+
+	id, err := s.ssmAMILookup(bastionAmiSSMParameter)
+	if err == nil {
+		s.scope.V(2).Info("Found bastion AMI in SSM parameter store", "ami-id", id)
+		return id, nil
+	}
+
+	s.scope.V(2).Info("Failed to get bastion AMI SSM parameter, falling back to default AMI for region", "parameter", bastionAmiSSMParameter, "error", err)
+	return s.defaultBastionAMILookup(s.scope.Region()), nil
+}
+
+// ssmAMILookup returns the AMI ID held by an SSM parameter.
+func (s *Service) ssmAMILookup(paramName string) (string, error) {
+	input := &ssm.GetParameterInput{
+		Name: aws.String(paramName),
+	}
+
+	out, err := s.SSMClient.GetParameter(input)
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedGetParameter", "Failed to get ami SSM parameter %q: %v", paramName, err)
+
+		return "", errors.Wrapf(err, "failed to get ami SSM parameter: %q", paramName)
+	}
+
+	if out.Parameter == nil || out.Parameter.Value == nil {
+		return "", errors.Errorf("SSM parameter returned with nil value: %q", paramName)
+	}
+
+	return aws.StringValue(out.Parameter.Value), nil
+}
+
 func (s *Service) defaultBastionAMILookup(region string) string {
 	switch region {
 	case "ap-northeast-1":
@@ -229,7 +405,8 @@ func (s *Service) defaultBastionAMILookup(region string) string {
 	}
 }
 
-func (s *Service) eksAMILookup(kubernetesVersion string, amiType *v1alpha4.EKSAMILookupType) (string, error) {
+// eksAMILookup looks up an EKS optimized AMI of the given architecture in SSM Parameter store.
+func (s *Service) eksAMILookup(kubernetesVersion, architecture string, amiType *v1alpha4.EKSAMILookupType) (string, error) {
 	// format ssm parameter path properly
 	formattedVersion, err := formatVersionForEKS(kubernetesVersion)
 	if err != nil {
@@ -244,27 +421,28 @@ func (s *Service) eksAMILookup(kubernetesVersion string, amiType *v1alpha4.EKSAM
 
 	switch *amiType {
 	case v1alpha4.AmazonLinuxGPU:
+		if architecture == Arm64ArchitectureTag {
+			return "", errors.Errorf("no %s EKS optimized AMI is available for the %s architecture", v1alpha4.AmazonLinuxGPU, architecture)
+		}
 		paramName = fmt.Sprintf(eksGPUAmiSSMParameterFormat, formattedVersion)
+	case v1alpha4.Bottlerocket:
+		if architecture == "" {
+			architecture = Amd64ArchitectureTag
+		}
+		paramName = fmt.Sprintf(eksBottlerocketAmiSSMParameterFormat, formattedVersion, architecture)
 	default:
-		paramName = fmt.Sprintf(eksAmiSSMParameterFormat, formattedVersion)
-	}
-
-	input := &ssm.GetParameterInput{
-		Name: aws.String(paramName),
+		if architecture == Arm64ArchitectureTag {
+			paramName = fmt.Sprintf(eksArm64AmiSSMParameterFormat, formattedVersion)
+		} else {
+			paramName = fmt.Sprintf(eksAmiSSMParameterFormat, formattedVersion)
+		}
 	}
 
-	out, err := s.SSMClient.GetParameter(input)
+	id, err := s.ssmAMILookup(paramName)
 	if err != nil {
-		record.Eventf(s.scope.InfraCluster(), "FailedGetParameter", "Failed to get ami SSM parameter %q: %v", paramName, err)
-
-		return "", errors.Wrapf(err, "failed to get ami SSM parameter: %q", paramName)
-	}
-
-	if out.Parameter.Value == nil {
-		return "", errors.Errorf("SSM parameter returned with nil value: %q", paramName)
+		return "", err
 	}
 
-	id := aws.StringValue(out.Parameter.Value)
 	s.scope.Info("found AMI", "id", id, "version", formattedVersion)
 
 	return id, nil
@@ -23,7 +23,10 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
@@ -78,7 +81,7 @@ func TestAMIs(t *testing.T) {
 			s := NewService(clusterScope)
 			s.EC2Client = ec2Mock
 
-			id, err := s.defaultAMIIDLookup("", "", "base os-baseos version", "1.11.1")
+			id, err := s.defaultAMIIDLookup("", "", "base os-baseos version", "x86_64", "1.11.1")
 			if err != nil {
 				t.Fatalf("did not expect error calling a mock: %v", err)
 			}
@@ -89,6 +92,78 @@ func TestAMIs(t *testing.T) {
 	}
 }
 
+func TestDefaultAMIIDLookupForInstanceType(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name          string
+		ownerID       string
+		expectedOwner string
+	}{
+		{
+			name:          "NVIDIA GPU instance type with the default images",
+			expectedOwner: DefaultMachineAMIOwnerID,
+		},
+		{
+			name:          "NVIDIA GPU instance type with a custom image lookup org",
+			ownerID:       "123456789012",
+			expectedOwner: "123456789012",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			ec2Mock.EXPECT().
+				DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+					InstanceTypes: []*string{aws.String("g4dn.xlarge")},
+				})).
+				Return(&ec2.DescribeInstanceTypesOutput{
+					InstanceTypes: []*ec2.InstanceTypeInfo{
+						{
+							InstanceType: aws.String("g4dn.xlarge"),
+							ProcessorInfo: &ec2.ProcessorInfo{
+								SupportedArchitectures: aws.StringSlice([]string{"x86_64"}),
+							},
+							GpuInfo: &ec2.GpuInfo{
+								Gpus: []*ec2.GpuDeviceInfo{
+									{Manufacturer: aws.String("NVIDIA"), Name: aws.String("T4")},
+								},
+							},
+						},
+					},
+				}, nil)
+			ec2Mock.EXPECT().
+				DescribeImages(gomock.AssignableToTypeOf(&ec2.DescribeImagesInput{})).
+				Do(func(input *ec2.DescribeImagesInput) {
+					g.Expect(aws.StringValue(input.Filters[0].Values[0])).To(Equal(tc.expectedOwner))
+					g.Expect(aws.StringValue(input.Filters[2].Values[0])).To(Equal(Amd64ArchitectureTag))
+				}).
+				Return(&ec2.DescribeImagesOutput{
+					Images: []*ec2.Image{
+						{
+							ImageId:      aws.String("ami-1"),
+							CreationDate: aws.String("2019-02-08T17:02:31.000Z"),
+						},
+					},
+				}, nil)
+
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			id, err := s.defaultAMIIDLookupForInstanceType("", tc.ownerID, "", "g4dn.xlarge", "v1.21.2")
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(id).To(Equal("ami-1"))
+		})
+	}
+}
+
 func TestAMIsWithInvalidCreationDate(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -134,7 +209,7 @@ func TestAMIsWithInvalidCreationDate(t *testing.T) {
 			s := NewService(clusterScope)
 			s.EC2Client = ec2Mock
 
-			_, err = s.defaultAMIIDLookup("", "", "base os-baseos version", "1.11.1")
+			_, err = s.defaultAMIIDLookup("", "", "base os-baseos version", "x86_64", "1.11.1")
 			if err == nil {
 				t.Fatalf("expected an error but did not get one")
 			}
@@ -142,6 +217,336 @@ func TestAMIsWithInvalidCreationDate(t *testing.T) {
 	}
 }
 
+func TestPickArchitectureForInstanceType(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name          string
+		instanceType  string
+		architectures []string
+		expected      string
+		expectError   bool
+	}{
+		{
+			name:          "x86_64 instance type",
+			instanceType:  "m5.large",
+			architectures: []string{"i386", "x86_64"},
+			expected:      Amd64ArchitectureTag,
+		},
+		{
+			name:          "Graviton instance type",
+			instanceType:  "m6g.large",
+			architectures: []string{"arm64"},
+			expected:      Arm64ArchitectureTag,
+		},
+		{
+			name:          "prefers x86_64 when both are supported",
+			instanceType:  "test.large",
+			architectures: []string{"arm64", "x86_64"},
+			expected:      Amd64ArchitectureTag,
+		},
+		{
+			name:          "unsupported architecture",
+			instanceType:  "mac1.metal",
+			architectures: []string{"x86_64_mac"},
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			expectDescribeInstanceType(ec2Mock.EXPECT(), tc.instanceType, tc.architectures...)
+
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			arch, err := s.pickArchitectureForInstanceType(tc.instanceType)
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(arch).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestPickEKSAMILookupType(t *testing.T) {
+	bottlerocket := infrav1.Bottlerocket
+	amazonLinuxGPU := infrav1.AmazonLinuxGPU
+	nvidiaGPUInfo := &ec2.GpuInfo{
+		Gpus: []*ec2.GpuDeviceInfo{
+			{Manufacturer: aws.String("NVIDIA"), Name: aws.String("V100")},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		architecture string
+		amiType      *infrav1.EKSAMILookupType
+		gpuInfo      *ec2.GpuInfo
+		expected     *infrav1.EKSAMILookupType
+	}{
+		{
+			name:         "keeps the requested AMI type",
+			architecture: "x86_64",
+			amiType:      &bottlerocket,
+			gpuInfo:      nvidiaGPUInfo,
+			expected:     &bottlerocket,
+		},
+		{
+			name:         "NVIDIA GPU instance type",
+			architecture: "x86_64",
+			gpuInfo:      nvidiaGPUInfo,
+			expected:     &amazonLinuxGPU,
+		},
+		{
+			name:         "Graviton NVIDIA GPU instance type",
+			architecture: "arm64",
+			gpuInfo: &ec2.GpuInfo{
+				Gpus: []*ec2.GpuDeviceInfo{
+					{Manufacturer: aws.String("NVIDIA"), Name: aws.String("T4g")},
+				},
+			},
+		},
+		{
+			name:         "AMD GPU instance type",
+			architecture: "x86_64",
+			gpuInfo: &ec2.GpuInfo{
+				Gpus: []*ec2.GpuDeviceInfo{
+					{Manufacturer: aws.String("AMD"), Name: aws.String("Radeon Pro V520")},
+				},
+			},
+		},
+		{
+			name:         "instance type without GPU",
+			architecture: "x86_64",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			info := &ec2.InstanceTypeInfo{GpuInfo: tc.gpuInfo}
+			g.Expect(pickEKSAMILookupType(info, tc.architecture, tc.amiType)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestEKSAMILookupForInstanceType(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	params := map[string]string{
+		"/aws/service/eks/optimized-ami/1.21/amazon-linux-2/recommended/image_id":       "ami-amd64",
+		"/aws/service/eks/optimized-ami/1.21/amazon-linux-2-arm64/recommended/image_id": "ami-arm64",
+		"/aws/service/bottlerocket/aws-k8s-1.21/x86_64/latest/image_id":                 "ami-bottlerocket-amd64",
+		"/aws/service/bottlerocket/aws-k8s-1.21/arm64/latest/image_id":                  "ami-bottlerocket-arm64",
+		"/aws/service/eks/optimized-ami/1.21/amazon-linux-2-gpu/recommended/image_id":   "ami-gpu",
+	}
+	bottlerocket := infrav1.Bottlerocket
+	amazonLinuxGPU := infrav1.AmazonLinuxGPU
+	nvidiaGPUInfo := &ec2.GpuInfo{
+		Gpus: []*ec2.GpuDeviceInfo{
+			{Manufacturer: aws.String("NVIDIA"), Name: aws.String("T4")},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		instanceType  string
+		architectures []string
+		gpuInfo       *ec2.GpuInfo
+		amiType       *infrav1.EKSAMILookupType
+		expected      string
+		expectErr     bool
+	}{
+		{
+			name:          "x86_64 instance type",
+			instanceType:  "m5.large",
+			architectures: []string{"x86_64"},
+			expected:      "ami-amd64",
+		},
+		{
+			name:          "Graviton instance type",
+			instanceType:  "m6g.large",
+			architectures: []string{"arm64"},
+			expected:      "ami-arm64",
+		},
+		{
+			name:          "Bottlerocket on x86_64 instance type",
+			instanceType:  "m5.large",
+			architectures: []string{"x86_64"},
+			amiType:       &bottlerocket,
+			expected:      "ami-bottlerocket-amd64",
+		},
+		{
+			name:          "Bottlerocket on Graviton instance type",
+			instanceType:  "m6g.large",
+			architectures: []string{"arm64"},
+			amiType:       &bottlerocket,
+			expected:      "ami-bottlerocket-arm64",
+		},
+		{
+			name:          "x86_64 NVIDIA GPU instance type",
+			instanceType:  "g4dn.xlarge",
+			architectures: []string{"x86_64"},
+			gpuInfo:       nvidiaGPUInfo,
+			expected:      "ami-gpu",
+		},
+		{
+			name:          "Graviton NVIDIA GPU instance type",
+			instanceType:  "g5g.xlarge",
+			architectures: []string{"arm64"},
+			gpuInfo:       nvidiaGPUInfo,
+			expected:      "ami-arm64",
+		},
+		{
+			name:          "GPU AMI requested for Graviton instance type",
+			instanceType:  "g5g.xlarge",
+			architectures: []string{"arm64"},
+			gpuInfo:       nvidiaGPUInfo,
+			amiType:       &amazonLinuxGPU,
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			ec2Mock.EXPECT().
+				DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+					InstanceTypes: []*string{aws.String(tc.instanceType)},
+				})).
+				Return(&ec2.DescribeInstanceTypesOutput{
+					InstanceTypes: []*ec2.InstanceTypeInfo{
+						{
+							InstanceType: aws.String(tc.instanceType),
+							ProcessorInfo: &ec2.ProcessorInfo{
+								SupportedArchitectures: aws.StringSlice(tc.architectures),
+							},
+							GpuInfo: tc.gpuInfo,
+						},
+					},
+				}, nil)
+
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+			s.SSMClient = &fakeSSM{params: params}
+
+			id, err := s.eksAMILookupForInstanceType("v1.21.2", tc.instanceType, tc.amiType)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(id).To(Equal(tc.expected))
+		})
+	}
+}
+
+// fakeSSM stands in for the SSM API: it serves the parameters it holds and fails for any other.
+type fakeSSM struct {
+	ssmiface.SSMAPI
+	params map[string]string
+}
+
+func (f *fakeSSM) GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	value, ok := f.params[aws.StringValue(input.Name)]
+	if !ok {
+		return nil, errors.Errorf("parameter %q not found", aws.StringValue(input.Name))
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Name: input.Name, Value: aws.String(value)}}, nil
+}
+
+func TestSSMAMILookup(t *testing.T) {
+	g := NewWithT(t)
+
+	clusterScope, err := setupCluster("test-cluster")
+	g.Expect(err).To(Not(HaveOccurred()))
+
+	s := NewService(clusterScope)
+	s.SSMClient = &fakeSSM{params: map[string]string{"/aws/service/ami-amazon-linux-latest/amzn2-ami-hvm-x86_64-gp2": "ami-amzn2"}}
+
+	id, err := s.ssmAMILookup("/aws/service/ami-amazon-linux-latest/amzn2-ami-hvm-x86_64-gp2")
+	g.Expect(err).To(Not(HaveOccurred()))
+	g.Expect(id).To(Equal("ami-amzn2"))
+
+	_, err = s.ssmAMILookup("/my-org/images/missing")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestBastionAMILookup(t *testing.T) {
+	testCases := []struct {
+		name          string
+		parameterName string
+		params        map[string]string
+		expected      string
+		expectErr     bool
+	}{
+		{
+			name:     "defaults to Canonical's Ubuntu parameter",
+			params:   map[string]string{bastionAmiSSMParameter: "ami-ubuntu"},
+			expected: "ami-ubuntu",
+		},
+		{
+			name:          "uses the configured parameter",
+			parameterName: "/aws/service/canonical/ubuntu/server/20.04/stable/current/amd64/hvm/ebs-gp2/ami-id",
+			params: map[string]string{
+				bastionAmiSSMParameter: "ami-ubuntu",
+				"/aws/service/canonical/ubuntu/server/20.04/stable/current/amd64/hvm/ebs-gp2/ami-id": "ami-focal",
+			},
+			expected: "ami-focal",
+		},
+		{
+			name:     "falls back to the AMI of the region",
+			params:   map[string]string{},
+			expected: "ami-0dba2cb6798deb6d8",
+		},
+		{
+			name:          "fails if the configured parameter can't be read",
+			parameterName: "/my-org/images/bastion",
+			params:        map[string]string{bastionAmiSSMParameter: "ami-ubuntu"},
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+			clusterScope.AWSCluster.Spec.Region = "us-east-1"
+			clusterScope.AWSCluster.Spec.Bastion.AMISSMParameterName = tc.parameterName
+
+			s := NewService(clusterScope)
+			s.SSMClient = &fakeSSM{params: tc.params}
+
+			id, err := s.bastionAMILookup()
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(Not(HaveOccurred()))
+			g.Expect(id).To(Equal(tc.expected))
+		})
+	}
+}
+
 func setupCluster(clusterName string) (*scope.ClusterScope, error) {
 	scheme := runtime.NewScheme()
 	_ = infrav1.AddToScheme(scheme)
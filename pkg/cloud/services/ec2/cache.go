@@ -0,0 +1,390 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/golang/groupcache/singleflight"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	// describeCacheTTL is how long a describe result is reused. It is well below the default sync
+	// period, so that each periodic resync of a cluster's machines performs the lookups once.
+	describeCacheTTL = 2 * time.Minute
+
+	describeCacheSize = 4096
+
+	// instanceSnapshotTTL is how long a description of all the instances of a cluster is reused.
+	// It is kept short, as instances change state, and instances missing from it are looked up by ID.
+	instanceSnapshotTTL = 30 * time.Second
+
+	// describeInstanceStatusBatchSize is how many instances are named in each DescribeInstanceStatus call.
+	describeInstanceStatusBatchSize = 100
+
+	// describeTagsBatchSize is how many resources are named in the filter of each DescribeTags call.
+	describeTagsBatchSize = 200
+)
+
+var (
+	// describeCache is shared by the services of all the machines of all the clusters.
+	describeCache = cache.NewLRUExpireCache(describeCacheSize)
+
+	// describeGroup makes the services missing the same key in describeCache wait for a single lookup.
+	describeGroup singleflight.Group
+
+	// snapshotGenerations counts how often the description of each cluster's instances was forgotten,
+	// so that a description started before it was forgotten isn't cached or shared afterwards.
+	snapshotGenerations = &generations{counts: map[string]uint64{}}
+)
+
+// generations guards describeCache entries against lookups that were started before the entries were
+// forgotten: such lookups would otherwise add what they found before the change back to the cache.
+type generations struct {
+	sync.Mutex
+	counts map[string]uint64
+}
+
+// get returns the current generation of key.
+func (g *generations) get(key string) uint64 {
+	g.Lock()
+	defer g.Unlock()
+	return g.counts[key]
+}
+
+// add adds value to describeCache under cacheKey, unless key was forgotten since generation.
+func (g *generations) add(key string, generation uint64, cacheKey string, value interface{}, ttl time.Duration) {
+	g.Lock()
+	defer g.Unlock()
+	if g.counts[key] == generation {
+		describeCache.Add(cacheKey, value, ttl)
+	}
+}
+
+// forget starts a new generation of key and removes cacheKeys from describeCache.
+func (g *generations) forget(key string, cacheKeys ...string) {
+	g.Lock()
+	defer g.Unlock()
+	g.counts[key]++
+	for _, cacheKey := range cacheKeys {
+		describeCache.Remove(cacheKey)
+	}
+}
+
+// cachingEC2Client serves the instance type, subnet and security group lookups, whose results
+// seldom change, from a cache, so that hundreds of machines reconciling against the same cluster
+// don't each repeat them. Instances are always described afresh, as their state changes.
+type cachingEC2Client struct {
+	ec2iface.EC2API
+
+	cache     *cache.LRUExpireCache
+	group     *singleflight.Group
+	keyPrefix string
+}
+
+func (c *cachingEC2Client) key(operation string, input fmt.Stringer) string {
+	return fmt.Sprintf("%s/%s/%s", c.keyPrefix, operation, input.String())
+}
+
+// describe returns a copy of the cached output for key, or of the output of lookup, which concurrent
+// callers missing the same key share. Errors aren't cached.
+func (c *cachingEC2Client) describe(key string, lookup func() (interface{}, error)) (interface{}, error) {
+	if out, ok := c.cache.Get(key); ok {
+		return awsutil.CopyOf(out), nil
+	}
+
+	out, err := c.group.Do(key, func() (interface{}, error) {
+		out, err := lookup()
+		if err != nil {
+			return nil, err
+		}
+
+		c.cache.Add(key, awsutil.CopyOf(out), describeCacheTTL)
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return awsutil.CopyOf(out), nil
+}
+
+// DescribeInstanceTypes describes instance types through the cache.
+func (c *cachingEC2Client) DescribeInstanceTypes(input *ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+	out, err := c.describe(c.key("DescribeInstanceTypes", input), func() (interface{}, error) {
+		return c.EC2API.DescribeInstanceTypes(input)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(*ec2.DescribeInstanceTypesOutput), nil
+}
+
+// DescribeInstanceTypeOfferings describes instance type offerings through the cache.
+func (c *cachingEC2Client) DescribeInstanceTypeOfferings(input *ec2.DescribeInstanceTypeOfferingsInput) (*ec2.DescribeInstanceTypeOfferingsOutput, error) {
+	out, err := c.describe(c.key("DescribeInstanceTypeOfferings", input), func() (interface{}, error) {
+		return c.EC2API.DescribeInstanceTypeOfferings(input)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(*ec2.DescribeInstanceTypeOfferingsOutput), nil
+}
+
+// DescribeSubnets describes subnets through the cache.
+func (c *cachingEC2Client) DescribeSubnets(input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	out, err := c.describe(c.key("DescribeSubnets", input), func() (interface{}, error) {
+		return c.EC2API.DescribeSubnets(input)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(*ec2.DescribeSubnetsOutput), nil
+}
+
+// DescribeSecurityGroups describes security groups through the cache.
+func (c *cachingEC2Client) DescribeSecurityGroups(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	out, err := c.describe(c.key("DescribeSecurityGroups", input), func() (interface{}, error) {
+		return c.EC2API.DescribeSecurityGroups(input)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(*ec2.DescribeSecurityGroupsOutput), nil
+}
+
+// clusterInstance returns a copy of the instance with the given ID in the description of the cluster's
+// instances, or nil when it isn't in it.
+func (s *Service) clusterInstance(id string) (*ec2.Instance, error) {
+	instances, err := s.clusterInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	inst, ok := instances[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return awsutil.CopyOf(inst).(*ec2.Instance), nil
+}
+
+// clusterInstances describes all the non-terminated instances owned by the cluster in one paginated
+// call and returns them by instance ID. The result is shared through the cache with the other
+// services of the cluster, so that each machine reconcile doesn't describe its own instance. The
+// instances must not be changed, clusterInstance returns copies of them.
+func (s *Service) clusterInstances() (map[string]*ec2.Instance, error) {
+	if out, ok := describeCache.Get(s.instanceSnapshotKey); ok {
+		return out.(map[string]*ec2.Instance), nil
+	}
+
+	// Lookups started after the description was forgotten don't join those started before.
+	generation := snapshotGenerations.get(s.instanceSnapshotKey)
+	out, err := describeGroup.Do(fmt.Sprintf("%s@%d", s.instanceSnapshotKey, generation), func() (interface{}, error) {
+		return s.describeClusterInstances(generation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(map[string]*ec2.Instance), nil
+}
+
+func (s *Service) describeClusterInstances(generation uint64) (map[string]*ec2.Instance, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.InstanceStates(
+				ec2.InstanceStateNamePending,
+				ec2.InstanceStateNameRunning,
+				ec2.InstanceStateNameStopping,
+				ec2.InstanceStateNameStopped,
+				ec2.InstanceStateNameShuttingDown,
+			),
+		},
+	}
+
+	instances := map[string]*ec2.Instance{}
+	err := s.EC2Client.DescribeInstancesPages(input, func(out *ec2.DescribeInstancesOutput, last bool) bool {
+		for _, res := range out.Reservations {
+			for _, inst := range res.Instances {
+				instances[aws.StringValue(inst.InstanceId)] = inst
+			}
+		}
+		return true
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeInstances", "Failed to describe cluster instances: %v", err)
+		return nil, errors.Wrap(err, "failed to describe cluster instances")
+	}
+
+	snapshotGenerations.add(s.instanceSnapshotKey, generation, s.instanceSnapshotKey, instances, instanceSnapshotTTL)
+	return instances, nil
+}
+
+// clusterInstanceStatuses describes the statuses of the instances in the description of the cluster's
+// instances, in batches, and returns them by instance ID. Instances whose status isn't reported yet map
+// to nil. The result is shared through the cache like the description of the instances.
+func (s *Service) clusterInstanceStatuses() (map[string]*ec2.InstanceStatus, error) {
+	key := s.instanceSnapshotKey + "/statuses"
+	if out, ok := describeCache.Get(key); ok {
+		return out.(map[string]*ec2.InstanceStatus), nil
+	}
+
+	generation := snapshotGenerations.get(s.instanceSnapshotKey)
+	out, err := describeGroup.Do(fmt.Sprintf("%s@%d", key, generation), func() (interface{}, error) {
+		return s.describeClusterInstanceStatuses(key, generation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(map[string]*ec2.InstanceStatus), nil
+}
+
+func (s *Service) describeClusterInstanceStatuses(key string, generation uint64) (map[string]*ec2.InstanceStatus, error) {
+	instances, err := s.clusterInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]*ec2.InstanceStatus, len(instances))
+	ids := make([]string, 0, len(instances))
+	for id := range instances {
+		statuses[id] = nil
+		ids = append(ids, id)
+	}
+
+	for start := 0; start < len(ids); start += describeInstanceStatusBatchSize {
+		end := start + describeInstanceStatusBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		input := &ec2.DescribeInstanceStatusInput{
+			InstanceIds: aws.StringSlice(ids[start:end]),
+		}
+		err := s.EC2Client.DescribeInstanceStatusPages(input, func(out *ec2.DescribeInstanceStatusOutput, last bool) bool {
+			for _, status := range out.InstanceStatuses {
+				statuses[aws.StringValue(status.InstanceId)] = status
+			}
+			return true
+		})
+		if err != nil {
+			record.Eventf(s.scope.InfraCluster(), "FailedDescribeInstanceStatus", "Failed to describe cluster instance statuses: %v", err)
+			return nil, errors.Wrap(err, "failed to describe cluster instance statuses")
+		}
+	}
+
+	snapshotGenerations.add(s.instanceSnapshotKey, generation, key, statuses, instanceSnapshotTTL)
+	return statuses, nil
+}
+
+// clusterResourceTags describes the tags of the volumes and the primary network interfaces of the instances in
+// the description of the cluster's instances, in batches, and returns them by resource ID. Resources without
+// tags map to empty tags. The result is shared through the cache like the description of the instances, and
+// must not be changed.
+func (s *Service) clusterResourceTags() (map[string]infrav1.Tags, error) {
+	key := s.instanceSnapshotKey + "/tags"
+	if out, ok := describeCache.Get(key); ok {
+		return out.(map[string]infrav1.Tags), nil
+	}
+
+	generation := snapshotGenerations.get(s.instanceSnapshotKey)
+	out, err := describeGroup.Do(fmt.Sprintf("%s@%d", key, generation), func() (interface{}, error) {
+		return s.describeClusterResourceTags(key, generation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(map[string]infrav1.Tags), nil
+}
+
+func (s *Service) describeClusterResourceTags(key string, generation uint64) (map[string]infrav1.Tags, error) {
+	instances, err := s.clusterInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]infrav1.Tags{}
+	ids := []string{}
+	for _, inst := range instances {
+		for _, mapping := range inst.BlockDeviceMappings {
+			if mapping.Ebs != nil {
+				ids = append(ids, aws.StringValue(mapping.Ebs.VolumeId))
+			}
+		}
+		for _, eni := range inst.NetworkInterfaces {
+			if eni.Attachment != nil && aws.Int64Value(eni.Attachment.DeviceIndex) == 0 {
+				ids = append(ids, aws.StringValue(eni.NetworkInterfaceId))
+			}
+		}
+	}
+	for _, id := range ids {
+		tags[id] = infrav1.Tags{}
+	}
+
+	for start := 0; start < len(ids); start += describeTagsBatchSize {
+		end := start + describeTagsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		input := &ec2.DescribeTagsInput{
+			Filters: []*ec2.Filter{{Name: aws.String("resource-id"), Values: aws.StringSlice(ids[start:end])}},
+		}
+		err := s.EC2Client.DescribeTagsPages(input, func(out *ec2.DescribeTagsOutput, last bool) bool {
+			for _, tag := range out.Tags {
+				tags[aws.StringValue(tag.ResourceId)][aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			return true
+		})
+		if err != nil {
+			record.Eventf(s.scope.InfraCluster(), "FailedDescribeTags", "Failed to describe cluster volume and network interface tags: %v", err)
+			return nil, errors.Wrap(err, "failed to describe cluster volume and network interface tags")
+		}
+	}
+
+	snapshotGenerations.add(s.instanceSnapshotKey, generation, key, tags, instanceSnapshotTTL)
+	return tags, nil
+}
+
+// forgetClusterInstances drops the shared description of the cluster's instances after an instance
+// was changed, so that the next lookup sees the change. Descriptions still in flight aren't cached.
+func (s *Service) forgetClusterInstances() {
+	if s.instanceSnapshotKey != "" {
+		snapshotGenerations.forget(s.instanceSnapshotKey, s.instanceSnapshotKey, s.instanceSnapshotKey+"/statuses", s.instanceSnapshotKey+"/tags")
+	}
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/groupcache/singleflight"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/cache"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+)
+
+func TestCachingEC2Client(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("repeated lookups are served from the cache", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		client := &cachingEC2Client{EC2API: ec2Mock, cache: cache.NewLRUExpireCache(10), group: &singleflight.Group{}, keyPrefix: "default/test/us-east-1"}
+
+		input := &ec2.DescribeSubnetsInput{SubnetIds: aws.StringSlice([]string{"subnet-1"})}
+		ec2Mock.EXPECT().DescribeSubnets(gomock.Eq(input)).
+			Return(&ec2.DescribeSubnetsOutput{
+				Subnets: []*ec2.Subnet{{SubnetId: aws.String("subnet-1"), CidrBlock: aws.String("10.0.0.0/24")}},
+			}, nil).
+			Times(1)
+
+		out, err := client.DescribeSubnets(input)
+		g.Expect(err).NotTo(HaveOccurred())
+		// Callers changing the output must not change what the cache returns next.
+		out.Subnets[0].CidrBlock = aws.String("changed")
+
+		out, err = client.DescribeSubnets(&ec2.DescribeSubnetsInput{SubnetIds: aws.StringSlice([]string{"subnet-1"})})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(aws.StringValue(out.Subnets[0].CidrBlock)).To(Equal("10.0.0.0/24"))
+	})
+
+	t.Run("concurrent lookups share one call", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		client := &cachingEC2Client{EC2API: ec2Mock, cache: cache.NewLRUExpireCache(10), group: &singleflight.Group{}, keyPrefix: "default/test/us-east-1"}
+
+		input := &ec2.DescribeSubnetsInput{SubnetIds: aws.StringSlice([]string{"subnet-1"})}
+		ec2Mock.EXPECT().DescribeSubnets(gomock.Eq(input)).
+			DoAndReturn(func(*ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+				// Give the other lookups the time to wait for this one.
+				time.Sleep(100 * time.Millisecond)
+				return &ec2.DescribeSubnetsOutput{
+					Subnets: []*ec2.Subnet{{SubnetId: aws.String("subnet-1")}},
+				}, nil
+			}).
+			Times(1)
+
+		outs := make([]*ec2.DescribeSubnetsOutput, 10)
+		var wg sync.WaitGroup
+		for i := range outs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				outs[i], _ = client.DescribeSubnets(&ec2.DescribeSubnetsInput{SubnetIds: aws.StringSlice([]string{"subnet-1"})})
+			}(i)
+		}
+		wg.Wait()
+
+		for _, out := range outs {
+			g.Expect(out).NotTo(BeNil())
+			g.Expect(aws.StringValue(out.Subnets[0].SubnetId)).To(Equal("subnet-1"))
+		}
+		// Each caller gets its own copy.
+		g.Expect(outs[0]).NotTo(BeIdenticalTo(outs[1]))
+	})
+
+	t.Run("different lookups aren't mixed up", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		client := &cachingEC2Client{EC2API: ec2Mock, cache: cache.NewLRUExpireCache(10), group: &singleflight.Group{}, keyPrefix: "default/test/us-east-1"}
+
+		ec2Mock.EXPECT().DescribeInstanceTypes(gomock.Any()).
+			DoAndReturn(func(input *ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+				return &ec2.DescribeInstanceTypesOutput{
+					InstanceTypes: []*ec2.InstanceTypeInfo{{InstanceType: input.InstanceTypes[0]}},
+				}, nil
+			}).
+			Times(2)
+
+		for _, instanceType := range []string{"m5.large", "m6g.large", "m5.large", "m6g.large"} {
+			out, err := client.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{InstanceTypes: aws.StringSlice([]string{instanceType})})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(aws.StringValue(out.InstanceTypes[0].InstanceType)).To(Equal(instanceType))
+		}
+	})
+
+	t.Run("errors aren't cached", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		client := &cachingEC2Client{EC2API: ec2Mock, cache: cache.NewLRUExpireCache(10), group: &singleflight.Group{}, keyPrefix: "default/test/us-east-1"}
+
+		input := &ec2.DescribeSecurityGroupsInput{Filters: []*ec2.Filter{{Name: aws.String("tag:Name"), Values: aws.StringSlice([]string{"sg"})}}}
+		gomock.InOrder(
+			ec2Mock.EXPECT().DescribeSecurityGroups(gomock.Eq(input)).Return(nil, errors.New("some unknown error")),
+			ec2Mock.EXPECT().DescribeSecurityGroups(gomock.Eq(input)).Return(&ec2.DescribeSecurityGroupsOutput{}, nil),
+		)
+
+		_, err := client.DescribeSecurityGroups(input)
+		g.Expect(err).To(HaveOccurred())
+		_, err = client.DescribeSecurityGroups(input)
+		g.Expect(err).NotTo(HaveOccurred())
+	})
+}
+
+func TestClusterInstances(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	describePages := func(ids ...string) func(*ec2.DescribeInstancesInput, func(*ec2.DescribeInstancesOutput, bool) bool) error {
+		return func(_ *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+			for i, id := range ids {
+				fn(&ec2.DescribeInstancesOutput{
+					Reservations: []*ec2.Reservation{{
+						Instances: []*ec2.Instance{{
+							InstanceId: aws.String(id),
+							State:      &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+						}},
+					}},
+				}, i == len(ids)-1)
+			}
+			return nil
+		}
+	}
+
+	newService := func(t *testing.T, ec2Mock *mock_ec2iface.MockEC2API) *Service {
+		t.Helper()
+		scheme := runtime.NewScheme()
+		_ = infrav1.AddToScheme(scheme)
+		client := fake.NewClientBuilder().WithScheme(scheme).Build()
+		scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+			Client:     client,
+			Cluster:    &clusterv1.Cluster{},
+			AWSCluster: &infrav1.AWSCluster{},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create test context: %v", err)
+		}
+
+		s := NewService(scope)
+		s.EC2Client = ec2Mock
+		s.instanceSnapshotKey = t.Name()
+		return s
+	}
+
+	t.Run("machines are looked up in one description of the cluster's instances", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		s := newService(t, ec2Mock)
+
+		ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).
+			DoAndReturn(describePages("i-1", "i-2")).
+			Times(1)
+		ec2Mock.EXPECT().DescribeInstances(gomock.Eq(&ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{"i-3"})})).
+			Return(&ec2.DescribeInstancesOutput{}, nil).
+			Times(1)
+
+		for _, id := range []string{"i-1", "i-2"} {
+			instance, err := s.InstanceIfExists(aws.String(id))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(instance.ID).To(Equal(id))
+		}
+
+		instance, err := s.InstanceIfExists(aws.String("i-3"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(instance).To(BeNil())
+	})
+
+	t.Run("instances are copied out of the description", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		s := newService(t, ec2Mock)
+
+		ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).
+			DoAndReturn(describePages("i-1")).
+			Times(1)
+
+		inst, err := s.clusterInstance("i-1")
+		g.Expect(err).NotTo(HaveOccurred())
+		inst.State.Name = aws.String(ec2.InstanceStateNameStopped)
+
+		inst, err = s.clusterInstance("i-1")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(aws.StringValue(inst.State.Name)).To(Equal(ec2.InstanceStateNameRunning))
+	})
+
+	t.Run("terminating an instance refreshes the description", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		s := newService(t, ec2Mock)
+
+		gomock.InOrder(
+			ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).DoAndReturn(describePages("i-1", "i-2")),
+			ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).DoAndReturn(describePages("i-2")),
+		)
+		ec2Mock.EXPECT().TerminateInstances(gomock.Any()).Return(&ec2.TerminateInstancesOutput{}, nil)
+		ec2Mock.EXPECT().DescribeInstances(gomock.Any()).Return(&ec2.DescribeInstancesOutput{}, nil)
+
+		_, err := s.InstanceIfExists(aws.String("i-1"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(s.TerminateInstance("i-1")).To(Succeed())
+
+		instance, err := s.InstanceIfExists(aws.String("i-1"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(instance).To(BeNil())
+	})
+
+	t.Run("a description started before an instance was terminated isn't cached", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		s := newService(t, ec2Mock)
+
+		gomock.InOrder(
+			ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+					// Another machine's instance is terminated while the instances are described.
+					s.forgetClusterInstances()
+					return describePages("i-1", "i-2")(input, fn)
+				}),
+			ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).DoAndReturn(describePages("i-2")),
+		)
+
+		instances, err := s.clusterInstances()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(instances).To(HaveKey("i-1"))
+
+		instances, err = s.clusterInstances()
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(instances).NotTo(HaveKey("i-1"))
+	})
+
+	t.Run("system statuses are described together for the cluster's instances", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		s := newService(t, ec2Mock)
+
+		ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).
+			DoAndReturn(describePages("i-1", "i-2")).
+			Times(1)
+		ec2Mock.EXPECT().DescribeInstanceStatusPages(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(input *ec2.DescribeInstanceStatusInput, fn func(*ec2.DescribeInstanceStatusOutput, bool) bool) error {
+				g.Expect(aws.StringValueSlice(input.InstanceIds)).To(ConsistOf("i-1", "i-2"))
+				fn(&ec2.DescribeInstanceStatusOutput{
+					InstanceStatuses: []*ec2.InstanceStatus{{
+						InstanceId:   aws.String("i-2"),
+						SystemStatus: &ec2.InstanceStatusSummary{Status: aws.String(ec2.SummaryStatusImpaired)},
+					}},
+				}, true)
+				return nil
+			}).
+			Times(1)
+		ec2Mock.EXPECT().DescribeInstanceStatus(gomock.Eq(&ec2.DescribeInstanceStatusInput{InstanceIds: aws.StringSlice([]string{"i-3"})})).
+			Return(&ec2.DescribeInstanceStatusOutput{}, nil).
+			Times(1)
+
+		for id, expected := range map[string]bool{"i-1": false, "i-2": true, "i-3": false} {
+			impaired, err := s.InstanceSystemStatusImpaired(id)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(impaired).To(Equal(expected), id)
+		}
+	})
+
+	t.Run("launch volume and network interface tags are described together for the cluster's instances", func(t *testing.T) {
+		g := NewWithT(t)
+		ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+		s := newService(t, ec2Mock)
+
+		instance := func(id string, deviceNames ...string) *ec2.Instance {
+			inst := &ec2.Instance{
+				InstanceId:     aws.String(id),
+				RootDeviceName: aws.String("/dev/xvda"),
+				NetworkInterfaces: []*ec2.InstanceNetworkInterface{
+					{NetworkInterfaceId: aws.String("eni-" + id), Attachment: &ec2.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int64(0)}},
+					{NetworkInterfaceId: aws.String("eni-pods-" + id), Attachment: &ec2.InstanceNetworkInterfaceAttachment{DeviceIndex: aws.Int64(1)}},
+				},
+			}
+			for _, name := range deviceNames {
+				inst.BlockDeviceMappings = append(inst.BlockDeviceMappings, &ec2.InstanceBlockDeviceMapping{
+					DeviceName: aws.String(name),
+					Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String(fmt.Sprintf("vol-%s%s", id, name))},
+				})
+			}
+			return inst
+		}
+
+		ec2Mock.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+				fn(&ec2.DescribeInstancesOutput{
+					Reservations: []*ec2.Reservation{{
+						Instances: []*ec2.Instance{
+							instance("i-1", "/dev/xvda", "/dev/sdb", "/dev/xvdba"),
+							instance("i-2", "/dev/xvda"),
+						},
+					}},
+				}, true)
+				return nil
+			}).
+			Times(1)
+		ec2Mock.EXPECT().DescribeTagsPages(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(input *ec2.DescribeTagsInput, fn func(*ec2.DescribeTagsOutput, bool) bool) error {
+				g.Expect(input.Filters).To(HaveLen(1))
+				g.Expect(aws.StringValueSlice(input.Filters[0].Values)).To(ConsistOf(
+					"vol-i-1/dev/xvda", "vol-i-1/dev/sdb", "vol-i-1/dev/xvdba", "eni-i-1",
+					"vol-i-2/dev/xvda", "eni-i-2",
+				))
+				fn(&ec2.DescribeTagsOutput{
+					Tags: []*ec2.TagDescription{
+						{ResourceId: aws.String("vol-i-1/dev/xvda"), Key: aws.String("team"), Value: aws.String("platform")},
+						{ResourceId: aws.String("eni-i-1"), Key: aws.String("team"), Value: aws.String("infra")},
+					},
+				}, true)
+				return nil
+			}).
+			Times(1)
+
+		volumes, networkInterfaces, err := s.GetLaunchResourceTags("i-1", []infrav1.Volume{{DeviceName: "/dev/sdb"}})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(volumes).To(Equal(map[string]infrav1.Tags{
+			"vol-i-1/dev/xvda": {"team": "platform"},
+			"vol-i-1/dev/sdb":  {},
+		}))
+		g.Expect(networkInterfaces).To(Equal(map[string]infrav1.Tags{"eni-i-1": {"team": "infra"}}))
+
+		volumes, networkInterfaces, err = s.GetLaunchResourceTags("i-2", nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(volumes).To(Equal(map[string]infrav1.Tags{"vol-i-2/dev/xvda": {}}))
+		g.Expect(networkInterfaces).To(Equal(map[string]infrav1.Tags{"eni-i-2": {}}))
+
+		// Instances launched since the description aren't looked up.
+		volumes, networkInterfaces, err = s.GetLaunchResourceTags("i-3", nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(volumes).To(BeNil())
+		g.Expect(networkInterfaces).To(BeNil())
+	})
+}
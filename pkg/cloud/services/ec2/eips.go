@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// AssociateControlPlaneElasticIP associates an Elastic IP address with a control plane instance,
+// reusing an address left behind by a deleted instance before allocating a new one.
+// The addresses are tagged as owned by the cluster, so they are released along with the cluster network.
+func (s *Service) AssociateControlPlaneElasticIP(instance *infrav1.Instance) error {
+	// Traffic to an Elastic IP only reaches instances in subnets routed through an internet gateway.
+	if subnet := s.scope.Subnets().FindByID(instance.SubnetID); subnet != nil && !subnet.IsPublic {
+		record.Warnf(s.scope.InfraCluster(), "FailedAssociateEIP", "Control plane instance %q is in private subnet %q, Elastic IPs require a public subnet", instance.ID, instance.SubnetID)
+		return errors.Errorf("control plane instance %q is in private subnet %q, Elastic IPs require a public subnet", instance.ID, instance.SubnetID)
+	}
+
+	out, err := s.describeControlPlaneAddresses()
+	if err != nil {
+		return err
+	}
+
+	var allocationID *string
+	for _, address := range out.Addresses {
+		if aws.StringValue(address.InstanceId) == instance.ID {
+			return nil
+		}
+		if address.AssociationId == nil && allocationID == nil {
+			allocationID = address.AllocationId
+		}
+	}
+
+	if allocationID == nil {
+		allocation, err := s.EC2Client.AllocateAddress(&ec2.AllocateAddressInput{
+			Domain: aws.String("vpc"),
+			TagSpecifications: []*ec2.TagSpecification{
+				tags.BuildParamsToTagSpecification(ec2.ResourceTypeElasticIp, s.getControlPlaneEIPTagParams()),
+			},
+		})
+		if err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedAllocateEIP", "Failed to allocate Elastic IP for control plane: %v", err)
+			return errors.Wrap(err, "failed to allocate Elastic IP for control plane")
+		}
+		allocationID = allocation.AllocationId
+	}
+
+	// Reassociation is not allowed so that two control plane instances racing for the
+	// same address can't steal it from each other; the loser retries on the next reconcile.
+	if _, err := s.EC2Client.AssociateAddress(&ec2.AssociateAddressInput{
+		AllocationId:       allocationID,
+		InstanceId:         aws.String(instance.ID),
+		AllowReassociation: aws.Bool(false),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedAssociateEIP", "Failed to associate Elastic IP %q with instance %q: %v", aws.StringValue(allocationID), instance.ID, err)
+		return errors.Wrapf(err, "failed to associate Elastic IP %q with instance %q", aws.StringValue(allocationID), instance.ID)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulAssociateEIP", "Associated Elastic IP %q with control plane instance %q", aws.StringValue(allocationID), instance.ID)
+	return nil
+}
+
+// ReconcileControlPlaneElasticIPs records the Elastic IP addresses allocated for control plane instances in the cluster status.
+func (s *Service) ReconcileControlPlaneElasticIPs() error {
+	if !s.scope.ControlPlaneElasticIPs() {
+		return nil
+	}
+
+	out, err := s.describeControlPlaneAddresses()
+	if err != nil {
+		return err
+	}
+
+	allocationIDs := make([]string, 0, len(out.Addresses))
+	for _, address := range out.Addresses {
+		allocationIDs = append(allocationIDs, aws.StringValue(address.AllocationId))
+	}
+	s.scope.Network().ControlPlaneElasticIPs = allocationIDs
+
+	return nil
+}
+
+func (s *Service) describeControlPlaneAddresses() (*ec2.DescribeAddressesOutput, error) {
+	out, err := s.EC2Client.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.Cluster(s.scope.Name()),
+			filter.EC2.ProviderRole(infrav1.APIServerRoleTagValue),
+		},
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeAddresses", "Failed to query control plane addresses: %v", err)
+		return nil, errors.Wrap(err, "failed to query control plane addresses")
+	}
+
+	return out, nil
+}
+
+func (s *Service) getControlPlaneEIPTagParams() infrav1.BuildParams {
+	name := fmt.Sprintf("%s-eip-%s", s.scope.Name(), infrav1.APIServerRoleTagValue)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.APIServerRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
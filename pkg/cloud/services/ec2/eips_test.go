@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAssociateControlPlaneElasticIP(t *testing.T) {
+	clusterName := "cluster"
+
+	describeInput := &ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.Cluster(clusterName),
+			filter.EC2.ProviderRole(infrav1.APIServerRoleTagValue),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		subnetID string
+		expect   func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		wantErr  bool
+	}{
+		{
+			name: "address already associated with the instance",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{
+								AllocationId:  aws.String("eipalloc-1"),
+								AssociationId: aws.String("eipassoc-1"),
+								InstanceId:    aws.String("id123"),
+							},
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "reuses an unassociated address",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{
+								AllocationId:  aws.String("eipalloc-1"),
+								AssociationId: aws.String("eipassoc-1"),
+								InstanceId:    aws.String("id456"),
+							},
+							{
+								AllocationId: aws.String("eipalloc-2"),
+							},
+						},
+					}, nil)
+				m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+					AllocationId:       aws.String("eipalloc-2"),
+					InstanceId:         aws.String("id123"),
+					AllowReassociation: aws.Bool(false),
+				})).
+					Return(&ec2.AssociateAddressOutput{}, nil)
+			},
+		},
+		{
+			name: "allocates a new address when none is free",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeAddressesOutput{}, nil)
+				m.AllocateAddress(gomock.AssignableToTypeOf(&ec2.AllocateAddressInput{})).
+					Return(&ec2.AllocateAddressOutput{
+						AllocationId: aws.String("eipalloc-3"),
+					}, nil)
+				m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+					AllocationId:       aws.String("eipalloc-3"),
+					InstanceId:         aws.String("id123"),
+					AllowReassociation: aws.Bool(false),
+				})).
+					Return(&ec2.AssociateAddressOutput{}, nil)
+			},
+		},
+		{
+			name:     "associates an address with an instance in a public subnet",
+			subnetID: "subnet-public",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeAddresses(gomock.Eq(describeInput)).
+					Return(&ec2.DescribeAddressesOutput{
+						Addresses: []*ec2.Address{
+							{
+								AllocationId: aws.String("eipalloc-2"),
+							},
+						},
+					}, nil)
+				m.AssociateAddress(gomock.Eq(&ec2.AssociateAddressInput{
+					AllocationId:       aws.String("eipalloc-2"),
+					InstanceId:         aws.String("id123"),
+					AllowReassociation: aws.Bool(false),
+				})).
+					Return(&ec2.AssociateAddressOutput{}, nil)
+			},
+		},
+		{
+			name:     "refuses an instance in a private subnet",
+			subnetID: "subnet-private",
+			expect:   func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockControl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).To(BeNil())
+
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					ControlPlaneElasticIPs: true,
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							{ID: "subnet-public", IsPublic: true},
+							{ID: "subnet-private"},
+						},
+					},
+				},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			ctx := context.TODO()
+			client.Create(ctx, awsCluster)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns",
+						Name:      clusterName,
+					},
+				},
+				AWSCluster: awsCluster,
+				Client:     client,
+			})
+			g.Expect(err).To(BeNil())
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			err = s.AssociateControlPlaneElasticIP(&infrav1.Instance{ID: "id123", SubnetID: tc.subnetID})
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(BeNil())
+		})
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	expinfrav1 "sigs.k8s.io/cluster-api-provider-aws/exp/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+)
+
+// runFleetInstance launches the instance through an EC2 Fleet of type instant, which picks its instance
+// type and subnet among the overrides of the fleet. CreateFleet only takes its launch parameters from a
+// launch template, so a temporary one is created from the instance and deleted once the fleet returns.
+func (s *Service) runFleetInstance(role string, i *infrav1.Instance, fleet *infrav1.Fleet) (*infrav1.Instance, error) {
+	s.scope.V(2).Info("userData size", "bytes", len(*i.UserData), "role", role)
+
+	data, err := s.fleetLaunchTemplateData(i)
+	if err != nil {
+		return nil, err
+	}
+
+	// The template is named after the instance, so that one left behind by a previous attempt is replaced.
+	templateName := i.Tags["Name"] + "-fleet"
+	templateInput := &ec2.CreateLaunchTemplateInput{
+		LaunchTemplateData: data,
+		LaunchTemplateName: aws.String(templateName),
+	}
+	if _, err := s.EC2Client.CreateLaunchTemplate(templateInput); err != nil {
+		if !awserrors.IsLaunchTemplateNameExists(err) {
+			return nil, errors.Wrapf(err, "failed to create launch template %q for fleet", templateName)
+		}
+		if err := s.deleteFleetLaunchTemplate(templateName); err != nil {
+			return nil, err
+		}
+		if _, err := s.EC2Client.CreateLaunchTemplate(templateInput); err != nil {
+			return nil, errors.Wrapf(err, "failed to create launch template %q for fleet", templateName)
+		}
+	}
+	defer func() {
+		if err := s.deleteFleetLaunchTemplate(templateName); err != nil {
+			s.scope.Info("Failed to delete launch template of fleet, it is replaced on the next launch", "name", templateName, "error", err.Error())
+		}
+	}()
+
+	out, err := s.EC2Client.CreateFleet(getCreateFleetInput(i, fleet, templateName))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create fleet")
+	}
+
+	var instanceID *string
+	for _, instance := range out.Instances {
+		if len(instance.InstanceIds) > 0 {
+			instanceID = instance.InstanceIds[0]
+			break
+		}
+	}
+	if instanceID == nil {
+		return nil, fleetErrors(out.Errors)
+	}
+
+	s.waitUntilInstanceRunning(instanceID)
+
+	instance, err := s.InstanceIfExists(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.Errorf("instance %q launched by fleet %q not found", aws.StringValue(instanceID), aws.StringValue(out.FleetId))
+	}
+
+	return instance, nil
+}
+
+// fleetLaunchTemplateData returns the launch parameters of the instance that don't depend on the
+// instance type and subnet, which are set by the overrides of the fleet.
+func (s *Service) fleetLaunchTemplateData(i *infrav1.Instance) (*ec2.RequestLaunchTemplateData, error) {
+	data := &ec2.RequestLaunchTemplateData{
+		ImageId:      aws.String(i.ImageID),
+		KeyName:      i.SSHKeyName,
+		EbsOptimized: i.EBSOptimized,
+		UserData:     i.UserData,
+	}
+
+	if len(i.SecurityGroupIDs) > 0 {
+		data.SecurityGroupIds = aws.StringSlice(i.SecurityGroupIDs)
+	}
+
+	if i.IAMProfile != "" {
+		data.IamInstanceProfile = &ec2.LaunchTemplateIamInstanceProfileSpecificationRequest{
+			Name: aws.String(i.IAMProfile),
+		}
+	}
+
+	if i.RootVolume != nil {
+		rootDeviceName, err := s.checkRootVolume(i.RootVolume, i.ImageID)
+		if err != nil {
+			return nil, err
+		}
+
+		i.RootVolume.DeviceName = aws.StringValue(rootDeviceName)
+		data.BlockDeviceMappings = append(data.BlockDeviceMappings, volumeToLaunchTemplateBlockDeviceMappingRequest(i.RootVolume))
+	}
+
+	for vi := range i.NonRootVolumes {
+		nonRootVolume := i.NonRootVolumes[vi]
+
+		if nonRootVolume.DeviceName == "" {
+			return nil, errors.Errorf("non root volume should have device name specified")
+		}
+
+		data.BlockDeviceMappings = append(data.BlockDeviceMappings, volumeToLaunchTemplateBlockDeviceMappingRequest(&nonRootVolume))
+	}
+
+	if len(i.Tags) > 0 {
+		spec := &ec2.LaunchTemplateTagSpecificationRequest{ResourceType: aws.String(ec2.ResourceTypeInstance)}
+		// We need to sort keys for tests to work
+		keys := make([]string, 0, len(i.Tags))
+		for k := range i.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			spec.Tags = append(spec.Tags, &ec2.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(i.Tags[key]),
+			})
+		}
+
+		data.TagSpecifications = append(data.TagSpecifications, spec)
+	}
+
+	if placement := getPlacement(i); placement != nil {
+		data.Placement = &ec2.LaunchTemplatePlacementRequest{
+			Tenancy:              placement.Tenancy,
+			HostId:               placement.HostId,
+			HostResourceGroupArn: placement.HostResourceGroupArn,
+			GroupName:            placement.GroupName,
+		}
+	}
+
+	if i.TerminationProtection {
+		data.DisableApiTermination = aws.Bool(true)
+	}
+
+	if i.DetailedMonitoring {
+		data.Monitoring = &ec2.LaunchTemplatesMonitoringRequest{
+			Enabled: aws.Bool(true),
+		}
+	}
+
+	if i.CPUOptions != nil {
+		data.CpuOptions = &ec2.LaunchTemplateCpuOptionsRequest{
+			CoreCount:      aws.Int64(i.CPUOptions.CoreCount),
+			ThreadsPerCore: aws.Int64(i.CPUOptions.ThreadsPerCore),
+		}
+	}
+
+	if i.CreditSpecification != nil {
+		data.CreditSpecification = &ec2.CreditSpecificationRequest{
+			CpuCredits: aws.String(string(i.CreditSpecification.CPUCredits)),
+		}
+	}
+
+	return data, nil
+}
+
+// getCreateFleetInput returns the request of an instant fleet launching a single instance from the
+// launch template. Spot instances are requested when the instance has spot market options.
+func getCreateFleetInput(i *infrav1.Instance, fleet *infrav1.Fleet, templateName string) *ec2.CreateFleetInput {
+	var maxPrice *string
+	if i.SpotMarketOptions != nil && aws.StringValue(i.SpotMarketOptions.MaxPrice) != "" {
+		maxPrice = i.SpotMarketOptions.MaxPrice
+	}
+
+	overrides := make([]*ec2.FleetLaunchTemplateOverridesRequest, 0, len(fleet.Overrides))
+	for _, override := range fleet.Overrides {
+		instanceType := override.InstanceType
+		if instanceType == "" {
+			instanceType = i.Type
+		}
+		subnetID := override.SubnetID
+		if subnetID == "" {
+			subnetID = i.SubnetID
+		}
+		overrides = append(overrides, &ec2.FleetLaunchTemplateOverridesRequest{
+			InstanceType: aws.String(instanceType),
+			SubnetId:     aws.String(subnetID),
+			MaxPrice:     maxPrice,
+		})
+	}
+
+	input := &ec2.CreateFleetInput{
+		Type: aws.String(ec2.FleetTypeInstant),
+		LaunchTemplateConfigs: []*ec2.FleetLaunchTemplateConfigRequest{
+			{
+				LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecificationRequest{
+					LaunchTemplateName: aws.String(templateName),
+					Version:            aws.String(expinfrav1.LaunchTemplateLatestVersion),
+				},
+				Overrides: overrides,
+			},
+		},
+		TargetCapacitySpecification: &ec2.TargetCapacitySpecificationRequest{
+			TotalTargetCapacity: aws.Int64(1),
+		},
+	}
+
+	allocationStrategy := fleet.AllocationStrategy
+	if allocationStrategy == "" {
+		allocationStrategy = infrav1.FleetAllocationStrategyLowestPrice
+	}
+
+	if i.SpotMarketOptions != nil {
+		input.TargetCapacitySpecification.DefaultTargetCapacityType = aws.String(ec2.DefaultTargetCapacityTypeSpot)
+		input.TargetCapacitySpecification.SpotTargetCapacity = aws.Int64(1)
+		// Interrupted instances are terminated and not replaced, as for instances launched without a fleet.
+		input.SpotOptions = &ec2.SpotOptionsRequest{
+			AllocationStrategy:           aws.String(string(allocationStrategy)),
+			InstanceInterruptionBehavior: aws.String(ec2.SpotInstanceInterruptionBehaviorTerminate),
+		}
+	} else {
+		input.TargetCapacitySpecification.DefaultTargetCapacityType = aws.String(ec2.DefaultTargetCapacityTypeOnDemand)
+		input.TargetCapacitySpecification.OnDemandTargetCapacity = aws.Int64(1)
+		input.OnDemandOptions = &ec2.OnDemandOptionsRequest{
+			AllocationStrategy: aws.String(ec2.FleetOnDemandAllocationStrategyLowestPrice),
+		}
+	}
+
+	return input
+}
+
+func (s *Service) deleteFleetLaunchTemplate(name string) error {
+	if _, err := s.EC2Client.DeleteLaunchTemplate(&ec2.DeleteLaunchTemplateInput{
+		LaunchTemplateName: aws.String(name),
+	}); err != nil && !awserrors.IsInvalidNotFoundError(err) {
+		return errors.Wrapf(err, "failed to delete launch template %q", name)
+	}
+	return nil
+}
+
+// fleetErrors returns the reasons an instant fleet launched no instance.
+func fleetErrors(fleetErrs []*ec2.CreateFleetError) error {
+	if len(fleetErrs) == 0 {
+		return errors.New("fleet launched no instance")
+	}
+
+	reasons := make([]string, 0, len(fleetErrs))
+	for _, fleetErr := range fleetErrs {
+		reason := aws.StringValue(fleetErr.ErrorCode) + ": " + aws.StringValue(fleetErr.ErrorMessage)
+		if fleetErr.LaunchTemplateAndOverrides != nil && fleetErr.LaunchTemplateAndOverrides.Overrides != nil {
+			overrides := fleetErr.LaunchTemplateAndOverrides.Overrides
+			reason = aws.StringValue(overrides.InstanceType) + " in " + aws.StringValue(overrides.SubnetId) + ": " + reason
+		}
+		reasons = append(reasons, reason)
+	}
+
+	return errors.Errorf("fleet launched no instance: %s", strings.Join(reasons, "; "))
+}
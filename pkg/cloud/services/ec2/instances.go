@@ -19,7 +19,9 @@ package ec2
 import (
 	"context"
 	"encoding/base64"
+	"encoding/binary"
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 	"time"
@@ -28,6 +30,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
@@ -84,6 +87,18 @@ func (s *Service) InstanceIfExists(id *string) (*infrav1.Instance, error) {
 
 	s.scope.V(2).Info("Looking for instance by id", "instance-id", *id)
 
+	if s.instanceSnapshotKey != "" {
+		inst, err := s.clusterInstance(*id)
+		if err != nil {
+			return nil, err
+		}
+		// Instances launched since the snapshot was taken, terminated ones, and those that lost
+		// their tags are looked up by ID below.
+		if inst != nil {
+			return s.SDKToInstance(inst)
+		}
+	}
+
 	input := &ec2.DescribeInstancesInput{
 		InstanceIds: []*string{id},
 	}
@@ -104,6 +119,43 @@ func (s *Service) InstanceIfExists(id *string) (*infrav1.Instance, error) {
 	return nil, nil
 }
 
+// DiscoverInstanceAMI returns the AMI a new instance of the machine is launched with: the AMI set in
+// the machine configuration, or the latest matching AMI looked up for the machine's Kubernetes version.
+func (s *Service) DiscoverInstanceAMI(scope *scope.MachineScope) (string, error) {
+	if scope.AWSMachine.Spec.AMI.ID != nil {
+		return *scope.AWSMachine.Spec.AMI.ID, nil
+	}
+
+	if scope.AWSMachine.Spec.AMI.SSMParameterName != nil {
+		return s.ssmAMILookup(*scope.AWSMachine.Spec.AMI.SSMParameterName)
+	}
+
+	if scope.Machine.Spec.Version == nil {
+		return "", errors.New("Either AWSMachine's spec.ami.id or Machine's spec.version must be defined")
+	}
+
+	imageLookupFormat := scope.AWSMachine.Spec.ImageLookupFormat
+	if imageLookupFormat == "" {
+		imageLookupFormat = scope.InfraCluster.ImageLookupFormat()
+	}
+
+	imageLookupOrg := scope.AWSMachine.Spec.ImageLookupOrg
+	if imageLookupOrg == "" {
+		imageLookupOrg = scope.InfraCluster.ImageLookupOrg()
+	}
+
+	imageLookupBaseOS := scope.AWSMachine.Spec.ImageLookupBaseOS
+	if imageLookupBaseOS == "" {
+		imageLookupBaseOS = scope.InfraCluster.ImageLookupBaseOS()
+	}
+
+	if scope.IsEKSManaged() && imageLookupFormat == "" && imageLookupOrg == "" && imageLookupBaseOS == "" {
+		return s.eksAMILookupForInstanceType(*scope.Machine.Spec.Version, scope.AWSMachine.Spec.InstanceType, scope.AWSMachine.Spec.AMI.EKSOptimizedLookupType)
+	}
+
+	return s.defaultAMIIDLookupForInstanceType(imageLookupFormat, imageLookupOrg, imageLookupBaseOS, scope.AWSMachine.Spec.InstanceType, *scope.Machine.Spec.Version)
+}
+
 // CreateInstance runs an ec2 instance.
 func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*infrav1.Instance, error) {
 	s.scope.V(2).Info("Creating an instance for a machine")
@@ -114,58 +166,30 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 		RootVolume:        scope.AWSMachine.Spec.RootVolume,
 		NonRootVolumes:    scope.AWSMachine.Spec.NonRootVolumes,
 		NetworkInterfaces: scope.AWSMachine.Spec.NetworkInterfaces,
+		PublicIPOnLaunch:  scope.AWSMachine.Spec.PublicIP,
 	}
 
-	// Make sure to use the MachineScope here to get the merger of AWSCluster and AWSMachine tags
-	additionalTags := scope.AdditionalTags()
-
-	input.Tags = infrav1.Build(infrav1.BuildParams{
-		ClusterName: s.scope.Name(),
-		Lifecycle:   infrav1.ResourceLifecycleOwned,
-		Name:        aws.String(scope.Name()),
-		Role:        aws.String(scope.Role()),
-		Additional:  additionalTags,
-	}.WithCloudProvider(s.scope.Name()).WithMachineName(scope.Machine))
-
-	var err error
-	// Pick image from the machine configuration, or use a default one.
-	if scope.AWSMachine.Spec.AMI.ID != nil { // nolint:nestif
-		input.ImageID = *scope.AWSMachine.Spec.AMI.ID
-	} else {
-		if scope.Machine.Spec.Version == nil {
-			err := errors.New("Either AWSMachine's spec.ami.id or Machine's spec.version must be defined")
-			scope.SetFailureReason(capierrors.CreateMachineError)
-			scope.SetFailureMessage(err)
-			return nil, err
-		}
-
-		imageLookupFormat := scope.AWSMachine.Spec.ImageLookupFormat
-		if imageLookupFormat == "" {
-			imageLookupFormat = scope.InfraCluster.ImageLookupFormat()
-		}
+	input.Tags = s.getInstanceTags(scope)
 
-		imageLookupOrg := scope.AWSMachine.Spec.ImageLookupOrg
-		if imageLookupOrg == "" {
-			imageLookupOrg = scope.InfraCluster.ImageLookupOrg()
-		}
+	if aws.BoolValue(input.PublicIPOnLaunch) && s.scope.VPC().IsIsolated() {
+		err := errors.New("AWSMachine's spec.publicIP cannot be set in an isolated VPC")
+		scope.SetFailureReason(capierrors.CreateMachineError)
+		scope.SetFailureMessage(err)
+		return nil, err
+	}
 
-		imageLookupBaseOS := scope.AWSMachine.Spec.ImageLookupBaseOS
-		if imageLookupBaseOS == "" {
-			imageLookupBaseOS = scope.InfraCluster.ImageLookupBaseOS()
-		}
+	if scope.AWSMachine.Spec.AMI.ID == nil && scope.AWSMachine.Spec.AMI.SSMParameterName == nil && scope.Machine.Spec.Version == nil {
+		err := errors.New("Either AWSMachine's spec.ami.id or Machine's spec.version must be defined")
+		scope.SetFailureReason(capierrors.CreateMachineError)
+		scope.SetFailureMessage(err)
+		return nil, err
+	}
 
-		if scope.IsEKSManaged() && imageLookupFormat == "" && imageLookupOrg == "" && imageLookupBaseOS == "" {
-			input.ImageID, err = s.eksAMILookup(*scope.Machine.Spec.Version, scope.AWSMachine.Spec.AMI.EKSOptimizedLookupType)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			input.ImageID, err = s.defaultAMIIDLookup(imageLookupFormat, imageLookupOrg, imageLookupBaseOS, *scope.Machine.Spec.Version)
-			if err != nil {
-				return nil, err
-			}
-		}
+	imageID, err := s.DiscoverInstanceAMI(scope)
+	if err != nil {
+		return nil, err
 	}
+	input.ImageID = imageID
 
 	subnetID, err := s.findSubnet(scope)
 	if err != nil {
@@ -173,6 +197,41 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	}
 	input.SubnetID = subnetID
 
+	if scope.AWSMachine.Spec.PrivateIP != nil {
+		if err := s.checkPrivateIP(*scope.AWSMachine.Spec.PrivateIP, subnetID); err != nil {
+			if awserrors.IsConflict(err) {
+				record.Warnf(scope.AWSMachine, "FailedCreate", "Failed to create instance: %v", err)
+				scope.SetFailureReason(capierrors.CreateMachineError)
+				scope.SetFailureMessage(err)
+			}
+			return nil, err
+		}
+		input.PrivateIP = scope.AWSMachine.Spec.PrivateIP
+	}
+
+	// Only launch the instance with the instance type and fallback instance types that are offered in the
+	// availability zone the instance is launched into, instead of surfacing the less helpful error returned
+	// by RunInstances. The machine only fails for good when none of them is offered.
+	// With a fleet, EC2 Fleet chooses among the instance types of its overrides instead, and reports
+	// those that can't be launched.
+	instanceTypes := append([]string{input.Type}, scope.AWSMachine.Spec.FallbackInstanceTypes...)
+	if availabilityZone := s.getAvailabilityZone(scope, subnetID); availabilityZone != "" && scope.AWSMachine.Spec.Fleet == nil {
+		offered, err := s.offeredInstanceTypes(instanceTypes, availabilityZone)
+		if err != nil {
+			if awserrors.IsNotFound(err) {
+				record.Warnf(scope.AWSMachine, "InstanceTypeNotOffered", "Failed to create instance: %v", err)
+				scope.SetFailureReason(capierrors.CreateMachineError)
+				scope.SetFailureMessage(err)
+			}
+			return nil, err
+		}
+		if len(offered) < len(instanceTypes) {
+			record.Warnf(scope.AWSMachine, "InstanceTypeNotOffered", "Skipping instance types not offered in availability zone %q, launching with %q", availabilityZone, offered)
+		}
+		instanceTypes = offered
+	}
+	input.Type = instanceTypes[0]
+
 	if !scope.IsExternallyManaged() && !scope.IsEKSManaged() && s.scope.Network().APIServerELB.DNSName == "" {
 		record.Eventf(s.scope.InfraCluster(), "FailedCreateInstance", "Failed to run controlplane, APIServer ELB not available")
 
@@ -194,6 +253,12 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	}
 	input.SecurityGroupIDs = append(input.SecurityGroupIDs, ids...)
 
+	additionalIDs, err := GetAdditionalSecurityGroupsIDs(s, scope.AWSMachine.Spec.AdditionalSecurityGroups)
+	if err != nil {
+		return nil, err
+	}
+	input.SecurityGroupIDs = append(input.SecurityGroupIDs, additionalIDs...)
+
 	// If SSHKeyName WAS NOT provided in the AWSMachine Spec, fallback to the value provided in the AWSCluster Spec.
 	// If a value was not provided in the AWSCluster Spec, then use the defaultSSHKeyName
 	// Note that:
@@ -223,9 +288,50 @@ func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte) (*i
 	input.SpotMarketOptions = scope.AWSMachine.Spec.SpotMarketOptions
 
 	input.Tenancy = scope.AWSMachine.Spec.Tenancy
+	input.HostID = scope.AWSMachine.Spec.HostID
+	input.HostResourceGroupArn = scope.AWSMachine.Spec.HostResourceGroupArn
+
+	input.TerminationProtection = aws.BoolValue(scope.AWSMachine.Spec.TerminationProtection)
+
+	if scope.AWSMachine.Spec.PlacementGroup != nil {
+		if err := s.reconcilePlacementGroup(scope.AWSMachine.Spec.PlacementGroup); err != nil {
+			return nil, err
+		}
+		input.PlacementGroupName = scope.AWSMachine.Spec.PlacementGroup.Name
+	}
+
+	input.CapacityReservation = scope.AWSMachine.Spec.CapacityReservation
+	input.DetailedMonitoring = scope.AWSMachine.Spec.DetailedMonitoring
+	input.CPUOptions = scope.AWSMachine.Spec.CPUOptions
+	input.CreditSpecification = scope.AWSMachine.Spec.CreditSpecification
+	input.ElasticFabricAdapter = scope.AWSMachine.Spec.ElasticFabricAdapter
+
+	if fleet := scope.AWSMachine.Spec.Fleet; fleet != nil {
+		s.scope.V(2).Info("Running instance through a fleet", "machine-role", scope.Role())
+		out, err := s.runFleetInstance(scope.Role(), input, fleet)
+		if err != nil {
+			if !awserrors.IsFailedDependency(errors.Cause(err)) {
+				record.Warnf(scope.AWSMachine, "FailedCreate", "Failed to create instance: %v", err)
+			}
+			return nil, err
+		}
+
+		record.Eventf(scope.AWSMachine, "SuccessfulCreate", "Created new %s instance with id %q and instance type %q through a fleet", scope.Role(), out.ID, out.Type)
+		return out, nil
+	}
 
 	s.scope.V(2).Info("Running instance", "machine-role", scope.Role())
-	out, err := s.runInstance(scope.Role(), input)
+	out, err := s.runInstance(scope.Role(), input, scope.AWSMachine.Spec.IPPrewarming)
+	// Retry with the fallback instance types, in order, as long as EC2 is out of capacity.
+	for _, instanceType := range instanceTypes[1:] {
+		if err == nil || !awserrors.IsInsufficientCapacity(errors.Cause(err)) {
+			break
+		}
+		record.Warnf(scope.AWSMachine, "InsufficientCapacity", "Insufficient capacity for instance type %q, retrying with instance type %q", input.Type, instanceType)
+		input.Type = instanceType
+		s.scope.V(2).Info("Running instance with fallback instance type", "machine-role", scope.Role(), "instance-type", instanceType)
+		out, err = s.runInstance(scope.Role(), input, scope.AWSMachine.Spec.IPPrewarming)
+	}
 	if err != nil {
 		// Only record the failure event if the error is not related to failed dependencies.
 		// This is to avoid spamming failure events since the machine will be requeued by the actuator.
@@ -320,7 +426,7 @@ func (s *Service) findSubnet(scope *scope.MachineScope) (string, error) {
 		return *subnets[0].SubnetId, nil
 
 	case failureDomain != nil:
-		subnets := s.scope.Subnets().FilterPrivate().FilterByZone(*failureDomain)
+		subnets := s.scope.Subnets().FilterPrimary().FilterPrivate().FilterByZone(*failureDomain)
 		if len(subnets) == 0 {
 			record.Warnf(scope.AWSMachine, "FailedCreate",
 				"Failed to create instance: no subnets available in availability zone %q", *failureDomain)
@@ -338,7 +444,7 @@ func (s *Service) findSubnet(scope *scope.MachineScope) (string, error) {
 		// with control plane machines.
 
 	default:
-		sns := s.scope.Subnets().FilterPrivate()
+		sns := s.scope.Subnets().FilterPrimary().FilterPrivate()
 		if len(sns) == 0 {
 			record.Eventf(s.scope.InfraCluster(), "FailedCreateInstance", "Failed to run machine %q, no subnets available", scope.Name())
 			return "", awserrors.NewFailedDependency(fmt.Sprintf("failed to run machine %q, no subnets available", scope.Name()))
@@ -347,6 +453,101 @@ func (s *Service) findSubnet(scope *scope.MachineScope) (string, error) {
 	}
 }
 
+// getAvailabilityZone returns the availability zone of the subnet the instance is launched into,
+// falling back to the machine's failure domain if the subnet isn't part of the cluster's network spec.
+func (s *Service) getAvailabilityZone(scope *scope.MachineScope, subnetID string) string {
+	if subnet := s.scope.Subnets().FindByID(subnetID); subnet != nil && subnet.AvailabilityZone != "" {
+		return subnet.AvailabilityZone
+	}
+
+	if scope.Machine.Spec.FailureDomain != nil {
+		return *scope.Machine.Spec.FailureDomain
+	}
+
+	return aws.StringValue(scope.AWSMachine.Spec.FailureDomain)
+}
+
+// checkPrivateIP returns a conflict error if the private IP address isn't one the instance can be
+// assigned in the subnet: EC2 reserves the first four and the last addresses of every subnet.
+func (s *Service) checkPrivateIP(privateIP, subnetID string) error {
+	var cidrBlock string
+	if subnet := s.scope.Subnets().FindByID(subnetID); subnet != nil {
+		cidrBlock = subnet.CidrBlock
+	}
+
+	if cidrBlock == "" {
+		out, err := s.EC2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice([]string{subnetID}),
+		})
+		if err != nil {
+			record.Eventf(s.scope.InfraCluster(), "FailedDescribeSubnet", "Failed to describe subnet %q: %v", subnetID, err)
+			return errors.Wrapf(err, "failed to describe subnet %q", subnetID)
+		}
+		if len(out.Subnets) == 0 {
+			return awserrors.NewNotFound(fmt.Sprintf("subnet %q not found", subnetID))
+		}
+		cidrBlock = aws.StringValue(out.Subnets[0].CidrBlock)
+	}
+
+	_, subnetNet, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse CIDR block %q of subnet %q", cidrBlock, subnetID)
+	}
+
+	ip := net.ParseIP(privateIP).To4()
+	network := subnetNet.IP.To4()
+	if ip == nil || network == nil || !subnetNet.Contains(ip) {
+		return awserrors.NewConflict(fmt.Sprintf("private IP %q is not in the CIDR block %q of subnet %q", privateIP, cidrBlock, subnetID))
+	}
+
+	ones, bits := subnetNet.Mask.Size()
+	offset := binary.BigEndian.Uint32(ip) - binary.BigEndian.Uint32(network)
+	if offset < 4 || offset == uint32(1)<<uint(bits-ones)-1 {
+		return awserrors.NewConflict(fmt.Sprintf("private IP %q is reserved by AWS in subnet %q", privateIP, subnetID))
+	}
+
+	return nil
+}
+
+// offeredInstanceTypes returns the instance types that are offered in the availability zone, in the given order.
+// It returns a not found error if none of them is.
+func (s *Service) offeredInstanceTypes(instanceTypes []string, availabilityZone string) ([]string, error) {
+	input := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+		Filters: []*ec2.Filter{
+			{Name: aws.String("instance-type"), Values: aws.StringSlice(instanceTypes)},
+			{Name: aws.String("location"), Values: aws.StringSlice([]string{availabilityZone})},
+		},
+	}
+
+	out, err := s.EC2Client.DescribeInstanceTypeOfferings(input)
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeInstanceTypeOfferings", "Failed to describe offerings of instance types %q: %v", instanceTypes, err)
+		return nil, errors.Wrapf(err, "failed to describe offerings of instance types %q", instanceTypes)
+	}
+
+	offerings := sets.NewString()
+	for _, offering := range out.InstanceTypeOfferings {
+		offerings.Insert(aws.StringValue(offering.InstanceType))
+	}
+
+	var offered []string
+	for _, instanceType := range instanceTypes {
+		if offerings.Has(instanceType) {
+			offered = append(offered, instanceType)
+		}
+	}
+
+	if len(offered) == 0 {
+		if len(instanceTypes) == 1 {
+			return nil, awserrors.NewNotFound(fmt.Sprintf("instance type %q is not offered in availability zone %q", instanceTypes[0], availabilityZone))
+		}
+		return nil, awserrors.NewNotFound(fmt.Sprintf("none of the instance types %q is offered in availability zone %q", instanceTypes, availabilityZone))
+	}
+
+	return offered, nil
+}
+
 // getFilteredSubnets fetches subnets filtered based on the criteria passed.
 func (s *Service) getFilteredSubnets(criteria ...*ec2.Filter) ([]*ec2.Subnet, error) {
 	out, err := s.EC2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: criteria})
@@ -356,6 +557,31 @@ func (s *Service) getFilteredSubnets(criteria ...*ec2.Filter) ([]*ec2.Subnet, er
 	return out.Subnets, nil
 }
 
+// FilteredSecurityGroupGetter looks up the ID of the security group matching the filters of a reference.
+type FilteredSecurityGroupGetter interface {
+	GetFilteredSecurityGroupID(securityGroup infrav1.AWSResourceReference) (string, error)
+}
+
+// GetAdditionalSecurityGroupsIDs resolves the additional security group references of a machine,
+// either by ID or by looking up the first security group matching the filters.
+func GetAdditionalSecurityGroupsIDs(getter FilteredSecurityGroupGetter, securityGroups []infrav1.AWSResourceReference) ([]string, error) {
+	ids := make([]string, 0, len(securityGroups))
+	for _, sg := range securityGroups {
+		if sg.ID != nil {
+			ids = append(ids, *sg.ID)
+		}
+
+		if sg.Filters != nil {
+			id, err := getter.GetFilteredSecurityGroupID(sg)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to resolve additional security group")
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
 // GetCoreSecurityGroups looks up the security group IDs managed by this actuator
 // They are considered "core" to its proper functioning.
 func (s *Service) GetCoreSecurityGroups(scope *scope.MachineScope) ([]string, error) {
@@ -419,6 +645,23 @@ func (s *Service) GetCoreNodeSecurityGroups(scope *scope.MachinePoolScope) ([]st
 	return ids, nil
 }
 
+// StartInstance starts a stopped EC2 instance.
+func (s *Service) StartInstance(instanceID string) error {
+	s.scope.V(2).Info("Attempting to start instance", "instance-id", instanceID)
+
+	input := &ec2.StartInstancesInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	}
+
+	if _, err := s.EC2Client.StartInstances(input); err != nil {
+		return errors.Wrapf(err, "failed to start instance with id %q", instanceID)
+	}
+
+	s.forgetClusterInstances()
+	s.scope.V(2).Info("Started instance", "instance-id", instanceID)
+	return nil
+}
+
 // TerminateInstance terminates an EC2 instance.
 // Returns nil on success, error in all other cases.
 func (s *Service) TerminateInstance(instanceID string) error {
@@ -428,10 +671,23 @@ func (s *Service) TerminateInstance(instanceID string) error {
 		InstanceIds: aws.StringSlice([]string{instanceID}),
 	}
 
-	if _, err := s.EC2Client.TerminateInstances(input); err != nil {
+	_, err := s.EC2Client.TerminateInstances(input)
+	if awserrors.IsOperationNotPermitted(err) {
+		// Termination protection guards against other tooling, not against us: lift it and try again.
+		s.scope.V(2).Info("Disabling termination protection", "instance-id", instanceID)
+		if _, err := s.EC2Client.ModifyInstanceAttribute(&ec2.ModifyInstanceAttributeInput{
+			InstanceId:            aws.String(instanceID),
+			DisableApiTermination: &ec2.AttributeBooleanValue{Value: aws.Bool(false)},
+		}); err != nil {
+			return errors.Wrapf(err, "failed to disable termination protection of instance with id %q", instanceID)
+		}
+		_, err = s.EC2Client.TerminateInstances(input)
+	}
+	if err != nil {
 		return errors.Wrapf(err, "failed to terminate instance with id %q", instanceID)
 	}
 
+	s.forgetClusterInstances()
 	s.scope.V(2).Info("Terminated instance", "instance-id", instanceID)
 	return nil
 }
@@ -456,7 +712,7 @@ func (s *Service) TerminateInstanceAndWait(instanceID string) error {
 	return nil
 }
 
-func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instance, error) {
+func (s *Service) runInstance(role string, i *infrav1.Instance, ipPrewarming *infrav1.IPPrewarming) (*infrav1.Instance, error) {
 	input := &ec2.RunInstancesInput{
 		InstanceType: aws.String(i.Type),
 		ImageId:      aws.String(i.ImageID),
@@ -480,8 +736,14 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		}
 
 		input.NetworkInterfaces = netInterfaces
+	} else if i.PublicIPOnLaunch != nil || ipPrewarming != nil || i.ElasticFabricAdapter {
+		// Overriding the public IP association of the subnet, pre-warming IP addresses or attaching
+		// an Elastic Fabric Adapter requires the subnet and the security groups to be set on the
+		// network interfaces instead.
+		input.NetworkInterfaces = getLaunchNetworkInterfaces(i, ipPrewarming)
 	} else {
 		input.SubnetId = aws.String(i.SubnetID)
+		input.PrivateIpAddress = i.PrivateIP
 
 		if len(i.SecurityGroupIDs) > 0 {
 			input.SecurityGroupIds = aws.StringSlice(i.SecurityGroupIDs)
@@ -542,9 +804,30 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 
 	input.InstanceMarketOptions = getInstanceMarketOptionsRequest(i.SpotMarketOptions)
 
-	if i.Tenancy != "" {
-		input.Placement = &ec2.Placement{
-			Tenancy: &i.Tenancy,
+	input.Placement = getPlacement(i)
+
+	input.CapacityReservationSpecification = getCapacityReservationSpecification(i.CapacityReservation)
+
+	if i.TerminationProtection {
+		input.DisableApiTermination = aws.Bool(true)
+	}
+
+	if i.DetailedMonitoring {
+		input.Monitoring = &ec2.RunInstancesMonitoringEnabled{
+			Enabled: aws.Bool(true),
+		}
+	}
+
+	if i.CPUOptions != nil {
+		input.CpuOptions = &ec2.CpuOptionsRequest{
+			CoreCount:      aws.Int64(i.CPUOptions.CoreCount),
+			ThreadsPerCore: aws.Int64(i.CPUOptions.ThreadsPerCore),
+		}
+	}
+
+	if i.CreditSpecification != nil {
+		input.CreditSpecification = &ec2.CreditSpecificationRequest{
+			CpuCredits: aws.String(string(i.CreditSpecification.CPUCredits)),
 		}
 	}
 
@@ -557,20 +840,26 @@ func (s *Service) runInstance(role string, i *infrav1.Instance) (*infrav1.Instan
 		return nil, errors.Errorf("no instance returned for reservation %v", out.GoString())
 	}
 
+	s.waitUntilInstanceRunning(out.Instances[0].InstanceId)
+
+	return s.SDKToInstance(out.Instances[0])
+}
+
+// waitUntilInstanceRunning waits for a newly launched instance to be running. The instance state is only
+// informational at this point, so the wait giving up doesn't fail the launch.
+func (s *Service) waitUntilInstanceRunning(instanceID *string) {
 	waitTimeout := 1 * time.Minute
-	s.scope.V(2).Info("Waiting for instance to be in running state", "instance-id", *out.Instances[0].InstanceId, "timeout", waitTimeout.String())
+	s.scope.V(2).Info("Waiting for instance to be in running state", "instance-id", *instanceID, "timeout", waitTimeout.String())
 	ctx, cancel := context.WithTimeout(aws.BackgroundContext(), waitTimeout)
 	defer cancel()
 
 	if err := s.EC2Client.WaitUntilInstanceRunningWithContext(
 		ctx,
-		&ec2.DescribeInstancesInput{InstanceIds: []*string{out.Instances[0].InstanceId}},
+		&ec2.DescribeInstancesInput{InstanceIds: []*string{instanceID}},
 		request.WithWaiterLogger(awslogs.NewWrapLogr(s.scope)),
 	); err != nil {
 		s.scope.V(2).Info("Could not determine if Machine is running. Machine state might be unavailable until next renconciliation.")
 	}
-
-	return s.SDKToInstance(out.Instances[0])
 }
 
 func volumeToBlockDeviceMapping(v *infrav1.Volume) *ec2.BlockDeviceMapping {
@@ -622,6 +911,44 @@ func (s *Service) GetInstanceSecurityGroups(instanceID string) (map[string][]str
 	return out, nil
 }
 
+// InstanceSystemStatusImpaired returns whether the given instance is failing its system status checks,
+// which report on the AWS infrastructure the instance runs on. The statuses of all the cluster's instances
+// are described together when the service shares them, and an instance missing from them is described by ID.
+func (s *Service) InstanceSystemStatusImpaired(instanceID string) (bool, error) {
+	if s.instanceSnapshotKey != "" {
+		statuses, err := s.clusterInstanceStatuses()
+		if err != nil {
+			return false, err
+		}
+		if status, ok := statuses[instanceID]; ok {
+			return systemStatusImpaired(status), nil
+		}
+	}
+
+	out, err := s.EC2Client.DescribeInstanceStatus(&ec2.DescribeInstanceStatusInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to describe status of instance %q", instanceID)
+	}
+
+	if len(out.InstanceStatuses) == 0 {
+		return false, nil
+	}
+
+	return systemStatusImpaired(out.InstanceStatuses[0]), nil
+}
+
+// systemStatusImpaired returns whether an instance status reports failing system status checks. The status
+// isn't reported until the checks have run.
+func systemStatusImpaired(status *ec2.InstanceStatus) bool {
+	if status == nil || status.SystemStatus == nil {
+		return false
+	}
+
+	return aws.StringValue(status.SystemStatus.Status) == ec2.SummaryStatusImpaired
+}
+
 // UpdateInstanceSecurityGroups modifies the security groups of the given
 // EC2 instance.
 func (s *Service) UpdateInstanceSecurityGroups(instanceID string, ids []string) error {
@@ -691,6 +1018,86 @@ func (s *Service) UpdateResourceTags(resourceID *string, create, remove map[stri
 	return nil
 }
 
+// DesiredInstanceTags returns the tags the machine expects on its instance: those of the cluster, of the
+// role of the machine and its name, and the additional tags of the cluster and the machine.
+func (s *Service) DesiredInstanceTags(scope *scope.MachineScope) infrav1.Tags {
+	return s.getInstanceTags(scope)
+}
+
+// GetLaunchResourceTags returns the tags of the root and non-root volumes the instance was launched with, and of
+// its primary network interface, by resource ID. They are looked up in the descriptions shared by the machines
+// of the cluster, so none are returned when the service doesn't share them or the instance isn't in them yet.
+// Volumes and network interfaces attached later on aren't returned.
+func (s *Service) GetLaunchResourceTags(instanceID string, nonRootVolumes []infrav1.Volume) (volumes map[string]infrav1.Tags, networkInterfaces map[string]infrav1.Tags, err error) {
+	if s.instanceSnapshotKey == "" {
+		return nil, nil, nil
+	}
+
+	instances, err := s.clusterInstances()
+	if err != nil {
+		return nil, nil, err
+	}
+	inst, ok := instances[instanceID]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	tags, err := s.clusterResourceTags()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	volumeIDs, networkInterfaceIDs := launchResourceIDs(inst, nonRootVolumes)
+	volumes = make(map[string]infrav1.Tags, len(volumeIDs))
+	for _, id := range volumeIDs {
+		if t, ok := tags[id]; ok {
+			volumes[id] = t.DeepCopy()
+		}
+	}
+	networkInterfaces = make(map[string]infrav1.Tags, len(networkInterfaceIDs))
+	for _, id := range networkInterfaceIDs {
+		if t, ok := tags[id]; ok {
+			networkInterfaces[id] = t.DeepCopy()
+		}
+	}
+
+	return volumes, networkInterfaces, nil
+}
+
+// launchResourceIDs returns the IDs of the root and non-root volumes the instance was launched with,
+// and of its primary network interface.
+func launchResourceIDs(v *ec2.Instance, nonRootVolumes []infrav1.Volume) (volumeIDs []string, networkInterfaceIDs []string) {
+	deviceNames := sets.NewString(aws.StringValue(v.RootDeviceName))
+	for _, volume := range nonRootVolumes {
+		deviceNames.Insert(volume.DeviceName)
+	}
+
+	for _, mapping := range v.BlockDeviceMappings {
+		if mapping.Ebs != nil && deviceNames.Has(aws.StringValue(mapping.DeviceName)) {
+			volumeIDs = append(volumeIDs, aws.StringValue(mapping.Ebs.VolumeId))
+		}
+	}
+	for _, eni := range v.NetworkInterfaces {
+		if eni.Attachment != nil && aws.Int64Value(eni.Attachment.DeviceIndex) == 0 {
+			networkInterfaceIDs = append(networkInterfaceIDs, aws.StringValue(eni.NetworkInterfaceId))
+		}
+	}
+
+	return volumeIDs, networkInterfaceIDs
+}
+
+// getInstanceTags returns the tags of the instance created for the machine.
+func (s *Service) getInstanceTags(scope *scope.MachineScope) infrav1.Tags {
+	// Make sure to use the MachineScope here to get the merger of AWSCluster and AWSMachine tags
+	return infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(scope.Name()),
+		Role:        aws.String(scope.Role()),
+		Additional:  scope.AdditionalTags(),
+	}.WithCloudProvider(s.scope.Name()).WithMachineName(scope.Machine))
+}
+
 func (s *Service) getInstanceENIs(instanceID string) ([]*ec2.NetworkInterface, error) {
 	input := &ec2.DescribeNetworkInterfacesInput{
 		Filters: []*ec2.Filter{
@@ -709,7 +1116,7 @@ func (s *Service) getInstanceENIs(instanceID string) ([]*ec2.NetworkInterface, e
 	return output.NetworkInterfaces, nil
 }
 
-func (s *Service) getImageRootDevice(imageID string) (*string, error) {
+func (s *Service) getImage(imageID string) (*ec2.Image, error) {
 	input := &ec2.DescribeImagesInput{
 		ImageIds: []*string{aws.String(imageID)},
 	}
@@ -723,24 +1130,22 @@ func (s *Service) getImageRootDevice(imageID string) (*string, error) {
 		return nil, errors.Errorf("no images returned when looking up ID %q", imageID)
 	}
 
-	return output.Images[0].RootDeviceName, nil
+	return output.Images[0], nil
 }
 
-func (s *Service) getImageSnapshotSize(imageID string) (*int64, error) {
-	input := &ec2.DescribeImagesInput{
-		ImageIds: []*string{aws.String(imageID)},
-	}
-
-	output, err := s.EC2Client.DescribeImages(input)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(output.Images) == 0 {
-		return nil, errors.Errorf("no images returned when looking up ID %q", imageID)
+// getImageRootSnapshotSize returns the size of the snapshot backing the image's root device.
+func getImageRootSnapshotSize(image *ec2.Image) (*int64, error) {
+	for _, mapping := range image.BlockDeviceMappings {
+		if aws.StringValue(mapping.DeviceName) != aws.StringValue(image.RootDeviceName) {
+			continue
+		}
+		if mapping.Ebs == nil || mapping.Ebs.VolumeSize == nil {
+			break
+		}
+		return mapping.Ebs.VolumeSize, nil
 	}
 
-	return output.Images[0].BlockDeviceMappings[0].Ebs.VolumeSize, nil
+	return nil, errors.Errorf("image %q has no EBS block device mapping for root device %q", aws.StringValue(image.ImageId), aws.StringValue(image.RootDeviceName))
 }
 
 // SDKToInstance converts an AWS EC2 SDK instance to the CAPA instance type.
@@ -782,6 +1187,35 @@ func (s *Service) SDKToInstance(v *ec2.Instance) (*infrav1.Instance, error) {
 	i.Addresses = s.getInstanceAddresses(v)
 
 	i.AvailabilityZone = aws.StringValue(v.Placement.AvailabilityZone)
+	i.PlacementGroupName = aws.StringValue(v.Placement.GroupName)
+	i.HostID = aws.StringValue(v.Placement.HostId)
+	i.HostResourceGroupArn = aws.StringValue(v.Placement.HostResourceGroupArn)
+
+	if v.CapacityReservationId != nil {
+		i.CapacityReservation = &infrav1.CapacityReservation{ID: v.CapacityReservationId}
+	} else if v.CapacityReservationSpecification != nil && v.CapacityReservationSpecification.CapacityReservationPreference != nil {
+		i.CapacityReservation = &infrav1.CapacityReservation{
+			Preference: infrav1.CapacityReservationPreference(*v.CapacityReservationSpecification.CapacityReservationPreference),
+		}
+	}
+
+	if v.Monitoring != nil {
+		state := aws.StringValue(v.Monitoring.State)
+		i.DetailedMonitoring = state == ec2.MonitoringStateEnabled || state == ec2.MonitoringStatePending
+	}
+
+	for _, eni := range v.NetworkInterfaces {
+		if eni.Attachment != nil && aws.Int64Value(eni.Attachment.DeviceIndex) == 0 && aws.StringValue(eni.InterfaceType) == ec2.NetworkInterfaceTypeEfa {
+			i.ElasticFabricAdapter = true
+		}
+	}
+
+	if v.CpuOptions != nil {
+		i.CPUOptions = &infrav1.CPUOptions{
+			CoreCount:      aws.Int64Value(v.CpuOptions.CoreCount),
+			ThreadsPerCore: aws.Int64Value(v.CpuOptions.ThreadsPerCore),
+		}
+	}
 
 	for _, volume := range v.BlockDeviceMappings {
 		i.VolumeIDs = append(i.VolumeIDs, *volume.Ebs.VolumeId)
@@ -898,12 +1332,12 @@ func (s *Service) DetachSecurityGroupsFromNetworkInterface(groups []string, inte
 // checkRootVolume checks the input root volume options against the requested AMI's defaults
 // and returns the AMI's root device name.
 func (s *Service) checkRootVolume(rootVolume *infrav1.Volume, imageID string) (*string, error) {
-	rootDeviceName, err := s.getImageRootDevice(imageID)
+	image, err := s.getImage(imageID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get root volume from image %q", imageID)
 	}
 
-	snapshotSize, err := s.getImageSnapshotSize(imageID)
+	snapshotSize, err := getImageRootSnapshotSize(image)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get root volume from image %q", imageID)
 	}
@@ -912,7 +1346,7 @@ func (s *Service) checkRootVolume(rootVolume *infrav1.Volume, imageID string) (*
 		return nil, errors.Errorf("root volume size (%d) must be greater than or equal to snapshot size (%d)", rootVolume.Size, *snapshotSize)
 	}
 
-	return rootDeviceName, nil
+	return image.RootDeviceName, nil
 }
 
 // filterGroups filters a list for a string.
@@ -965,6 +1399,93 @@ func getInstanceMarketOptionsRequest(spotMarketOptions *infrav1.SpotMarketOption
 	return instanceMarketOptionsRequest
 }
 
+func getPlacement(i *infrav1.Instance) *ec2.Placement {
+	if i.Tenancy == "" && i.HostID == "" && i.HostResourceGroupArn == "" && i.PlacementGroupName == "" {
+		return nil
+	}
+
+	placement := &ec2.Placement{}
+	if i.Tenancy != "" {
+		placement.SetTenancy(i.Tenancy)
+	}
+	if i.HostID != "" {
+		placement.SetHostId(i.HostID)
+	}
+	if i.HostResourceGroupArn != "" {
+		placement.SetHostResourceGroupArn(i.HostResourceGroupArn)
+	}
+	if i.PlacementGroupName != "" {
+		placement.SetGroupName(i.PlacementGroupName)
+	}
+
+	return placement
+}
+
+// getLaunchNetworkInterfaces returns the network interfaces created along with the instance: the primary
+// network interface, followed by the additional ones requested for IP pre-warming.
+func getLaunchNetworkInterfaces(i *infrav1.Instance, ipPrewarming *infrav1.IPPrewarming) []*ec2.InstanceNetworkInterfaceSpecification {
+	count := int64(1)
+	var secondaryPrivateIPCount *int64
+	if ipPrewarming != nil {
+		count += ipPrewarming.AdditionalNetworkInterfaces
+		if ipPrewarming.SecondaryPrivateIPCount > 0 {
+			secondaryPrivateIPCount = aws.Int64(ipPrewarming.SecondaryPrivateIPCount)
+		}
+	}
+
+	netInterfaces := make([]*ec2.InstanceNetworkInterfaceSpecification, 0, count)
+	for index := int64(0); index < count; index++ {
+		netInterface := &ec2.InstanceNetworkInterfaceSpecification{
+			DeviceIndex:                    aws.Int64(index),
+			SubnetId:                       aws.String(i.SubnetID),
+			SecondaryPrivateIpAddressCount: secondaryPrivateIPCount,
+		}
+
+		if index == 0 {
+			netInterface.AssociatePublicIpAddress = i.PublicIPOnLaunch
+			netInterface.PrivateIpAddress = i.PrivateIP
+
+			if i.ElasticFabricAdapter {
+				netInterface.InterfaceType = aws.String(ec2.NetworkInterfaceTypeEfa)
+			}
+		} else {
+			// Network interfaces created along with the instance are deleted with it by default. The deletion of the
+			// machine relies on that for the additional ones, so it's requested explicitly.
+			netInterface.DeleteOnTermination = aws.Bool(true)
+		}
+
+		if len(i.SecurityGroupIDs) > 0 {
+			netInterface.Groups = aws.StringSlice(i.SecurityGroupIDs)
+		}
+
+		netInterfaces = append(netInterfaces, netInterface)
+	}
+
+	return netInterfaces
+}
+
+func getCapacityReservationSpecification(capacityReservation *infrav1.CapacityReservation) *ec2.CapacityReservationSpecification {
+	if capacityReservation == nil {
+		return nil
+	}
+
+	if capacityReservation.ID != nil {
+		return &ec2.CapacityReservationSpecification{
+			CapacityReservationTarget: &ec2.CapacityReservationTarget{
+				CapacityReservationId: capacityReservation.ID,
+			},
+		}
+	}
+
+	if capacityReservation.Preference != "" {
+		return &ec2.CapacityReservationSpecification{
+			CapacityReservationPreference: aws.String(string(capacityReservation.Preference)),
+		}
+	}
+
+	return nil
+}
+
 // GetFilteredSecurityGroupID get security group ID using filters.
 func (s *Service) GetFilteredSecurityGroupID(securityGroup infrav1.AWSResourceReference) (string, error) {
 	if securityGroup.Filters == nil {
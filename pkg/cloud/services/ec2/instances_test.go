@@ -226,6 +226,32 @@ func TestTerminateInstance(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:       "instance with termination protection",
+			instanceID: "i-protected",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				gomock.InOrder(
+					m.TerminateInstances(gomock.Eq(&ec2.TerminateInstancesInput{
+						InstanceIds: []*string{aws.String("i-protected")},
+					})).
+						Return(nil, awserr.New(awserrors.OperationNotPermitted, "The instance 'i-protected' may not be terminated", nil)),
+					m.ModifyInstanceAttribute(gomock.Eq(&ec2.ModifyInstanceAttributeInput{
+						InstanceId:            aws.String("i-protected"),
+						DisableApiTermination: &ec2.AttributeBooleanValue{Value: aws.Bool(false)},
+					})).
+						Return(&ec2.ModifyInstanceAttributeOutput{}, nil),
+					m.TerminateInstances(gomock.Eq(&ec2.TerminateInstancesInput{
+						InstanceIds: []*string{aws.String("i-protected")},
+					})).
+						Return(&ec2.TerminateInstancesOutput{}, nil),
+				)
+			},
+			check: func(err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
 		{
 			name:       "instance does not exist",
 			instanceID: "i-donotexist",
@@ -270,6 +296,156 @@ func TestTerminateInstance(t *testing.T) {
 	}
 }
 
+func TestStartInstance(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name       string
+		instanceID string
+		expect     func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		wantErr    bool
+	}{
+		{
+			name:       "instance starts",
+			instanceID: "i-stopped",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.StartInstances(gomock.Eq(&ec2.StartInstancesInput{
+					InstanceIds: []*string{aws.String("i-stopped")},
+				})).
+					Return(&ec2.StartInstancesOutput{}, nil)
+			},
+		},
+		{
+			name:       "error starting instance",
+			instanceID: "i-stopped",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.StartInstances(gomock.Any()).
+					Return(nil, errors.New("some unknown error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			err = s.StartInstance(tc.instanceID)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestInstanceSystemStatusImpaired(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name         string
+		expect       func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		wantImpaired bool
+		wantErr      bool
+	}{
+		{
+			name: "system status checks passing",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstanceStatus(gomock.Eq(&ec2.DescribeInstanceStatusInput{
+					InstanceIds: []*string{aws.String("i-1")},
+				})).
+					Return(&ec2.DescribeInstanceStatusOutput{
+						InstanceStatuses: []*ec2.InstanceStatus{
+							{
+								InstanceId:   aws.String("i-1"),
+								SystemStatus: &ec2.InstanceStatusSummary{Status: aws.String(ec2.SummaryStatusOk)},
+							},
+						},
+					}, nil)
+			},
+		},
+		{
+			name: "system status checks failing",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstanceStatus(gomock.Any()).
+					Return(&ec2.DescribeInstanceStatusOutput{
+						InstanceStatuses: []*ec2.InstanceStatus{
+							{
+								InstanceId:   aws.String("i-1"),
+								SystemStatus: &ec2.InstanceStatusSummary{Status: aws.String(ec2.SummaryStatusImpaired)},
+							},
+						},
+					}, nil)
+			},
+			wantImpaired: true,
+		},
+		{
+			name: "system status not reported yet",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstanceStatus(gomock.Any()).
+					Return(&ec2.DescribeInstanceStatusOutput{}, nil)
+			},
+		},
+		{
+			name: "error describing instance status",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeInstanceStatus(gomock.Any()).
+					Return(nil, errors.New("some unknown error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client:     client,
+				Cluster:    &clusterv1.Cluster{},
+				AWSCluster: &infrav1.AWSCluster{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			impaired, err := s.InstanceSystemStatusImpaired("i-1")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if impaired != tc.wantImpaired {
+				t.Fatalf("expected impaired %v, got %v", tc.wantImpaired, impaired)
+			}
+		})
+	}
+}
+
 func TestCreateInstance(t *testing.T) {
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -290,6 +466,79 @@ func TestCreateInstance(t *testing.T) {
 		t.Fatal("Failed to gzip test user data")
 	}
 
+	// newNodeMachine, newPrivateSubnetCluster and expectRunInstance are the fixture shared by the cases
+	// that only differ in the machine spec field under test.
+	newNodeMachine := func() clusterv1.Machine {
+		return clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"set": "node"},
+			},
+			Spec: clusterv1.MachineSpec{
+				Bootstrap: clusterv1.Bootstrap{
+					DataSecretName: pointer.StringPtr("bootstrap-data"),
+				},
+			},
+		}
+	}
+
+	newPrivateSubnetCluster := func(subnets ...infrav1.SubnetSpec) *infrav1.AWSCluster {
+		return &infrav1.AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					Subnets: subnets,
+				},
+			},
+			Status: infrav1.AWSClusterStatus{
+				Network: infrav1.NetworkStatus{
+					SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+						infrav1.SecurityGroupControlPlane: {
+							ID: "1",
+						},
+						infrav1.SecurityGroupNode: {
+							ID: "2",
+						},
+						infrav1.SecurityGroupLB: {
+							ID: "3",
+						},
+					},
+					APIServerELB: infrav1.ClassicELB{
+						DNSName: "test-apiserver.us-east-1.aws",
+					},
+				},
+			},
+		}
+	}
+
+	// expectRunInstance expects a single instance to be launched, checking the RunInstances input with
+	// verify, and lets launched fill in the fields of the pending instance under test.
+	expectRunInstance := func(m *mock_ec2iface.MockEC2APIMockRecorder, verify func(*ec2.RunInstancesInput), launched func(*ec2.Instance)) {
+		instance := &ec2.Instance{
+			State: &ec2.InstanceState{
+				Name: aws.String(ec2.InstanceStateNamePending),
+			},
+			InstanceId:   aws.String("two"),
+			InstanceType: aws.String("m5.large"),
+			SubnetId:     aws.String("subnet-1"),
+			ImageId:      aws.String("ami-1"),
+			Placement: &ec2.Placement{
+				AvailabilityZone: &az,
+			},
+		}
+		if launched != nil {
+			launched(instance)
+		}
+
+		m.
+			RunInstances(gomock.Any()).
+			Do(verify).
+			Return(&ec2.Reservation{
+				Instances: []*ec2.Instance{instance},
+			}, nil)
+		m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+	}
+
 	testcases := []struct {
 		name          string
 		machine       clusterv1.Machine
@@ -391,132 +640,354 @@ func TestCreateInstance(t *testing.T) {
 			},
 		},
 		{
-			name: "with availability zone",
-			machine: clusterv1.Machine{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"set": "node"},
-				},
-				Spec: clusterv1.MachineSpec{
-					Bootstrap: clusterv1.Bootstrap{
-						DataSecretName: pointer.StringPtr("bootstrap-data"),
-					},
-				},
-			},
+			name:    "with additional security groups",
+			machine: newNodeMachine(),
 			machineConfig: &infrav1.AWSMachineSpec{
 				AMI: infrav1.AMIReference{
 					ID: aws.String("abc"),
 				},
-				InstanceType:  "m5.2xlarge",
-				FailureDomain: aws.String("us-east-1c"),
-			},
-			awsCluster: &infrav1.AWSCluster{
-				ObjectMeta: metav1.ObjectMeta{Name: "test"},
-				Spec: infrav1.AWSClusterSpec{
-					NetworkSpec: infrav1.NetworkSpec{
-						Subnets: infrav1.Subnets{
-							infrav1.SubnetSpec{
-								ID:               "subnet-1",
-								AvailabilityZone: "us-east-1a",
-								IsPublic:         false,
-							},
-							infrav1.SubnetSpec{
-								ID:               "subnet-2",
-								AvailabilityZone: "us-east-1b",
-								IsPublic:         false,
-							},
-							infrav1.SubnetSpec{
-								ID:               "subnet-3",
-								AvailabilityZone: "us-east-1c",
-								IsPublic:         false,
-							},
-							infrav1.SubnetSpec{
-								ID:               "subnet-3-public",
-								AvailabilityZone: "us-east-1c",
-								IsPublic:         true,
-							},
-						},
+				InstanceType: "m5.large",
+				AdditionalSecurityGroups: []infrav1.AWSResourceReference{
+					{
+						ID: aws.String("sg-rds-access"),
 					},
-				},
-				Status: infrav1.AWSClusterStatus{
-					Network: infrav1.NetworkStatus{
-						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
-							infrav1.SecurityGroupControlPlane: {
-								ID: "1",
-							},
-							infrav1.SecurityGroupNode: {
-								ID: "2",
-							},
-							infrav1.SecurityGroupLB: {
-								ID: "3",
+					{
+						Filters: []infrav1.Filter{
+							{
+								Name:   "tag:Name",
+								Values: []string{"shared"},
 							},
 						},
-						APIServerELB: infrav1.ClassicELB{
-							DNSName: "test-apiserver.us-east-1.aws",
-						},
 					},
 				},
 			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				m.
-					RunInstances(gomock.Any()).
-					Return(&ec2.Reservation{
-						Instances: []*ec2.Instance{
+				m.DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{
+					Filters: []*ec2.Filter{
+						{
+							Name:   aws.String("tag:Name"),
+							Values: aws.StringSlice([]string{"shared"}),
+						},
+					},
+				})).
+					Return(&ec2.DescribeSecurityGroupsOutput{
+						SecurityGroups: []*ec2.SecurityGroup{
 							{
-								State: &ec2.InstanceState{
-									Name: aws.String(ec2.InstanceStateNamePending),
-								},
-								IamInstanceProfile: &ec2.IamInstanceProfile{
-									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
-								},
-								InstanceId:     aws.String("two"),
-								InstanceType:   aws.String("m5.large"),
-								SubnetId:       aws.String("subnet-3"),
-								ImageId:        aws.String("ami-1"),
-								RootDeviceName: aws.String("device-1"),
-								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
-									{
-										DeviceName: aws.String("device-1"),
-										Ebs: &ec2.EbsInstanceBlockDevice{
-											VolumeId: aws.String("volume-1"),
-										},
-									},
-								},
-								Placement: &ec2.Placement{
-									AvailabilityZone: &az,
-								},
+								GroupId: aws.String("sg-shared"),
 							},
 						},
 					}, nil)
-
-				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(nil)
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					expected := []string{"2", "3", "sg-rds-access", "sg-shared"}
+					if !reflect.DeepEqual(aws.StringValueSlice(input.SecurityGroupIds), expected) {
+						t.Fatalf("expected security groups %v, got %v", expected, aws.StringValueSlice(input.SecurityGroupIds))
+					}
+				}, nil)
 			},
 			check: func(instance *infrav1.Instance, err error) {
 				if err != nil {
 					t.Fatalf("did not expect error: %v", err)
 				}
-
-				if instance.SubnetID != "subnet-3" {
-					t.Fatalf("expected subnet-3 from availability zone us-east-1c, got %q", instance.SubnetID)
-				}
 			},
 		},
 		{
-			name: "with ImageLookupOrg specified at the machine level",
-			machine: clusterv1.Machine{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"set": "node"},
-				},
-				Spec: clusterv1.MachineSpec{
-					Bootstrap: clusterv1.Bootstrap{
-						DataSecretName: pointer.StringPtr("bootstrap-data"),
-					},
-					Version: pointer.StringPtr("v1.16.1"),
+			name:    "with public IP disabled",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
 				},
+				InstanceType: "m5.large",
+				PublicIP:     aws.Bool(false),
 			},
-			machineConfig: &infrav1.AWSMachineSpec{
-				ImageLookupOrg: "test-org-123",
-				InstanceType:   "m5.large",
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if input.SubnetId != nil || input.SecurityGroupIds != nil {
+						t.Fatalf("expected subnet and security groups to be set on the network interface, got %v", input)
+					}
+					expected := []*ec2.InstanceNetworkInterfaceSpecification{
+						{
+							DeviceIndex:              aws.Int64(0),
+							SubnetId:                 aws.String("subnet-1"),
+							Groups:                   aws.StringSlice([]string{"2", "3"}),
+							AssociatePublicIpAddress: aws.Bool(false),
+						},
+					}
+					if !reflect.DeepEqual(input.NetworkInterfaces, expected) {
+						t.Fatalf("expected network interfaces %v, got %v", expected, input.NetworkInterfaces)
+					}
+				}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name:    "with ip prewarming",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				IPPrewarming: &infrav1.IPPrewarming{
+					AdditionalNetworkInterfaces: 1,
+					SecondaryPrivateIPCount:     9,
+				},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if input.SubnetId != nil || input.SecurityGroupIds != nil {
+						t.Fatalf("expected subnet and security groups to be set on the network interface, got %v", input)
+					}
+					expected := []*ec2.InstanceNetworkInterfaceSpecification{
+						{
+							DeviceIndex:                    aws.Int64(0),
+							SubnetId:                       aws.String("subnet-1"),
+							Groups:                         aws.StringSlice([]string{"2", "3"}),
+							SecondaryPrivateIpAddressCount: aws.Int64(9),
+						},
+						{
+							DeviceIndex:                    aws.Int64(1),
+							SubnetId:                       aws.String("subnet-1"),
+							Groups:                         aws.StringSlice([]string{"2", "3"}),
+							SecondaryPrivateIpAddressCount: aws.Int64(9),
+							DeleteOnTermination:            aws.Bool(true),
+						},
+					}
+					if !reflect.DeepEqual(input.NetworkInterfaces, expected) {
+						t.Fatalf("expected network interfaces %v, got %v", expected, input.NetworkInterfaces)
+					}
+				}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name:    "with detailed monitoring",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:       "m5.large",
+				DetailedMonitoring: true,
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					expected := &ec2.RunInstancesMonitoringEnabled{Enabled: aws.Bool(true)}
+					if !reflect.DeepEqual(input.Monitoring, expected) {
+						t.Fatalf("expected monitoring %v, got %v", expected, input.Monitoring)
+					}
+				}, func(instance *ec2.Instance) {
+					instance.Monitoring = &ec2.Monitoring{
+						State: aws.String(ec2.MonitoringStatePending),
+					}
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if !instance.DetailedMonitoring {
+					t.Fatalf("expected detailed monitoring to be reported as enabled")
+				}
+			},
+		},
+		{
+			name:    "with cpu options",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				CPUOptions: &infrav1.CPUOptions{
+					CoreCount:      1,
+					ThreadsPerCore: 1,
+				},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					expected := &ec2.CpuOptionsRequest{
+						CoreCount:      aws.Int64(1),
+						ThreadsPerCore: aws.Int64(1),
+					}
+					if !reflect.DeepEqual(input.CpuOptions, expected) {
+						t.Fatalf("expected cpu options %v, got %v", expected, input.CpuOptions)
+					}
+				}, func(instance *ec2.Instance) {
+					instance.CpuOptions = &ec2.CpuOptions{
+						CoreCount:      aws.Int64(1),
+						ThreadsPerCore: aws.Int64(1),
+					}
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				expected := &infrav1.CPUOptions{CoreCount: 1, ThreadsPerCore: 1}
+				if !reflect.DeepEqual(instance.CPUOptions, expected) {
+					t.Fatalf("expected cpu options %v, got %v", expected, instance.CPUOptions)
+				}
+			},
+		},
+		{
+			name:    "with private ip",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				PrivateIP:    aws.String("10.0.0.10"),
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1", CidrBlock: "10.0.0.0/24"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if aws.StringValue(input.PrivateIpAddress) != "10.0.0.10" {
+						t.Fatalf("expected private ip 10.0.0.10, got %v", aws.StringValue(input.PrivateIpAddress))
+					}
+				}, func(instance *ec2.Instance) {
+					instance.PrivateIpAddress = aws.String("10.0.0.10")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if aws.StringValue(instance.PrivateIP) != "10.0.0.10" {
+					t.Fatalf("expected private ip 10.0.0.10, got %v", aws.StringValue(instance.PrivateIP))
+				}
+			},
+		},
+		{
+			name:    "with reserved private ip",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				PrivateIP:    aws.String("10.0.0.255"),
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1", CidrBlock: "10.0.0.0/24"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if !awserrors.IsConflict(err) {
+					t.Fatalf("expected a conflict error, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "with elastic fabric adapter",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:         "p4d.24xlarge",
+				ElasticFabricAdapter: true,
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if input.SubnetId != nil || input.SecurityGroupIds != nil {
+						t.Fatalf("expected the subnet and security groups to be set on the network interface")
+					}
+					expected := []*ec2.InstanceNetworkInterfaceSpecification{
+						{
+							DeviceIndex:   aws.Int64(0),
+							SubnetId:      aws.String("subnet-1"),
+							Groups:        aws.StringSlice([]string{"2", "3"}),
+							InterfaceType: aws.String("efa"),
+						},
+					}
+					if !reflect.DeepEqual(input.NetworkInterfaces, expected) {
+						t.Fatalf("expected network interfaces %v, got %v", expected, input.NetworkInterfaces)
+					}
+				}, func(instance *ec2.Instance) {
+					instance.InstanceType = aws.String("p4d.24xlarge")
+					instance.NetworkInterfaces = []*ec2.InstanceNetworkInterface{
+						{
+							Attachment: &ec2.InstanceNetworkInterfaceAttachment{
+								DeviceIndex: aws.Int64(0),
+							},
+							InterfaceType: aws.String("efa"),
+						},
+					}
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if !instance.ElasticFabricAdapter {
+					t.Fatalf("expected the elastic fabric adapter to be reported")
+				}
+			},
+		},
+		{
+			name:    "with credit specification",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "t3.medium",
+				CreditSpecification: &infrav1.CreditSpecification{
+					CPUCredits: infrav1.CPUCreditsUnlimited,
+				},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					expected := &ec2.CreditSpecificationRequest{
+						CpuCredits: aws.String("unlimited"),
+					}
+					if !reflect.DeepEqual(input.CreditSpecification, expected) {
+						t.Fatalf("expected credit specification %v, got %v", expected, input.CreditSpecification)
+					}
+				}, func(instance *ec2.Instance) {
+					instance.InstanceType = aws.String("t3.medium")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "with availability zone",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:  "m5.2xlarge",
+				FailureDomain: aws.String("us-east-1c"),
 			},
 			awsCluster: &infrav1.AWSCluster{
 				ObjectMeta: metav1.ObjectMeta{Name: "test"},
@@ -524,11 +995,24 @@ func TestCreateInstance(t *testing.T) {
 					NetworkSpec: infrav1.NetworkSpec{
 						Subnets: infrav1.Subnets{
 							infrav1.SubnetSpec{
-								ID:       "subnet-1",
-								IsPublic: false,
+								ID:               "subnet-1",
+								AvailabilityZone: "us-east-1a",
+								IsPublic:         false,
 							},
 							infrav1.SubnetSpec{
-								IsPublic: false,
+								ID:               "subnet-2",
+								AvailabilityZone: "us-east-1b",
+								IsPublic:         false,
+							},
+							infrav1.SubnetSpec{
+								ID:               "subnet-3",
+								AvailabilityZone: "us-east-1c",
+								IsPublic:         false,
+							},
+							infrav1.SubnetSpec{
+								ID:               "subnet-3-public",
+								AvailabilityZone: "us-east-1c",
+								IsPublic:         true,
 							},
 						},
 					},
@@ -553,45 +1037,21 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				amiName, err := GenerateAmiName("capa-ami-{{.BaseOS}}-?{{.K8sVersion}}-*", "ubuntu-18.04", "v1.16.1")
-				if err != nil {
-					t.Fatalf("Failed to process ami format: %v", err)
-				}
-				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
-					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+					DescribeInstanceTypeOfferings(gomock.Eq(&ec2.DescribeInstanceTypeOfferingsInput{
+						LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
 						Filters: []*ec2.Filter{
-							{
-								Name:   aws.String("owner-id"),
-								Values: []*string{aws.String("test-org-123")},
-							},
-							{
-								Name:   aws.String("name"),
-								Values: []*string{aws.String(amiName)},
-							},
-							{
-								Name:   aws.String("architecture"),
-								Values: []*string{aws.String("x86_64")},
-							},
-							{
-								Name:   aws.String("state"),
-								Values: []*string{aws.String("available")},
-							},
-							{
-								Name:   aws.String("virtualization-type"),
-								Values: []*string{aws.String("hvm")},
-							},
+							{Name: aws.String("instance-type"), Values: aws.StringSlice([]string{"m5.2xlarge"})},
+							{Name: aws.String("location"), Values: aws.StringSlice([]string{"us-east-1c"})},
 						},
 					})).
-					Return(&ec2.DescribeImagesOutput{
-						Images: []*ec2.Image{
-							{
-								Name:         aws.String("ami-1"),
-								CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
-							},
-						},
+					Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+						InstanceTypeOfferings: []*ec2.InstanceTypeOffering{{
+							InstanceType: aws.String("m5.2xlarge"),
+							Location:     aws.String("us-east-1c"),
+						}},
 					}, nil)
-				m. // TODO: Restore these parameters, but with the tags as well
+				m.
 					RunInstances(gomock.Any()).
 					Return(&ec2.Reservation{
 						Instances: []*ec2.Instance{
@@ -604,7 +1064,7 @@ func TestCreateInstance(t *testing.T) {
 								},
 								InstanceId:     aws.String("two"),
 								InstanceType:   aws.String("m5.large"),
-								SubnetId:       aws.String("subnet-1"),
+								SubnetId:       aws.String("subnet-3"),
 								ImageId:        aws.String("ami-1"),
 								RootDeviceName: aws.String("device-1"),
 								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
@@ -629,10 +1089,83 @@ func TestCreateInstance(t *testing.T) {
 				if err != nil {
 					t.Fatalf("did not expect error: %v", err)
 				}
+
+				if instance.SubnetID != "subnet-3" {
+					t.Fatalf("expected subnet-3 from availability zone us-east-1c, got %q", instance.SubnetID)
+				}
 			},
 		},
 		{
-			name: "with ImageLookupOrg specified at the cluster-level",
+			name:    "with secondary subnet for pods in availability zone",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:  "m5.2xlarge",
+				FailureDomain: aws.String("us-east-1c"),
+			},
+			awsCluster: newPrivateSubnetCluster(
+				infrav1.SubnetSpec{
+					ID:               "subnet-1",
+					AvailabilityZone: "us-east-1a",
+				},
+				infrav1.SubnetSpec{
+					ID:               "subnet-2",
+					AvailabilityZone: "us-east-1b",
+				},
+				infrav1.SubnetSpec{
+					ID:               "subnet-3-secondary",
+					AvailabilityZone: "us-east-1c",
+					Tags: infrav1.Tags{
+						infrav1.NameAWSSubnetAssociation: infrav1.SecondarySubnetTagValue,
+					},
+				},
+				infrav1.SubnetSpec{
+					ID:               "subnet-3",
+					AvailabilityZone: "us-east-1c",
+				},
+				infrav1.SubnetSpec{
+					ID:               "subnet-3-public",
+					AvailabilityZone: "us-east-1c",
+					IsPublic:         true,
+				},
+			),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypeOfferings(gomock.Eq(&ec2.DescribeInstanceTypeOfferingsInput{
+						LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+						Filters: []*ec2.Filter{
+							{Name: aws.String("instance-type"), Values: aws.StringSlice([]string{"m5.2xlarge"})},
+							{Name: aws.String("location"), Values: aws.StringSlice([]string{"us-east-1c"})},
+						},
+					})).
+					Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+						InstanceTypeOfferings: []*ec2.InstanceTypeOffering{{
+							InstanceType: aws.String("m5.2xlarge"),
+							Location:     aws.String("us-east-1c"),
+						}},
+					}, nil)
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if aws.StringValue(input.SubnetId) != "subnet-3" {
+						t.Fatalf("expected instance to be launched into subnet-3, got %q", aws.StringValue(input.SubnetId))
+					}
+				}, func(instance *ec2.Instance) {
+					instance.SubnetId = aws.String("subnet-3")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+
+				if instance.SubnetID != "subnet-3" {
+					t.Fatalf("expected primary subnet-3 from availability zone us-east-1c, got %q", instance.SubnetID)
+				}
+			},
+		},
+		{
+			name: "with ImageLookupOrg specified at the machine level",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{"set": "node"},
@@ -645,7 +1178,8 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			machineConfig: &infrav1.AWSMachineSpec{
-				InstanceType: "m5.large",
+				ImageLookupOrg: "test-org-123",
+				InstanceType:   "m5.large",
 			},
 			awsCluster: &infrav1.AWSCluster{
 				ObjectMeta: metav1.ObjectMeta{Name: "test"},
@@ -661,7 +1195,6 @@ func TestCreateInstance(t *testing.T) {
 							},
 						},
 					},
-					ImageLookupOrg: "cluster-level-image-lookup-org",
 				},
 				Status: infrav1.AWSClusterStatus{
 					Network: infrav1.NetworkStatus{
@@ -687,13 +1220,14 @@ func TestCreateInstance(t *testing.T) {
 				if err != nil {
 					t.Fatalf("Failed to process ami format: %v", err)
 				}
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
 					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
 						Filters: []*ec2.Filter{
 							{
 								Name:   aws.String("owner-id"),
-								Values: []*string{aws.String("cluster-level-image-lookup-org")},
+								Values: []*string{aws.String("test-org-123")},
 							},
 							{
 								Name:   aws.String("name"),
@@ -762,7 +1296,7 @@ func TestCreateInstance(t *testing.T) {
 			},
 		},
 		{
-			name: "AWSMachine ImageLookupOrg overrides AWSCluster ImageLookupOrg",
+			name: "with ImageLookupOrg specified at the cluster-level",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{"set": "node"},
@@ -775,8 +1309,7 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			machineConfig: &infrav1.AWSMachineSpec{
-				InstanceType:   "m5.large",
-				ImageLookupOrg: "machine-level-image-lookup-org",
+				InstanceType: "m5.large",
 			},
 			awsCluster: &infrav1.AWSCluster{
 				ObjectMeta: metav1.ObjectMeta{Name: "test"},
@@ -818,13 +1351,14 @@ func TestCreateInstance(t *testing.T) {
 				if err != nil {
 					t.Fatalf("Failed to process ami format: %v", err)
 				}
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				// verify that the ImageLookupOrg is used when finding AMIs
 				m.
 					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
 						Filters: []*ec2.Filter{
 							{
 								Name:   aws.String("owner-id"),
-								Values: []*string{aws.String("machine-level-image-lookup-org")},
+								Values: []*string{aws.String("cluster-level-image-lookup-org")},
 							},
 							{
 								Name:   aws.String("name"),
@@ -893,7 +1427,7 @@ func TestCreateInstance(t *testing.T) {
 			},
 		},
 		{
-			name: "subnet filter and failureDomain defined",
+			name: "AWSMachine ImageLookupOrg overrides AWSCluster ImageLookupOrg",
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: map[string]string{"set": "node"},
@@ -902,16 +1436,148 @@ func TestCreateInstance(t *testing.T) {
 					Bootstrap: clusterv1.Bootstrap{
 						DataSecretName: pointer.StringPtr("bootstrap-data"),
 					},
+					Version: pointer.StringPtr("v1.16.1"),
 				},
 			},
 			machineConfig: &infrav1.AWSMachineSpec{
-				AMI: infrav1.AMIReference{
-					ID: aws.String("abc"),
-				},
-				InstanceType: "m5.large",
-				Subnet: &infrav1.AWSResourceReference{
-					Filters: []infrav1.Filter{{
-						Name:   "tag:some-tag",
+				InstanceType:   "m5.large",
+				ImageLookupOrg: "machine-level-image-lookup-org",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+					ImageLookupOrg: "cluster-level-image-lookup-org",
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.NetworkStatus{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				amiName, err := GenerateAmiName("capa-ami-{{.BaseOS}}-?{{.K8sVersion}}-*", "ubuntu-18.04", "v1.16.1")
+				if err != nil {
+					t.Fatalf("Failed to process ami format: %v", err)
+				}
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
+				// verify that the ImageLookupOrg is used when finding AMIs
+				m.
+					DescribeImages(gomock.Eq(&ec2.DescribeImagesInput{
+						Filters: []*ec2.Filter{
+							{
+								Name:   aws.String("owner-id"),
+								Values: []*string{aws.String("machine-level-image-lookup-org")},
+							},
+							{
+								Name:   aws.String("name"),
+								Values: []*string{aws.String(amiName)},
+							},
+							{
+								Name:   aws.String("architecture"),
+								Values: []*string{aws.String("x86_64")},
+							},
+							{
+								Name:   aws.String("state"),
+								Values: []*string{aws.String("available")},
+							},
+							{
+								Name:   aws.String("virtualization-type"),
+								Values: []*string{aws.String("hvm")},
+							},
+						},
+					})).
+					Return(&ec2.DescribeImagesOutput{
+						Images: []*ec2.Image{
+							{
+								Name:         aws.String("ami-1"),
+								CreationDate: aws.String("2006-01-02T15:04:05.000Z"),
+							},
+						},
+					}, nil)
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Any()).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+								Placement: &ec2.Placement{
+									AvailabilityZone: &az,
+								},
+							},
+						},
+					}, nil)
+
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name: "subnet filter and failureDomain defined",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Subnet: &infrav1.AWSResourceReference{
+					Filters: []infrav1.Filter{{
+						Name:   "tag:some-tag",
 						Values: []string{"some-value"},
 					}},
 				},
@@ -960,6 +1626,20 @@ func TestCreateInstance(t *testing.T) {
 							SubnetId: aws.String("filtered-subnet-1"),
 						}},
 					}, nil)
+				m.
+					DescribeInstanceTypeOfferings(gomock.Eq(&ec2.DescribeInstanceTypeOfferingsInput{
+						LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+						Filters: []*ec2.Filter{
+							{Name: aws.String("instance-type"), Values: aws.StringSlice([]string{"m5.large"})},
+							{Name: aws.String("location"), Values: aws.StringSlice([]string{"us-east-1b"})},
+						},
+					})).
+					Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+						InstanceTypeOfferings: []*ec2.InstanceTypeOffering{{
+							InstanceType: aws.String("m5.large"),
+							Location:     aws.String("us-east-1b"),
+						}},
+					}, nil)
 				m.
 					RunInstances(gomock.Any()).
 					Return(&ec2.Reservation{
@@ -1066,6 +1746,90 @@ func TestCreateInstance(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:    "instance type not offered in the availability zone",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Subnet: &infrav1.AWSResourceReference{
+					ID: aws.String("subnet-1"),
+				},
+				FailureDomain: aws.String("us-east-1b"),
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1", AvailabilityZone: "us-east-1b"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypeOfferings(gomock.Eq(&ec2.DescribeInstanceTypeOfferingsInput{
+						LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+						Filters: []*ec2.Filter{
+							{Name: aws.String("instance-type"), Values: aws.StringSlice([]string{"m5.large"})},
+							{Name: aws.String("location"), Values: aws.StringSlice([]string{"us-east-1b"})},
+						},
+					})).
+					Return(&ec2.DescribeInstanceTypeOfferingsOutput{}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				expectedErrMsg := "instance type \"m5.large\" is not offered in availability zone \"us-east-1b\""
+				if err == nil {
+					t.Fatalf("Expected error, but got nil")
+				}
+
+				if !strings.Contains(err.Error(), expectedErrMsg) {
+					t.Fatalf("Expected error: %s\nInstead got: `%s", expectedErrMsg, err.Error())
+				}
+			},
+		},
+		{
+			name:    "launches the first offered fallback instance type when the instance type is not offered",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:          "m5.large",
+				FallbackInstanceTypes: []string{"m5a.large", "m4.large"},
+				Subnet: &infrav1.AWSResourceReference{
+					ID: aws.String("subnet-1"),
+				},
+				FailureDomain: aws.String("us-east-1b"),
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1", AvailabilityZone: "us-east-1b"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					DescribeInstanceTypeOfferings(gomock.Eq(&ec2.DescribeInstanceTypeOfferingsInput{
+						LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+						Filters: []*ec2.Filter{
+							{Name: aws.String("instance-type"), Values: aws.StringSlice([]string{"m5.large", "m5a.large", "m4.large"})},
+							{Name: aws.String("location"), Values: aws.StringSlice([]string{"us-east-1b"})},
+						},
+					})).
+					Return(&ec2.DescribeInstanceTypeOfferingsOutput{
+						InstanceTypeOfferings: []*ec2.InstanceTypeOffering{{
+							InstanceType: aws.String("m4.large"),
+							Location:     aws.String("us-east-1b"),
+						}},
+					}, nil)
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if aws.StringValue(input.InstanceType) != "m4.large" {
+						t.Fatalf("expected instance type %q, got %q", "m4.large", aws.StringValue(input.InstanceType))
+					}
+				}, func(instance *ec2.Instance) {
+					instance.InstanceType = aws.String("m4.large")
+					instance.Placement.AvailabilityZone = aws.String("us-east-1b")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.Type != "m4.large" {
+					t.Fatalf("expected instance type %q, got %q", "m4.large", instance.Type)
+				}
+			},
+		},
 		{
 			name: "with multiple block device mappings",
 			machine: clusterv1.Machine{
@@ -1169,133 +1933,452 @@ func TestCreateInstance(t *testing.T) {
 			},
 		},
 		{
-			name: "with dedicated tenancy",
-			machine: clusterv1.Machine{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels:    map[string]string{"set": "node"},
-					Namespace: "default",
-					Name:      "machine-aws-test1",
-				},
-				Spec: clusterv1.MachineSpec{
-					Bootstrap: clusterv1.Bootstrap{
-						DataSecretName: pointer.StringPtr("bootstrap-data"),
-					},
-				},
-			},
+			name: "with dedicated tenancy",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:    map[string]string{"set": "node"},
+					Namespace: "default",
+					Name:      "machine-aws-test1",
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Tenancy:      "dedicated",
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+							infrav1.SubnetSpec{
+								IsPublic: false,
+							},
+						},
+					},
+				},
+				Status: infrav1.AWSClusterStatus{
+					Network: infrav1.NetworkStatus{
+						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+							infrav1.SecurityGroupControlPlane: {
+								ID: "1",
+							},
+							infrav1.SecurityGroupNode: {
+								ID: "2",
+							},
+							infrav1.SecurityGroupLB: {
+								ID: "3",
+							},
+						},
+						APIServerELB: infrav1.ClassicELB{
+							DNSName: "test-apiserver.us-east-1.aws",
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m. // TODO: Restore these parameters, but with the tags as well
+					RunInstances(gomock.Eq(&ec2.RunInstancesInput{
+						ImageId:      aws.String("abc"),
+						InstanceType: aws.String("m5.large"),
+						KeyName:      aws.String("default"),
+						MaxCount:     aws.Int64(1),
+						MinCount:     aws.Int64(1),
+						Placement: &ec2.Placement{
+							Tenancy: &tenancy,
+						},
+						SecurityGroupIds: []*string{aws.String("2"), aws.String("3")},
+						SubnetId:         aws.String("subnet-1"),
+						TagSpecifications: []*ec2.TagSpecification{
+							{
+								ResourceType: aws.String("instance"),
+								Tags: []*ec2.Tag{
+									{
+										Key:   aws.String("MachineName"),
+										Value: aws.String("default/machine-aws-test1"),
+									},
+									{
+										Key:   aws.String("Name"),
+										Value: aws.String("aws-test1"),
+									},
+									{
+										Key:   aws.String("kubernetes.io/cluster/test1"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test1"),
+										Value: aws.String("owned"),
+									},
+									{
+										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+										Value: aws.String("node"),
+									},
+								},
+							},
+						},
+						UserData: aws.String(base64.StdEncoding.EncodeToString(userData)),
+					})).
+					Return(&ec2.Reservation{
+						Instances: []*ec2.Instance{
+							{
+								State: &ec2.InstanceState{
+									Name: aws.String(ec2.InstanceStateNamePending),
+								},
+								IamInstanceProfile: &ec2.IamInstanceProfile{
+									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
+								},
+								InstanceId:     aws.String("two"),
+								InstanceType:   aws.String("m5.large"),
+								SubnetId:       aws.String("subnet-1"),
+								ImageId:        aws.String("ami-1"),
+								RootDeviceName: aws.String("device-1"),
+								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+									{
+										DeviceName: aws.String("device-1"),
+										Ebs: &ec2.EbsInstanceBlockDevice{
+											VolumeId: aws.String("volume-1"),
+										},
+									},
+								},
+								Placement: &ec2.Placement{
+									AvailabilityZone: &az,
+									Tenancy:          &tenancy,
+								},
+							},
+						},
+					}, nil)
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+			},
+		},
+		{
+			name:    "with a dedicated host",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Tenancy:      "host",
+				HostID:       "h-0123456789abcdef0",
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					expected := &ec2.Placement{
+						Tenancy: aws.String("host"),
+						HostId:  aws.String("h-0123456789abcdef0"),
+					}
+					if !reflect.DeepEqual(input.Placement, expected) {
+						t.Fatalf("expected placement %v, got %v", expected, input.Placement)
+					}
+				}, func(instance *ec2.Instance) {
+					instance.Placement.Tenancy = aws.String("host")
+					instance.Placement.HostId = aws.String("h-0123456789abcdef0")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.HostID != "h-0123456789abcdef0" {
+					t.Fatalf("expected host id %q, got %q", "h-0123456789abcdef0", instance.HostID)
+				}
+			},
+		},
+		{
+			name:    "with placement group",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				PlacementGroup: &infrav1.PlacementGroup{
+					Name:     "test-pg",
+					Strategy: infrav1.PlacementGroupStrategyCluster,
+				},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.Eq(&ec2.DescribePlacementGroupsInput{
+					GroupNames: []*string{aws.String("test-pg")},
+				})).
+					Return(nil, awserr.New(awserrors.PlacementGroupNotFound, "not found", nil))
+				m.CreatePlacementGroup(gomock.AssignableToTypeOf(&ec2.CreatePlacementGroupInput{})).
+					DoAndReturn(func(input *ec2.CreatePlacementGroupInput) (*ec2.CreatePlacementGroupOutput, error) {
+						if aws.StringValue(input.GroupName) != "test-pg" || aws.StringValue(input.Strategy) != "cluster" {
+							t.Fatalf("unexpected placement group input: %v", input)
+						}
+						return &ec2.CreatePlacementGroupOutput{}, nil
+					})
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if input.Placement == nil || aws.StringValue(input.Placement.GroupName) != "test-pg" {
+						t.Fatalf("expected instance to be launched into placement group %q, got %v", "test-pg", input.Placement)
+					}
+				}, func(instance *ec2.Instance) {
+					instance.Placement.GroupName = aws.String("test-pg")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.PlacementGroupName != "test-pg" {
+					t.Fatalf("expected placement group name %q, got %q", "test-pg", instance.PlacementGroupName)
+				}
+			},
+		},
+		{
+			name:    "with fallback instance types on insufficient capacity",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:          "m5.large",
+				FallbackInstanceTypes: []string{"m5a.large", "m4.large"},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					RunInstances(gomock.AssignableToTypeOf(&ec2.RunInstancesInput{})).
+					DoAndReturn(func(input *ec2.RunInstancesInput) (*ec2.Reservation, error) {
+						if aws.StringValue(input.InstanceType) != "m5.large" {
+							t.Fatalf("expected instance type %q, got %q", "m5.large", aws.StringValue(input.InstanceType))
+						}
+						return nil, awserr.New(awserrors.InsufficientCapacity, "insufficient capacity", nil)
+					})
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if aws.StringValue(input.InstanceType) != "m5a.large" {
+						t.Fatalf("expected instance type %q, got %q", "m5a.large", aws.StringValue(input.InstanceType))
+					}
+				}, func(instance *ec2.Instance) {
+					instance.InstanceType = aws.String("m5a.large")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.Type != "m5a.large" {
+					t.Fatalf("expected instance type %q, got %q", "m5a.large", instance.Type)
+				}
+			},
+		},
+		{
+			name:    "with a spot fleet",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType:      "m5.large",
+				SpotMarketOptions: &infrav1.SpotMarketOptions{},
+				Fleet: &infrav1.Fleet{
+					Overrides: []infrav1.FleetOverride{
+						{},
+						{InstanceType: "m5a.large", SubnetID: "subnet-2"},
+					},
+					AllocationStrategy: infrav1.FleetAllocationStrategyCapacityOptimized,
+				},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.
+					CreateLaunchTemplate(gomock.AssignableToTypeOf(&ec2.CreateLaunchTemplateInput{})).
+					DoAndReturn(func(input *ec2.CreateLaunchTemplateInput) (*ec2.CreateLaunchTemplateOutput, error) {
+						if aws.StringValue(input.LaunchTemplateName) != "aws-test1-fleet" {
+							t.Fatalf("expected launch template %q, got %q", "aws-test1-fleet", aws.StringValue(input.LaunchTemplateName))
+						}
+						if input.LaunchTemplateData.InstanceType != nil || input.LaunchTemplateData.InstanceMarketOptions != nil {
+							t.Fatalf("expected the instance type and market options to be left to the fleet, got %v", input.LaunchTemplateData)
+						}
+						return &ec2.CreateLaunchTemplateOutput{}, nil
+					})
+				m.
+					CreateFleet(gomock.AssignableToTypeOf(&ec2.CreateFleetInput{})).
+					DoAndReturn(func(input *ec2.CreateFleetInput) (*ec2.CreateFleetOutput, error) {
+						if aws.StringValue(input.Type) != ec2.FleetTypeInstant {
+							t.Fatalf("expected fleet type %q, got %q", ec2.FleetTypeInstant, aws.StringValue(input.Type))
+						}
+						if aws.StringValue(input.TargetCapacitySpecification.DefaultTargetCapacityType) != ec2.DefaultTargetCapacityTypeSpot {
+							t.Fatalf("expected spot capacity, got %v", input.TargetCapacitySpecification)
+						}
+						if input.SpotOptions == nil || aws.StringValue(input.SpotOptions.AllocationStrategy) != ec2.SpotAllocationStrategyCapacityOptimized {
+							t.Fatalf("expected capacity optimized allocation, got %v", input.SpotOptions)
+						}
+						expected := []*ec2.FleetLaunchTemplateOverridesRequest{
+							{InstanceType: aws.String("m5.large"), SubnetId: aws.String("subnet-1")},
+							{InstanceType: aws.String("m5a.large"), SubnetId: aws.String("subnet-2")},
+						}
+						if !reflect.DeepEqual(input.LaunchTemplateConfigs[0].Overrides, expected) {
+							t.Fatalf("expected overrides %v, got %v", expected, input.LaunchTemplateConfigs[0].Overrides)
+						}
+						return &ec2.CreateFleetOutput{
+							FleetId: aws.String("fleet-1"),
+							Instances: []*ec2.CreateFleetInstance{
+								{InstanceIds: aws.StringSlice([]string{"two"})},
+							},
+						}, nil
+					})
+				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil)
+				m.
+					DescribeInstances(&ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{"two"})}).
+					Return(&ec2.DescribeInstancesOutput{
+						Reservations: []*ec2.Reservation{
+							{
+								Instances: []*ec2.Instance{
+									{
+										State: &ec2.InstanceState{
+											Name: aws.String(ec2.InstanceStateNameRunning),
+										},
+										InstanceId:        aws.String("two"),
+										InstanceType:      aws.String("m5a.large"),
+										SubnetId:          aws.String("subnet-2"),
+										ImageId:           aws.String("abc"),
+										InstanceLifecycle: aws.String(ec2.InstanceLifecycleTypeSpot),
+										Placement: &ec2.Placement{
+											AvailabilityZone: &az,
+										},
+									},
+								},
+							},
+						},
+					}, nil)
+				m.
+					DeleteLaunchTemplate(&ec2.DeleteLaunchTemplateInput{LaunchTemplateName: aws.String("aws-test1-fleet")}).
+					Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.Type != "m5a.large" || instance.SubnetID != "subnet-2" {
+					t.Fatalf("expected instance type %q in %q, got %q in %q", "m5a.large", "subnet-2", instance.Type, instance.SubnetID)
+				}
+			},
+		},
+		{
+			name:    "with a fleet that launches no instance",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				Fleet: &infrav1.Fleet{
+					Overrides: []infrav1.FleetOverride{{InstanceType: "m5a.large"}},
+				},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				// The launch template left behind by a previous attempt is replaced.
+				m.
+					CreateLaunchTemplate(gomock.AssignableToTypeOf(&ec2.CreateLaunchTemplateInput{})).
+					Return(nil, awserr.New(awserrors.LaunchTemplateNameExists, "already exists", nil))
+				m.
+					DeleteLaunchTemplate(&ec2.DeleteLaunchTemplateInput{LaunchTemplateName: aws.String("aws-test1-fleet")}).
+					Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+				m.
+					CreateLaunchTemplate(gomock.AssignableToTypeOf(&ec2.CreateLaunchTemplateInput{})).
+					Return(&ec2.CreateLaunchTemplateOutput{}, nil)
+				m.
+					CreateFleet(gomock.AssignableToTypeOf(&ec2.CreateFleetInput{})).
+					DoAndReturn(func(input *ec2.CreateFleetInput) (*ec2.CreateFleetOutput, error) {
+						if aws.StringValue(input.TargetCapacitySpecification.DefaultTargetCapacityType) != ec2.DefaultTargetCapacityTypeOnDemand {
+							t.Fatalf("expected on-demand capacity, got %v", input.TargetCapacitySpecification)
+						}
+						return &ec2.CreateFleetOutput{
+							FleetId: aws.String("fleet-1"),
+							Errors: []*ec2.CreateFleetError{
+								{
+									ErrorCode:    aws.String(awserrors.InsufficientCapacity),
+									ErrorMessage: aws.String("insufficient capacity"),
+								},
+							},
+						}, nil
+					})
+				m.
+					DeleteLaunchTemplate(&ec2.DeleteLaunchTemplateInput{LaunchTemplateName: aws.String("aws-test1-fleet")}).
+					Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err == nil || !strings.Contains(err.Error(), awserrors.InsufficientCapacity) {
+					t.Fatalf("expected the fleet errors to be returned, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "with a capacity reservation",
+			machine: newNodeMachine(),
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				CapacityReservation: &infrav1.CapacityReservation{
+					ID: aws.String("cr-0123456789abcdef0"),
+				},
+			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					expected := &ec2.CapacityReservationSpecification{
+						CapacityReservationTarget: &ec2.CapacityReservationTarget{
+							CapacityReservationId: aws.String("cr-0123456789abcdef0"),
+						},
+					}
+					if !reflect.DeepEqual(input.CapacityReservationSpecification, expected) {
+						t.Fatalf("expected capacity reservation %v, got %v", expected, input.CapacityReservationSpecification)
+					}
+				}, func(instance *ec2.Instance) {
+					instance.CapacityReservationId = aws.String("cr-0123456789abcdef0")
+				})
+			},
+			check: func(instance *infrav1.Instance, err error) {
+				if err != nil {
+					t.Fatalf("did not expect error: %v", err)
+				}
+				if instance.CapacityReservation == nil || aws.StringValue(instance.CapacityReservation.ID) != "cr-0123456789abcdef0" {
+					t.Fatalf("expected capacity reservation %q, got %v", "cr-0123456789abcdef0", instance.CapacityReservation)
+				}
+			},
+		},
+		{
+			name:    "machine with termination protection",
+			machine: newNodeMachine(),
 			machineConfig: &infrav1.AWSMachineSpec{
 				AMI: infrav1.AMIReference{
 					ID: aws.String("abc"),
 				},
-				InstanceType: "m5.large",
-				Tenancy:      "dedicated",
-			},
-			awsCluster: &infrav1.AWSCluster{
-				ObjectMeta: metav1.ObjectMeta{Name: "test"},
-				Spec: infrav1.AWSClusterSpec{
-					NetworkSpec: infrav1.NetworkSpec{
-						Subnets: infrav1.Subnets{
-							infrav1.SubnetSpec{
-								ID:       "subnet-1",
-								IsPublic: false,
-							},
-							infrav1.SubnetSpec{
-								IsPublic: false,
-							},
-						},
-					},
-				},
-				Status: infrav1.AWSClusterStatus{
-					Network: infrav1.NetworkStatus{
-						SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
-							infrav1.SecurityGroupControlPlane: {
-								ID: "1",
-							},
-							infrav1.SecurityGroupNode: {
-								ID: "2",
-							},
-							infrav1.SecurityGroupLB: {
-								ID: "3",
-							},
-						},
-						APIServerELB: infrav1.ClassicELB{
-							DNSName: "test-apiserver.us-east-1.aws",
-						},
-					},
-				},
+				InstanceType:          "m5.large",
+				TerminationProtection: aws.Bool(true),
 			},
+			awsCluster: newPrivateSubnetCluster(infrav1.SubnetSpec{ID: "subnet-1"}),
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
-				m. // TODO: Restore these parameters, but with the tags as well
-					RunInstances(gomock.Eq(&ec2.RunInstancesInput{
-						ImageId:      aws.String("abc"),
-						InstanceType: aws.String("m5.large"),
-						KeyName:      aws.String("default"),
-						MaxCount:     aws.Int64(1),
-						MinCount:     aws.Int64(1),
-						Placement: &ec2.Placement{
-							Tenancy: &tenancy,
-						},
-						SecurityGroupIds: []*string{aws.String("2"), aws.String("3")},
-						SubnetId:         aws.String("subnet-1"),
-						TagSpecifications: []*ec2.TagSpecification{
-							{
-								ResourceType: aws.String("instance"),
-								Tags: []*ec2.Tag{
-									{
-										Key:   aws.String("MachineName"),
-										Value: aws.String("default/machine-aws-test1"),
-									},
-									{
-										Key:   aws.String("Name"),
-										Value: aws.String("aws-test1"),
-									},
-									{
-										Key:   aws.String("kubernetes.io/cluster/test1"),
-										Value: aws.String("owned"),
-									},
-									{
-										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test1"),
-										Value: aws.String("owned"),
-									},
-									{
-										Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
-										Value: aws.String("node"),
-									},
-								},
-							},
-						},
-						UserData: aws.String(base64.StdEncoding.EncodeToString(userData)),
-					})).
-					Return(&ec2.Reservation{
-						Instances: []*ec2.Instance{
-							{
-								State: &ec2.InstanceState{
-									Name: aws.String(ec2.InstanceStateNamePending),
-								},
-								IamInstanceProfile: &ec2.IamInstanceProfile{
-									Arn: aws.String("arn:aws:iam::123456789012:instance-profile/foo"),
-								},
-								InstanceId:     aws.String("two"),
-								InstanceType:   aws.String("m5.large"),
-								SubnetId:       aws.String("subnet-1"),
-								ImageId:        aws.String("ami-1"),
-								RootDeviceName: aws.String("device-1"),
-								BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
-									{
-										DeviceName: aws.String("device-1"),
-										Ebs: &ec2.EbsInstanceBlockDevice{
-											VolumeId: aws.String("volume-1"),
-										},
-									},
-								},
-								Placement: &ec2.Placement{
-									AvailabilityZone: &az,
-									Tenancy:          &tenancy,
-								},
-							},
-						},
-					}, nil)
-				m.WaitUntilInstanceRunningWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(nil)
+				expectRunInstance(m, func(input *ec2.RunInstancesInput) {
+					if !aws.BoolValue(input.DisableApiTermination) {
+						t.Fatalf("expected termination protection to be enabled")
+					}
+				}, nil)
 			},
 			check: func(instance *infrav1.Instance, err error) {
 				if err != nil {
@@ -1354,6 +2437,7 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
@@ -1463,6 +2547,7 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
@@ -1573,6 +2658,7 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
@@ -1683,6 +2769,7 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
@@ -1790,6 +2877,7 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
@@ -1897,6 +2985,7 @@ func TestCreateInstance(t *testing.T) {
 				},
 			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				expectDescribeInstanceType(m, "m5.large", "x86_64")
 				m.
 					DescribeImages(gomock.Any()).
 					Return(&ec2.DescribeImagesOutput{
@@ -1951,6 +3040,48 @@ func TestCreateInstance(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "with public IP in an isolated VPC",
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"set": "node"},
+				},
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: pointer.StringPtr("bootstrap-data"),
+					},
+				},
+			},
+			machineConfig: &infrav1.AWSMachineSpec{
+				AMI: infrav1.AMIReference{
+					ID: aws.String("abc"),
+				},
+				InstanceType: "m5.large",
+				PublicIP:     aws.Bool(true),
+			},
+			awsCluster: &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{
+							Isolated: true,
+						},
+						Subnets: infrav1.Subnets{
+							infrav1.SubnetSpec{
+								ID:       "subnet-1",
+								IsPublic: false,
+							},
+						},
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			check: func(instance *infrav1.Instance, err error) {
+				if err == nil {
+					t.Fatalf("expected an error for a public IP in an isolated VPC")
+				}
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -2088,6 +3219,103 @@ func TestGetInstanceMarketOptionsRequest(t *testing.T) {
 	}
 }
 
+func TestGetPlacement(t *testing.T) {
+	testCases := []struct {
+		name              string
+		instance          *infrav1.Instance
+		expectedPlacement *ec2.Placement
+	}{
+		{
+			name:              "with no placement specified",
+			instance:          &infrav1.Instance{},
+			expectedPlacement: nil,
+		},
+		{
+			name: "with dedicated tenancy",
+			instance: &infrav1.Instance{
+				Tenancy: "dedicated",
+			},
+			expectedPlacement: &ec2.Placement{
+				Tenancy: aws.String("dedicated"),
+			},
+		},
+		{
+			name: "with a dedicated host",
+			instance: &infrav1.Instance{
+				Tenancy: "host",
+				HostID:  "h-0123456789abcdef0",
+			},
+			expectedPlacement: &ec2.Placement{
+				Tenancy: aws.String("host"),
+				HostId:  aws.String("h-0123456789abcdef0"),
+			},
+		},
+		{
+			name: "with a host resource group",
+			instance: &infrav1.Instance{
+				Tenancy:              "host",
+				HostResourceGroupArn: "arn:aws:resource-groups:us-east-1:123456789012:group/hosts",
+			},
+			expectedPlacement: &ec2.Placement{
+				Tenancy:              aws.String("host"),
+				HostResourceGroupArn: aws.String("arn:aws:resource-groups:us-east-1:123456789012:group/hosts"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			placement := getPlacement(tc.instance)
+			if !reflect.DeepEqual(placement, tc.expectedPlacement) {
+				t.Errorf("Case: %s. Got: %v, expected: %v", tc.name, placement, tc.expectedPlacement)
+			}
+		})
+	}
+}
+
+func TestGetCapacityReservationSpecification(t *testing.T) {
+	testCases := []struct {
+		name                string
+		capacityReservation *infrav1.CapacityReservation
+		expected            *ec2.CapacityReservationSpecification
+	}{
+		{
+			name:                "with no capacity reservation specified",
+			capacityReservation: nil,
+			expected:            nil,
+		},
+		{
+			name: "with a capacity reservation id",
+			capacityReservation: &infrav1.CapacityReservation{
+				ID: aws.String("cr-0123456789abcdef0"),
+			},
+			expected: &ec2.CapacityReservationSpecification{
+				CapacityReservationTarget: &ec2.CapacityReservationTarget{
+					CapacityReservationId: aws.String("cr-0123456789abcdef0"),
+				},
+			},
+		},
+		{
+			name: "with a capacity reservation preference",
+			capacityReservation: &infrav1.CapacityReservation{
+				Preference: infrav1.CapacityReservationPreferenceNone,
+			},
+			expected: &ec2.CapacityReservationSpecification{
+				CapacityReservationPreference: aws.String("none"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := getCapacityReservationSpecification(tc.capacityReservation)
+			if !reflect.DeepEqual(spec, tc.expected) {
+				t.Errorf("Case: %s. Got: %v, expected: %v", tc.name, spec, tc.expected)
+			}
+		})
+	}
+}
+
 func TestGetFilteredSecurityGroupID(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -2222,6 +3450,83 @@ func TestGetFilteredSecurityGroupID(t *testing.T) {
 	}
 }
 
+func TestCheckRootVolume(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	image := &ec2.Image{
+		ImageId:        aws.String("ami-1"),
+		RootDeviceName: aws.String("/dev/sda1"),
+		BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+			{
+				DeviceName: aws.String("/dev/sdb"),
+				Ebs:        &ec2.EbsBlockDevice{VolumeSize: aws.Int64(100)},
+			},
+			{
+				DeviceName: aws.String("/dev/sda1"),
+				Ebs:        &ec2.EbsBlockDevice{VolumeSize: aws.Int64(20)},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		volume       *infrav1.Volume
+		image        *ec2.Image
+		expectError  bool
+		expectDevice string
+	}{
+		{
+			name:         "root volume larger than root snapshot",
+			volume:       &infrav1.Volume{Size: 50, Type: infrav1.VolumeTypeGP3},
+			image:        image,
+			expectDevice: "/dev/sda1",
+		},
+		{
+			name:        "root volume smaller than root snapshot",
+			volume:      &infrav1.Volume{Size: 10},
+			image:       image,
+			expectError: true,
+		},
+		{
+			name:   "image without root device mapping",
+			volume: &infrav1.Volume{Size: 50},
+			image: &ec2.Image{
+				ImageId:        aws.String("ami-1"),
+				RootDeviceName: aws.String("/dev/xvda"),
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+			ec2Mock.EXPECT().
+				DescribeImages(&ec2.DescribeImagesInput{ImageIds: []*string{aws.String("ami-1")}}).
+				Return(&ec2.DescribeImagesOutput{Images: []*ec2.Image{tc.image}}, nil)
+
+			s := Service{
+				EC2Client: ec2Mock,
+			}
+
+			device, err := s.checkRootVolume(tc.volume, "ami-1")
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected an error but did not get one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if aws.StringValue(device) != tc.expectDevice {
+				t.Fatalf("expected root device %q, got %q", tc.expectDevice, aws.StringValue(device))
+			}
+		})
+	}
+}
+
 func setupScheme() (*runtime.Scheme, error) {
 	scheme := runtime.NewScheme()
 	if err := clusterv1.AddToScheme(scheme); err != nil {
@@ -2235,3 +3540,21 @@ func setupScheme() (*runtime.Scheme, error) {
 	}
 	return scheme, nil
 }
+
+// expectDescribeInstanceType expects the instance type to be described once, supporting the given architectures.
+func expectDescribeInstanceType(m *mock_ec2iface.MockEC2APIMockRecorder, instanceType string, architectures ...string) {
+	m.
+		DescribeInstanceTypes(gomock.Eq(&ec2.DescribeInstanceTypesInput{
+			InstanceTypes: []*string{aws.String(instanceType)},
+		})).
+		Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				{
+					InstanceType: aws.String(instanceType),
+					ProcessorInfo: &ec2.ProcessorInfo{
+						SupportedArchitectures: aws.StringSlice(architectures),
+					},
+				},
+			},
+		}, nil)
+}
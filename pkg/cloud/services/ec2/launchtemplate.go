@@ -402,6 +402,14 @@ func (s *Service) DiscoverLaunchTemplateAMI(scope *scope.MachinePoolScope) (*str
 		return lt.AMI.ID, nil
 	}
 
+	if lt.AMI.SSMParameterName != nil {
+		id, err := s.ssmAMILookup(*lt.AMI.SSMParameterName)
+		if err != nil {
+			return nil, err
+		}
+		return aws.String(id), nil
+	}
+
 	if scope.MachinePool.Spec.Template.Spec.Version == nil {
 		err := errors.New("Either AWSMachinePool's spec.awslaunchtemplate.ami.id or MachinePool's spec.template.spec.version must be defined")
 		s.scope.Error(err, "")
@@ -427,12 +435,12 @@ func (s *Service) DiscoverLaunchTemplateAMI(scope *scope.MachinePoolScope) (*str
 	}
 
 	if scope.IsEKSManaged() && imageLookupFormat == "" && imageLookupOrg == "" && imageLookupBaseOS == "" {
-		lookupAMI, err = s.eksAMILookup(*scope.MachinePool.Spec.Template.Spec.Version, scope.AWSMachinePool.Spec.AWSLaunchTemplate.AMI.EKSOptimizedLookupType)
+		lookupAMI, err = s.eksAMILookupForInstanceType(*scope.MachinePool.Spec.Template.Spec.Version, lt.InstanceType, lt.AMI.EKSOptimizedLookupType)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		lookupAMI, err = s.defaultAMIIDLookup(imageLookupFormat, imageLookupOrg, imageLookupBaseOS, *scope.MachinePool.Spec.Template.Spec.Version)
+		lookupAMI, err = s.defaultAMIIDLookupForInstanceType(imageLookupFormat, imageLookupOrg, imageLookupBaseOS, lt.InstanceType, *scope.MachinePool.Spec.Template.Spec.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// reconcilePlacementGroup creates the placement group if it does not exist yet.
+// Placement groups created here are tagged as owned by the cluster so that they are removed with it.
+func (s *Service) reconcilePlacementGroup(pg *infrav1.PlacementGroup) error {
+	strategy := pg.Strategy
+	if strategy == "" {
+		strategy = infrav1.PlacementGroupStrategyCluster
+	}
+
+	out, err := s.EC2Client.DescribePlacementGroups(&ec2.DescribePlacementGroupsInput{
+		GroupNames: aws.StringSlice([]string{pg.Name}),
+	})
+	switch {
+	case awserrors.IsInvalidNotFoundError(err):
+	case err != nil:
+		return errors.Wrapf(err, "failed to describe placement group %q", pg.Name)
+	default:
+		for _, group := range out.PlacementGroups {
+			if aws.StringValue(group.GroupName) != pg.Name {
+				continue
+			}
+			if aws.StringValue(group.Strategy) != string(strategy) {
+				return errors.Errorf("placement group %q has strategy %q, expected %q", pg.Name, aws.StringValue(group.Strategy), strategy)
+			}
+			return nil
+		}
+	}
+
+	input := &ec2.CreatePlacementGroupInput{
+		GroupName: aws.String(pg.Name),
+		Strategy:  aws.String(string(strategy)),
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2.ResourceTypePlacementGroup, s.getPlacementGroupTagParams(pg.Name)),
+		},
+	}
+	if strategy == infrav1.PlacementGroupStrategyPartition {
+		input.PartitionCount = pg.PartitionCount
+	}
+
+	if _, err := s.EC2Client.CreatePlacementGroup(input); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreatePlacementGroup", "Failed to create placement group %q: %v", pg.Name, err)
+		return errors.Wrapf(err, "failed to create placement group %q", pg.Name)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreatePlacementGroup", "Created placement group %q with strategy %q", pg.Name, strategy)
+	return nil
+}
+
+// DeletePlacementGroups deletes the placement groups owned by the cluster.
+func (s *Service) DeletePlacementGroups() error {
+	out, err := s.EC2Client.DescribePlacementGroups(&ec2.DescribePlacementGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to describe placement groups")
+	}
+
+	for _, group := range out.PlacementGroups {
+		if _, err := s.EC2Client.DeletePlacementGroup(&ec2.DeletePlacementGroupInput{
+			GroupName: group.GroupName,
+		}); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedDeletePlacementGroup", "Failed to delete placement group %q: %v", aws.StringValue(group.GroupName), err)
+			return errors.Wrapf(err, "failed to delete placement group %q", aws.StringValue(group.GroupName))
+		}
+
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulDeletePlacementGroup", "Deleted placement group %q", aws.StringValue(group.GroupName))
+	}
+
+	return nil
+}
+
+func (s *Service) getPlacementGroupTagParams(name string) infrav1.BuildParams {
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
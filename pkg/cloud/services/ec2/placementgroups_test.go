@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ec2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDeletePlacementGroups(t *testing.T) {
+	clusterName := "cluster"
+
+	// Only the groups owned by the cluster are described, so groups created outside of it are never deleted.
+	describeInput := &ec2.DescribePlacementGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(clusterName),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		expect      func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectError bool
+	}{
+		{
+			name: "deletes the groups owned by the cluster",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{GroupName: aws.String("pg-1")},
+							{GroupName: aws.String("pg-2")},
+						},
+					}, nil)
+				m.DeletePlacementGroup(gomock.Eq(&ec2.DeletePlacementGroupInput{GroupName: aws.String("pg-1")})).
+					Return(&ec2.DeletePlacementGroupOutput{}, nil)
+				m.DeletePlacementGroup(gomock.Eq(&ec2.DeletePlacementGroupInput{GroupName: aws.String("pg-2")})).
+					Return(&ec2.DeletePlacementGroupOutput{}, nil)
+			},
+		},
+		{
+			name: "leaves unowned groups alone",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribePlacementGroupsOutput{}, nil)
+			},
+		},
+		{
+			name: "fails while a group is still in use",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribePlacementGroups(gomock.Eq(describeInput)).
+					Return(&ec2.DescribePlacementGroupsOutput{
+						PlacementGroups: []*ec2.PlacementGroup{
+							{GroupName: aws.String("pg-1")},
+						},
+					}, nil)
+				m.DeletePlacementGroup(gomock.Eq(&ec2.DeletePlacementGroupInput{GroupName: aws.String("pg-1")})).
+					Return(nil, awserr.New("InvalidPlacementGroup.InUse", "The placement group is in use", nil))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockControl)
+
+			scheme, err := setupScheme()
+			g.Expect(err).To(BeNil())
+
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			}
+
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			ctx := context.TODO()
+			client.Create(ctx, awsCluster)
+
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "ns",
+						Name:      clusterName,
+					},
+				},
+				AWSCluster: awsCluster,
+				Client:     client,
+			})
+			g.Expect(err).To(BeNil())
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			err = s.DeletePlacementGroups()
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(BeNil())
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package ec2
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
 
@@ -30,8 +32,12 @@ type Service struct {
 	scope     scope.EC2Scope
 	EC2Client ec2iface.EC2API
 
-	// SSMClient is used to look up the official EKS AMI ID
+	// SSMClient is used to look up the official EKS and bastion AMI IDs
 	SSMClient ssmiface.SSMAPI
+
+	// instanceSnapshotKey is where the description of all the cluster's instances is cached. When
+	// empty, instances are always described by ID.
+	instanceSnapshotKey string
 }
 
 // NewService returns a new service given the ec2 api client.
@@ -42,3 +48,19 @@ func NewService(clusterScope scope.EC2Scope) *Service {
 		SSMClient: scope.NewSSMClient(clusterScope, clusterScope, clusterScope, clusterScope.InfraCluster()),
 	}
 }
+
+// NewCachedService returns a new service whose instance type, subnet and security group lookups
+// are cached for a short while and shared with the other services of the same cluster. Instances
+// are looked up in a description of all the cluster's instances, refreshed every few seconds.
+func NewCachedService(clusterScope scope.EC2Scope) *Service {
+	s := NewService(clusterScope)
+	keyPrefix := fmt.Sprintf("%s/%s/%s", clusterScope.Namespace(), clusterScope.InfraClusterName(), clusterScope.Region())
+	s.EC2Client = &cachingEC2Client{
+		EC2API:    s.EC2Client,
+		cache:     describeCache,
+		group:     &describeGroup,
+		keyPrefix: keyPrefix,
+	}
+	s.instanceSnapshotKey = keyPrefix + "/instances"
+	return s
+}
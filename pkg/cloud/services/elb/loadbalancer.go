@@ -26,6 +26,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -41,8 +42,12 @@ import (
 
 // ResourceGroups are filtered by ARN identifier: https://docs.aws.amazon.com/general/latest/gr/aws-arns-and-namespaces.html#arns-syntax
 // this is the identifier for classic ELBs: https://docs.aws.amazon.com/IAM/latest/UserGuide/list_elasticloadbalancing.html#elasticloadbalancing-resources-for-iam-policies
+// It also matches network and application load balancers, whose ARNs have a loadbalancer/net/ or loadbalancer/app/ resource.
 const elbResourceType = "elasticloadbalancing:loadbalancer"
 
+// elbResourcePrefix precedes the name of a classic ELB, or the type, name and id of a v2 load balancer, in its ARN.
+const elbResourcePrefix = ":loadbalancer/"
+
 // maxELBsDescribeTagsRequest is the maximum number of loadbalancers for the DescribeTags API call
 // see: https://docs.aws.amazon.com/elasticloadbalancing/2012-06-01/APIReference/API_DescribeTags.html
 const maxELBsDescribeTagsRequest = 20
@@ -51,6 +56,10 @@ const maxELBsDescribeTagsRequest = 20
 func (s *Service) ReconcileLoadbalancers() error {
 	s.scope.V(2).Info("Reconciling load balancers")
 
+	if s.isNetworkLoadBalancer() {
+		return s.reconcileNetworkLoadBalancer()
+	}
+
 	// Get default api server spec.
 	spec, err := s.getAPIServerClassicELBSpec()
 	if err != nil {
@@ -77,6 +86,13 @@ func (s *Service) ReconcileLoadbalancers() error {
 		}
 	}
 
+	if spec.HealthCheck != nil && !reflect.DeepEqual(spec.HealthCheck, apiELB.HealthCheck) {
+		if err := s.configureHealthCheck(apiELB.Name, spec.HealthCheck); err != nil {
+			return err
+		}
+		apiELB.HealthCheck = spec.HealthCheck
+	}
+
 	if err := s.reconcileELBTags(apiELB.Name, spec.Tags); err != nil {
 		return errors.Wrapf(err, "failed to reconcile tags for apiserver load balancer %q", apiELB.Name)
 	}
@@ -124,17 +140,33 @@ func (s *Service) DeleteLoadbalancers() error {
 		return err
 	}
 
+	// Network load balancers created by the cloud provider for Services of type LoadBalancer.
+	v2LoadBalancers, err := s.listOwnedV2LoadBalancers()
+	if err != nil {
+		return err
+	}
+
 	elbName, err := GenerateELBName(s.scope.Name())
 	if err != nil {
 		return err
 	}
-	elbs = append(elbs, elbName)
+	if !s.isNetworkLoadBalancer() {
+		elbs = append(elbs, elbName)
+	}
 
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
 		return err
 	}
 
+	if s.isNetworkLoadBalancer() {
+		s.scope.V(3).Info("deleting network load balancer", "name", elbName)
+		if err := s.deleteNetworkLoadBalancer(elbName); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+	}
+
 	for _, elb := range elbs {
 		s.scope.V(3).Info("deleting load balancer", "arn", elb)
 		if err := s.deleteClassicELB(elb); err != nil {
@@ -143,18 +175,38 @@ func (s *Service) DeleteLoadbalancers() error {
 		}
 	}
 
+	for _, arn := range v2LoadBalancers {
+		s.scope.V(3).Info("deleting load balancer", "arn", arn)
+		if err := s.deleteV2LoadBalancer(arn); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+	}
+
 	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (done bool, err error) {
 		elbs, err := s.listOwnedELBs()
 		if err != nil {
 			return false, err
 		}
 
+		v2LoadBalancers, err := s.listOwnedV2LoadBalancers()
+		if err != nil {
+			return false, err
+		}
+
 		_, err = s.describeClassicELB(elbName)
-		done = len(elbs) == 0 && IsNotFound(err)
+		done = len(elbs) == 0 && len(v2LoadBalancers) == 0 && IsNotFound(err)
 		return done, nil
 	}); err != nil {
 		return errors.Wrapf(err, "failed to wait for %q ELB deletions", s.scope.Name())
 	}
+
+	// The target groups of the load balancers created by the cloud provider outlive them. They are
+	// swept once all the load balancers are gone, which also catches the ones of a previous attempt.
+	if err := s.deleteOwnedV2TargetGroups(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.LoadBalancerReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 	s.scope.V(2).Info("Deleting load balancers completed successfully")
 	return nil
@@ -177,6 +229,10 @@ func (s *Service) RegisterInstanceWithClassicELB(instanceID, loadBalancer string
 
 // InstanceIsRegisteredWithAPIServerELB returns true if the instance is already registered with the APIServer ELB.
 func (s *Service) InstanceIsRegisteredWithAPIServerELB(i *infrav1.Instance) (bool, error) {
+	if s.isNetworkLoadBalancer() {
+		return s.instanceIsRegisteredWithAPIServerNLB(i)
+	}
+
 	name, err := GenerateELBName(s.scope.Name())
 	if err != nil {
 		return false, err
@@ -205,6 +261,10 @@ func (s *Service) InstanceIsRegisteredWithAPIServerELB(i *infrav1.Instance) (boo
 
 // RegisterInstanceWithAPIServerELB registers an instance with a classic ELB.
 func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
+	if s.isNetworkLoadBalancer() {
+		return s.registerInstanceWithAPIServerNLB(i)
+	}
+
 	name, err := GenerateELBName(s.scope.Name())
 	if err != nil {
 		return err
@@ -242,6 +302,10 @@ func (s *Service) RegisterInstanceWithAPIServerELB(i *infrav1.Instance) error {
 
 // DeregisterInstanceFromAPIServerELB de-registers an instance from a classic ELB.
 func (s *Service) DeregisterInstanceFromAPIServerELB(i *infrav1.Instance) error {
+	if s.isNetworkLoadBalancer() {
+		return s.deregisterInstanceFromAPIServerNLB(i)
+	}
+
 	name, err := GenerateELBName(s.scope.Name())
 	if err != nil {
 		return err
@@ -342,6 +406,7 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 
 	if s.scope.ControlPlaneLoadBalancer() != nil {
 		res.Attributes.CrossZoneLoadBalancing = s.scope.ControlPlaneLoadBalancer().CrossZoneLoadBalancing
+		applyHealthCheckOverrides(res.HealthCheck, s.scope.ControlPlaneLoadBalancer().HealthCheck)
 	}
 
 	res.Tags = infrav1.Build(infrav1.BuildParams{
@@ -369,10 +434,10 @@ func (s *Service) getAPIServerClassicELBSpec() (*infrav1.ClassicELB, error) {
 		}
 	} else {
 		// The load balancer APIs require us to only attach one subnet for each AZ.
-		subnets := s.scope.Subnets().FilterPrivate()
+		subnets := s.scope.Subnets().FilterPrimary().FilterPrivate()
 
 		if s.scope.ControlPlaneLoadBalancerScheme() == infrav1.ClassicELBSchemeInternetFacing {
-			subnets = s.scope.Subnets().FilterPublic()
+			subnets = s.scope.Subnets().FilterPrimary().FilterPublic()
 		}
 
 	subnetLoop:
@@ -416,22 +481,8 @@ func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicEL
 	}
 
 	if spec.HealthCheck != nil {
-		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
-			if _, err := s.ELBClient.ConfigureHealthCheck(&elb.ConfigureHealthCheckInput{
-				LoadBalancerName: aws.String(spec.Name),
-				HealthCheck: &elb.HealthCheck{
-					Target:             aws.String(spec.HealthCheck.Target),
-					Interval:           aws.Int64(int64(spec.HealthCheck.Interval.Seconds())),
-					Timeout:            aws.Int64(int64(spec.HealthCheck.Timeout.Seconds())),
-					HealthyThreshold:   aws.Int64(spec.HealthCheck.HealthyThreshold),
-					UnhealthyThreshold: aws.Int64(spec.HealthCheck.UnhealthyThreshold),
-				},
-			}); err != nil {
-				return false, err
-			}
-			return true, nil
-		}, awserrors.LoadBalancerNotFound); err != nil {
-			return nil, errors.Wrapf(err, "failed to configure health check for classic load balancer: %v", spec)
+		if err := s.configureHealthCheck(spec.Name, spec.HealthCheck); err != nil {
+			return nil, err
 		}
 	}
 
@@ -442,6 +493,28 @@ func (s *Service) createClassicELB(spec *infrav1.ClassicELB) (*infrav1.ClassicEL
 	return res, nil
 }
 
+func (s *Service) configureHealthCheck(name string, healthCheck *infrav1.ClassicELBHealthCheck) error {
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.ELBClient.ConfigureHealthCheck(&elb.ConfigureHealthCheckInput{
+			LoadBalancerName: aws.String(name),
+			HealthCheck: &elb.HealthCheck{
+				Target:             aws.String(healthCheck.Target),
+				Interval:           aws.Int64(int64(healthCheck.Interval.Seconds())),
+				Timeout:            aws.Int64(int64(healthCheck.Timeout.Seconds())),
+				HealthyThreshold:   aws.Int64(healthCheck.HealthyThreshold),
+				UnhealthyThreshold: aws.Int64(healthCheck.UnhealthyThreshold),
+			},
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.LoadBalancerNotFound); err != nil {
+		return errors.Wrapf(err, "failed to configure health check for classic load balancer: %v", name)
+	}
+
+	return nil
+}
+
 func (s *Service) configureAttributes(name string, attributes infrav1.ClassicELBAttributes) error {
 	attrs := &elb.ModifyLoadBalancerAttributesInput{
 		LoadBalancerName: aws.String(name),
@@ -481,6 +554,68 @@ func (s *Service) deleteClassicELB(name string) error {
 	return nil
 }
 
+func (s *Service) deleteV2LoadBalancer(arn string) error {
+	if _, err := s.ELBV2Client.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{
+		LoadBalancerArn: aws.String(arn),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteLoadBalancer", "Failed to delete load balancer %q: %v", arn, err)
+		return errors.Wrapf(err, "failed to delete load balancer %q", arn)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteLoadBalancer", "Deleted load balancer %q", arn)
+	return nil
+}
+
+// deleteOwnedV2TargetGroups deletes the target groups in the cluster VPC created by the cloud provider
+// for the cluster, which are not deleted along with their load balancers.
+func (s *Service) deleteOwnedV2TargetGroups() error {
+	serviceTag := infrav1.ClusterAWSCloudProviderTagKey(s.scope.Name())
+
+	var arns []string
+	err := s.ELBV2Client.DescribeTargetGroupsPages(&elbv2.DescribeTargetGroupsInput{}, func(r *elbv2.DescribeTargetGroupsOutput, last bool) bool {
+		for _, tg := range r.TargetGroups {
+			if s.scope.VPC().ID != "" && s.scope.VPC().ID != aws.StringValue(tg.VpcId) {
+				continue
+			}
+			arns = append(arns, aws.StringValue(tg.TargetGroupArn))
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to describe target groups")
+	}
+
+	for _, chunk := range chunkELBs(arns) {
+		output, err := s.ELBV2Client.DescribeTags(&elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice(chunk)})
+		if err != nil {
+			return errors.Wrap(err, "failed to describe target group tags")
+		}
+		for _, tagDesc := range output.TagDescriptions {
+			if !isOwnedV2(tagDesc.Tags, serviceTag) {
+				continue
+			}
+
+			arn := aws.StringValue(tagDesc.ResourceArn)
+			// A target group stays in use until the deletion of its load balancer has completed.
+			if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+				if _, err := s.ELBV2Client.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{
+					TargetGroupArn: aws.String(arn),
+				}); err != nil {
+					return false, err
+				}
+				return true, nil
+			}, elbv2.ErrCodeResourceInUseException); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedDeleteTargetGroup", "Failed to delete target group %q: %v", arn, err)
+				return errors.Wrapf(err, "failed to delete target group %q", arn)
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteTargetGroup", "Deleted target group %q", arn)
+		}
+	}
+
+	return nil
+}
+
+// listByTag returns the ARNs of the load balancers owned according to the tag.
 func (s *Service) listByTag(tag string) ([]string, error) {
 	input := rgapi.GetResourcesInput{
 		ResourceTypeFilters: aws.StringSlice([]string{elbResourceType}),
@@ -492,19 +627,17 @@ func (s *Service) listByTag(tag string) ([]string, error) {
 		},
 	}
 
-	names := []string{}
+	arns := []string{}
 
 	err := s.ResourceTaggingClient.GetResourcesPages(&input, func(r *rgapi.GetResourcesOutput, last bool) bool {
 		for _, tagmapping := range r.ResourceTagMappingList {
 			if tagmapping.ResourceARN != nil {
 				// We can't use arn.Parse because the "Resource" is loadbalancer/<name>
-				parts := strings.Split(*tagmapping.ResourceARN, "/")
-				name := parts[len(parts)-1]
-				if name == "" {
+				if !strings.Contains(*tagmapping.ResourceARN, elbResourcePrefix) || strings.HasSuffix(*tagmapping.ResourceARN, "/") {
 					s.scope.Info("failed to parse ARN", "arn", *tagmapping.ResourceARN, "tag", tag)
 					continue
 				}
-				names = append(names, name)
+				arns = append(arns, *tagmapping.ResourceARN)
 			}
 		}
 		return true
@@ -514,7 +647,14 @@ func (s *Service) listByTag(tag string) ([]string, error) {
 		return nil, errors.Wrapf(err, "failed to list %s ELBs by tag group", s.scope.Name())
 	}
 
-	return names, nil
+	return arns, nil
+}
+
+// isV2LoadBalancerARN returns true if the ARN is the one of a network or application load balancer
+// rather than of a classic ELB.
+func isV2LoadBalancerARN(arn string) bool {
+	resource := arn[strings.Index(arn, elbResourcePrefix)+len(elbResourcePrefix):]
+	return strings.HasPrefix(resource, "net/") || strings.HasPrefix(resource, "app/")
 }
 
 func (s *Service) filterByOwnedTag(tagKey string) ([]string, error) {
@@ -558,13 +698,83 @@ func (s *Service) listOwnedELBs() ([]string, error) {
 	arns, err := s.listByTag(serviceTag)
 	if err != nil {
 		// retry by listing all ELBs as listByTag will fail in air-gapped environments
-		arns, err = s.filterByOwnedTag(serviceTag)
+		return s.filterByOwnedTag(serviceTag)
+	}
+
+	names := []string{}
+	for _, arn := range arns {
+		if isV2LoadBalancerARN(arn) {
+			continue
+		}
+		names = append(names, arn[strings.Index(arn, elbResourcePrefix)+len(elbResourcePrefix):])
+	}
+	return names, nil
+}
+
+// listOwnedV2LoadBalancers returns the ARNs of the network and application load balancers
+// created by the cloud provider for the cluster.
+func (s *Service) listOwnedV2LoadBalancers() ([]string, error) {
+	// k8s.io/cluster/<name>, created by k/k cloud provider
+	serviceTag := infrav1.ClusterAWSCloudProviderTagKey(s.scope.Name())
+	arns, err := s.listByTag(serviceTag)
+	if err != nil {
+		// retry by listing all load balancers as listByTag will fail in air-gapped environments
+		return s.filterV2ByOwnedTag(serviceTag)
+	}
+
+	v2ARNs := []string{}
+	for _, arn := range arns {
+		if isV2LoadBalancerARN(arn) {
+			v2ARNs = append(v2ARNs, arn)
+		}
+	}
+	return v2ARNs, nil
+}
+
+func (s *Service) filterV2ByOwnedTag(tagKey string) ([]string, error) {
+	var arns []string
+	err := s.ELBV2Client.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{}, func(r *elbv2.DescribeLoadBalancersOutput, last bool) bool {
+		for _, lb := range r.LoadBalancers {
+			// Only the load balancers in the cluster VPC can belong to the cluster.
+			if s.scope.VPC().ID != "" && s.scope.VPC().ID != aws.StringValue(lb.VpcId) {
+				continue
+			}
+			arns = append(arns, *lb.LoadBalancerArn)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(arns) == 0 {
+		return nil, nil
+	}
+
+	var ownedLoadBalancers []string
+	for _, chunk := range chunkELBs(arns) {
+		output, err := s.ELBV2Client.DescribeTags(&elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice(chunk)})
 		if err != nil {
 			return nil, err
 		}
+		for _, tagDesc := range output.TagDescriptions {
+			if isOwnedV2(tagDesc.Tags, tagKey) {
+				ownedLoadBalancers = append(ownedLoadBalancers, *tagDesc.ResourceArn)
+			}
+		}
 	}
 
-	return arns, nil
+	return ownedLoadBalancers, nil
+}
+
+// isOwnedV2 returns true if the tags mark an ELBv2 resource as owned according to the tag key.
+func isOwnedV2(tags []*elbv2.Tag, tagKey string) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == tagKey && aws.StringValue(tag.Value) == string(infrav1.ResourceLifecycleOwned) {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Service) describeClassicELB(name string) (*infrav1.ClassicELB, error) {
@@ -682,9 +892,39 @@ func fromSDKTypeToClassicELB(v *elb.LoadBalancerDescription, attrs *elb.LoadBala
 
 	res.Attributes.CrossZoneLoadBalancing = aws.BoolValue(attrs.CrossZoneLoadBalancing.Enabled)
 
+	if v.HealthCheck != nil {
+		res.HealthCheck = &infrav1.ClassicELBHealthCheck{
+			Target:             aws.StringValue(v.HealthCheck.Target),
+			Interval:           time.Duration(aws.Int64Value(v.HealthCheck.Interval)) * time.Second,
+			Timeout:            time.Duration(aws.Int64Value(v.HealthCheck.Timeout)) * time.Second,
+			HealthyThreshold:   aws.Int64Value(v.HealthCheck.HealthyThreshold),
+			UnhealthyThreshold: aws.Int64Value(v.HealthCheck.UnhealthyThreshold),
+		}
+	}
+
 	return res
 }
 
+// applyHealthCheckOverrides replaces the default health check settings with the ones set in the cluster spec.
+func applyHealthCheckOverrides(healthCheck *infrav1.ClassicELBHealthCheck, overrides *infrav1.AWSLoadBalancerHealthCheck) {
+	if overrides == nil {
+		return
+	}
+
+	if overrides.IntervalSeconds != nil {
+		healthCheck.Interval = time.Duration(*overrides.IntervalSeconds) * time.Second
+	}
+	if overrides.TimeoutSeconds != nil {
+		healthCheck.Timeout = time.Duration(*overrides.TimeoutSeconds) * time.Second
+	}
+	if overrides.HealthyThreshold != nil {
+		healthCheck.HealthyThreshold = *overrides.HealthyThreshold
+	}
+	if overrides.UnhealthyThreshold != nil {
+		healthCheck.UnhealthyThreshold = *overrides.UnhealthyThreshold
+	}
+}
+
 func chunkELBs(names []string) [][]string {
 	var chunked [][]string
 	for i := 0; i < len(names); i += maxELBsDescribeTagsRequest {
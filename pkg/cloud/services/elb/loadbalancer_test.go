@@ -19,12 +19,16 @@ package elb
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +38,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbv2iface"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_resourcegroupstaggingapiiface"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -85,10 +90,11 @@ func TestGenerateELBName(t *testing.T) {
 
 func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 	tests := []struct {
-		name   string
-		lb     *infrav1.AWSLoadBalancerSpec
-		mocks  func(m *mock_ec2iface.MockEC2APIMockRecorder)
-		expect func(t *testing.T, res *infrav1.ClassicELB)
+		name    string
+		lb      *infrav1.AWSLoadBalancerSpec
+		subnets infrav1.Subnets
+		mocks   func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expect  func(t *testing.T, res *infrav1.ClassicELB)
 	}{
 		{
 			name:  "nil load balancer config",
@@ -146,6 +152,37 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "secondary subnets for pods are not used",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				Scheme: &infrav1.ClassicELBSchemeInternal,
+			},
+			subnets: infrav1.Subnets{
+				{
+					ID:               "subnet-secondary-a",
+					AvailabilityZone: "us-east-1a",
+					Tags:             infrav1.Tags{infrav1.NameAWSSubnetAssociation: infrav1.SecondarySubnetTagValue},
+				},
+				{
+					ID:               "subnet-1",
+					AvailabilityZone: "us-east-1a",
+				},
+				{
+					ID:               "subnet-secondary-b",
+					AvailabilityZone: "us-east-1b",
+					Tags:             infrav1.Tags{infrav1.NameAWSSubnetAssociation: infrav1.SecondarySubnetTagValue},
+				},
+			},
+			mocks: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expect: func(t *testing.T, res *infrav1.ClassicELB) {
+				if !reflect.DeepEqual(res.SubnetIDs, []string{"subnet-1"}) {
+					t.Errorf("Expected load balancer to be configured for subnet-1 only, got %v", res.SubnetIDs)
+				}
+				if !reflect.DeepEqual(res.AvailabilityZones, []string{"us-east-1a"}) {
+					t.Errorf("Expected load balancer to be configured for us-east-1a only, got %v", res.AvailabilityZones)
+				}
+			},
+		},
 		{
 			name: "load balancer config with additional security groups specified",
 			lb: &infrav1.AWSLoadBalancerSpec{
@@ -158,6 +195,51 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "load balancer config with health check overrides",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				HealthCheck: &infrav1.AWSLoadBalancerHealthCheck{
+					IntervalSeconds:  aws.Int64(5),
+					TimeoutSeconds:   aws.Int64(3),
+					HealthyThreshold: aws.Int64(2),
+				},
+			},
+			mocks: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expect: func(t *testing.T, res *infrav1.ClassicELB) {
+				expected := &infrav1.ClassicELBHealthCheck{
+					Target:             "SSL:6443",
+					Interval:           5 * time.Second,
+					Timeout:            3 * time.Second,
+					HealthyThreshold:   2,
+					UnhealthyThreshold: 3,
+				}
+				if !reflect.DeepEqual(res.HealthCheck, expected) {
+					t.Errorf("Expected health check %v, got %v", expected, res.HealthCheck)
+				}
+			},
+		},
+		{
+			name: "network load balancer config",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				LoadBalancerType:       infrav1.LoadBalancerTypeNLB,
+				CrossZoneLoadBalancing: true,
+			},
+			mocks: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+			expect: func(t *testing.T, res *infrav1.ClassicELB) {
+				if len(res.SecurityGroupIDs) != 0 {
+					t.Errorf("Expected network load balancer not to have security groups, got %v", res.SecurityGroupIDs)
+				}
+				if res.Attributes.IdleTimeout != 0 {
+					t.Errorf("Expected network load balancer not to have an idle timeout, got %v", res.Attributes.IdleTimeout)
+				}
+				if !res.Attributes.CrossZoneLoadBalancing {
+					t.Error("Expected load balancer to have cross-zone load balancing enabled")
+				}
+				if res.HealthCheck.Target != "TCP:6443" {
+					t.Errorf("Expected network load balancer health check target TCP:6443, got %v", res.HealthCheck.Target)
+				}
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -181,6 +263,9 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{Name: "test"},
 					Spec: infrav1.AWSClusterSpec{
 						ControlPlaneLoadBalancer: tc.lb,
+						NetworkSpec: infrav1.NetworkSpec{
+							Subnets: tc.subnets,
+						},
 					},
 				},
 			})
@@ -195,7 +280,11 @@ func TestGetAPIServerClassicELBSpec_ControlPlaneLoadBalancer(t *testing.T) {
 				EC2Client: ec2Mock,
 			}
 
-			spec, err := s.getAPIServerClassicELBSpec()
+			getSpec := s.getAPIServerClassicELBSpec
+			if s.isNetworkLoadBalancer() {
+				getSpec = s.getAPIServerNLBSpec
+			}
+			spec, err := getSpec()
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -212,6 +301,7 @@ func TestDeleteLoadbalancers(t *testing.T) {
 		rgAPIMocks            func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder)
 		elbAPIMocks           func(m *mock_elbiface.MockELBAPIMockRecorder)
 		postDeleteElbAPIMocks func(m *mock_elbiface.MockELBAPIMockRecorder)
+		elbV2APIMocks         func(m *mock_elbv2iface.MockELBV2APIMockRecorder)
 	}{
 		{
 			name: "deletes ELBs successfully",
@@ -226,6 +316,9 @@ func TestDeleteLoadbalancers(t *testing.T) {
 					LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver"}),
 				})).Return(nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "", nil))
 			},
+			elbV2APIMocks: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+			},
 		},
 		{
 			name: "successful delete. falls back to listing all ELBs when listing by tag fails",
@@ -280,6 +373,137 @@ func TestDeleteLoadbalancers(t *testing.T) {
 				})).Return(nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "", nil))
 				m.DescribeLoadBalancersPages(gomock.Any(), gomock.Any()).Return(nil)
 			},
+			elbV2APIMocks: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeLoadBalancersPages(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				m.DescribeTargetGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "falls back to listing the network load balancers of the cluster VPC when listing by tag fails",
+			rgAPIMocks: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesPages(gomock.Any(), gomock.Any()).Return(errors.Errorf("connection failure")).AnyTimes()
+			},
+			elbAPIMocks: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancersPages(gomock.Any(), gomock.Any()).Return(nil)
+				m.DeleteLoadBalancer(gomock.Eq(&elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String("bar-apiserver")})).Return(nil, nil)
+			},
+			postDeleteElbAPIMocks: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Eq(&elb.DescribeLoadBalancersInput{
+					LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver"}),
+				})).Return(nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "", nil))
+				m.DescribeLoadBalancersPages(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			elbV2APIMocks: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				gomock.InOrder(
+					m.DescribeLoadBalancersPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+						funct := y.(func(output *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool)
+						funct(&elbv2.DescribeLoadBalancersOutput{
+							LoadBalancers: []*elbv2.LoadBalancer{
+								{
+									LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/nlb-service-name/0123456789abcdef"),
+									VpcId:           aws.String("vpc-1"),
+								},
+								{
+									LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/other-vpc/0123456789abcdef"),
+									VpcId:           aws.String("vpc-2"),
+								},
+							},
+						}, true)
+					}).Return(nil),
+					m.DescribeLoadBalancersPages(gomock.Any(), gomock.Any()).Return(nil),
+				)
+				m.DescribeTags(gomock.Eq(&elbv2.DescribeTagsInput{
+					ResourceArns: aws.StringSlice([]string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/nlb-service-name/0123456789abcdef"}),
+				})).Return(&elbv2.DescribeTagsOutput{
+					TagDescriptions: []*elbv2.TagDescription{
+						{
+							ResourceArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/nlb-service-name/0123456789abcdef"),
+							Tags: []*elbv2.Tag{{
+								Key:   aws.String(infrav1.ClusterAWSCloudProviderTagKey(clusterName)),
+								Value: aws.String(string(infrav1.ResourceLifecycleOwned)),
+							}},
+						},
+					},
+				}, nil)
+				m.DeleteLoadBalancer(gomock.Eq(&elbv2.DeleteLoadBalancerInput{
+					LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/nlb-service-name/0123456789abcdef"),
+				})).Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+				m.DescribeTargetGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "deletes the classic and network load balancers created by the cloud provider",
+			rgAPIMocks: func(m *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder) {
+				m.GetResourcesPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *rgapi.GetResourcesOutput, lastPage bool) bool)
+					funct(&rgapi.GetResourcesOutput{
+						ResourceTagMappingList: []*rgapi.ResourceTagMapping{
+							{ResourceARN: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/lb-service-name")},
+							{ResourceARN: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/nlb-service-name/0123456789abcdef")},
+						},
+					}, true)
+				}).Return(nil).Times(2)
+				m.GetResourcesPages(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			},
+			elbAPIMocks: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DeleteLoadBalancer(gomock.Eq(&elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String("bar-apiserver")})).Return(nil, nil)
+				m.DeleteLoadBalancer(gomock.Eq(&elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String("lb-service-name")})).Return(nil, nil)
+			},
+			postDeleteElbAPIMocks: func(m *mock_elbiface.MockELBAPIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Eq(&elb.DescribeLoadBalancersInput{
+					LoadBalancerNames: aws.StringSlice([]string{"bar-apiserver"}),
+				})).Return(nil, awserr.New(elb.ErrCodeAccessPointNotFoundException, "", nil))
+			},
+			elbV2APIMocks: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DeleteLoadBalancer(gomock.Eq(&elbv2.DeleteLoadBalancerInput{
+					LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/nlb-service-name/0123456789abcdef"),
+				})).Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+				m.DescribeTargetGroupsPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *elbv2.DescribeTargetGroupsOutput, lastPage bool) bool)
+					funct(&elbv2.DescribeTargetGroupsOutput{
+						TargetGroups: []*elbv2.TargetGroup{
+							{
+								TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-owned/0123456789abcdef"),
+								VpcId:          aws.String("vpc-1"),
+							},
+							{
+								TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-not-owned/0123456789abcdef"),
+								VpcId:          aws.String("vpc-1"),
+							},
+							{
+								TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-other-vpc/0123456789abcdef"),
+								VpcId:          aws.String("vpc-2"),
+							},
+						},
+					}, true)
+				}).Return(nil)
+				m.DescribeTags(gomock.Eq(&elbv2.DescribeTagsInput{
+					ResourceArns: aws.StringSlice([]string{
+						"arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-owned/0123456789abcdef",
+						"arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-not-owned/0123456789abcdef",
+					}),
+				})).Return(&elbv2.DescribeTagsOutput{
+					TagDescriptions: []*elbv2.TagDescription{
+						{
+							ResourceArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-owned/0123456789abcdef"),
+							Tags: []*elbv2.Tag{{
+								Key:   aws.String(infrav1.ClusterAWSCloudProviderTagKey(clusterName)),
+								Value: aws.String(string(infrav1.ResourceLifecycleOwned)),
+							}},
+						},
+						{
+							ResourceArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-not-owned/0123456789abcdef"),
+							Tags: []*elbv2.Tag{{
+								Key:   aws.String("some-tag-key"),
+								Value: aws.String("some-tag-value"),
+							}},
+						},
+					},
+				}, nil)
+				m.DeleteTargetGroup(gomock.Eq(&elbv2.DeleteTargetGroupInput{
+					TargetGroupArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/k8s-owned/0123456789abcdef"),
+				})).Return(&elbv2.DeleteTargetGroupOutput{}, nil)
+			},
 		},
 	}
 
@@ -289,6 +513,7 @@ func TestDeleteLoadbalancers(t *testing.T) {
 			defer mockCtrl.Finish()
 			rgapiMock := mock_resourcegroupstaggingapiiface.NewMockResourceGroupsTaggingAPIAPI(mockCtrl)
 			elbapiMock := mock_elbiface.NewMockELBAPI(mockCtrl)
+			elbV2APIMock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
 
 			scheme, err := setupScheme()
 			if err != nil {
@@ -297,7 +522,11 @@ func TestDeleteLoadbalancers(t *testing.T) {
 
 			awsCluster := &infrav1.AWSCluster{
 				ObjectMeta: metav1.ObjectMeta{Name: "test"},
-				Spec:       infrav1.AWSClusterSpec{},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{ID: "vpc-1"},
+					},
+				},
 			}
 
 			client := fake.NewClientBuilder().WithScheme(scheme).Build()
@@ -321,11 +550,13 @@ func TestDeleteLoadbalancers(t *testing.T) {
 			tc.rgAPIMocks(rgapiMock.EXPECT())
 			tc.elbAPIMocks(elbapiMock.EXPECT())
 			tc.postDeleteElbAPIMocks(elbapiMock.EXPECT())
+			tc.elbV2APIMocks(elbV2APIMock.EXPECT())
 
 			s := &Service{
 				scope:                 clusterScope,
 				ResourceTaggingClient: rgapiMock,
 				ELBClient:             elbapiMock,
+				ELBV2Client:           elbV2APIMock,
 			}
 
 			err = s.DeleteLoadbalancers()
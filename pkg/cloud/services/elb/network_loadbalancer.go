@@ -0,0 +1,571 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+)
+
+const (
+	// crossZoneLoadBalancingAttribute is the network load balancer attribute controlling cross-zone load balancing.
+	crossZoneLoadBalancingAttribute = "load_balancing.cross_zone.enabled"
+
+	// preserveClientIPAttribute is the target group attribute controlling client IP preservation.
+	preserveClientIPAttribute = "preserve_client_ip.enabled"
+)
+
+// isNetworkLoadBalancer returns true if the API server should be fronted by a network load balancer.
+func (s *Service) isNetworkLoadBalancer() bool {
+	lb := s.scope.ControlPlaneLoadBalancer()
+	return lb != nil && lb.LoadBalancerType == infrav1.LoadBalancerTypeNLB
+}
+
+// reconcileNetworkLoadBalancer reconciles the API server network load balancer
+// along with its target group and listener.
+func (s *Service) reconcileNetworkLoadBalancer() error {
+	spec, err := s.getAPIServerNLBSpec()
+	if err != nil {
+		return err
+	}
+
+	// Describe or create.
+	lb, err := s.describeNetworkLoadBalancer(spec.Name)
+	if IsNotFound(err) {
+		lb, err = s.createNetworkLoadBalancer(spec)
+		if err != nil {
+			return err
+		}
+
+		s.scope.V(2).Info("Created new network load balancer for apiserver", "api-server-nlb-name", spec.Name)
+	} else if err != nil {
+		return err
+	}
+	lbARN := aws.StringValue(lb.LoadBalancerArn)
+
+	attributes, err := s.describeNetworkLoadBalancerAttributes(lbARN)
+	if err != nil {
+		return err
+	}
+	if attributes.CrossZoneLoadBalancing != spec.Attributes.CrossZoneLoadBalancing {
+		if err := s.configureNetworkLoadBalancerAttributes(lbARN, spec.Attributes); err != nil {
+			return err
+		}
+	}
+
+	if err := s.reconcileV2Tags(lbARN, spec.Tags); err != nil {
+		return errors.Wrapf(err, "failed to reconcile tags for apiserver load balancer %q", spec.Name)
+	}
+
+	targetGroupARN, err := s.reconcileAPIServerTargetGroup(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reconcileAPIServerListener(lbARN, targetGroupARN); err != nil {
+		return err
+	}
+
+	// The subnets of a network load balancer can't be changed once it has been created,
+	// so the status reflects the subnets it was created with.
+	apiNLB := fromSDKTypeToNetworkLoadBalancer(lb)
+	apiNLB.Attributes = spec.Attributes
+	apiNLB.Listeners = spec.Listeners
+	apiNLB.HealthCheck = spec.HealthCheck
+	apiNLB.DeepCopyInto(&s.scope.Network().APIServerELB)
+	s.scope.V(4).Info("Control plane load balancer", "api-server-nlb", apiNLB)
+
+	s.scope.V(2).Info("Reconcile load balancers completed successfully")
+	return nil
+}
+
+// deleteNetworkLoadBalancer deletes the API server network load balancer and its target group.
+// Listeners are deleted along with the load balancer.
+func (s *Service) deleteNetworkLoadBalancer(name string) error {
+	lb, err := s.describeNetworkLoadBalancer(name)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	if lb != nil {
+		if _, err := s.ELBV2Client.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{
+			LoadBalancerArn: lb.LoadBalancerArn,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete network load balancer %q", name)
+		}
+		s.scope.V(2).Info("Deleted network load balancer", "api-server-nlb-name", name)
+	}
+
+	tg, err := s.describeAPIServerTargetGroup(name)
+	if IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// The target group stays in use until the load balancer deletion has completed.
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.ELBV2Client.DeleteTargetGroup(&elbv2.DeleteTargetGroupInput{
+			TargetGroupArn: tg.TargetGroupArn,
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, elbv2.ErrCodeResourceInUseException); err != nil {
+		return errors.Wrapf(err, "failed to delete target group %q", name)
+	}
+
+	return nil
+}
+
+// instanceIsRegisteredWithAPIServerNLB returns true if the instance is already a target of the APIServer NLB.
+func (s *Service) instanceIsRegisteredWithAPIServerNLB(i *infrav1.Instance) (bool, error) {
+	name, err := GenerateELBName(s.scope.Name())
+	if err != nil {
+		return false, err
+	}
+
+	tg, err := s.describeAPIServerTargetGroup(name)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := s.ELBV2Client.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: tg.TargetGroupArn,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "error describing targets of target group %q", name)
+	}
+
+	for _, target := range out.TargetHealthDescriptions {
+		if target.Target != nil && aws.StringValue(target.Target.Id) == i.ID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// registerInstanceWithAPIServerNLB registers an instance as a target of the APIServer NLB.
+func (s *Service) registerInstanceWithAPIServerNLB(i *infrav1.Instance) error {
+	name, err := GenerateELBName(s.scope.Name())
+	if err != nil {
+		return err
+	}
+
+	tg, err := s.describeAPIServerTargetGroup(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.ELBV2Client.RegisterTargets(&elbv2.RegisterTargetsInput{
+		TargetGroupArn: tg.TargetGroupArn,
+		Targets:        []*elbv2.TargetDescription{{Id: aws.String(i.ID)}},
+	})
+	return err
+}
+
+// deregisterInstanceFromAPIServerNLB de-registers an instance from the APIServer NLB.
+func (s *Service) deregisterInstanceFromAPIServerNLB(i *infrav1.Instance) error {
+	name, err := GenerateELBName(s.scope.Name())
+	if err != nil {
+		return err
+	}
+
+	tg, err := s.describeAPIServerTargetGroup(name)
+	if IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	_, err = s.ELBV2Client.DeregisterTargets(&elbv2.DeregisterTargetsInput{
+		TargetGroupArn: tg.TargetGroupArn,
+		Targets:        []*elbv2.TargetDescription{{Id: aws.String(i.ID)}},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case elbv2.ErrCodeTargetGroupNotFoundException, elbv2.ErrCodeInvalidTargetException:
+				// Ignoring TargetGroupNotFound and InvalidTarget when deregistering
+				return nil
+			default:
+				return err
+			}
+		}
+	}
+	return err
+}
+
+func (s *Service) getAPIServerNLBSpec() (*infrav1.ClassicELB, error) {
+	res, err := s.getAPIServerClassicELBSpec()
+	if err != nil {
+		return nil, err
+	}
+
+	// Network load balancers don't support security groups nor idle timeouts,
+	// and health check the API servers over plain TCP.
+	port := int64(s.scope.APIServerPort())
+	res.SecurityGroupIDs = nil
+	res.Attributes.IdleTimeout = 0
+	for i := range res.Listeners {
+		res.Listeners[i].InstancePort = port
+	}
+	res.HealthCheck = &infrav1.ClassicELBHealthCheck{
+		Target:             fmt.Sprintf("%v:%d", infrav1.ClassicELBProtocolTCP, port),
+		Interval:           10 * time.Second,
+		Timeout:            10 * time.Second,
+		HealthyThreshold:   3,
+		UnhealthyThreshold: 3,
+	}
+	if lb := s.scope.ControlPlaneLoadBalancer(); lb != nil {
+		applyHealthCheckOverrides(res.HealthCheck, lb.HealthCheck)
+	}
+
+	return res, nil
+}
+
+func (s *Service) createNetworkLoadBalancer(spec *infrav1.ClassicELB) (*elbv2.LoadBalancer, error) {
+	out, err := s.ELBV2Client.CreateLoadBalancer(&elbv2.CreateLoadBalancerInput{
+		Name:    aws.String(spec.Name),
+		Type:    aws.String(elbv2.LoadBalancerTypeEnumNetwork),
+		Scheme:  aws.String(toNetworkLoadBalancerScheme(spec.Scheme)),
+		Subnets: aws.StringSlice(spec.SubnetIDs),
+		Tags:    converters.MapToV2Tags(spec.Tags),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create network load balancer: %v", spec)
+	}
+
+	if len(out.LoadBalancers) == 0 {
+		return nil, errors.Errorf("no network load balancer returned when creating %q", spec.Name)
+	}
+
+	s.scope.V(2).Info("Created network load balancer", "dns-name", aws.StringValue(out.LoadBalancers[0].DNSName))
+	return out.LoadBalancers[0], nil
+}
+
+func (s *Service) describeNetworkLoadBalancer(name string) (*elbv2.LoadBalancer, error) {
+	out, err := s.ELBV2Client.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+		Names: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elbv2.ErrCodeLoadBalancerNotFoundException {
+			return nil, NewNotFound(fmt.Sprintf("no network load balancer found with name: %q", name))
+		}
+		return nil, errors.Wrapf(err, "failed to describe network load balancer: %s", name)
+	}
+
+	if len(out.LoadBalancers) == 0 {
+		return nil, NewNotFound(fmt.Sprintf("no network load balancer found with name %q", name))
+	}
+
+	lb := out.LoadBalancers[0]
+	if aws.StringValue(lb.Type) != elbv2.LoadBalancerTypeEnumNetwork {
+		return nil, errors.Errorf(
+			"load balancer names must be unique within a region: %q load balancer already exists in this region with type %q",
+			name, aws.StringValue(lb.Type))
+	}
+
+	if s.scope.VPC().ID != "" && s.scope.VPC().ID != aws.StringValue(lb.VpcId) {
+		return nil, errors.Errorf(
+			"load balancer names must be unique within a region: %q load balancer already exists in this region in VPC %q",
+			name, aws.StringValue(lb.VpcId))
+	}
+
+	if s.scope.ControlPlaneLoadBalancer().Scheme != nil &&
+		toNetworkLoadBalancerScheme(*s.scope.ControlPlaneLoadBalancer().Scheme) != aws.StringValue(lb.Scheme) {
+		return nil, errors.Errorf(
+			"load balancer names must be unique within a region: %q load balancer already exists in this region with a different scheme %q",
+			name, aws.StringValue(lb.Scheme))
+	}
+
+	return lb, nil
+}
+
+func (s *Service) describeNetworkLoadBalancerAttributes(arn string) (infrav1.ClassicELBAttributes, error) {
+	res := infrav1.ClassicELBAttributes{}
+
+	out, err := s.ELBV2Client.DescribeLoadBalancerAttributes(&elbv2.DescribeLoadBalancerAttributesInput{
+		LoadBalancerArn: aws.String(arn),
+	})
+	if err != nil {
+		return res, errors.Wrapf(err, "failed to describe network load balancer %q attributes", arn)
+	}
+
+	for _, attr := range out.Attributes {
+		if aws.StringValue(attr.Key) == crossZoneLoadBalancingAttribute {
+			res.CrossZoneLoadBalancing = aws.StringValue(attr.Value) == "true"
+		}
+	}
+
+	return res, nil
+}
+
+func (s *Service) configureNetworkLoadBalancerAttributes(arn string, attributes infrav1.ClassicELBAttributes) error {
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		if _, err := s.ELBV2Client.ModifyLoadBalancerAttributes(&elbv2.ModifyLoadBalancerAttributesInput{
+			LoadBalancerArn: aws.String(arn),
+			Attributes: []*elbv2.LoadBalancerAttribute{
+				{
+					Key:   aws.String(crossZoneLoadBalancingAttribute),
+					Value: aws.String(strconv.FormatBool(attributes.CrossZoneLoadBalancing)),
+				},
+			},
+		}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, awserrors.LoadBalancerNotFound); err != nil {
+		return errors.Wrapf(err, "failed to configure attributes for network load balancer: %v", arn)
+	}
+
+	return nil
+}
+
+func (s *Service) reconcileAPIServerTargetGroup(spec *infrav1.ClassicELB) (string, error) {
+	tg, err := s.describeAPIServerTargetGroup(spec.Name)
+	if err == nil {
+		if err := s.reconcileAPIServerTargetGroupHealthCheck(tg, spec.HealthCheck); err != nil {
+			return "", err
+		}
+		return aws.StringValue(tg.TargetGroupArn), nil
+	} else if !IsNotFound(err) {
+		return "", err
+	}
+
+	out, err := s.ELBV2Client.CreateTargetGroup(&elbv2.CreateTargetGroupInput{
+		Name:                       aws.String(spec.Name),
+		Port:                       aws.Int64(int64(s.scope.APIServerPort())),
+		Protocol:                   aws.String(elbv2.ProtocolEnumTcp),
+		TargetType:                 aws.String(elbv2.TargetTypeEnumInstance),
+		VpcId:                      aws.String(s.scope.VPC().ID),
+		HealthCheckProtocol:        aws.String(elbv2.ProtocolEnumTcp),
+		HealthCheckPort:            aws.String("traffic-port"),
+		HealthCheckIntervalSeconds: aws.Int64(int64(spec.HealthCheck.Interval.Seconds())),
+		HealthyThresholdCount:      aws.Int64(spec.HealthCheck.HealthyThreshold),
+		UnhealthyThresholdCount:    aws.Int64(spec.HealthCheck.UnhealthyThreshold),
+		Tags:                       converters.MapToV2Tags(spec.Tags),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create target group %q", spec.Name)
+	}
+	if len(out.TargetGroups) == 0 {
+		return "", errors.Errorf("no target group returned when creating %q", spec.Name)
+	}
+	arn := aws.StringValue(out.TargetGroups[0].TargetGroupArn)
+
+	// Without client IP preservation the API servers see the load balancer as the source of
+	// the traffic, which lets a control plane instance reach itself through the load balancer
+	// and keeps the control plane security group closed to clients outside of the VPC.
+	if _, err := s.ELBV2Client.ModifyTargetGroupAttributes(&elbv2.ModifyTargetGroupAttributesInput{
+		TargetGroupArn: aws.String(arn),
+		Attributes: []*elbv2.TargetGroupAttribute{
+			{
+				Key:   aws.String(preserveClientIPAttribute),
+				Value: aws.String("false"),
+			},
+		},
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to configure attributes for target group %q", spec.Name)
+	}
+
+	s.scope.V(2).Info("Created target group for apiserver", "target-group-arn", arn)
+	return arn, nil
+}
+
+func (s *Service) reconcileAPIServerTargetGroupHealthCheck(tg *elbv2.TargetGroup, healthCheck *infrav1.ClassicELBHealthCheck) error {
+	interval := int64(healthCheck.Interval.Seconds())
+	if aws.Int64Value(tg.HealthCheckIntervalSeconds) == interval &&
+		aws.Int64Value(tg.HealthyThresholdCount) == healthCheck.HealthyThreshold &&
+		aws.Int64Value(tg.UnhealthyThresholdCount) == healthCheck.UnhealthyThreshold {
+		return nil
+	}
+
+	if _, err := s.ELBV2Client.ModifyTargetGroup(&elbv2.ModifyTargetGroupInput{
+		TargetGroupArn:             tg.TargetGroupArn,
+		HealthCheckIntervalSeconds: aws.Int64(interval),
+		HealthyThresholdCount:      aws.Int64(healthCheck.HealthyThreshold),
+		UnhealthyThresholdCount:    aws.Int64(healthCheck.UnhealthyThreshold),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to configure health check for target group %q", aws.StringValue(tg.TargetGroupName))
+	}
+
+	return nil
+}
+
+func (s *Service) describeAPIServerTargetGroup(name string) (*elbv2.TargetGroup, error) {
+	out, err := s.ELBV2Client.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
+		Names: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elbv2.ErrCodeTargetGroupNotFoundException {
+			return nil, NewNotFound(fmt.Sprintf("no target group found with name: %q", name))
+		}
+		return nil, errors.Wrapf(err, "failed to describe target group: %s", name)
+	}
+
+	if len(out.TargetGroups) == 0 {
+		return nil, NewNotFound(fmt.Sprintf("no target group found with name %q", name))
+	}
+
+	return out.TargetGroups[0], nil
+}
+
+func (s *Service) reconcileAPIServerListener(lbARN, targetGroupARN string) error {
+	port := int64(s.scope.APIServerPort())
+
+	out, err := s.ELBV2Client.DescribeListeners(&elbv2.DescribeListenersInput{
+		LoadBalancerArn: aws.String(lbARN),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe listeners of network load balancer %q", lbARN)
+	}
+
+	defaultActions := []*elbv2.Action{
+		{
+			Type:           aws.String(elbv2.ActionTypeEnumForward),
+			TargetGroupArn: aws.String(targetGroupARN),
+		},
+	}
+
+	for _, listener := range out.Listeners {
+		if aws.Int64Value(listener.Port) != port {
+			continue
+		}
+
+		if aws.StringValue(listener.Protocol) == elbv2.ProtocolEnumTcp && forwardsTo(listener, targetGroupARN) {
+			return nil
+		}
+
+		if _, err := s.ELBV2Client.ModifyListener(&elbv2.ModifyListenerInput{
+			ListenerArn:    listener.ListenerArn,
+			Port:           aws.Int64(port),
+			Protocol:       aws.String(elbv2.ProtocolEnumTcp),
+			DefaultActions: defaultActions,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to modify listener of network load balancer %q", lbARN)
+		}
+		return nil
+	}
+
+	if _, err := s.ELBV2Client.CreateListener(&elbv2.CreateListenerInput{
+		LoadBalancerArn: aws.String(lbARN),
+		Port:            aws.Int64(port),
+		Protocol:        aws.String(elbv2.ProtocolEnumTcp),
+		DefaultActions:  defaultActions,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create listener for network load balancer %q", lbARN)
+	}
+
+	return nil
+}
+
+// forwardsTo returns true if the listener forwards all of its traffic to the target group.
+func forwardsTo(listener *elbv2.Listener, targetGroupARN string) bool {
+	if len(listener.DefaultActions) != 1 {
+		return false
+	}
+	action := listener.DefaultActions[0]
+	return aws.StringValue(action.Type) == elbv2.ActionTypeEnumForward && aws.StringValue(action.TargetGroupArn) == targetGroupARN
+}
+
+func (s *Service) reconcileV2Tags(arn string, desiredTags map[string]string) error {
+	tags, err := s.ELBV2Client.DescribeTags(&elbv2.DescribeTagsInput{
+		ResourceArns: []*string{aws.String(arn)},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(tags.TagDescriptions) == 0 {
+		return errors.Errorf("no tag information returned for load balancer %q", arn)
+	}
+
+	currentTags := converters.V2TagsToMap(tags.TagDescriptions[0].Tags)
+
+	addTagsInput := &elbv2.AddTagsInput{
+		ResourceArns: []*string{aws.String(arn)},
+	}
+
+	removeTagsInput := &elbv2.RemoveTagsInput{
+		ResourceArns: []*string{aws.String(arn)},
+	}
+
+	for k, v := range desiredTags {
+		if val, ok := currentTags[k]; !ok || val != v {
+			s.scope.V(4).Info("adding tag to load balancer", "elb-arn", arn, "key", k, "value", v)
+			addTagsInput.Tags = append(addTagsInput.Tags, &elbv2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+	}
+
+	for k := range currentTags {
+		if _, ok := desiredTags[k]; !ok {
+			s.scope.V(4).Info("removing tag from load balancer", "elb-arn", arn, "key", k)
+			removeTagsInput.TagKeys = append(removeTagsInput.TagKeys, aws.String(k))
+		}
+	}
+
+	if len(addTagsInput.Tags) > 0 {
+		if _, err := s.ELBV2Client.AddTags(addTagsInput); err != nil {
+			return err
+		}
+	}
+
+	if len(removeTagsInput.TagKeys) > 0 {
+		if _, err := s.ELBV2Client.RemoveTags(removeTagsInput); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toNetworkLoadBalancerScheme converts a classic ELB scheme to the lowercase form used by the ELBv2 API.
+func toNetworkLoadBalancerScheme(scheme infrav1.ClassicELBScheme) string {
+	return strings.ToLower(string(scheme))
+}
+
+func fromSDKTypeToNetworkLoadBalancer(v *elbv2.LoadBalancer) *infrav1.ClassicELB {
+	res := &infrav1.ClassicELB{
+		Name:    aws.StringValue(v.LoadBalancerName),
+		DNSName: aws.StringValue(v.DNSName),
+		Scheme:  infrav1.ClassicELBSchemeInternal,
+	}
+
+	if aws.StringValue(v.Scheme) == elbv2.LoadBalancerSchemeEnumInternetFacing {
+		res.Scheme = infrav1.ClassicELBSchemeInternetFacing
+	}
+
+	for _, az := range v.AvailabilityZones {
+		res.AvailabilityZones = append(res.AvailabilityZones, aws.StringValue(az.ZoneName))
+		res.SubnetIDs = append(res.SubnetIDs, aws.StringValue(az.SubnetId))
+	}
+
+	return res
+}
@@ -0,0 +1,546 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbv2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNLBName        = "test-cluster-apiserver"
+	testNLBARN         = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/test-cluster-apiserver/0123456789abcdef"
+	testTargetGroupARN = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/test-cluster-apiserver/0123456789abcdef"
+	testListenerARN    = "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/net/test-cluster-apiserver/0123456789abcdef/0123456789abcdef"
+)
+
+func TestReconcileNetworkLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name          string
+		lb            *infrav1.AWSLoadBalancerSpec
+		apiServerPort *int32
+		expect        func(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB)
+	}{
+		{
+			name: "creates the load balancer, target group and listener",
+			lb:   &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB},
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB) {
+				expectCreatedNLB(m, g, spec, 6443)
+			},
+		},
+		{
+			name:          "creates the target group and listener on the API server port of the cluster",
+			lb:            &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB},
+			apiServerPort: pointer.Int32(8443),
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB) {
+				expectCreatedNLB(m, g, spec, 8443)
+			},
+		},
+		{
+			name: "leaves an up to date load balancer alone",
+			lb:   &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB},
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB) {
+				expectExistingNLB(m, spec, false)
+				m.DescribeListeners(gomock.Eq(&elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(testNLBARN)})).
+					Return(&elbv2.DescribeListenersOutput{Listeners: []*elbv2.Listener{testAPIServerListener()}}, nil)
+			},
+		},
+		{
+			name: "updates the health check of the target group",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				LoadBalancerType: infrav1.LoadBalancerTypeNLB,
+				HealthCheck: &infrav1.AWSLoadBalancerHealthCheck{
+					IntervalSeconds:    aws.Int64(30),
+					UnhealthyThreshold: aws.Int64(5),
+				},
+			},
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB) {
+				expectExistingNLB(m, spec, false)
+				m.ModifyTargetGroup(gomock.Eq(&elbv2.ModifyTargetGroupInput{
+					TargetGroupArn:             aws.String(testTargetGroupARN),
+					HealthCheckIntervalSeconds: aws.Int64(30),
+					HealthyThresholdCount:      aws.Int64(3),
+					UnhealthyThresholdCount:    aws.Int64(5),
+				})).
+					Return(&elbv2.ModifyTargetGroupOutput{}, nil)
+				m.DescribeListeners(gomock.Eq(&elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(testNLBARN)})).
+					Return(&elbv2.DescribeListenersOutput{Listeners: []*elbv2.Listener{testAPIServerListener()}}, nil)
+			},
+		},
+		{
+			name: "updates the cross-zone load balancing attribute",
+			lb: &infrav1.AWSLoadBalancerSpec{
+				LoadBalancerType:       infrav1.LoadBalancerTypeNLB,
+				CrossZoneLoadBalancing: true,
+			},
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB) {
+				expectExistingNLB(m, spec, false)
+				m.ModifyLoadBalancerAttributes(gomock.Eq(&elbv2.ModifyLoadBalancerAttributesInput{
+					LoadBalancerArn: aws.String(testNLBARN),
+					Attributes: []*elbv2.LoadBalancerAttribute{
+						{
+							Key:   aws.String(crossZoneLoadBalancingAttribute),
+							Value: aws.String("true"),
+						},
+					},
+				})).
+					Return(&elbv2.ModifyLoadBalancerAttributesOutput{}, nil)
+				m.DescribeListeners(gomock.Eq(&elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(testNLBARN)})).
+					Return(&elbv2.DescribeListenersOutput{Listeners: []*elbv2.Listener{testAPIServerListener()}}, nil)
+			},
+		},
+		{
+			name: "fixes a listener that doesn't forward to the target group over TCP",
+			lb:   &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB},
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB) {
+				expectExistingNLB(m, spec, false)
+				listener := testAPIServerListener()
+				listener.Protocol = aws.String(elbv2.ProtocolEnumTls)
+				listener.DefaultActions[0].TargetGroupArn = aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/other/0123456789abcdef")
+				m.DescribeListeners(gomock.Eq(&elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(testNLBARN)})).
+					Return(&elbv2.DescribeListenersOutput{Listeners: []*elbv2.Listener{listener}}, nil)
+				m.ModifyListener(gomock.Eq(&elbv2.ModifyListenerInput{
+					ListenerArn: aws.String(testListenerARN),
+					Port:        aws.Int64(6443),
+					Protocol:    aws.String(elbv2.ProtocolEnumTcp),
+					DefaultActions: []*elbv2.Action{
+						{
+							Type:           aws.String(elbv2.ActionTypeEnumForward),
+							TargetGroupArn: aws.String(testTargetGroupARN),
+						},
+					},
+				})).
+					Return(&elbv2.ModifyListenerOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbv2Mock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
+
+			s := newNLBTestService(t, tc.lb, tc.apiServerPort, elbv2Mock)
+			spec, err := s.getAPIServerNLBSpec()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tc.expect(elbv2Mock.EXPECT(), g, spec)
+
+			g.Expect(s.reconcileNetworkLoadBalancer()).To(Succeed())
+			g.Expect(s.scope.Network().APIServerELB.DNSName).To(Equal("test-cluster-apiserver.elb.amazonaws.com"))
+			g.Expect(s.scope.Network().APIServerELB.SubnetIDs).To(Equal([]string{"subnet-1"}))
+		})
+	}
+}
+
+func TestDeleteNetworkLoadBalancer(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(m *mock_elbv2iface.MockELBV2APIMockRecorder)
+	}{
+		{
+			name: "deletes the load balancer and its target group",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Any()).Return(testDescribeNLBOutput(), nil)
+				m.DeleteLoadBalancer(gomock.Eq(&elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String(testNLBARN)})).
+					Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				m.DeleteTargetGroup(gomock.Eq(&elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(testTargetGroupARN)})).
+					Return(&elbv2.DeleteTargetGroupOutput{}, nil)
+			},
+		},
+		{
+			name: "deletes a target group left behind by a deleted load balancer",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "", nil))
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				m.DeleteTargetGroup(gomock.Eq(&elbv2.DeleteTargetGroupInput{TargetGroupArn: aws.String(testTargetGroupARN)})).
+					Return(&elbv2.DeleteTargetGroupOutput{}, nil)
+			},
+		},
+		{
+			name: "nothing left to delete",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "", nil))
+				m.DescribeTargetGroups(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "", nil))
+			},
+		},
+		{
+			name: "retries deleting the target group while it's still in use",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeLoadBalancers(gomock.Any()).Return(testDescribeNLBOutput(), nil)
+				m.DeleteLoadBalancer(gomock.Any()).Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				inUse := m.DeleteTargetGroup(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeResourceInUseException, "", nil))
+				m.DeleteTargetGroup(gomock.Any()).Return(&elbv2.DeleteTargetGroupOutput{}, nil).After(inUse)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbv2Mock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
+
+			s := newNLBTestService(t, &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB}, nil, elbv2Mock)
+			tc.expect(elbv2Mock.EXPECT())
+
+			g.Expect(s.deleteNetworkLoadBalancer(testNLBName)).To(Succeed())
+		})
+	}
+}
+
+func TestInstanceIsRegisteredWithAPIServerNLB(t *testing.T) {
+	tests := []struct {
+		name        string
+		expect      func(m *mock_elbv2iface.MockELBV2APIMockRecorder)
+		expected    bool
+		expectError bool
+	}{
+		{
+			name: "instance is a target of the target group",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				m.DescribeTargetHealth(gomock.Eq(&elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(testTargetGroupARN)})).
+					Return(&elbv2.DescribeTargetHealthOutput{
+						TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+							{Target: &elbv2.TargetDescription{Id: aws.String("i-other")}},
+							{Target: &elbv2.TargetDescription{Id: aws.String("i-1")}},
+						},
+					}, nil)
+			},
+			expected: true,
+		},
+		{
+			name: "instance is not a target of the target group",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				m.DescribeTargetHealth(gomock.Any()).
+					Return(&elbv2.DescribeTargetHealthOutput{
+						TargetHealthDescriptions: []*elbv2.TargetHealthDescription{
+							{Target: &elbv2.TargetDescription{Id: aws.String("i-other")}},
+						},
+					}, nil)
+			},
+			expected: false,
+		},
+		{
+			name: "target group doesn't exist yet",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "", nil))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbv2Mock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
+
+			s := newNLBTestService(t, &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB}, nil, elbv2Mock)
+			tc.expect(elbv2Mock.EXPECT())
+
+			registered, err := s.instanceIsRegisteredWithAPIServerNLB(&infrav1.Instance{ID: "i-1"})
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(registered).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRegisterInstanceWithAPIServerNLB(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	elbv2Mock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
+
+	s := newNLBTestService(t, &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB}, nil, elbv2Mock)
+	elbv2Mock.EXPECT().DescribeTargetGroups(gomock.Eq(&elbv2.DescribeTargetGroupsInput{Names: aws.StringSlice([]string{testNLBName})})).
+		Return(testDescribeTargetGroupsOutput(), nil)
+	elbv2Mock.EXPECT().RegisterTargets(gomock.Eq(&elbv2.RegisterTargetsInput{
+		TargetGroupArn: aws.String(testTargetGroupARN),
+		Targets:        []*elbv2.TargetDescription{{Id: aws.String("i-1")}},
+	})).
+		Return(&elbv2.RegisterTargetsOutput{}, nil)
+
+	g.Expect(s.registerInstanceWithAPIServerNLB(&infrav1.Instance{ID: "i-1"})).To(Succeed())
+}
+
+func TestDeregisterInstanceFromAPIServerNLB(t *testing.T) {
+	tests := []struct {
+		name        string
+		expect      func(m *mock_elbv2iface.MockELBV2APIMockRecorder)
+		expectError bool
+	}{
+		{
+			name: "deregisters the instance from the target group",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				m.DeregisterTargets(gomock.Eq(&elbv2.DeregisterTargetsInput{
+					TargetGroupArn: aws.String(testTargetGroupARN),
+					Targets:        []*elbv2.TargetDescription{{Id: aws.String("i-1")}},
+				})).
+					Return(&elbv2.DeregisterTargetsOutput{}, nil)
+			},
+		},
+		{
+			name: "nothing to do without a target group",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "", nil))
+			},
+		},
+		{
+			name: "ignores instances that aren't targets",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				m.DeregisterTargets(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeInvalidTargetException, "", nil))
+			},
+		},
+		{
+			name: "fails on other errors",
+			expect: func(m *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeTargetGroups(gomock.Any()).Return(testDescribeTargetGroupsOutput(), nil)
+				m.DeregisterTargets(gomock.Any()).Return(nil, awserr.New("Throttling", "", nil))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			elbv2Mock := mock_elbv2iface.NewMockELBV2API(mockCtrl)
+
+			s := newNLBTestService(t, &infrav1.AWSLoadBalancerSpec{LoadBalancerType: infrav1.LoadBalancerTypeNLB}, nil, elbv2Mock)
+			tc.expect(elbv2Mock.EXPECT())
+
+			err := s.deregisterInstanceFromAPIServerNLB(&infrav1.Instance{ID: "i-1"})
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+// expectCreatedNLB expects the API server network load balancer, its target group and its listener to be created.
+func expectCreatedNLB(m *mock_elbv2iface.MockELBV2APIMockRecorder, g *WithT, spec *infrav1.ClassicELB, port int64) {
+	m.DescribeLoadBalancers(gomock.Eq(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{testNLBName})})).
+		Return(nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "", nil))
+	m.CreateLoadBalancer(gomock.AssignableToTypeOf(&elbv2.CreateLoadBalancerInput{})).
+		DoAndReturn(func(input *elbv2.CreateLoadBalancerInput) (*elbv2.CreateLoadBalancerOutput, error) {
+			g.Expect(input.Name).To(Equal(aws.String(testNLBName)))
+			g.Expect(input.Type).To(Equal(aws.String(elbv2.LoadBalancerTypeEnumNetwork)))
+			g.Expect(input.Scheme).To(Equal(aws.String(elbv2.LoadBalancerSchemeEnumInternal)))
+			g.Expect(input.Subnets).To(Equal(aws.StringSlice([]string{"subnet-1"})))
+			g.Expect(converters.V2TagsToMap(input.Tags)).To(Equal(spec.Tags))
+			return &elbv2.CreateLoadBalancerOutput{LoadBalancers: testDescribeNLBOutput().LoadBalancers}, nil
+		})
+	m.DescribeLoadBalancerAttributes(gomock.Any()).Return(testDescribeNLBAttributesOutput(false), nil)
+	m.DescribeTags(gomock.Any()).Return(testDescribeNLBTagsOutput(spec), nil)
+	m.DescribeTargetGroups(gomock.Any()).Return(nil, awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "", nil))
+	m.CreateTargetGroup(gomock.AssignableToTypeOf(&elbv2.CreateTargetGroupInput{})).
+		DoAndReturn(func(input *elbv2.CreateTargetGroupInput) (*elbv2.CreateTargetGroupOutput, error) {
+			g.Expect(input.Name).To(Equal(aws.String(testNLBName)))
+			g.Expect(input.Port).To(Equal(aws.Int64(port)))
+			g.Expect(input.Protocol).To(Equal(aws.String(elbv2.ProtocolEnumTcp)))
+			g.Expect(input.TargetType).To(Equal(aws.String(elbv2.TargetTypeEnumInstance)))
+			g.Expect(input.VpcId).To(Equal(aws.String("vpc-1")))
+			g.Expect(input.HealthCheckProtocol).To(Equal(aws.String(elbv2.ProtocolEnumTcp)))
+			g.Expect(input.HealthyThresholdCount).To(Equal(aws.Int64(3)))
+			return &elbv2.CreateTargetGroupOutput{TargetGroups: testDescribeTargetGroupsOutput().TargetGroups}, nil
+		})
+	m.ModifyTargetGroupAttributes(gomock.Eq(&elbv2.ModifyTargetGroupAttributesInput{
+		TargetGroupArn: aws.String(testTargetGroupARN),
+		Attributes: []*elbv2.TargetGroupAttribute{
+			{
+				Key:   aws.String(preserveClientIPAttribute),
+				Value: aws.String("false"),
+			},
+		},
+	})).
+		Return(&elbv2.ModifyTargetGroupAttributesOutput{}, nil)
+	m.DescribeListeners(gomock.Any()).Return(&elbv2.DescribeListenersOutput{}, nil)
+	m.CreateListener(gomock.Eq(&elbv2.CreateListenerInput{
+		LoadBalancerArn: aws.String(testNLBARN),
+		Port:            aws.Int64(port),
+		Protocol:        aws.String(elbv2.ProtocolEnumTcp),
+		DefaultActions: []*elbv2.Action{
+			{
+				Type:           aws.String(elbv2.ActionTypeEnumForward),
+				TargetGroupArn: aws.String(testTargetGroupARN),
+			},
+		},
+	})).
+		Return(&elbv2.CreateListenerOutput{}, nil)
+}
+
+// expectExistingNLB expects the lookups of an existing API server network load balancer and its target group.
+func expectExistingNLB(m *mock_elbv2iface.MockELBV2APIMockRecorder, spec *infrav1.ClassicELB, crossZone bool) {
+	m.DescribeLoadBalancers(gomock.Eq(&elbv2.DescribeLoadBalancersInput{Names: aws.StringSlice([]string{testNLBName})})).
+		Return(testDescribeNLBOutput(), nil)
+	m.DescribeLoadBalancerAttributes(gomock.Eq(&elbv2.DescribeLoadBalancerAttributesInput{LoadBalancerArn: aws.String(testNLBARN)})).
+		Return(testDescribeNLBAttributesOutput(crossZone), nil)
+	m.DescribeTags(gomock.Eq(&elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{testNLBARN})})).
+		Return(testDescribeNLBTagsOutput(spec), nil)
+	m.DescribeTargetGroups(gomock.Eq(&elbv2.DescribeTargetGroupsInput{Names: aws.StringSlice([]string{testNLBName})})).
+		Return(testDescribeTargetGroupsOutput(), nil)
+}
+
+func testDescribeNLBOutput() *elbv2.DescribeLoadBalancersOutput {
+	return &elbv2.DescribeLoadBalancersOutput{
+		LoadBalancers: []*elbv2.LoadBalancer{
+			{
+				LoadBalancerArn:  aws.String(testNLBARN),
+				LoadBalancerName: aws.String(testNLBName),
+				DNSName:          aws.String("test-cluster-apiserver.elb.amazonaws.com"),
+				Type:             aws.String(elbv2.LoadBalancerTypeEnumNetwork),
+				Scheme:           aws.String(elbv2.LoadBalancerSchemeEnumInternal),
+				VpcId:            aws.String("vpc-1"),
+				AvailabilityZones: []*elbv2.AvailabilityZone{
+					{ZoneName: aws.String("us-east-1a"), SubnetId: aws.String("subnet-1")},
+				},
+			},
+		},
+	}
+}
+
+func testDescribeNLBAttributesOutput(crossZone bool) *elbv2.DescribeLoadBalancerAttributesOutput {
+	value := "false"
+	if crossZone {
+		value = "true"
+	}
+	return &elbv2.DescribeLoadBalancerAttributesOutput{
+		Attributes: []*elbv2.LoadBalancerAttribute{
+			{Key: aws.String(crossZoneLoadBalancingAttribute), Value: aws.String(value)},
+		},
+	}
+}
+
+func testDescribeNLBTagsOutput(spec *infrav1.ClassicELB) *elbv2.DescribeTagsOutput {
+	return &elbv2.DescribeTagsOutput{
+		TagDescriptions: []*elbv2.TagDescription{
+			{ResourceArn: aws.String(testNLBARN), Tags: converters.MapToV2Tags(spec.Tags)},
+		},
+	}
+}
+
+func testDescribeTargetGroupsOutput() *elbv2.DescribeTargetGroupsOutput {
+	return &elbv2.DescribeTargetGroupsOutput{
+		TargetGroups: []*elbv2.TargetGroup{
+			{
+				TargetGroupArn:             aws.String(testTargetGroupARN),
+				TargetGroupName:            aws.String(testNLBName),
+				HealthCheckIntervalSeconds: aws.Int64(10),
+				HealthyThresholdCount:      aws.Int64(3),
+				UnhealthyThresholdCount:    aws.Int64(3),
+			},
+		},
+	}
+}
+
+func testAPIServerListener() *elbv2.Listener {
+	return &elbv2.Listener{
+		ListenerArn: aws.String(testListenerARN),
+		Port:        aws.Int64(6443),
+		Protocol:    aws.String(elbv2.ProtocolEnumTcp),
+		DefaultActions: []*elbv2.Action{
+			{
+				Type:           aws.String(elbv2.ActionTypeEnumForward),
+				TargetGroupArn: aws.String(testTargetGroupARN),
+			},
+		},
+	}
+}
+
+func newNLBTestService(t *testing.T, lb *infrav1.AWSLoadBalancerSpec, apiServerPort *int32, elbv2Mock *mock_elbv2iface.MockELBV2API) *Service {
+	t.Helper()
+
+	internal := infrav1.ClassicELBSchemeInternal
+	lb.Scheme = &internal
+
+	scheme, err := setupScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			ControlPlaneLoadBalancer: lb,
+			NetworkSpec: infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{ID: "vpc-1"},
+				Subnets: infrav1.Subnets{
+					{ID: "subnet-1", AvailabilityZone: "us-east-1a"},
+				},
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if err := client.Create(context.TODO(), awsCluster); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "test-cluster"},
+	}
+	if apiServerPort != nil {
+		cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{APIServerPort: apiServerPort}
+	}
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster:    cluster,
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Service{
+		scope:       clusterScope,
+		ELBV2Client: elbv2Mock,
+	}
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+const (
+	loadBalancerResourceType = "elasticloadbalancing:loadbalancer"
+
+	// loadBalancerResourcePrefix precedes the name of a classic ELB, or the type, name and id of a v2
+	// load balancer, in its ARN.
+	loadBalancerResourcePrefix = ":loadbalancer/"
+
+	defaultSecurityGroupName = "default"
+)
+
+// orphanKind finds and deletes the orphans of one kind of resource.
+type orphanKind struct {
+	name   string
+	find   func() ([]string, error)
+	delete func(ids []string) error
+}
+
+// kinds returns the kinds of resources swept, in the order they must be deleted: instances and load
+// balancers keep network interfaces, addresses and security groups in use until they are gone.
+func (s *Service) kinds() []orphanKind {
+	return []orphanKind{
+		{name: "instance", find: s.findOrphanedInstances, delete: s.terminateInstances},
+		{name: "load balancer", find: s.findOrphanedLoadBalancers, delete: s.deleteLoadBalancers},
+		{name: "NAT gateway", find: s.findOrphanedNatGateways, delete: s.deleteNatGateways},
+		{name: "network interface", find: s.findOrphanedNetworkInterfaces, delete: s.deleteNetworkInterfaces},
+		{name: "Elastic IP", find: s.findOrphanedAddresses, delete: s.releaseAddresses},
+		{name: "security group", find: s.findOrphanedSecurityGroups, delete: s.deleteSecurityGroups},
+	}
+}
+
+// ListOrphans returns the resources tagged as owned by the cluster that the other services won't delete
+// with it, because the cluster status doesn't track them. Each is described by its kind and ID.
+func (s *Service) ListOrphans() ([]string, error) {
+	var orphans []string
+	for _, kind := range s.kinds() {
+		ids, err := kind.find()
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			orphans = append(orphans, fmt.Sprintf("%s %s", kind.name, id))
+		}
+	}
+	return orphans, nil
+}
+
+// DeleteOrphans deletes the resources tagged as owned by the cluster that the other services won't
+// delete with it, because the cluster status doesn't track them, e.g. the instance of an AWSMachine
+// whose status was lost, or the resources created by a reconcile that failed before recording them.
+// The resources the status tracks are left to the other services.
+func (s *Service) DeleteOrphans() error {
+	for _, kind := range s.kinds() {
+		// Each kind is looked up only once the kinds before it are gone, as deleting them can leave
+		// more resources behind, e.g. the network interfaces that weren't deleted on termination.
+		ids, err := kind.find()
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		s.scope.Info("Deleting orphaned resources", "kind", kind.name, "ids", ids)
+		if err := kind.delete(ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) findOrphanedInstances() ([]string, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.InstanceStates(ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopping, ec2.InstanceStateNameStopped),
+		},
+	}
+
+	var ids []string
+	err := s.EC2Client.DescribeInstancesPages(input, func(out *ec2.DescribeInstancesOutput, last bool) bool {
+		for _, res := range out.Reservations {
+			for _, instance := range res.Instances {
+				// The bastion is found by its tags and deleted with the rest of the cluster.
+				if converters.TagsToMap(instance.Tags).GetRole() == infrav1.BastionRoleTagValue {
+					continue
+				}
+				ids = append(ids, aws.StringValue(instance.InstanceId))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe instances owned by cluster %q", s.scope.Name())
+	}
+	return ids, nil
+}
+
+func (s *Service) terminateInstances(ids []string) error {
+	if _, err := s.EC2Client.TerminateInstances(&ec2.TerminateInstancesInput{
+		InstanceIds: aws.StringSlice(ids),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteOrphanedResource", "Failed to terminate orphaned instances %v: %v", ids, err)
+		return errors.Wrapf(err, "failed to terminate orphaned instances %v", ids)
+	}
+
+	// The network interfaces of the instances keep their security groups in use until they are terminated.
+	if err := s.EC2Client.WaitUntilInstanceTerminated(&ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice(ids),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to wait for orphaned instances %v to terminate", ids)
+	}
+
+	for _, id := range ids {
+		s.recordDeleted("instance", id)
+	}
+	return nil
+}
+
+func (s *Service) findOrphanedLoadBalancers() ([]string, error) {
+	input := &rgapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice([]string{loadBalancerResourceType}),
+		TagFilters: []*rgapi.TagFilter{
+			{
+				Key:    aws.String(infrav1.ClusterTagKey(s.scope.Name())),
+				Values: aws.StringSlice([]string{string(infrav1.ResourceLifecycleOwned)}),
+			},
+		},
+	}
+
+	var arns []string
+	err := s.ResourceTaggingClient.GetResourcesPages(input, func(out *rgapi.GetResourcesOutput, last bool) bool {
+		for _, mapping := range out.ResourceTagMappingList {
+			// The API server load balancer is deleted by name with the rest of the cluster.
+			if isAPIServerLoadBalancer(mapping.Tags) {
+				continue
+			}
+			arns = append(arns, aws.StringValue(mapping.ResourceARN))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list load balancers owned by cluster %q", s.scope.Name())
+	}
+	return arns, nil
+}
+
+func isAPIServerLoadBalancer(tags []*rgapi.Tag) bool {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == infrav1.NameAWSClusterAPIRole && aws.StringValue(tag.Value) == infrav1.APIServerRoleTagValue {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) deleteLoadBalancers(arns []string) error {
+	var errs []error
+	for _, arn := range arns {
+		var err error
+		resource := arn[strings.Index(arn, loadBalancerResourcePrefix)+len(loadBalancerResourcePrefix):]
+		if strings.HasPrefix(resource, "net/") || strings.HasPrefix(resource, "app/") {
+			_, err = s.ELBV2Client.DeleteLoadBalancer(&elbv2.DeleteLoadBalancerInput{
+				LoadBalancerArn: aws.String(arn),
+			})
+		} else {
+			_, err = s.ELBClient.DeleteLoadBalancer(&elb.DeleteLoadBalancerInput{
+				LoadBalancerName: aws.String(resource),
+			})
+		}
+		if err != nil {
+			errs = append(errs, s.recordFailedDelete("load balancer", arn, err))
+			continue
+		}
+		s.recordDeleted("load balancer", arn)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func (s *Service) findOrphanedNatGateways() ([]string, error) {
+	tracked := map[string]bool{}
+	for _, subnet := range s.scope.Subnets() {
+		if subnet.NatGatewayID != nil {
+			tracked[*subnet.NatGatewayID] = true
+		}
+	}
+
+	input := &ec2.DescribeNatGatewaysInput{
+		Filter: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			filter.EC2.NATGatewayStates(ec2.NatGatewayStatePending, ec2.NatGatewayStateAvailable, ec2.NatGatewayStateFailed),
+		},
+	}
+
+	var ids []string
+	err := s.EC2Client.DescribeNatGatewaysPages(input, func(out *ec2.DescribeNatGatewaysOutput, last bool) bool {
+		for _, ng := range out.NatGateways {
+			if !tracked[aws.StringValue(ng.NatGatewayId)] {
+				ids = append(ids, aws.StringValue(ng.NatGatewayId))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe NAT gateways owned by cluster %q", s.scope.Name())
+	}
+	return ids, nil
+}
+
+func (s *Service) deleteNatGateways(ids []string) error {
+	var errs []error
+	for _, id := range ids {
+		if _, err := s.EC2Client.DeleteNatGateway(&ec2.DeleteNatGatewayInput{
+			NatGatewayId: aws.String(id),
+		}); err != nil {
+			errs = append(errs, s.recordFailedDelete("NAT gateway", id, err))
+			continue
+		}
+		s.recordDeleted("NAT gateway", id)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func (s *Service) findOrphanedNetworkInterfaces() ([]string, error) {
+	// Interfaces in use go away with their instance or load balancer.
+	input := &ec2.DescribeNetworkInterfacesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+			{
+				Name:   aws.String("status"),
+				Values: aws.StringSlice([]string{ec2.NetworkInterfaceStatusAvailable}),
+			},
+		},
+	}
+
+	var ids []string
+	err := s.EC2Client.DescribeNetworkInterfacesPages(input, func(out *ec2.DescribeNetworkInterfacesOutput, last bool) bool {
+		for _, ni := range out.NetworkInterfaces {
+			ids = append(ids, aws.StringValue(ni.NetworkInterfaceId))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe network interfaces owned by cluster %q", s.scope.Name())
+	}
+	return ids, nil
+}
+
+func (s *Service) deleteNetworkInterfaces(ids []string) error {
+	var errs []error
+	for _, id := range ids {
+		if _, err := s.EC2Client.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{
+			NetworkInterfaceId: aws.String(id),
+		}); err != nil && !awserrors.IsNotFound(err) {
+			errs = append(errs, s.recordFailedDelete("network interface", id, err))
+			continue
+		}
+		s.recordDeleted("network interface", id)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func (s *Service) findOrphanedAddresses() ([]string, error) {
+	out, err := s.EC2Client.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe Elastic IPs owned by cluster %q", s.scope.Name())
+	}
+
+	var ids []string
+	for _, address := range out.Addresses {
+		// Addresses still associated belong to a NAT gateway or instance the cluster tracks, and are
+		// released with the network.
+		if address.AssociationId != nil {
+			continue
+		}
+		ids = append(ids, aws.StringValue(address.AllocationId))
+	}
+	return ids, nil
+}
+
+func (s *Service) releaseAddresses(ids []string) error {
+	var errs []error
+	for _, id := range ids {
+		if _, err := s.EC2Client.ReleaseAddress(&ec2.ReleaseAddressInput{
+			AllocationId: aws.String(id),
+		}); err != nil {
+			errs = append(errs, s.recordFailedDelete("Elastic IP", id, err))
+			continue
+		}
+		s.recordDeleted("Elastic IP", id)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func (s *Service) findOrphanedSecurityGroups() ([]string, error) {
+	tracked := map[string]bool{}
+	for _, sg := range s.scope.SecurityGroups() {
+		tracked[sg.ID] = true
+	}
+
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+		},
+	}
+
+	var ids []string
+	err := s.EC2Client.DescribeSecurityGroupsPages(input, func(out *ec2.DescribeSecurityGroupsOutput, last bool) bool {
+		for _, sg := range out.SecurityGroups {
+			// The default security group of a VPC goes away with the VPC.
+			if tracked[aws.StringValue(sg.GroupId)] || aws.StringValue(sg.GroupName) == defaultSecurityGroupName {
+				continue
+			}
+			ids = append(ids, aws.StringValue(sg.GroupId))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe security groups owned by cluster %q", s.scope.Name())
+	}
+	return ids, nil
+}
+
+func (s *Service) deleteSecurityGroups(ids []string) error {
+	out, err := s.EC2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		GroupIds: aws.StringSlice(ids),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe orphaned security groups %v", ids)
+	}
+
+	// A security group can't be deleted while a rule of another group references it, so the rules of
+	// all the orphaned groups are revoked before any of them is deleted.
+	for _, sg := range out.SecurityGroups {
+		if len(sg.IpPermissions) > 0 {
+			if _, err := s.EC2Client.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+				GroupId:       sg.GroupId,
+				IpPermissions: sg.IpPermissions,
+			}); awserrors.IsIgnorableSecurityGroupError(err) != nil {
+				return errors.Wrapf(err, "failed to revoke ingress rules of orphaned security group %q", aws.StringValue(sg.GroupId))
+			}
+		}
+		if len(sg.IpPermissionsEgress) > 0 {
+			if _, err := s.EC2Client.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+				GroupId:       sg.GroupId,
+				IpPermissions: sg.IpPermissionsEgress,
+			}); awserrors.IsIgnorableSecurityGroupError(err) != nil {
+				return errors.Wrapf(err, "failed to revoke egress rules of orphaned security group %q", aws.StringValue(sg.GroupId))
+			}
+		}
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if _, err := s.EC2Client.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{
+			GroupId: aws.String(id),
+		}); awserrors.IsIgnorableSecurityGroupError(err) != nil {
+			errs = append(errs, s.recordFailedDelete("security group", id, err))
+			continue
+		}
+		s.recordDeleted("security group", id)
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+func (s *Service) recordDeleted(kind, id string) {
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteOrphanedResource", "Deleted orphaned %s %q", kind, id)
+	s.scope.V(2).Info("Deleted orphaned resource", "kind", kind, "id", id)
+}
+
+func (s *Service) recordFailedDelete(kind, id string, err error) error {
+	record.Warnf(s.scope.InfraCluster(), "FailedDeleteOrphanedResource", "Failed to delete orphaned %s %q: %v", kind, id, err)
+	return errors.Wrapf(err, "failed to delete orphaned %s %q", kind, id)
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	rgapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbiface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_elbv2iface"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/elb/mock_resourcegroupstaggingapiiface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const clusterName = "cluster"
+
+func TestDeleteOrphans(t *testing.T) {
+	tests := []struct {
+		name        string
+		expect      func(m *mock_ec2iface.MockEC2APIMockRecorder, rg *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder, e *mock_elbiface.MockELBAPIMockRecorder, e2 *mock_elbv2iface.MockELBV2APIMockRecorder)
+		expectError bool
+	}{
+		{
+			name: "deletes the owned resources the cluster status doesn't track",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder, rg *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder, e *mock_elbiface.MockELBAPIMockRecorder, e2 *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeInstancesPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *ec2.DescribeInstancesOutput, lastPage bool) bool)
+					funct(&ec2.DescribeInstancesOutput{
+						Reservations: []*ec2.Reservation{
+							{
+								Instances: []*ec2.Instance{
+									{InstanceId: aws.String("i-orphan")},
+									{
+										InstanceId: aws.String("i-bastion"),
+										Tags: []*ec2.Tag{
+											{Key: aws.String(infrav1.NameAWSClusterAPIRole), Value: aws.String(infrav1.BastionRoleTagValue)},
+										},
+									},
+								},
+							},
+						},
+					}, true)
+				}).Return(nil)
+				m.TerminateInstances(gomock.Eq(&ec2.TerminateInstancesInput{InstanceIds: aws.StringSlice([]string{"i-orphan"})})).
+					Return(&ec2.TerminateInstancesOutput{}, nil)
+				m.WaitUntilInstanceTerminated(gomock.Eq(&ec2.DescribeInstancesInput{InstanceIds: aws.StringSlice([]string{"i-orphan"})})).
+					Return(nil)
+
+				rg.GetResourcesPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *rgapi.GetResourcesOutput, lastPage bool) bool)
+					funct(&rgapi.GetResourcesOutput{
+						ResourceTagMappingList: []*rgapi.ResourceTagMapping{
+							{ResourceARN: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/orphan-elb")},
+							{ResourceARN: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/orphan-nlb/50dc6c495c0c9188")},
+							{
+								ResourceARN: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/cluster-apiserver"),
+								Tags: []*rgapi.Tag{
+									{Key: aws.String(infrav1.NameAWSClusterAPIRole), Value: aws.String(infrav1.APIServerRoleTagValue)},
+								},
+							},
+						},
+					}, true)
+				}).Return(nil)
+				e.DeleteLoadBalancer(gomock.Eq(&elb.DeleteLoadBalancerInput{LoadBalancerName: aws.String("orphan-elb")})).
+					Return(&elb.DeleteLoadBalancerOutput{}, nil)
+				e2.DeleteLoadBalancer(gomock.Eq(&elbv2.DeleteLoadBalancerInput{
+					LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/orphan-nlb/50dc6c495c0c9188"),
+				})).Return(&elbv2.DeleteLoadBalancerOutput{}, nil)
+
+				m.DescribeNatGatewaysPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *ec2.DescribeNatGatewaysOutput, lastPage bool) bool)
+					funct(&ec2.DescribeNatGatewaysOutput{
+						NatGateways: []*ec2.NatGateway{
+							{NatGatewayId: aws.String("nat-orphan")},
+							{NatGatewayId: aws.String("nat-tracked")},
+						},
+					}, true)
+				}).Return(nil)
+				m.DeleteNatGateway(gomock.Eq(&ec2.DeleteNatGatewayInput{NatGatewayId: aws.String("nat-orphan")})).
+					Return(&ec2.DeleteNatGatewayOutput{}, nil)
+
+				m.DescribeNetworkInterfacesPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool)
+					funct(&ec2.DescribeNetworkInterfacesOutput{
+						NetworkInterfaces: []*ec2.NetworkInterface{
+							{NetworkInterfaceId: aws.String("eni-orphan")},
+						},
+					}, true)
+				}).Return(nil)
+				m.DeleteNetworkInterface(gomock.Eq(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: aws.String("eni-orphan")})).
+					Return(&ec2.DeleteNetworkInterfaceOutput{}, nil)
+
+				m.DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{
+					Addresses: []*ec2.Address{
+						{AllocationId: aws.String("eipalloc-orphan")},
+						{AllocationId: aws.String("eipalloc-nat"), AssociationId: aws.String("eipassoc-nat")},
+					},
+				}, nil)
+				m.ReleaseAddress(gomock.Eq(&ec2.ReleaseAddressInput{AllocationId: aws.String("eipalloc-orphan")})).
+					Return(&ec2.ReleaseAddressOutput{}, nil)
+
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool)
+					funct(&ec2.DescribeSecurityGroupsOutput{
+						SecurityGroups: []*ec2.SecurityGroup{
+							{GroupId: aws.String("sg-orphan"), GroupName: aws.String("orphan")},
+							{GroupId: aws.String("sg-node"), GroupName: aws.String("cluster-node")},
+							{GroupId: aws.String("sg-default"), GroupName: aws.String("default")},
+						},
+					}, true)
+				}).Return(nil)
+				ingress := []*ec2.IpPermission{
+					{
+						IpProtocol:       aws.String("tcp"),
+						FromPort:         aws.Int64(443),
+						ToPort:           aws.Int64(443),
+						UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-node")}},
+					},
+				}
+				m.DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{GroupIds: aws.StringSlice([]string{"sg-orphan"})})).
+					Return(&ec2.DescribeSecurityGroupsOutput{
+						SecurityGroups: []*ec2.SecurityGroup{
+							{GroupId: aws.String("sg-orphan"), IpPermissions: ingress},
+						},
+					}, nil)
+				m.RevokeSecurityGroupIngress(gomock.Eq(&ec2.RevokeSecurityGroupIngressInput{
+					GroupId:       aws.String("sg-orphan"),
+					IpPermissions: ingress,
+				})).Return(&ec2.RevokeSecurityGroupIngressOutput{}, nil)
+				m.DeleteSecurityGroup(gomock.Eq(&ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-orphan")})).
+					Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+			},
+		},
+		{
+			name: "does nothing without orphans",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder, rg *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder, e *mock_elbiface.MockELBAPIMockRecorder, e2 *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeInstancesPages(gomock.Any(), gomock.Any()).Return(nil)
+				rg.GetResourcesPages(gomock.Any(), gomock.Any()).Return(nil)
+				m.DescribeNatGatewaysPages(gomock.Any(), gomock.Any()).Return(nil)
+				m.DescribeNetworkInterfacesPages(gomock.Any(), gomock.Any()).Return(nil)
+				m.DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{}, nil)
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "stops before the security groups when an instance can't be terminated",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder, rg *mock_resourcegroupstaggingapiiface.MockResourceGroupsTaggingAPIAPIMockRecorder, e *mock_elbiface.MockELBAPIMockRecorder, e2 *mock_elbv2iface.MockELBV2APIMockRecorder) {
+				m.DescribeInstancesPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+					funct := y.(func(output *ec2.DescribeInstancesOutput, lastPage bool) bool)
+					funct(&ec2.DescribeInstancesOutput{
+						Reservations: []*ec2.Reservation{
+							{Instances: []*ec2.Instance{{InstanceId: aws.String("i-orphan")}}},
+						},
+					}, true)
+				}).Return(nil)
+				m.TerminateInstances(gomock.Any()).
+					Return(nil, awserr.New("OperationNotPermitted", "The instance may not be terminated", nil))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockControl := gomock.NewController(t)
+			defer mockControl.Finish()
+
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockControl)
+			rgMock := mock_resourcegroupstaggingapiiface.NewMockResourceGroupsTaggingAPIAPI(mockControl)
+			elbMock := mock_elbiface.NewMockELBAPI(mockControl)
+			elbV2Mock := mock_elbv2iface.NewMockELBV2API(mockControl)
+
+			s := newService(g)
+			s.EC2Client = ec2Mock
+			s.ResourceTaggingClient = rgMock
+			s.ELBClient = elbMock
+			s.ELBV2Client = elbV2Mock
+
+			tc.expect(ec2Mock.EXPECT(), rgMock.EXPECT(), elbMock.EXPECT(), elbV2Mock.EXPECT())
+
+			err := s.DeleteOrphans()
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(BeNil())
+		})
+	}
+}
+
+func TestListOrphans(t *testing.T) {
+	g := NewWithT(t)
+
+	mockControl := gomock.NewController(t)
+	defer mockControl.Finish()
+
+	ec2Mock := mock_ec2iface.NewMockEC2API(mockControl)
+	rgMock := mock_resourcegroupstaggingapiiface.NewMockResourceGroupsTaggingAPIAPI(mockControl)
+
+	s := newService(g)
+	s.EC2Client = ec2Mock
+	s.ResourceTaggingClient = rgMock
+
+	// Nothing is deleted, so any deletion would fail the test as unexpected.
+	m := ec2Mock.EXPECT()
+	m.DescribeInstancesPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+		funct := y.(func(output *ec2.DescribeInstancesOutput, lastPage bool) bool)
+		funct(&ec2.DescribeInstancesOutput{
+			Reservations: []*ec2.Reservation{
+				{Instances: []*ec2.Instance{{InstanceId: aws.String("i-orphan")}}},
+			},
+		}, true)
+	}).Return(nil)
+	rgMock.EXPECT().GetResourcesPages(gomock.Any(), gomock.Any()).Return(nil)
+	m.DescribeNatGatewaysPages(gomock.Any(), gomock.Any()).Return(nil)
+	m.DescribeNetworkInterfacesPages(gomock.Any(), gomock.Any()).Do(func(_, y interface{}) {
+		funct := y.(func(output *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool)
+		funct(&ec2.DescribeNetworkInterfacesOutput{
+			NetworkInterfaces: []*ec2.NetworkInterface{{NetworkInterfaceId: aws.String("eni-orphan")}},
+		}, true)
+	}).Return(nil)
+	m.DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{}, nil)
+	m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+
+	orphans, err := s.ListOrphans()
+	g.Expect(err).To(BeNil())
+	g.Expect(orphans).To(Equal([]string{"instance i-orphan", "network interface eni-orphan"}))
+}
+
+func newService(g *WithT) *Service {
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(infrav1.AddToScheme(scheme)).To(Succeed())
+
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			NetworkSpec: infrav1.NetworkSpec{
+				Subnets: infrav1.Subnets{
+					{ID: "subnet-public", IsPublic: true, NatGatewayID: aws.String("nat-tracked")},
+				},
+			},
+		},
+		Status: infrav1.AWSClusterStatus{
+			Network: infrav1.NetworkStatus{
+				SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+					infrav1.SecurityGroupNode: {ID: "sg-node"},
+				},
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	g.Expect(client.Create(context.TODO(), awsCluster)).To(Succeed())
+
+	clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      clusterName,
+			},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+	g.Expect(err).To(BeNil())
+
+	return NewService(clusterScope)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc deletes the AWS resources owned by a cluster that its status has lost track of.
+package gc
+
+import (
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+// Scope is a scope for use with the garbage collection service.
+type Scope interface {
+	cloud.ClusterScoper
+
+	// Subnets returns the cluster subnets.
+	Subnets() infrav1.Subnets
+
+	// SecurityGroups returns the cluster security groups as a map, it creates the map if empty.
+	SecurityGroups() map[infrav1.SecurityGroupRole]infrav1.SecurityGroup
+}
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+// One alternative is to have a large list of functions from the ec2 client.
+type Service struct {
+	scope                 Scope
+	EC2Client             ec2iface.EC2API
+	ELBClient             elbiface.ELBAPI
+	ELBV2Client           elbv2iface.ELBV2API
+	ResourceTaggingClient resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+}
+
+// NewService returns a new service given the api clients.
+func NewService(gcScope Scope) *Service {
+	return &Service{
+		scope:                 gcScope,
+		EC2Client:             scope.NewEC2Client(gcScope, gcScope, gcScope, gcScope.InfraCluster()),
+		ELBClient:             scope.NewELBClient(gcScope, gcScope, gcScope, gcScope.InfraCluster()),
+		ELBV2Client:           scope.NewELBv2Client(gcScope, gcScope, gcScope, gcScope.InfraCluster()),
+		ResourceTaggingClient: scope.NewResourgeTaggingClient(gcScope, gcScope, gcScope, gcScope.InfraCluster()),
+	}
+}
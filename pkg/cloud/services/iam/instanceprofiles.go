@@ -0,0 +1,261 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/cmd/clusterawsadm/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// ReconcileInstanceProfile creates the named instance profile if it doesn't exist, together with
+// a role of the same name that EC2 can assume and that is granted the given policy.
+// Instance profiles that already have a role keep their policies, as they may be managed outside of the cluster.
+// The additional managed policies are only attached to roles created by this service.
+func (s *Service) ReconcileInstanceProfile(name string, policy *infrav1.PolicyDocument, additionalPolicies []string) error {
+	profile, err := s.getInstanceProfile(name)
+	if err != nil {
+		return err
+	}
+
+	roleName := name
+	if profile != nil && len(profile.Roles) > 0 {
+		roleName = aws.StringValue(profile.Roles[0].RoleName)
+	} else if err := s.createInstanceProfile(name, profile == nil, policy); err != nil {
+		return err
+	}
+
+	return s.ensurePoliciesAttached(roleName, additionalPolicies)
+}
+
+func (s *Service) createInstanceProfile(name string, create bool, policy *infrav1.PolicyDocument) (err error) {
+	roleCreated, err := s.reconcileInstanceProfileRole(name, policy)
+	if roleCreated {
+		// Don't leave a role behind that no instance profile uses, the next attempt creates it again.
+		defer func() {
+			if err != nil {
+				s.deleteInstanceProfileRole(name)
+			}
+		}()
+	}
+	if err != nil {
+		return err
+	}
+
+	if create {
+		s.scope.V(2).Info("Creating instance profile", "instance-profile", name)
+		if _, err := s.IAMClient.CreateInstanceProfile(&iam.CreateInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+			Tags:                s.getTags(),
+		}); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedCreateInstanceProfile", "Failed to create instance profile %q: %v", name, err)
+			return errors.Wrapf(err, "failed to create instance profile %q", name)
+		}
+	}
+
+	if _, err := s.IAMClient.AddRoleToInstanceProfile(&iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+		RoleName:            aws.String(name),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateInstanceProfile", "Failed to add role %q to instance profile %q: %v", name, name, err)
+		return errors.Wrapf(err, "failed to add role %q to instance profile %q", name, name)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateInstanceProfile", "Created instance profile %q", name)
+	return nil
+}
+
+// reconcileInstanceProfileRole creates the role of the instance profile if it doesn't exist, and puts the policy on it.
+// It returns true if the role was created.
+func (s *Service) reconcileInstanceProfileRole(name string, policy *infrav1.PolicyDocument) (bool, error) {
+	created := false
+	_, err := s.IAMClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(name),
+	})
+	switch {
+	case isNoSuchEntity(err):
+		trustRelationshipJSON, err := converters.IAMPolicyDocumentToJSON(*ec2TrustRelationship())
+		if err != nil {
+			return false, errors.Wrap(err, "error converting trust relationship to json")
+		}
+
+		s.scope.V(2).Info("Creating role for instance profile", "role", name)
+		if _, err := s.IAMClient.CreateRole(&iam.CreateRoleInput{
+			RoleName:                 aws.String(name),
+			AssumeRolePolicyDocument: aws.String(trustRelationshipJSON),
+			Tags: append(s.getTags(), &iam.Tag{
+				Key:   aws.String(infrav1.NameAWSClusterAPIRole),
+				Value: aws.String(infrav1.InstanceProfileRoleTagValue),
+			}),
+		}); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedCreateRole", "Failed to create role %q: %v", name, err)
+			return false, errors.Wrapf(err, "failed to create role %q", name)
+		}
+		created = true
+	case err != nil:
+		return false, errors.Wrapf(err, "failed to get role %q", name)
+	}
+
+	policyJSON, err := converters.IAMPolicyDocumentToJSON(*policy)
+	if err != nil {
+		return created, errors.Wrap(err, "error converting policy to json")
+	}
+
+	// The policy is put inline on every attempt, as a previous one may have failed between creating the role and granting it.
+	if _, err := s.IAMClient.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(name),
+		PolicyName:     aws.String(name),
+		PolicyDocument: aws.String(policyJSON),
+	}); err != nil {
+		return created, errors.Wrapf(err, "failed to put policy on role %q", name)
+	}
+
+	return created, nil
+}
+
+// deleteInstanceProfileRole deletes a role created for an instance profile that couldn't be set up.
+// Errors are only logged, as the error that caused the rollback is the one returned to the caller.
+func (s *Service) deleteInstanceProfileRole(name string) {
+	s.scope.V(2).Info("Deleting role of instance profile that couldn't be created", "role", name)
+	if _, err := s.IAMClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(name),
+		PolicyName: aws.String(name),
+	}); err != nil && !isNoSuchEntity(err) {
+		s.scope.Error(err, "failed to delete policy of role", "role", name)
+		return
+	}
+
+	if _, err := s.IAMClient.DeleteRole(&iam.DeleteRoleInput{
+		RoleName: aws.String(name),
+	}); err != nil && !isNoSuchEntity(err) {
+		s.scope.Error(err, "failed to delete role", "role", name)
+	}
+}
+
+// ensurePoliciesAttached attaches the given managed policies to the role if it was created by this service,
+// roles created by other means are left as they are. Policies are never detached, as the role may be shared
+// with machines requesting other policies.
+func (s *Service) ensurePoliciesAttached(roleName string, policies []string) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	role, err := s.IAMClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get role %q", roleName)
+	}
+	if !isInstanceProfileRole(role.Role) {
+		record.Warnf(s.scope.InfraCluster(), "SkippedAttachRolePolicy", "Not attaching policies to role %q, which wasn't created for an instance profile by the controller", roleName)
+		return nil
+	}
+
+	out, err := s.IAMClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list policies attached to role %q", roleName)
+	}
+
+	attached := make(map[string]struct{}, len(out.AttachedPolicies))
+	for _, policy := range out.AttachedPolicies {
+		attached[aws.StringValue(policy.PolicyArn)] = struct{}{}
+	}
+
+	for _, policyARN := range policies {
+		if _, ok := attached[policyARN]; ok {
+			continue
+		}
+
+		s.scope.V(2).Info("Attaching policy to role", "role", roleName, "policy", policyARN)
+		if _, err := s.IAMClient.AttachRolePolicy(&iam.AttachRolePolicyInput{
+			RoleName:  aws.String(roleName),
+			PolicyArn: aws.String(policyARN),
+		}); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedAttachRolePolicy", "Failed to attach policy %q to role %q: %v", policyARN, roleName, err)
+			return errors.Wrapf(err, "failed to attach policy %q to role %q", policyARN, roleName)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) getInstanceProfile(name string) (*iam.InstanceProfile, error) {
+	out, err := s.IAMClient.GetInstanceProfile(&iam.GetInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+	})
+	switch {
+	case isNoSuchEntity(err):
+		return nil, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to get instance profile %q", name)
+	}
+
+	return out.InstanceProfile, nil
+}
+
+// getTags returns the additional tags of the cluster. Instance profiles are not tagged as owned by the
+// cluster, since several clusters may share them and they are not removed when the cluster is deleted.
+func (s *Service) getTags() []*iam.Tag {
+	tags := []*iam.Tag{}
+	for k, v := range s.scope.AdditionalTags() {
+		tags = append(tags, &iam.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	return tags
+}
+
+func ec2TrustRelationship() *infrav1.PolicyDocument {
+	return &infrav1.PolicyDocument{
+		Version: infrav1.CurrentVersion,
+		Statement: []infrav1.StatementEntry{
+			{
+				Effect: infrav1.EffectAllow,
+				Action: infrav1.Actions{
+					"sts:AssumeRole",
+				},
+				Principal: infrav1.Principals{
+					infrav1.PrincipalService: []string{"ec2.amazonaws.com"},
+				},
+			},
+		},
+	}
+}
+
+func isInstanceProfileRole(role *iam.Role) bool {
+	if role == nil {
+		return false
+	}
+	for _, tag := range role.Tags {
+		if aws.StringValue(tag.Key) == infrav1.NameAWSClusterAPIRole && aws.StringValue(tag.Value) == infrav1.InstanceProfileRoleTagValue {
+			return true
+		}
+	}
+	return false
+}
+
+func isNoSuchEntity(err error) bool {
+	code, _ := awserrors.Code(err)
+	return code == iam.ErrCodeNoSuchEntityException
+}
@@ -0,0 +1,335 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileInstanceProfile(t *testing.T) {
+	const name = "custom-nodes"
+	noSuchEntity := awserr.New(iam.ErrCodeNoSuchEntityException, "", nil)
+	instanceProfileRole := &iam.Role{
+		Tags: []*iam.Tag{{
+			Key:   aws.String(infrav1.NameAWSClusterAPIRole),
+			Value: aws.String(infrav1.InstanceProfileRoleTagValue),
+		}},
+	}
+
+	testCases := []struct {
+		name               string
+		additionalPolicies []string
+		expect             func(m *mockIAMAPIMockRecorder)
+		expectErr          bool
+	}{
+		{
+			name:               "existing instance profile only gets the additional policies attached",
+			additionalPolicies: []string{"arn:aws:iam::aws:policy/CloudWatchAgentServerPolicy", "arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"},
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(&iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)}).
+					Return(&iam.GetInstanceProfileOutput{InstanceProfile: &iam.InstanceProfile{
+						Roles: []*iam.Role{{RoleName: aws.String("shared-nodes")}},
+					}}, nil)
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String("shared-nodes")}).
+					Return(&iam.GetRoleOutput{Role: instanceProfileRole}, nil)
+				m.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String("shared-nodes")}).
+					Return(&iam.ListAttachedRolePoliciesOutput{AttachedPolicies: []*iam.AttachedPolicy{
+						{PolicyArn: aws.String("arn:aws:iam::aws:policy/CloudWatchAgentServerPolicy")},
+					}}, nil)
+				m.AttachRolePolicy(&iam.AttachRolePolicyInput{
+					RoleName:  aws.String("shared-nodes"),
+					PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"),
+				}).Return(&iam.AttachRolePolicyOutput{}, nil)
+			},
+		},
+		{
+			name:               "role not created by the controller doesn't get the additional policies attached",
+			additionalPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(gomock.Any()).
+					Return(&iam.GetInstanceProfileOutput{InstanceProfile: &iam.InstanceProfile{
+						Roles: []*iam.Role{{RoleName: aws.String("nodes.cluster-api-provider-aws.sigs.k8s.io")}},
+					}}, nil)
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String("nodes.cluster-api-provider-aws.sigs.k8s.io")}).
+					Return(&iam.GetRoleOutput{Role: &iam.Role{}}, nil)
+			},
+		},
+		{
+			name:               "missing instance profile is created with a new role that gets the additional policies attached",
+			additionalPolicies: []string{"arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"},
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(gomock.Any()).Return(nil, noSuchEntity)
+				m.GetRole(gomock.Any()).Return(nil, noSuchEntity)
+				m.CreateRole(gomock.Any()).Return(&iam.CreateRoleOutput{}, nil)
+				m.PutRolePolicy(gomock.Any()).Return(&iam.PutRolePolicyOutput{}, nil)
+				m.CreateInstanceProfile(gomock.Any()).Return(&iam.CreateInstanceProfileOutput{}, nil)
+				m.AddRoleToInstanceProfile(gomock.Any()).Return(&iam.AddRoleToInstanceProfileOutput{}, nil)
+				m.GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: instanceProfileRole}, nil)
+				m.ListAttachedRolePolicies(gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+				m.AttachRolePolicy(&iam.AttachRolePolicyInput{
+					RoleName:  aws.String(name),
+					PolicyArn: aws.String("arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"),
+				}).Return(&iam.AttachRolePolicyOutput{}, nil)
+			},
+		},
+		{
+			name: "missing instance profile is created with a new role",
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(gomock.Any()).Return(nil, noSuchEntity)
+				m.GetRole(&iam.GetRoleInput{RoleName: aws.String(name)}).Return(nil, noSuchEntity)
+				m.CreateRole(gomock.AssignableToTypeOf(&iam.CreateRoleInput{})).Return(&iam.CreateRoleOutput{}, nil)
+				m.PutRolePolicy(gomock.AssignableToTypeOf(&iam.PutRolePolicyInput{})).Return(&iam.PutRolePolicyOutput{}, nil)
+				m.CreateInstanceProfile(gomock.AssignableToTypeOf(&iam.CreateInstanceProfileInput{})).Return(&iam.CreateInstanceProfileOutput{}, nil)
+				m.AddRoleToInstanceProfile(&iam.AddRoleToInstanceProfileInput{
+					InstanceProfileName: aws.String(name),
+					RoleName:            aws.String(name),
+				}).Return(&iam.AddRoleToInstanceProfileOutput{}, nil)
+			},
+		},
+		{
+			name: "existing instance profile without a role gets one",
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(gomock.Any()).Return(&iam.GetInstanceProfileOutput{InstanceProfile: &iam.InstanceProfile{}}, nil)
+				m.GetRole(gomock.Any()).Return(&iam.GetRoleOutput{}, nil)
+				m.PutRolePolicy(gomock.AssignableToTypeOf(&iam.PutRolePolicyInput{})).Return(&iam.PutRolePolicyOutput{}, nil)
+				m.AddRoleToInstanceProfile(gomock.Any()).Return(&iam.AddRoleToInstanceProfileOutput{}, nil)
+			},
+		},
+		{
+			name: "role created for a failed instance profile is deleted",
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(gomock.Any()).Return(nil, noSuchEntity)
+				m.GetRole(gomock.Any()).Return(nil, noSuchEntity)
+				m.CreateRole(gomock.Any()).Return(&iam.CreateRoleOutput{}, nil)
+				m.PutRolePolicy(gomock.Any()).Return(&iam.PutRolePolicyOutput{}, nil)
+				m.CreateInstanceProfile(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeLimitExceededException, "", nil))
+				m.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+					RoleName:   aws.String(name),
+					PolicyName: aws.String(name),
+				}).Return(&iam.DeleteRolePolicyOutput{}, nil)
+				m.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(name)}).Return(&iam.DeleteRoleOutput{}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "role created when the policy can't be put on it is deleted",
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(gomock.Any()).Return(nil, noSuchEntity)
+				m.GetRole(gomock.Any()).Return(nil, noSuchEntity)
+				m.CreateRole(gomock.Any()).Return(&iam.CreateRoleOutput{}, nil)
+				m.PutRolePolicy(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeMalformedPolicyDocumentException, "", nil))
+				m.DeleteRolePolicy(gomock.Any()).Return(nil, noSuchEntity)
+				m.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(name)}).Return(&iam.DeleteRoleOutput{}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name: "existing role is kept when the instance profile can't be created",
+			expect: func(m *mockIAMAPIMockRecorder) {
+				m.GetInstanceProfile(gomock.Any()).Return(nil, noSuchEntity)
+				m.GetRole(gomock.Any()).Return(&iam.GetRoleOutput{}, nil)
+				m.PutRolePolicy(gomock.Any()).Return(&iam.PutRolePolicyOutput{}, nil)
+				m.CreateInstanceProfile(gomock.Any()).Return(nil, awserr.New(iam.ErrCodeLimitExceededException, "", nil))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			iamMock := newMockIAMAPI(mockCtrl)
+			tc.expect(iamMock.EXPECT())
+
+			clusterScope, err := setupCluster("test-cluster")
+			if err != nil {
+				t.Fatalf("failed to create test context: %v", err)
+			}
+
+			s := &Service{scope: clusterScope, IAMClient: iamMock}
+
+			err = s.ReconcileInstanceProfile(name, NodeInstanceProfilePolicy(infrav1.SecretBackendSecretsManager), tc.additionalPolicies)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error: %v, got: %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func setupCluster(clusterName string) (*scope.ClusterScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(awsCluster).Build()
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+}
+
+// mockIAMAPI is a gomock mock of the IAM API calls made by the instance profile service.
+// Calling any other method of the IAM API panics.
+type mockIAMAPI struct {
+	iamiface.IAMAPI
+	ctrl     *gomock.Controller
+	recorder *mockIAMAPIMockRecorder
+}
+
+type mockIAMAPIMockRecorder struct {
+	mock *mockIAMAPI
+}
+
+func newMockIAMAPI(ctrl *gomock.Controller) *mockIAMAPI {
+	mock := &mockIAMAPI{ctrl: ctrl}
+	mock.recorder = &mockIAMAPIMockRecorder{mock}
+	return mock
+}
+
+func (m *mockIAMAPI) EXPECT() *mockIAMAPIMockRecorder {
+	return m.recorder
+}
+
+func (m *mockIAMAPI) call(method string, arg0 interface{}) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, method, arg0)
+	ret1, _ := ret[1].(error)
+	return ret[0], ret1
+}
+
+func (mr *mockIAMAPIMockRecorder) record(method string, fn interface{}, arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, method, reflect.TypeOf(fn), arg0)
+}
+
+func (m *mockIAMAPI) AddRoleToInstanceProfile(arg0 *iam.AddRoleToInstanceProfileInput) (*iam.AddRoleToInstanceProfileOutput, error) {
+	ret0, err := m.call("AddRoleToInstanceProfile", arg0)
+	out, _ := ret0.(*iam.AddRoleToInstanceProfileOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) AddRoleToInstanceProfile(arg0 interface{}) *gomock.Call {
+	return mr.record("AddRoleToInstanceProfile", (*mockIAMAPI)(nil).AddRoleToInstanceProfile, arg0)
+}
+
+func (m *mockIAMAPI) AttachRolePolicy(arg0 *iam.AttachRolePolicyInput) (*iam.AttachRolePolicyOutput, error) {
+	ret0, err := m.call("AttachRolePolicy", arg0)
+	out, _ := ret0.(*iam.AttachRolePolicyOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) AttachRolePolicy(arg0 interface{}) *gomock.Call {
+	return mr.record("AttachRolePolicy", (*mockIAMAPI)(nil).AttachRolePolicy, arg0)
+}
+
+func (m *mockIAMAPI) CreateInstanceProfile(arg0 *iam.CreateInstanceProfileInput) (*iam.CreateInstanceProfileOutput, error) {
+	ret0, err := m.call("CreateInstanceProfile", arg0)
+	out, _ := ret0.(*iam.CreateInstanceProfileOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) CreateInstanceProfile(arg0 interface{}) *gomock.Call {
+	return mr.record("CreateInstanceProfile", (*mockIAMAPI)(nil).CreateInstanceProfile, arg0)
+}
+
+func (m *mockIAMAPI) CreateRole(arg0 *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+	ret0, err := m.call("CreateRole", arg0)
+	out, _ := ret0.(*iam.CreateRoleOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) CreateRole(arg0 interface{}) *gomock.Call {
+	return mr.record("CreateRole", (*mockIAMAPI)(nil).CreateRole, arg0)
+}
+
+func (m *mockIAMAPI) DeleteRole(arg0 *iam.DeleteRoleInput) (*iam.DeleteRoleOutput, error) {
+	ret0, err := m.call("DeleteRole", arg0)
+	out, _ := ret0.(*iam.DeleteRoleOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) DeleteRole(arg0 interface{}) *gomock.Call {
+	return mr.record("DeleteRole", (*mockIAMAPI)(nil).DeleteRole, arg0)
+}
+
+func (m *mockIAMAPI) DeleteRolePolicy(arg0 *iam.DeleteRolePolicyInput) (*iam.DeleteRolePolicyOutput, error) {
+	ret0, err := m.call("DeleteRolePolicy", arg0)
+	out, _ := ret0.(*iam.DeleteRolePolicyOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) DeleteRolePolicy(arg0 interface{}) *gomock.Call {
+	return mr.record("DeleteRolePolicy", (*mockIAMAPI)(nil).DeleteRolePolicy, arg0)
+}
+
+func (m *mockIAMAPI) GetInstanceProfile(arg0 *iam.GetInstanceProfileInput) (*iam.GetInstanceProfileOutput, error) {
+	ret0, err := m.call("GetInstanceProfile", arg0)
+	out, _ := ret0.(*iam.GetInstanceProfileOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) GetInstanceProfile(arg0 interface{}) *gomock.Call {
+	return mr.record("GetInstanceProfile", (*mockIAMAPI)(nil).GetInstanceProfile, arg0)
+}
+
+func (m *mockIAMAPI) GetRole(arg0 *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	ret0, err := m.call("GetRole", arg0)
+	out, _ := ret0.(*iam.GetRoleOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) GetRole(arg0 interface{}) *gomock.Call {
+	return mr.record("GetRole", (*mockIAMAPI)(nil).GetRole, arg0)
+}
+
+func (m *mockIAMAPI) ListAttachedRolePolicies(arg0 *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error) {
+	ret0, err := m.call("ListAttachedRolePolicies", arg0)
+	out, _ := ret0.(*iam.ListAttachedRolePoliciesOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) ListAttachedRolePolicies(arg0 interface{}) *gomock.Call {
+	return mr.record("ListAttachedRolePolicies", (*mockIAMAPI)(nil).ListAttachedRolePolicies, arg0)
+}
+
+func (m *mockIAMAPI) PutRolePolicy(arg0 *iam.PutRolePolicyInput) (*iam.PutRolePolicyOutput, error) {
+	ret0, err := m.call("PutRolePolicy", arg0)
+	out, _ := ret0.(*iam.PutRolePolicyOutput)
+	return out, err
+}
+
+func (mr *mockIAMAPIMockRecorder) PutRolePolicy(arg0 interface{}) *gomock.Call {
+	return mr.record("PutRolePolicy", (*mockIAMAPI)(nil).PutRolePolicy, arg0)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+)
+
+// ControlPlaneCloudProviderPolicy returns the policy the AWS cloud provider needs on control plane instances.
+// From https://github.com/kubernetes/cloud-provider-aws
+func ControlPlaneCloudProviderPolicy() *infrav1.PolicyDocument {
+	return &infrav1.PolicyDocument{
+		Version: infrav1.CurrentVersion,
+		Statement: []infrav1.StatementEntry{
+			{
+				Effect:   infrav1.EffectAllow,
+				Resource: infrav1.Resources{infrav1.Any},
+				Action: infrav1.Actions{
+					"autoscaling:DescribeAutoScalingGroups",
+					"autoscaling:DescribeLaunchConfigurations",
+					"autoscaling:DescribeTags",
+					"ec2:DescribeInstances",
+					"ec2:DescribeImages",
+					"ec2:DescribeRegions",
+					"ec2:DescribeRouteTables",
+					"ec2:DescribeSecurityGroups",
+					"ec2:DescribeSubnets",
+					"ec2:DescribeVolumes",
+					"ec2:CreateSecurityGroup",
+					"ec2:CreateTags",
+					"ec2:CreateVolume",
+					"ec2:ModifyInstanceAttribute",
+					"ec2:ModifyVolume",
+					"ec2:AttachVolume",
+					"ec2:AuthorizeSecurityGroupIngress",
+					"ec2:CreateRoute",
+					"ec2:DeleteRoute",
+					"ec2:DeleteSecurityGroup",
+					"ec2:DeleteVolume",
+					"ec2:DetachVolume",
+					"ec2:RevokeSecurityGroupIngress",
+					"ec2:DescribeVpcs",
+					"elasticloadbalancing:AddTags",
+					"elasticloadbalancing:AttachLoadBalancerToSubnets",
+					"elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+					"elasticloadbalancing:CreateLoadBalancer",
+					"elasticloadbalancing:CreateLoadBalancerPolicy",
+					"elasticloadbalancing:CreateLoadBalancerListeners",
+					"elasticloadbalancing:ConfigureHealthCheck",
+					"elasticloadbalancing:DeleteLoadBalancer",
+					"elasticloadbalancing:DeleteLoadBalancerListeners",
+					"elasticloadbalancing:DescribeLoadBalancers",
+					"elasticloadbalancing:DescribeLoadBalancerAttributes",
+					"elasticloadbalancing:DetachLoadBalancerFromSubnets",
+					"elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+					"elasticloadbalancing:ModifyLoadBalancerAttributes",
+					"elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+					"elasticloadbalancing:SetLoadBalancerPoliciesForBackendServer",
+					"elasticloadbalancing:AddTags",
+					"elasticloadbalancing:CreateListener",
+					"elasticloadbalancing:CreateTargetGroup",
+					"elasticloadbalancing:DeleteListener",
+					"elasticloadbalancing:DeleteTargetGroup",
+					"elasticloadbalancing:DescribeListeners",
+					"elasticloadbalancing:DescribeLoadBalancerPolicies",
+					"elasticloadbalancing:DescribeTargetGroups",
+					"elasticloadbalancing:DescribeTargetHealth",
+					"elasticloadbalancing:ModifyListener",
+					"elasticloadbalancing:ModifyTargetGroup",
+					"elasticloadbalancing:RegisterTargets",
+					"elasticloadbalancing:SetLoadBalancerPoliciesOfListener",
+					"iam:CreateServiceLinkedRole",
+					"kms:DescribeKey",
+				},
+			},
+		},
+	}
+}
+
+// NodeCloudProviderPolicy returns the policy the AWS cloud provider needs on all instances.
+// From https://github.com/kubernetes/cloud-provider-aws
+func NodeCloudProviderPolicy() *infrav1.PolicyDocument {
+	return &infrav1.PolicyDocument{
+		Version: infrav1.CurrentVersion,
+		Statement: []infrav1.StatementEntry{
+			{
+				Effect:   infrav1.EffectAllow,
+				Resource: infrav1.Resources{infrav1.Any},
+				Action: infrav1.Actions{
+					"ec2:DescribeInstances",
+					"ec2:DescribeRegions",
+					"ecr:GetAuthorizationToken",
+					"ecr:BatchCheckLayerAvailability",
+					"ecr:GetDownloadUrlForLayer",
+					"ecr:GetRepositoryPolicy",
+					"ecr:DescribeRepositories",
+					"ecr:ListImages",
+					"ecr:BatchGetImage",
+				},
+			},
+		},
+	}
+}
+
+// ControlPlaneInstanceProfilePolicy returns the policy granted to control plane instances: the one granted to
+// nodes, along with the control plane policy of the AWS cloud provider.
+func ControlPlaneInstanceProfilePolicy(secureSecretsBackends ...infrav1.SecretBackend) *infrav1.PolicyDocument {
+	policyDocument := ControlPlaneCloudProviderPolicy()
+	policyDocument.Statement = append(policyDocument.Statement, NodeInstanceProfilePolicy(secureSecretsBackends...).Statement...)
+	return policyDocument
+}
+
+// NodeInstanceProfilePolicy returns the policy granted to node instances, letting them read their bootstrap
+// data from the given secret backends and register with Systems Manager.
+func NodeInstanceProfilePolicy(secureSecretsBackends ...infrav1.SecretBackend) *infrav1.PolicyDocument {
+	policyDocument := NodeCloudProviderPolicy()
+	for _, secureSecretsBackend := range secureSecretsBackends {
+		if statement, ok := SecretBackendPolicy(secureSecretsBackend); ok {
+			policyDocument.Statement = append(policyDocument.Statement, statement)
+		}
+	}
+	policyDocument.Statement = append(policyDocument.Statement, SessionManagerPolicy())
+	return policyDocument
+}
+
+// SecretBackendPolicy returns the statement letting instances read and delete their bootstrap data from the
+// given secret backend, and false if the secret backend is unknown.
+func SecretBackendPolicy(secureSecretsBackend infrav1.SecretBackend) (infrav1.StatementEntry, bool) {
+	switch secureSecretsBackend {
+	case infrav1.SecretBackendSecretsManager:
+		return infrav1.StatementEntry{
+			Effect: infrav1.EffectAllow,
+			Resource: infrav1.Resources{
+				"arn:*:secretsmanager:*:*:secret:aws.cluster.x-k8s.io/*",
+			},
+			Action: infrav1.Actions{
+				"secretsmanager:DeleteSecret",
+				"secretsmanager:GetSecretValue",
+			},
+		}, true
+	case infrav1.SecretBackendSSMParameterStore:
+		return infrav1.StatementEntry{
+			Effect: infrav1.EffectAllow,
+			Resource: infrav1.Resources{
+				"arn:*:ssm:*:*:parameter/cluster.x-k8s.io/*",
+			},
+			Action: infrav1.Actions{
+				"ssm:DeleteParameter",
+				"ssm:GetParameter",
+			},
+		}, true
+	}
+	return infrav1.StatementEntry{}, false
+}
+
+// SessionManagerPolicy returns the statement letting instances register with Systems Manager Session Manager.
+func SessionManagerPolicy() infrav1.StatementEntry {
+	return infrav1.StatementEntry{
+		Effect:   infrav1.EffectAllow,
+		Resource: infrav1.Resources{infrav1.Any},
+		Action: infrav1.Actions{
+			"ssm:UpdateInstanceInformation",
+			"ssmmessages:CreateControlChannel",
+			"ssmmessages:CreateDataChannel",
+			"ssmmessages:OpenControlChannel",
+			"ssmmessages:OpenDataChannel",
+			"s3:GetEncryptionConfiguration",
+		},
+	}
+}
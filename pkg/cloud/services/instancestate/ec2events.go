@@ -22,11 +22,19 @@ func (s Service) ReconcileEC2Events() error {
 		return err
 	}
 
-	return s.reconcileRules()
+	if err := s.reconcileRules(); err != nil {
+		return err
+	}
+
+	return s.reconcileSpotRule()
 }
 
 // DeleteEC2Events will delete a Service's EC2 events.
 func (s Service) DeleteEC2Events() error {
+	if err := s.deleteSpotRule(); err != nil {
+		return err
+	}
+
 	if err := s.deleteRules(); err != nil {
 		return err
 	}
@@ -96,6 +96,49 @@ func (s *Service) createPolicyForRule(input *createPolicyForRuleInput) error {
 	return errors.Wrap(err, "unable to update queue attributes")
 }
 
+// addRuleToQueuePolicy adds a statement authorizing the rule to send messages to the queue,
+// unless the queue policy already has one.
+func (s *Service) addRuleToQueuePolicy(input *addRuleToQueuePolicyInput) error {
+	sid := fmt.Sprintf("CAPAEvents_%s_%s", input.RuleName, GenerateQueueName(s.scope.Name()))
+
+	policy := v1alpha4.PolicyDocument{
+		Version: v1alpha4.CurrentVersion,
+		ID:      input.QueueArn,
+	}
+	if input.Policy != "" {
+		if err := json.Unmarshal([]byte(input.Policy), &policy); err != nil {
+			return errors.Wrap(err, "unable to JSON unmarshal queue policy")
+		}
+	}
+	for _, statement := range policy.Statement {
+		if statement.Sid == sid {
+			return nil
+		}
+	}
+
+	policy.Statement = append(policy.Statement, v1alpha4.StatementEntry{
+		Sid:       sid,
+		Effect:    v1alpha4.EffectAllow,
+		Principal: v1alpha4.Principals{v1alpha4.PrincipalService: v1alpha4.PrincipalID{"events.amazonaws.com"}},
+		Action:    v1alpha4.Actions{"sqs:SendMessage"},
+		Resource:  v1alpha4.Resources{input.QueueArn},
+		Condition: v1alpha4.Conditions{
+			"ArnEquals": map[string]string{"aws:SourceArn": input.RuleArn},
+		},
+	})
+	policyData, err := json.Marshal(policy)
+	if err != nil {
+		return errors.Wrap(err, "unable to JSON marshal policy")
+	}
+
+	_, err = s.SQSClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl:   aws.String(input.QueueURL),
+		Attributes: aws.StringMap(map[string]string{sqs.QueueAttributeNamePolicy: string(policyData)}),
+	})
+
+	return errors.Wrap(err, "unable to update queue attributes")
+}
+
 // GenerateQueueName will generate a queue name.
 func GenerateQueueName(clusterName string) string {
 	adjusted := strings.ReplaceAll(clusterName, ".", "-")
@@ -116,3 +159,11 @@ type createPolicyForRuleInput struct {
 	QueueURL string
 	RuleArn  string
 }
+
+type addRuleToQueuePolicyInput struct {
+	QueueArn string
+	QueueURL string
+	Policy   string
+	RuleName string
+	RuleArn  string
+}
@@ -25,12 +25,21 @@ import (
 	"github.com/aws/aws-sdk-go/service/eventbridge"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
 )
 
-// Ec2StateChangeNotification defines the EC2 instance's state change notification.
-const Ec2StateChangeNotification = "EC2 Instance State-change Notification"
+const (
+	// Ec2StateChangeNotification defines the EC2 instance's state change notification.
+	Ec2StateChangeNotification = "EC2 Instance State-change Notification"
+
+	// Ec2SpotInterruptionWarning defines the notification sent two minutes before a spot instance is interrupted.
+	Ec2SpotInterruptionWarning = "EC2 Spot Instance Interruption Warning"
+
+	// Ec2RebalanceRecommendation defines the notification sent when a spot instance is at elevated risk of interruption.
+	Ec2RebalanceRecommendation = "EC2 Instance Rebalance Recommendation"
+)
 
 // reconcileRules creates rules and attaches the queue as a target.
 func (s Service) reconcileRules() error {
@@ -238,10 +247,140 @@ func (s Service) RemoveInstanceFromEventPattern(instanceID string) {
 	}
 }
 
+// reconcileSpotRule creates the rule forwarding spot interruption warnings, and rebalance recommendations if
+// enabled, to the queue, and authorizes it to send messages to the queue.
+func (s Service) reconcileSpotRule() error {
+	// Spot notifications don't carry an instance state, so they can't be matched by the EC2 rule. Instances
+	// can't be filtered on either, as the instance IDs aren't known before the instances are launched;
+	// notifications about instances outside of the cluster are ignored when they are received.
+	detailTypes := []string{Ec2SpotInterruptionWarning}
+	if s.RebalanceRecommendations {
+		detailTypes = append(detailTypes, Ec2RebalanceRecommendation)
+	}
+	data, _ := json.Marshal(eventPattern{
+		Source:     []string{"aws.ec2"},
+		DetailType: detailTypes,
+	})
+
+	ruleResp, err := s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
+		Name: aws.String(s.getSpotRuleName()),
+	})
+	switch {
+	case err == nil && !spotRuleMatches(ruleResp.EventPattern, detailTypes):
+		// Rebalance recommendations were enabled or disabled since the rule was created.
+		if _, err := s.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
+			Name:         aws.String(s.getSpotRuleName()),
+			EventPattern: aws.String(string(data)),
+			State:        aws.String(eventbridge.RuleStateEnabled),
+		}); err != nil {
+			return errors.Wrap(err, "unable to update spot rule")
+		}
+	case err != nil:
+		if !resourceNotFoundError(err) {
+			return errors.Wrapf(err, "unable to describe rule %s", s.getSpotRuleName())
+		}
+
+		if _, err := s.EventBridgeClient.PutRule(&eventbridge.PutRuleInput{
+			Name:         aws.String(s.getSpotRuleName()),
+			EventPattern: aws.String(string(data)),
+			State:        aws.String(eventbridge.RuleStateEnabled),
+		}); err != nil {
+			return errors.Wrap(err, "unable to create spot rule")
+		}
+
+		ruleResp, err = s.EventBridgeClient.DescribeRule(&eventbridge.DescribeRuleInput{
+			Name: aws.String(s.getSpotRuleName()),
+		})
+		if err != nil {
+			return errors.Wrapf(err, "unable to describe new rule %s", s.getSpotRuleName())
+		}
+	}
+
+	queueURLResp, err := s.SQSClient.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName: aws.String(GenerateQueueName(s.scope.Name())),
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to get queue URL")
+	}
+	queueAttrs, err := s.SQSClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn, sqs.QueueAttributeNamePolicy}),
+		QueueUrl:       queueURLResp.QueueUrl,
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to get queue attributes")
+	}
+
+	targetsResp, err := s.EventBridgeClient.ListTargetsByRule(&eventbridge.ListTargetsByRuleInput{
+		Rule: aws.String(s.getSpotRuleName()),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list targets for rule %s", s.getSpotRuleName())
+	}
+
+	targetFound := false
+	for _, target := range targetsResp.Targets {
+		if *target.Id == GenerateQueueName(s.scope.Name()) && *target.Arn == *queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn] {
+			targetFound = true
+		}
+	}
+
+	if !targetFound {
+		if _, err := s.EventBridgeClient.PutTargets(&eventbridge.PutTargetsInput{
+			Rule: ruleResp.Name,
+			Targets: []*eventbridge.Target{{
+				Arn: queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn],
+				Id:  aws.String(GenerateQueueName(s.scope.Name())),
+			}},
+		}); err != nil {
+			return errors.Wrapf(err, "unable to add SQS target %s to rule %s", GenerateQueueName(s.scope.Name()), s.getSpotRuleName())
+		}
+	}
+
+	return s.addRuleToQueuePolicy(&addRuleToQueuePolicyInput{
+		QueueArn: *queueAttrs.Attributes[sqs.QueueAttributeNameQueueArn],
+		QueueURL: *queueURLResp.QueueUrl,
+		Policy:   aws.StringValue(queueAttrs.Attributes[sqs.QueueAttributeNamePolicy]),
+		RuleName: s.getSpotRuleName(),
+		RuleArn:  *ruleResp.Arn,
+	})
+}
+
+// spotRuleMatches returns true if the event pattern of the spot rule forwards exactly the given notifications.
+func spotRuleMatches(pattern *string, detailTypes []string) bool {
+	e := eventPattern{}
+	if err := json.Unmarshal([]byte(aws.StringValue(pattern)), &e); err != nil {
+		return false
+	}
+	return sets.NewString(e.DetailType...).Equal(sets.NewString(detailTypes...))
+}
+
+func (s Service) deleteSpotRule() error {
+	_, err := s.EventBridgeClient.RemoveTargets(&eventbridge.RemoveTargetsInput{
+		Rule: aws.String(s.getSpotRuleName()),
+		Ids:  aws.StringSlice([]string{GenerateQueueName(s.scope.Name())}),
+	})
+	if err != nil && !resourceNotFoundError(err) {
+		return errors.Wrapf(err, "unable to remove target %s for rule %s", GenerateQueueName(s.scope.Name()), s.getSpotRuleName())
+	}
+	_, err = s.EventBridgeClient.DeleteRule(&eventbridge.DeleteRuleInput{
+		Name: aws.String(s.getSpotRuleName()),
+	})
+
+	if err != nil && resourceNotFoundError(err) {
+		return nil
+	}
+
+	return err
+}
+
 func (s Service) getEC2RuleName() string {
 	return fmt.Sprintf("%s-ec2-rule", s.scope.Name())
 }
 
+func (s Service) getSpotRuleName() string {
+	return fmt.Sprintf("%s-spot-rule", s.scope.Name())
+}
+
 func resourceNotFoundError(err error) bool {
 	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == eventbridge.ErrCodeResourceNotFoundException {
 		return true
@@ -158,6 +158,159 @@ func TestReconcileRules(t *testing.T) {
 	}
 }
 
+func TestReconcileSpotRule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	ruleName := "test-cluster-spot-rule"
+	interruptionPattern, _ := json.Marshal(&eventPattern{
+		Source:     []string{"aws.ec2"},
+		DetailType: []string{Ec2SpotInterruptionWarning},
+	})
+	rebalancePattern, _ := json.Marshal(&eventPattern{
+		Source:     []string{"aws.ec2"},
+		DetailType: []string{Ec2SpotInterruptionWarning, Ec2RebalanceRecommendation},
+	})
+	existingTargetExpect := func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+		m.ListTargetsByRule(gomock.AssignableToTypeOf(&eventbridge.ListTargetsByRuleInput{})).Return(&eventbridge.ListTargetsByRuleOutput{
+			Targets: []*eventbridge.Target{{
+				Id:  aws.String("test-cluster-queue"),
+				Arn: aws.String("test-cluster-queue-arn"),
+			}},
+		}, nil)
+	}
+	existingQueuePolicyExpect := func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+		m.GetQueueUrl(gomock.AssignableToTypeOf(&sqs.GetQueueUrlInput{})).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("test-cluster-queue-url")}, nil)
+		attrs := make(map[string]string)
+		attrs[sqs.QueueAttributeNameQueueArn] = "test-cluster-queue-arn"
+		attrs[sqs.QueueAttributeNamePolicy] = `{"Version":"2012-10-17","Statement":[{"Sid":"CAPAEvents_test-cluster-spot-rule_test-cluster-queue","Effect":"Allow","Action":["sqs:SendMessage"]}]}`
+		m.GetQueueAttributes(gomock.AssignableToTypeOf(&sqs.GetQueueAttributesInput{})).Return(&sqs.GetQueueAttributesOutput{Attributes: aws.StringMap(attrs)}, nil)
+	}
+
+	testCases := []struct {
+		name                     string
+		rebalanceRecommendations bool
+		eventBridgeExpect        func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder)
+		sqsExpect                func(m *mock_sqsiface.MockSQSAPIMockRecorder)
+		expectErr                bool
+	}{
+		{
+			name: "creates missing rule and target, and authorizes the rule on the queue",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
+					Name: aws.String(ruleName),
+				})).Return(nil, awserr.New(eventbridge.ErrCodeResourceNotFoundException, "", nil))
+				m.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
+					Name:         aws.String(ruleName),
+					State:        aws.String(eventbridge.RuleStateEnabled),
+					EventPattern: aws.String(string(interruptionPattern)),
+				})).Return(nil, nil)
+				m.DescribeRule(gomock.Eq(&eventbridge.DescribeRuleInput{
+					Name: aws.String(ruleName),
+				})).Return(&eventbridge.DescribeRuleOutput{Name: aws.String(ruleName), Arn: aws.String("spot-rule-arn")}, nil)
+				m.ListTargetsByRule(gomock.Eq(&eventbridge.ListTargetsByRuleInput{
+					Rule: aws.String(ruleName),
+				})).Return(&eventbridge.ListTargetsByRuleOutput{}, nil)
+				m.PutTargets(gomock.Eq(&eventbridge.PutTargetsInput{
+					Rule: aws.String(ruleName),
+					Targets: []*eventbridge.Target{{
+						Arn: aws.String("test-cluster-queue-arn"),
+						Id:  aws.String("test-cluster-queue"),
+					}},
+				})).Return(nil, nil)
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {
+				m.GetQueueUrl(gomock.AssignableToTypeOf(&sqs.GetQueueUrlInput{})).Return(&sqs.GetQueueUrlOutput{QueueUrl: aws.String("test-cluster-queue-url")}, nil)
+				attrs := make(map[string]string)
+				attrs[sqs.QueueAttributeNameQueueArn] = "test-cluster-queue-arn"
+				attrs[sqs.QueueAttributeNamePolicy] = expectedPolicyJSON
+				m.GetQueueAttributes(gomock.AssignableToTypeOf(&sqs.GetQueueAttributesInput{})).Return(&sqs.GetQueueAttributesOutput{Attributes: aws.StringMap(attrs)}, nil)
+				m.SetQueueAttributes(gomock.AssignableToTypeOf(&sqs.SetQueueAttributesInput{})).
+					Do(func(input *sqs.SetQueueAttributesInput) {
+						policy := infrav1.PolicyDocument{}
+						_ = json.Unmarshal([]byte(aws.StringValue(input.Attributes[sqs.QueueAttributeNamePolicy])), &policy)
+						if len(policy.Statement) != 2 || policy.Statement[1].Sid != "CAPAEvents_test-cluster-spot-rule_test-cluster-queue" {
+							t.Errorf("unexpected queue policy %s", aws.StringValue(input.Attributes[sqs.QueueAttributeNamePolicy]))
+						}
+					}).Return(nil, nil)
+			},
+			expectErr: false,
+		},
+		{
+			name: "skips creating target and policy statement if they already exist",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(gomock.AssignableToTypeOf(&eventbridge.DescribeRuleInput{})).
+					Return(&eventbridge.DescribeRuleOutput{Name: aws.String(ruleName), Arn: aws.String("spot-rule-arn"), EventPattern: aws.String(string(interruptionPattern))}, nil)
+				existingTargetExpect(m)
+			},
+			sqsExpect: existingQueuePolicyExpect,
+			expectErr: false,
+		},
+		{
+			name:                     "adds rebalance recommendations to an existing rule once enabled",
+			rebalanceRecommendations: true,
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(gomock.AssignableToTypeOf(&eventbridge.DescribeRuleInput{})).
+					Return(&eventbridge.DescribeRuleOutput{Name: aws.String(ruleName), Arn: aws.String("spot-rule-arn"), EventPattern: aws.String(string(interruptionPattern))}, nil)
+				m.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
+					Name:         aws.String(ruleName),
+					State:        aws.String(eventbridge.RuleStateEnabled),
+					EventPattern: aws.String(string(rebalancePattern)),
+				})).Return(nil, nil)
+				existingTargetExpect(m)
+			},
+			sqsExpect: existingQueuePolicyExpect,
+			expectErr: false,
+		},
+		{
+			name: "removes rebalance recommendations from an existing rule once disabled",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(gomock.AssignableToTypeOf(&eventbridge.DescribeRuleInput{})).
+					Return(&eventbridge.DescribeRuleOutput{Name: aws.String(ruleName), Arn: aws.String("spot-rule-arn"), EventPattern: aws.String(string(rebalancePattern))}, nil)
+				m.PutRule(gomock.Eq(&eventbridge.PutRuleInput{
+					Name:         aws.String(ruleName),
+					State:        aws.String(eventbridge.RuleStateEnabled),
+					EventPattern: aws.String(string(interruptionPattern)),
+				})).Return(nil, nil)
+				existingTargetExpect(m)
+			},
+			sqsExpect: existingQueuePolicyExpect,
+			expectErr: false,
+		},
+		{
+			name: "returns error if DescribeRule runs into unexpected error",
+			eventBridgeExpect: func(m *mock_eventbridgeiface.MockEventBridgeAPIMockRecorder) {
+				m.DescribeRule(gomock.AssignableToTypeOf(&eventbridge.DescribeRuleInput{})).Return(nil, errors.New("some error"))
+			},
+			sqsExpect: func(m *mock_sqsiface.MockSQSAPIMockRecorder) {},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			eventbridgeMock := mock_eventbridgeiface.NewMockEventBridgeAPI(mockCtrl)
+			sqsMock := mock_sqsiface.NewMockSQSAPI(mockCtrl)
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).To(Not(HaveOccurred()))
+			tc.sqsExpect(sqsMock.EXPECT())
+			tc.eventBridgeExpect(eventbridgeMock.EXPECT())
+
+			s := NewService(clusterScope)
+			s.EventBridgeClient = eventbridgeMock
+			s.SQSClient = sqsMock
+			s.RebalanceRecommendations = tc.rebalanceRecommendations
+
+			err = s.reconcileSpotRule()
+			if tc.expectErr {
+				g.Expect(err).NotTo(BeNil())
+			} else {
+				g.Expect(err).To(BeNil())
+			}
+		})
+	}
+}
+
 func TestDeleteRules(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -27,6 +27,9 @@ type Service struct {
 	scope             scope.EC2Scope
 	EventBridgeClient eventbridgeiface.EventBridgeAPI
 	SQSClient         sqsiface.SQSAPI
+
+	// RebalanceRecommendations forwards rebalance recommendations to the queue, next to spot interruption warnings.
+	RebalanceRecommendations bool
 }
 
 // NewService returns a new service given the ec2 api client.
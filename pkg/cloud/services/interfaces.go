@@ -27,6 +27,8 @@ const (
 	TemporaryResourceID = "temporary-resource-id"
 	// AnyIPv4CidrBlock is the CIDR block to match all IPv4 addresses.
 	AnyIPv4CidrBlock = "0.0.0.0/0"
+	// AnyIPv6CidrBlock is the CIDR block to match all IPv6 addresses.
+	AnyIPv6CidrBlock = "::/0"
 )
 
 // ASGInterface encapsulates the methods exposed to the machinepool
@@ -47,14 +49,20 @@ type ASGInterface interface {
 type EC2MachineInterface interface {
 	InstanceIfExists(id *string) (*infrav1.Instance, error)
 	TerminateInstance(id string) error
+	StartInstance(id string) error
 	CreateInstance(scope *scope.MachineScope, userData []byte) (*infrav1.Instance, error)
+	DiscoverInstanceAMI(scope *scope.MachineScope) (string, error)
 	GetRunningInstanceByTags(scope *scope.MachineScope) (*infrav1.Instance, error)
+	AssociateControlPlaneElasticIP(instance *infrav1.Instance) error
 
 	GetCoreSecurityGroups(machine *scope.MachineScope) ([]string, error)
 	GetInstanceSecurityGroups(instanceID string) (map[string][]string, error)
 	GetFilteredSecurityGroupID(securityGroup infrav1.AWSResourceReference) (string, error)
 	UpdateInstanceSecurityGroups(id string, securityGroups []string) error
 	UpdateResourceTags(resourceID *string, create, remove map[string]string) error
+	DesiredInstanceTags(scope *scope.MachineScope) infrav1.Tags
+	GetLaunchResourceTags(instanceID string, nonRootVolumes []infrav1.Volume) (volumes map[string]infrav1.Tags, networkInterfaces map[string]infrav1.Tags, err error)
+	InstanceSystemStatusImpaired(instanceID string) (bool, error)
 
 	TerminateInstanceAndWait(instanceID string) error
 	DetachSecurityGroupsFromNetworkInterface(groups []string, interfaceID string) error
@@ -52,6 +52,20 @@ func (m *MockEC2MachineInterface) EXPECT() *MockEC2MachineInterfaceMockRecorder
 	return m.recorder
 }
 
+// AssociateControlPlaneElasticIP mocks base method.
+func (m *MockEC2MachineInterface) AssociateControlPlaneElasticIP(arg0 *v1alpha4.Instance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateControlPlaneElasticIP", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssociateControlPlaneElasticIP indicates an expected call of AssociateControlPlaneElasticIP.
+func (mr *MockEC2MachineInterfaceMockRecorder) AssociateControlPlaneElasticIP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateControlPlaneElasticIP", reflect.TypeOf((*MockEC2MachineInterface)(nil).AssociateControlPlaneElasticIP), arg0)
+}
+
 // CreateInstance mocks base method.
 func (m *MockEC2MachineInterface) CreateInstance(arg0 *scope.MachineScope, arg1 []byte) (*v1alpha4.Instance, error) {
 	m.ctrl.T.Helper()
@@ -110,6 +124,20 @@ func (mr *MockEC2MachineInterfaceMockRecorder) DeleteLaunchTemplate(arg0 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLaunchTemplate", reflect.TypeOf((*MockEC2MachineInterface)(nil).DeleteLaunchTemplate), arg0)
 }
 
+// DesiredInstanceTags mocks base method.
+func (m *MockEC2MachineInterface) DesiredInstanceTags(arg0 *scope.MachineScope) v1alpha4.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DesiredInstanceTags", arg0)
+	ret0, _ := ret[0].(v1alpha4.Tags)
+	return ret0
+}
+
+// DesiredInstanceTags indicates an expected call of DesiredInstanceTags.
+func (mr *MockEC2MachineInterfaceMockRecorder) DesiredInstanceTags(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DesiredInstanceTags", reflect.TypeOf((*MockEC2MachineInterface)(nil).DesiredInstanceTags), arg0)
+}
+
 // DetachSecurityGroupsFromNetworkInterface mocks base method.
 func (m *MockEC2MachineInterface) DetachSecurityGroupsFromNetworkInterface(arg0 []string, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -124,6 +152,21 @@ func (mr *MockEC2MachineInterfaceMockRecorder) DetachSecurityGroupsFromNetworkIn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachSecurityGroupsFromNetworkInterface", reflect.TypeOf((*MockEC2MachineInterface)(nil).DetachSecurityGroupsFromNetworkInterface), arg0, arg1)
 }
 
+// DiscoverInstanceAMI mocks base method.
+func (m *MockEC2MachineInterface) DiscoverInstanceAMI(arg0 *scope.MachineScope) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscoverInstanceAMI", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiscoverInstanceAMI indicates an expected call of DiscoverInstanceAMI.
+func (mr *MockEC2MachineInterfaceMockRecorder) DiscoverInstanceAMI(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscoverInstanceAMI", reflect.TypeOf((*MockEC2MachineInterface)(nil).DiscoverInstanceAMI), arg0)
+}
+
 // DiscoverLaunchTemplateAMI mocks base method.
 func (m *MockEC2MachineInterface) DiscoverLaunchTemplateAMI(arg0 *scope.MachinePoolScope) (*string, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +227,22 @@ func (mr *MockEC2MachineInterfaceMockRecorder) GetInstanceSecurityGroups(arg0 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceSecurityGroups", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetInstanceSecurityGroups), arg0)
 }
 
+// GetLaunchResourceTags mocks base method.
+func (m *MockEC2MachineInterface) GetLaunchResourceTags(arg0 string, arg1 []v1alpha4.Volume) (map[string]v1alpha4.Tags, map[string]v1alpha4.Tags, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLaunchResourceTags", arg0, arg1)
+	ret0, _ := ret[0].(map[string]v1alpha4.Tags)
+	ret1, _ := ret[1].(map[string]v1alpha4.Tags)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetLaunchResourceTags indicates an expected call of GetLaunchResourceTags.
+func (mr *MockEC2MachineInterfaceMockRecorder) GetLaunchResourceTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLaunchResourceTags", reflect.TypeOf((*MockEC2MachineInterface)(nil).GetLaunchResourceTags), arg0, arg1)
+}
+
 // GetLaunchTemplate mocks base method.
 func (m *MockEC2MachineInterface) GetLaunchTemplate(arg0 string) (*v1alpha40.AWSLaunchTemplate, string, error) {
 	m.ctrl.T.Helper()
@@ -245,6 +304,21 @@ func (mr *MockEC2MachineInterfaceMockRecorder) InstanceIfExists(arg0 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceIfExists", reflect.TypeOf((*MockEC2MachineInterface)(nil).InstanceIfExists), arg0)
 }
 
+// InstanceSystemStatusImpaired mocks base method.
+func (m *MockEC2MachineInterface) InstanceSystemStatusImpaired(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceSystemStatusImpaired", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstanceSystemStatusImpaired indicates an expected call of InstanceSystemStatusImpaired.
+func (mr *MockEC2MachineInterfaceMockRecorder) InstanceSystemStatusImpaired(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceSystemStatusImpaired", reflect.TypeOf((*MockEC2MachineInterface)(nil).InstanceSystemStatusImpaired), arg0)
+}
+
 // LaunchTemplateNeedsUpdate mocks base method.
 func (m *MockEC2MachineInterface) LaunchTemplateNeedsUpdate(arg0 *scope.MachinePoolScope, arg1, arg2 *v1alpha40.AWSLaunchTemplate) (bool, error) {
 	m.ctrl.T.Helper()
@@ -274,6 +348,20 @@ func (mr *MockEC2MachineInterfaceMockRecorder) PruneLaunchTemplateVersions(arg0
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneLaunchTemplateVersions", reflect.TypeOf((*MockEC2MachineInterface)(nil).PruneLaunchTemplateVersions), arg0)
 }
 
+// StartInstance mocks base method.
+func (m *MockEC2MachineInterface) StartInstance(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartInstance", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartInstance indicates an expected call of StartInstance.
+func (mr *MockEC2MachineInterfaceMockRecorder) StartInstance(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartInstance", reflect.TypeOf((*MockEC2MachineInterface)(nil).StartInstance), arg0)
+}
+
 // TerminateInstance mocks base method.
 func (m *MockEC2MachineInterface) TerminateInstance(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,235 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	dhcpOptionDomainName        = "domain-name"
+	dhcpOptionDomainNameServers = "domain-name-servers"
+)
+
+func (s *Service) reconcileDHCPOptions() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping DHCP options reconcile in unmanaged mode")
+		return nil
+	}
+
+	if s.scope.VPC().DHCPOptions == nil {
+		s.scope.V(4).Info("Skipping DHCP options reconcile, no DHCP options specified")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling DHCP options")
+
+	existing, err := s.describeDHCPOptions()
+	if err != nil {
+		return err
+	}
+
+	// DHCP options sets cannot be modified, a new one replaces the current one when the spec changes.
+	desired := s.getDHCPConfigurations()
+	var current *ec2.DhcpOptions
+	for _, options := range existing {
+		if reflect.DeepEqual(dhcpConfigurationsToMap(options.DhcpConfigurations), desired) {
+			current = options
+			break
+		}
+	}
+
+	if current == nil {
+		current, err = s.createDHCPOptions(desired)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.associateDHCPOptions(aws.StringValue(current.DhcpOptionsId)); err != nil {
+		return err
+	}
+
+	for _, options := range existing {
+		if options == current {
+			continue
+		}
+		if err := s.deleteDHCPOptionsSet(options); err != nil {
+			return err
+		}
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.DHCPOptionsReadyCondition)
+	return nil
+}
+
+// deleteDHCPOptions deletes the DHCP options sets of the cluster, which can only happen once the VPC is gone.
+func (s *Service) deleteDHCPOptions() error {
+	existing, err := s.describeDHCPOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, options := range existing {
+		if err := s.deleteDHCPOptionsSet(options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) describeDHCPOptions() ([]*ec2.DhcpOptions, error) {
+	out, err := s.EC2Client.DescribeDhcpOptions(&ec2.DescribeDhcpOptionsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.ClusterOwned(s.scope.Name()),
+		},
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeDHCPOptions", "Failed to describe DHCP options: %v", err)
+		return nil, errors.Wrap(err, "failed to describe DHCP options")
+	}
+
+	return out.DhcpOptions, nil
+}
+
+func (s *Service) createDHCPOptions(configurations map[string][]string) (*ec2.DhcpOptions, error) {
+	keys := make([]string, 0, len(configurations))
+	for key := range configurations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	input := &ec2.CreateDhcpOptionsInput{
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2.ResourceTypeDhcpOptions, s.getDHCPOptionsTagParams(services.TemporaryResourceID)),
+		},
+	}
+	for _, key := range keys {
+		input.DhcpConfigurations = append(input.DhcpConfigurations, &ec2.NewDhcpConfiguration{
+			Key:    aws.String(key),
+			Values: aws.StringSlice(configurations[key]),
+		})
+	}
+
+	out, err := s.EC2Client.CreateDhcpOptions(input)
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateDHCPOptions", "Failed to create managed DHCP options: %v", err)
+		return nil, errors.Wrap(err, "failed to create DHCP options")
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateDHCPOptions", "Created managed DHCP options %q", aws.StringValue(out.DhcpOptions.DhcpOptionsId))
+	return out.DhcpOptions, nil
+}
+
+func (s *Service) associateDHCPOptions(id string) error {
+	out, err := s.EC2Client.DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: []*string{aws.String(s.scope.VPC().ID)},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe vpc %q", s.scope.VPC().ID)
+	}
+	if len(out.Vpcs) == 0 {
+		return errors.Errorf("vpc %q not found", s.scope.VPC().ID)
+	}
+	if aws.StringValue(out.Vpcs[0].DhcpOptionsId) == id {
+		return nil
+	}
+
+	if _, err := s.EC2Client.AssociateDhcpOptions(&ec2.AssociateDhcpOptionsInput{
+		DhcpOptionsId: aws.String(id),
+		VpcId:         aws.String(s.scope.VPC().ID),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedAssociateDHCPOptions", "Failed to associate DHCP options %q with vpc %q: %v", id, s.scope.VPC().ID, err)
+		return errors.Wrapf(err, "failed to associate DHCP options %q with vpc %q", id, s.scope.VPC().ID)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulAssociateDHCPOptions", "Associated managed DHCP options %q with vpc %q", id, s.scope.VPC().ID)
+	return nil
+}
+
+func (s *Service) deleteDHCPOptionsSet(options *ec2.DhcpOptions) error {
+	if _, err := s.EC2Client.DeleteDhcpOptions(&ec2.DeleteDhcpOptionsInput{
+		DhcpOptionsId: options.DhcpOptionsId,
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteDHCPOptions", "Failed to delete managed DHCP options %q: %v", aws.StringValue(options.DhcpOptionsId), err)
+		return errors.Wrapf(err, "failed to delete DHCP options %q", aws.StringValue(options.DhcpOptionsId))
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteDHCPOptions", "Deleted managed DHCP options %q", aws.StringValue(options.DhcpOptionsId))
+	return nil
+}
+
+// getDHCPConfigurations returns the DHCP options of the spec, filling in the values of the
+// default DHCP options set of the region for the ones that are not set.
+func (s *Service) getDHCPConfigurations() map[string][]string {
+	options := s.scope.VPC().DHCPOptions
+
+	domainName := options.DomainName
+	if domainName == "" {
+		domainName = fmt.Sprintf("%s.compute.internal", s.scope.Region())
+		if s.scope.Region() == "us-east-1" {
+			domainName = "ec2.internal"
+		}
+	}
+
+	domainNameServers := options.DomainNameServers
+	if len(domainNameServers) == 0 {
+		domainNameServers = []string{infrav1.AmazonProvidedDNS}
+	}
+
+	return map[string][]string{
+		dhcpOptionDomainName:        {domainName},
+		dhcpOptionDomainNameServers: domainNameServers,
+	}
+}
+
+func dhcpConfigurationsToMap(configurations []*ec2.DhcpConfiguration) map[string][]string {
+	res := make(map[string][]string, len(configurations))
+	for _, c := range configurations {
+		for _, v := range c.Values {
+			res[aws.StringValue(c.Key)] = append(res[aws.StringValue(c.Key)], aws.StringValue(v.Value))
+		}
+	}
+	return res
+}
+
+func (s *Service) getDHCPOptionsTagParams(id string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-dhcp-options", s.scope.Name())
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.CommonRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
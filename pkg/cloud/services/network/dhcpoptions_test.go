@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+func TestReconcileDHCPOptions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	managedVPC := func(options *infrav1.DHCPOptions) *infrav1.NetworkSpec {
+		return &infrav1.NetworkSpec{
+			VPC: infrav1.VPCSpec{
+				ID: "vpc-dhcp",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+				DHCPOptions: options,
+			},
+		}
+	}
+
+	corpOptions := func(id string) *ec2.DhcpOptions {
+		return &ec2.DhcpOptions{
+			DhcpOptionsId: aws.String(id),
+			DhcpConfigurations: []*ec2.DhcpConfiguration{
+				{
+					Key:    aws.String("domain-name"),
+					Values: []*ec2.AttributeValue{{Value: aws.String("corp.example.com")}},
+				},
+				{
+					Key: aws.String("domain-name-servers"),
+					Values: []*ec2.AttributeValue{
+						{Value: aws.String("10.1.0.2")},
+						{Value: aws.String("10.1.0.3")},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		input  *infrav1.NetworkSpec
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name:  "no dhcp options, does nothing",
+			input: managedVPC(nil),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "unmanaged vpc, does nothing",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID:          "vpc-dhcp",
+					DHCPOptions: &infrav1.DHCPOptions{DomainName: "corp.example.com"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:  "no dhcp options set, creates one with the defaults of the region and associates it",
+			input: managedVPC(&infrav1.DHCPOptions{DomainNameServers: []string{"10.1.0.2"}}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(&ec2.DescribeDhcpOptionsOutput{}, nil)
+				m.CreateDhcpOptions(gomock.AssignableToTypeOf(&ec2.CreateDhcpOptionsInput{})).
+					DoAndReturn(func(input *ec2.CreateDhcpOptionsInput) (*ec2.CreateDhcpOptionsOutput, error) {
+						expected := []*ec2.NewDhcpConfiguration{
+							{Key: aws.String("domain-name"), Values: aws.StringSlice([]string{"ec2.internal"})},
+							{Key: aws.String("domain-name-servers"), Values: aws.StringSlice([]string{"10.1.0.2"})},
+						}
+						if !reflect.DeepEqual(input.DhcpConfigurations, expected) {
+							t.Fatalf("expected DHCP configurations %v, got %v", expected, input.DhcpConfigurations)
+						}
+						return &ec2.CreateDhcpOptionsOutput{
+							DhcpOptions: &ec2.DhcpOptions{DhcpOptionsId: aws.String("dopt-new")},
+						}, nil
+					})
+				m.DescribeVpcs(gomock.Eq(&ec2.DescribeVpcsInput{VpcIds: aws.StringSlice([]string{"vpc-dhcp"})})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-default")}},
+					}, nil)
+				m.AssociateDhcpOptions(gomock.Eq(&ec2.AssociateDhcpOptionsInput{
+					DhcpOptionsId: aws.String("dopt-new"),
+					VpcId:         aws.String("vpc-dhcp"),
+				})).
+					Return(&ec2.AssociateDhcpOptionsOutput{}, nil)
+			},
+		},
+		{
+			name: "matching dhcp options set already associated, does nothing",
+			input: managedVPC(&infrav1.DHCPOptions{
+				DomainName:        "corp.example.com",
+				DomainNameServers: []string{"10.1.0.2", "10.1.0.3"},
+			}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(&ec2.DescribeDhcpOptionsOutput{
+						DhcpOptions: []*ec2.DhcpOptions{corpOptions("dopt-corp")},
+					}, nil)
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-corp")}},
+					}, nil)
+				m.CreateDhcpOptions(gomock.Any()).Times(0)
+				m.AssociateDhcpOptions(gomock.Any()).Times(0)
+				m.DeleteDhcpOptions(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "dhcp options changed, replaces the dhcp options set",
+			input: managedVPC(&infrav1.DHCPOptions{
+				DomainName:        "corp.example.com",
+				DomainNameServers: []string{"10.1.0.4"},
+			}),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeDhcpOptions(gomock.AssignableToTypeOf(&ec2.DescribeDhcpOptionsInput{})).
+					Return(&ec2.DescribeDhcpOptionsOutput{
+						DhcpOptions: []*ec2.DhcpOptions{corpOptions("dopt-corp")},
+					}, nil)
+				m.CreateDhcpOptions(gomock.AssignableToTypeOf(&ec2.CreateDhcpOptionsInput{})).
+					Return(&ec2.CreateDhcpOptionsOutput{
+						DhcpOptions: &ec2.DhcpOptions{DhcpOptionsId: aws.String("dopt-new")},
+					}, nil)
+				m.DescribeVpcs(gomock.AssignableToTypeOf(&ec2.DescribeVpcsInput{})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{{VpcId: aws.String("vpc-dhcp"), DhcpOptionsId: aws.String("dopt-corp")}},
+					}, nil)
+				m.AssociateDhcpOptions(gomock.Eq(&ec2.AssociateDhcpOptionsInput{
+					DhcpOptionsId: aws.String("dopt-new"),
+					VpcId:         aws.String("vpc-dhcp"),
+				})).
+					Return(&ec2.AssociateDhcpOptionsOutput{}, nil)
+				m.DeleteDhcpOptions(gomock.Eq(&ec2.DeleteDhcpOptionsInput{DhcpOptionsId: aws.String("dopt-corp")})).
+					Return(&ec2.DeleteDhcpOptionsOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						Region:      "us-east-1",
+						NetworkSpec: *tc.input,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			if err := s.reconcileDHCPOptions(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
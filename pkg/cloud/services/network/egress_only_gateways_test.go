@@ -0,0 +1,250 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileEgressOnlyInternetGateways(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	managedIPv6VPC := infrav1.VPCSpec{
+		ID:   "vpc-egress",
+		IPv6: &infrav1.IPv6{CidrBlock: "2001:db8:1234:1a00::/56"},
+		Tags: infrav1.Tags{
+			infrav1.ClusterTagKey("test-cluster"): "owned",
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		vpc        infrav1.VPCSpec
+		expect     func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectEIGW *string
+	}{
+		{
+			name: "does nothing without ipv6",
+			vpc: infrav1.VPCSpec{
+				ID: "vpc-egress",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "does nothing for unmanaged vpcs",
+			vpc: infrav1.VPCSpec{
+				ID:   "vpc-egress",
+				IPv6: &infrav1.IPv6{CidrBlock: "2001:db8:1234:1a00::/56"},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {},
+		},
+		{
+			name: "uses the gateway attached to the vpc",
+			vpc:  managedIPv6VPC,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeEgressOnlyInternetGateways(gomock.AssignableToTypeOf(&ec2.DescribeEgressOnlyInternetGatewaysInput{})).
+					Return(&ec2.DescribeEgressOnlyInternetGatewaysOutput{
+						EgressOnlyInternetGateways: []*ec2.EgressOnlyInternetGateway{
+							{
+								EgressOnlyInternetGatewayId: aws.String("eigw-other"),
+								Attachments: []*ec2.InternetGatewayAttachment{
+									{VpcId: aws.String("vpc-other")},
+								},
+							},
+							{
+								EgressOnlyInternetGatewayId: aws.String("eigw-1"),
+								Attachments: []*ec2.InternetGatewayAttachment{
+									{VpcId: aws.String("vpc-egress")},
+								},
+							},
+						},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+			expectEIGW: aws.String("eigw-1"),
+		},
+		{
+			name: "creates a gateway if none is attached to the vpc",
+			vpc:  managedIPv6VPC,
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeEgressOnlyInternetGateways(gomock.AssignableToTypeOf(&ec2.DescribeEgressOnlyInternetGatewaysInput{})).
+					Return(&ec2.DescribeEgressOnlyInternetGatewaysOutput{
+						EgressOnlyInternetGateways: []*ec2.EgressOnlyInternetGateway{
+							{
+								EgressOnlyInternetGatewayId: aws.String("eigw-other"),
+								Attachments: []*ec2.InternetGatewayAttachment{
+									{VpcId: aws.String("vpc-other")},
+								},
+							},
+						},
+					}, nil)
+				m.CreateEgressOnlyInternetGateway(&ec2.CreateEgressOnlyInternetGatewayInput{
+					VpcId: aws.String("vpc-egress"),
+					TagSpecifications: []*ec2.TagSpecification{
+						{
+							ResourceType: aws.String(ec2.ResourceTypeEgressOnlyInternetGateway),
+							Tags: []*ec2.Tag{
+								{
+									Key:   aws.String("Name"),
+									Value: aws.String("test-cluster-eigw"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+									Value: aws.String("owned"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+									Value: aws.String("common"),
+								},
+							},
+						},
+					},
+				}).
+					Return(&ec2.CreateEgressOnlyInternetGatewayOutput{
+						EgressOnlyInternetGateway: &ec2.EgressOnlyInternetGateway{
+							EgressOnlyInternetGatewayId: aws.String("eigw-2"),
+						},
+					}, nil)
+				m.CreateTags(gomock.AssignableToTypeOf(&ec2.CreateTagsInput{})).
+					Return(nil, nil)
+			},
+			expectEIGW: aws.String("eigw-2"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			clusterScope, err := newEgressOnlyGatewayTestScope(tc.vpc)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			g.Expect(s.reconcileEgressOnlyInternetGateways()).To(Succeed())
+			if tc.expectEIGW != nil {
+				g.Expect(clusterScope.VPC().IPv6.EgressOnlyInternetGatewayID).To(Equal(tc.expectEIGW))
+			}
+		})
+	}
+}
+
+func TestDeleteEgressOnlyInternetGateways(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	testCases := []struct {
+		name   string
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "deletes the gateways attached to the vpc",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeEgressOnlyInternetGateways(gomock.AssignableToTypeOf(&ec2.DescribeEgressOnlyInternetGatewaysInput{})).
+					Return(&ec2.DescribeEgressOnlyInternetGatewaysOutput{
+						EgressOnlyInternetGateways: []*ec2.EgressOnlyInternetGateway{
+							{
+								EgressOnlyInternetGatewayId: aws.String("eigw-other"),
+								Attachments: []*ec2.InternetGatewayAttachment{
+									{VpcId: aws.String("vpc-other")},
+								},
+							},
+							{
+								EgressOnlyInternetGatewayId: aws.String("eigw-1"),
+								Attachments: []*ec2.InternetGatewayAttachment{
+									{VpcId: aws.String("vpc-egress")},
+								},
+							},
+						},
+					}, nil)
+				m.DeleteEgressOnlyInternetGateway(&ec2.DeleteEgressOnlyInternetGatewayInput{
+					EgressOnlyInternetGatewayId: aws.String("eigw-1"),
+				}).
+					Return(&ec2.DeleteEgressOnlyInternetGatewayOutput{}, nil)
+			},
+		},
+		{
+			name: "does nothing if no gateway is attached to the vpc",
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeEgressOnlyInternetGateways(gomock.AssignableToTypeOf(&ec2.DescribeEgressOnlyInternetGatewaysInput{})).
+					Return(&ec2.DescribeEgressOnlyInternetGatewaysOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			clusterScope, err := newEgressOnlyGatewayTestScope(infrav1.VPCSpec{
+				ID:   "vpc-egress",
+				IPv6: &infrav1.IPv6{CidrBlock: "2001:db8:1234:1a00::/56"},
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(clusterScope)
+			s.EC2Client = ec2Mock
+
+			g.Expect(s.deleteEgressOnlyInternetGateways()).To(Succeed())
+		})
+	}
+}
+
+func newEgressOnlyGatewayTestScope(vpc infrav1.VPCSpec) (*scope.ClusterScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{VPC: vpc},
+			},
+		},
+	})
+}
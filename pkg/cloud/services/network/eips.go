@@ -102,8 +102,18 @@ func (s *Service) disassociateAddress(ip *ec2.Address) error {
 }
 
 func (s *Service) releaseAddresses() error {
+	return s.releaseAddressesWithFilters(filter.EC2.Cluster(s.scope.Name()))
+}
+
+// releaseControlPlaneAddresses releases the Elastic IP addresses allocated for control plane instances.
+// Unlike the other addresses of the cluster, they don't depend on the VPC still existing.
+func (s *Service) releaseControlPlaneAddresses() error {
+	return s.releaseAddressesWithFilters(filter.EC2.Cluster(s.scope.Name()), filter.EC2.ProviderRole(infrav1.APIServerRoleTagValue))
+}
+
+func (s *Service) releaseAddressesWithFilters(filters ...*ec2.Filter) error {
 	out, err := s.EC2Client.DescribeAddresses(&ec2.DescribeAddressesInput{
-		Filters: []*ec2.Filter{filter.EC2.Cluster(s.scope.Name())},
+		Filters: filters,
 	})
 	if err != nil {
 		return errors.Wrapf(err, "failed to describe elastic IPs %q", err)
@@ -39,6 +39,11 @@ func (s *Service) reconcileInternetGateways() error {
 		return nil
 	}
 
+	if s.scope.VPC().IsIsolated() {
+		s.scope.V(4).Info("Skipping internet gateways reconcile in isolated mode")
+		return nil
+	}
+
 	s.scope.V(2).Info("Reconciling internet gateways")
 
 	igs, err := s.describeVpcInternetGateways()
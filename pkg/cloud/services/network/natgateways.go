@@ -42,6 +42,11 @@ func (s *Service) reconcileNatGateways() error {
 		return nil
 	}
 
+	if s.scope.VPC().IsIsolated() {
+		s.scope.V(4).Info("Skipping NAT gateway reconcile in isolated mode")
+		return nil
+	}
+
 	s.scope.V(2).Info("Reconciling NAT gateways")
 
 	if len(s.scope.Subnets().FilterPrivate()) == 0 {
@@ -96,6 +101,15 @@ func (s *Service) reconcileNatGateways() error {
 		subnetIDs = append(subnetIDs, sn.ID)
 	}
 
+	// With a single NAT gateway topology, only create a gateway if none exist yet.
+	if s.scope.VPC().IsSingleNatGateway() && len(subnetIDs) > 0 {
+		if len(existing) > 0 {
+			subnetIDs = nil
+		} else {
+			subnetIDs = subnetIDs[:1]
+		}
+	}
+
 	// Batch the creation of NAT gateways
 	if len(subnetIDs) > 0 {
 		// set NatGatewayCreationStarted if the condition has never been set before
@@ -328,5 +342,14 @@ func (s *Service) getNatGatewayForSubnet(sn *infrav1.SubnetSpec) (string, error)
 		return gws[0], nil
 	}
 
+	// With a single NAT gateway topology, private subnets route through the gateway of another availability zone.
+	if s.scope.VPC().IsSingleNatGateway() {
+		for _, psn := range s.scope.Subnets().FilterPublic() {
+			if psn.NatGatewayID != nil {
+				return *psn.NatGatewayID, nil
+			}
+		}
+	}
+
 	return "", errors.Errorf("no nat gateways available in %q for private subnet %q, current state: %+v", sn.AvailabilityZone, sn.ID, azGateways)
 }
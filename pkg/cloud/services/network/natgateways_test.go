@@ -20,6 +20,8 @@ import (
 	"context"
 	"testing"
 
+	. "github.com/onsi/gomega"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/golang/mock/gomock"
@@ -41,9 +43,10 @@ func TestReconcileNatGateways(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	testCases := []struct {
-		name   string
-		input  []infrav1.SubnetSpec
-		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		name     string
+		input    []infrav1.SubnetSpec
+		topology *infrav1.NatGatewayTopology
+		expect   func(m *mock_ec2iface.MockEC2APIMockRecorder)
 	}{
 		{
 			name: "single private subnet exists, should create no NAT gateway",
@@ -169,6 +172,84 @@ func TestReconcileNatGateways(t *testing.T) {
 				}).Return(nil)
 			},
 		},
+		{
+			name:     "single topology, two public & two private subnets, should create 1 NAT gateway",
+			topology: &infrav1.NatGatewayTopologySingle,
+			input: []infrav1.SubnetSpec{
+				{
+					ID:               "subnet-1",
+					AvailabilityZone: "us-east-1a",
+					CidrBlock:        "10.0.10.0/24",
+					IsPublic:         true,
+				},
+				{
+					ID:               "subnet-2",
+					AvailabilityZone: "us-east-1a",
+					CidrBlock:        "10.0.12.0/24",
+					IsPublic:         false,
+				},
+				{
+					ID:               "subnet-3",
+					AvailabilityZone: "us-east-1b",
+					CidrBlock:        "10.0.14.0/24",
+					IsPublic:         true,
+				},
+				{
+					ID:               "subnet-4",
+					AvailabilityZone: "us-east-1b",
+					CidrBlock:        "10.0.16.0/24",
+					IsPublic:         false,
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNatGatewaysPages(gomock.Any(), gomock.Any()).
+					Return(nil)
+
+				m.DescribeAddresses(gomock.Any()).
+					Return(&ec2.DescribeAddressesOutput{}, nil)
+
+				m.AllocateAddress(gomock.AssignableToTypeOf(&ec2.AllocateAddressInput{})).
+					Return(&ec2.AllocateAddressOutput{
+						AllocationId: aws.String(ElasticIPAllocationID),
+					}, nil).
+					Times(1)
+
+				m.CreateNatGateway(&ec2.CreateNatGatewayInput{
+					AllocationId: aws.String(ElasticIPAllocationID),
+					SubnetId:     aws.String("subnet-1"),
+					TagSpecifications: []*ec2.TagSpecification{
+						{
+							ResourceType: aws.String("natgateway"),
+							Tags: []*ec2.Tag{
+								{
+									Key:   aws.String("Name"),
+									Value: aws.String("test-cluster-nat"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+									Value: aws.String("owned"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+									Value: aws.String("common"),
+								},
+							},
+						},
+					},
+				},
+				).Return(&ec2.CreateNatGatewayOutput{
+					NatGateway: &ec2.NatGateway{
+						NatGatewayId: aws.String("natgateway"),
+						SubnetId:     aws.String("subnet-1"),
+					},
+				}, nil).
+					Times(1)
+
+				m.WaitUntilNatGatewayAvailable(&ec2.DescribeNatGatewaysInput{
+					NatGatewayIds: []*string{aws.String("natgateway")},
+				}).Return(nil)
+			},
+		},
 		{
 			name: "two public & 1 private subnet, and one NAT gateway exists",
 			input: []infrav1.SubnetSpec{
@@ -373,6 +454,7 @@ func TestReconcileNatGateways(t *testing.T) {
 							Tags: infrav1.Tags{
 								infrav1.ClusterTagKey("test-cluster"): "owned",
 							},
+							NatGatewayTopology: tc.topology,
 						},
 						Subnets: tc.input,
 					},
@@ -403,3 +485,90 @@ func TestReconcileNatGateways(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNatGatewayForSubnet(t *testing.T) {
+	subnets := []infrav1.SubnetSpec{
+		{
+			ID:               "subnet-public-1a",
+			AvailabilityZone: "us-east-1a",
+			IsPublic:         true,
+			NatGatewayID:     aws.String("natgateway-1a"),
+		},
+		{
+			ID:               "subnet-public-1b",
+			AvailabilityZone: "us-east-1b",
+			IsPublic:         true,
+		},
+		{
+			ID:               "subnet-private-1a",
+			AvailabilityZone: "us-east-1a",
+		},
+		{
+			ID:               "subnet-private-1b",
+			AvailabilityZone: "us-east-1b",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		topology    *infrav1.NatGatewayTopology
+		subnetID    string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "per AZ topology uses the NAT gateway in the same availability zone",
+			subnetID: "subnet-private-1a",
+			expected: "natgateway-1a",
+		},
+		{
+			name:        "per AZ topology fails without a NAT gateway in the same availability zone",
+			subnetID:    "subnet-private-1b",
+			expectError: true,
+		},
+		{
+			name:     "single topology uses the shared NAT gateway",
+			topology: &infrav1.NatGatewayTopologySingle,
+			subnetID: "subnet-private-1b",
+			expected: "natgateway-1a",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			awsCluster := &infrav1.AWSCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: infrav1.AWSClusterSpec{
+					NetworkSpec: infrav1.NetworkSpec{
+						VPC: infrav1.VPCSpec{
+							ID:                 subnetsVPCID,
+							NatGatewayTopology: tc.topology,
+						},
+						Subnets: subnets,
+					},
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			clusterScope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: awsCluster,
+				Client:     client,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			s := NewService(clusterScope)
+			natGatewayID, err := s.getNatGatewayForSubnet(clusterScope.Subnets().FindByID(tc.subnetID))
+			if tc.expectError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(natGatewayID).To(Equal(tc.expected))
+		})
+	}
+}
@@ -34,6 +34,12 @@ func (s *Service) ReconcileNetwork() (err error) {
 	}
 	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.VpcReadyCondition)
 
+	// DHCP Options.
+	if err := s.reconcileDHCPOptions(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.DHCPOptionsReadyCondition, infrav1.DHCPOptionsReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
 	// Secondary CIDR
 	if err := s.associateSecondaryCidr(); err != nil {
 		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SecondaryCidrsReadyCondition, infrav1.SecondaryCidrReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
@@ -52,6 +58,12 @@ func (s *Service) ReconcileNetwork() (err error) {
 		return err
 	}
 
+	// Egress Only Internet Gateways.
+	if err := s.reconcileEgressOnlyInternetGateways(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, infrav1.EgressOnlyInternetGatewayFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
 	// NAT Gateways.
 	if err := s.reconcileNatGateways(); err != nil {
 		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NatGatewaysReadyCondition, infrav1.NatGatewaysReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
@@ -64,6 +76,24 @@ func (s *Service) ReconcileNetwork() (err error) {
 		return err
 	}
 
+	// Network ACLs.
+	if err := s.reconcileNetworkACLs(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.NetworkACLsReadyCondition, infrav1.NetworkACLsReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	// VPC Peering Connections.
+	if err := s.reconcileVPCPeeringConnections(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VPCPeeringConnectionsReadyCondition, infrav1.VPCPeeringConnectionsReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
+	// VPC Endpoints.
+	if err := s.reconcileVPCEndpoints(); err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VPCEndpointsReadyCondition, infrav1.VPCEndpointsReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return err
+	}
+
 	s.scope.V(2).Info("Reconcile network completed successfully")
 	return nil
 }
@@ -72,6 +102,17 @@ func (s *Service) ReconcileNetwork() (err error) {
 func (s *Service) DeleteNetwork() (err error) {
 	s.scope.V(2).Info("Deleting network")
 
+	// The VPC described from AWS doesn't know whether it was created isolated or with DHCP options.
+	isolated := s.scope.VPC().IsIsolated()
+	dhcpOptions := s.scope.VPC().DHCPOptions != nil
+
+	// Control plane EIPs are released first, as they are left behind even when the VPC is already gone.
+	if s.scope.ControlPlaneElasticIPs() {
+		if err := s.releaseControlPlaneAddresses(); err != nil {
+			return err
+		}
+	}
+
 	vpc := &infrav1.VPCSpec{}
 	// Get VPC used for the cluster
 	if s.scope.VPC().ID != "" {
@@ -79,7 +120,10 @@ func (s *Service) DeleteNetwork() (err error) {
 		vpc, err = s.describeVPCByID()
 		if err != nil {
 			if awserrors.IsNotFound(err) {
-				// If the VPC does not exist, nothing to do
+				// If the VPC does not exist, only its DHCP options set may be left behind
+				if dhcpOptions {
+					return s.deleteDHCPOptions()
+				}
 				return nil
 			}
 			return err
@@ -97,6 +141,25 @@ func (s *Service) DeleteNetwork() (err error) {
 		return err
 	}
 
+	// VPC Peering Connections.
+	if err := s.deleteVPCPeeringConnections(); err != nil {
+		return err
+	}
+
+	// VPC Endpoints.
+	if isolated {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VPCEndpointsReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+		if err := s.scope.PatchObject(); err != nil {
+			return err
+		}
+
+		if err := s.deleteVPCEndpoints(); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VPCEndpointsReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VPCEndpointsReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
+	}
+
 	// Routing tables.
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.RouteTablesReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
@@ -138,6 +201,20 @@ func (s *Service) DeleteNetwork() (err error) {
 	}
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.InternetGatewayReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 
+	// Egress Only Internet Gateways.
+	if s.scope.VPC().IsIPv6Enabled() {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+		if err := s.scope.PatchObject(); err != nil {
+			return err
+		}
+
+		if err := s.deleteEgressOnlyInternetGateways(); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.EgressOnlyInternetGatewayReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
+	}
+
 	// Subnets.
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SubnetsReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
@@ -150,6 +227,11 @@ func (s *Service) DeleteNetwork() (err error) {
 	}
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.SubnetsReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 
+	// Network ACLs.
+	if err := s.deleteNetworkACLs(); err != nil {
+		return err
+	}
+
 	// VPC.
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VpcReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	if err := s.scope.PatchObject(); err != nil {
@@ -162,6 +244,20 @@ func (s *Service) DeleteNetwork() (err error) {
 	}
 	conditions.MarkFalse(s.scope.InfraCluster(), infrav1.VpcReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
 
+	// DHCP Options.
+	if dhcpOptions {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.DHCPOptionsReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+		if err := s.scope.PatchObject(); err != nil {
+			return err
+		}
+
+		if err := s.deleteDHCPOptions(); err != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.DHCPOptionsReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.DHCPOptionsReadyCondition, clusterv1.DeletedReason, clusterv1.ConditionSeverityInfo, "")
+	}
+
 	s.scope.V(2).Info("Delete network completed successfully")
 	return nil
 }
@@ -0,0 +1,357 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/converters"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// networkACLDefaultRuleNumber is the number of the rules that deny all the traffic
+	// not matched by any other rule, which are part of every network ACL and cannot be changed.
+	networkACLDefaultRuleNumber = 32767
+)
+
+// networkACLProtocols maps the protocols of the spec to the protocol numbers used by network ACL entries.
+var networkACLProtocols = map[infrav1.SecurityGroupProtocol]string{
+	infrav1.SecurityGroupProtocolAll:  "-1",
+	infrav1.SecurityGroupProtocolICMP: "1",
+	infrav1.SecurityGroupProtocolTCP:  "6",
+	infrav1.SecurityGroupProtocolUDP:  "17",
+}
+
+type networkACLEntryKey struct {
+	egress     bool
+	ruleNumber int64
+}
+
+func (s *Service) reconcileNetworkACLs() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping network ACLs reconcile in unmanaged mode")
+		return nil
+	}
+
+	acls, err := s.describeVpcNetworkACLs()
+	if err != nil {
+		return err
+	}
+
+	owned, defaultACL := s.getClusterNetworkACL(acls)
+	if len(s.scope.NetworkACLRules()) == 0 {
+		if owned == nil {
+			return nil
+		}
+
+		// The rules were removed from the spec, the subnets go back to the default network ACL of the VPC.
+		s.scope.V(2).Info("Deleting network ACL", "network-acl-id", aws.StringValue(owned.NetworkAclId))
+		if err := s.releaseNetworkACL(owned, defaultACL); err != nil {
+			return err
+		}
+		return s.deleteNetworkACL(owned)
+	}
+
+	s.scope.V(2).Info("Reconciling network ACLs")
+
+	if owned == nil {
+		owned, err = s.createNetworkACL()
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.reconcileNetworkACLEntries(owned); err != nil {
+		return err
+	}
+
+	subnetIDs := make(map[string]bool, len(s.scope.Subnets()))
+	for _, subnet := range s.scope.Subnets() {
+		subnetIDs[subnet.ID] = true
+	}
+
+	// Every subnet is associated with exactly one network ACL, the association is moved rather than created.
+	for _, acl := range acls {
+		if acl == owned {
+			continue
+		}
+		for _, association := range acl.Associations {
+			if !subnetIDs[aws.StringValue(association.SubnetId)] {
+				continue
+			}
+			if err := s.replaceNetworkACLAssociation(association, aws.StringValue(owned.NetworkAclId)); err != nil {
+				return err
+			}
+		}
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.NetworkACLsReadyCondition)
+	return nil
+}
+
+// deleteNetworkACLs deletes the network ACL of the cluster, which can only happen once its subnets are gone.
+func (s *Service) deleteNetworkACLs() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping network ACLs deletion in unmanaged mode")
+		return nil
+	}
+
+	acls, err := s.describeVpcNetworkACLs()
+	if err != nil {
+		return err
+	}
+
+	owned, _ := s.getClusterNetworkACL(acls)
+	if owned == nil {
+		return nil
+	}
+
+	return s.deleteNetworkACL(owned)
+}
+
+func (s *Service) describeVpcNetworkACLs() ([]*ec2.NetworkAcl, error) {
+	out, err := s.EC2Client.DescribeNetworkAcls(&ec2.DescribeNetworkAclsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+		},
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeNetworkACLs", "Failed to describe network ACLs in vpc %q: %v", s.scope.VPC().ID, err)
+		return nil, errors.Wrapf(err, "failed to describe network ACLs in vpc %q", s.scope.VPC().ID)
+	}
+
+	return out.NetworkAcls, nil
+}
+
+// getClusterNetworkACL returns the network ACL owned by the cluster, if any, and the default network ACL of the VPC.
+func (s *Service) getClusterNetworkACL(acls []*ec2.NetworkAcl) (owned, defaultACL *ec2.NetworkAcl) {
+	for _, acl := range acls {
+		if aws.BoolValue(acl.IsDefault) {
+			defaultACL = acl
+		}
+		if infrav1.Tags(converters.TagsToMap(acl.Tags)).HasOwned(s.scope.Name()) {
+			owned = acl
+		}
+	}
+	return owned, defaultACL
+}
+
+func (s *Service) createNetworkACL() (*ec2.NetworkAcl, error) {
+	out, err := s.EC2Client.CreateNetworkAcl(&ec2.CreateNetworkAclInput{
+		VpcId: aws.String(s.scope.VPC().ID),
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2.ResourceTypeNetworkAcl, s.getNetworkACLTagParams(services.TemporaryResourceID)),
+		},
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateNetworkACL", "Failed to create managed network ACL: %v", err)
+		return nil, errors.Wrap(err, "failed to create network ACL")
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateNetworkACL", "Created managed network ACL %q", aws.StringValue(out.NetworkAcl.NetworkAclId))
+	return out.NetworkAcl, nil
+}
+
+func (s *Service) deleteNetworkACL(acl *ec2.NetworkAcl) error {
+	if _, err := s.EC2Client.DeleteNetworkAcl(&ec2.DeleteNetworkAclInput{
+		NetworkAclId: acl.NetworkAclId,
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteNetworkACL", "Failed to delete managed network ACL %q: %v", aws.StringValue(acl.NetworkAclId), err)
+		return errors.Wrapf(err, "failed to delete network ACL %q", aws.StringValue(acl.NetworkAclId))
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteNetworkACL", "Deleted managed network ACL %q", aws.StringValue(acl.NetworkAclId))
+	return nil
+}
+
+// releaseNetworkACL moves the subnets associated with the network ACL of the cluster to the default network ACL.
+func (s *Service) releaseNetworkACL(owned, defaultACL *ec2.NetworkAcl) error {
+	if len(owned.Associations) == 0 {
+		return nil
+	}
+	if defaultACL == nil {
+		return errors.Errorf("failed to find the default network ACL of vpc %q", s.scope.VPC().ID)
+	}
+
+	for _, association := range owned.Associations {
+		if err := s.replaceNetworkACLAssociation(association, aws.StringValue(defaultACL.NetworkAclId)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) replaceNetworkACLAssociation(association *ec2.NetworkAclAssociation, id string) error {
+	if _, err := s.EC2Client.ReplaceNetworkAclAssociation(&ec2.ReplaceNetworkAclAssociationInput{
+		AssociationId: association.NetworkAclAssociationId,
+		NetworkAclId:  aws.String(id),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedAssociateNetworkACL", "Failed to associate network ACL %q with subnet %q: %v", id, aws.StringValue(association.SubnetId), err)
+		return errors.Wrapf(err, "failed to associate network ACL %q with subnet %q", id, aws.StringValue(association.SubnetId))
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulAssociateNetworkACL", "Associated network ACL %q with subnet %q", id, aws.StringValue(association.SubnetId))
+	return nil
+}
+
+// reconcileNetworkACLEntries makes the entries of the network ACL match the rules of the spec.
+func (s *Service) reconcileNetworkACLEntries(acl *ec2.NetworkAcl) error {
+	current := make(map[networkACLEntryKey]*ec2.NetworkAclEntry, len(acl.Entries))
+	for _, entry := range acl.Entries {
+		if aws.Int64Value(entry.RuleNumber) == networkACLDefaultRuleNumber {
+			continue
+		}
+		current[networkACLEntryKey{egress: aws.BoolValue(entry.Egress), ruleNumber: aws.Int64Value(entry.RuleNumber)}] = entry
+	}
+
+	for _, rule := range s.scope.NetworkACLRules() {
+		key := networkACLEntryKey{egress: rule.Egress, ruleNumber: rule.RuleNumber}
+		entry, ok := current[key]
+		delete(current, key)
+
+		desired := networkACLEntryFromRule(rule)
+		switch {
+		case !ok:
+			if _, err := s.EC2Client.CreateNetworkAclEntry(&ec2.CreateNetworkAclEntryInput{
+				NetworkAclId: acl.NetworkAclId,
+				RuleNumber:   desired.RuleNumber,
+				Egress:       desired.Egress,
+				RuleAction:   desired.RuleAction,
+				Protocol:     desired.Protocol,
+				CidrBlock:    desired.CidrBlock,
+				PortRange:    desired.PortRange,
+				IcmpTypeCode: desired.IcmpTypeCode,
+			}); err != nil {
+				return errors.Wrapf(err, "failed to create rule %s of network ACL %q", describeNetworkACLRule(rule), aws.StringValue(acl.NetworkAclId))
+			}
+		case !networkACLEntriesEqual(entry, desired):
+			if _, err := s.EC2Client.ReplaceNetworkAclEntry(&ec2.ReplaceNetworkAclEntryInput{
+				NetworkAclId: acl.NetworkAclId,
+				RuleNumber:   desired.RuleNumber,
+				Egress:       desired.Egress,
+				RuleAction:   desired.RuleAction,
+				Protocol:     desired.Protocol,
+				CidrBlock:    desired.CidrBlock,
+				PortRange:    desired.PortRange,
+				IcmpTypeCode: desired.IcmpTypeCode,
+			}); err != nil {
+				return errors.Wrapf(err, "failed to replace rule %s of network ACL %q", describeNetworkACLRule(rule), aws.StringValue(acl.NetworkAclId))
+			}
+		default:
+			continue
+		}
+		s.scope.V(2).Info("Reconciled network ACL rule", "network-acl-id", aws.StringValue(acl.NetworkAclId), "rule", describeNetworkACLRule(rule))
+	}
+
+	// Entries that were removed from the spec.
+	for key, entry := range current {
+		if _, err := s.EC2Client.DeleteNetworkAclEntry(&ec2.DeleteNetworkAclEntryInput{
+			NetworkAclId: acl.NetworkAclId,
+			RuleNumber:   entry.RuleNumber,
+			Egress:       entry.Egress,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to delete rule %d of network ACL %q", key.ruleNumber, aws.StringValue(acl.NetworkAclId))
+		}
+		s.scope.V(2).Info("Deleted network ACL rule", "network-acl-id", aws.StringValue(acl.NetworkAclId), "rule-number", key.ruleNumber, "egress", key.egress)
+	}
+
+	return nil
+}
+
+func networkACLEntryFromRule(rule infrav1.NetworkACLRule) *ec2.NetworkAclEntry {
+	entry := &ec2.NetworkAclEntry{
+		RuleNumber: aws.Int64(rule.RuleNumber),
+		Egress:     aws.Bool(rule.Egress),
+		RuleAction: aws.String(string(rule.Action)),
+		Protocol:   aws.String(networkACLProtocols[rule.Protocol]),
+		CidrBlock:  aws.String(rule.CidrBlock),
+	}
+
+	switch rule.Protocol {
+	case infrav1.SecurityGroupProtocolTCP, infrav1.SecurityGroupProtocolUDP:
+		entry.PortRange = &ec2.PortRange{
+			From: aws.Int64(rule.FromPort),
+			To:   aws.Int64(rule.ToPort),
+		}
+	case infrav1.SecurityGroupProtocolICMP:
+		// All ICMP types and codes.
+		entry.IcmpTypeCode = &ec2.IcmpTypeCode{
+			Type: aws.Int64(-1),
+			Code: aws.Int64(-1),
+		}
+	}
+
+	return entry
+}
+
+// networkACLEntriesEqual compares the fields of the entries that can be set from the spec.
+func networkACLEntriesEqual(a, b *ec2.NetworkAclEntry) bool {
+	if aws.StringValue(a.RuleAction) != aws.StringValue(b.RuleAction) ||
+		aws.StringValue(a.Protocol) != aws.StringValue(b.Protocol) ||
+		aws.StringValue(a.CidrBlock) != aws.StringValue(b.CidrBlock) {
+		return false
+	}
+
+	if (a.PortRange == nil) != (b.PortRange == nil) {
+		return false
+	}
+	if a.PortRange != nil &&
+		(aws.Int64Value(a.PortRange.From) != aws.Int64Value(b.PortRange.From) || aws.Int64Value(a.PortRange.To) != aws.Int64Value(b.PortRange.To)) {
+		return false
+	}
+
+	if (a.IcmpTypeCode == nil) != (b.IcmpTypeCode == nil) {
+		return false
+	}
+	if a.IcmpTypeCode != nil &&
+		(aws.Int64Value(a.IcmpTypeCode.Type) != aws.Int64Value(b.IcmpTypeCode.Type) || aws.Int64Value(a.IcmpTypeCode.Code) != aws.Int64Value(b.IcmpTypeCode.Code)) {
+		return false
+	}
+
+	return true
+}
+
+func describeNetworkACLRule(rule infrav1.NetworkACLRule) string {
+	direction := "ingress"
+	if rule.Egress {
+		direction = "egress"
+	}
+	return fmt.Sprintf("%s/%d", direction, rule.RuleNumber)
+}
+
+func (s *Service) getNetworkACLTagParams(id string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-nacl", s.scope.Name())
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.CommonRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
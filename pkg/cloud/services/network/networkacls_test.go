@@ -0,0 +1,268 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+func TestReconcileNetworkACLs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	rules := []infrav1.NetworkACLRule{
+		{
+			RuleNumber: 100,
+			Action:     infrav1.NetworkACLRuleActionAllow,
+			Protocol:   infrav1.SecurityGroupProtocolTCP,
+			CidrBlock:  "10.0.0.0/8",
+			FromPort:   443,
+			ToPort:     443,
+		},
+		{
+			RuleNumber: 100,
+			Egress:     true,
+			Action:     infrav1.NetworkACLRuleActionAllow,
+			Protocol:   infrav1.SecurityGroupProtocolAll,
+			CidrBlock:  "0.0.0.0/0",
+		},
+	}
+
+	managedVPC := func(rules []infrav1.NetworkACLRule) *infrav1.NetworkSpec {
+		return &infrav1.NetworkSpec{
+			VPC: infrav1.VPCSpec{
+				ID: "vpc-nacl",
+				Tags: infrav1.Tags{
+					infrav1.ClusterTagKey("test-cluster"): "owned",
+				},
+			},
+			Subnets: infrav1.Subnets{
+				{ID: "subnet-1"},
+				{ID: "subnet-2"},
+			},
+			NetworkACLRules: rules,
+		}
+	}
+
+	defaultACL := func(subnetIDs ...string) *ec2.NetworkAcl {
+		acl := &ec2.NetworkAcl{
+			NetworkAclId: aws.String("acl-default"),
+			IsDefault:    aws.Bool(true),
+		}
+		for _, id := range subnetIDs {
+			acl.Associations = append(acl.Associations, &ec2.NetworkAclAssociation{
+				NetworkAclAssociationId: aws.String("aclassoc-" + id),
+				NetworkAclId:            aws.String("acl-default"),
+				SubnetId:                aws.String(id),
+			})
+		}
+		return acl
+	}
+
+	ownedACL := func(entries []*ec2.NetworkAclEntry, subnetIDs ...string) *ec2.NetworkAcl {
+		acl := &ec2.NetworkAcl{
+			NetworkAclId: aws.String("acl-cluster"),
+			IsDefault:    aws.Bool(false),
+			Tags: []*ec2.Tag{
+				{Key: aws.String(infrav1.ClusterTagKey("test-cluster")), Value: aws.String("owned")},
+			},
+			Entries: entries,
+		}
+		for _, id := range subnetIDs {
+			acl.Associations = append(acl.Associations, &ec2.NetworkAclAssociation{
+				NetworkAclAssociationId: aws.String("aclassoc-" + id),
+				NetworkAclId:            aws.String("acl-cluster"),
+				SubnetId:                aws.String(id),
+			})
+		}
+		return acl
+	}
+
+	defaultEntries := []*ec2.NetworkAclEntry{
+		{RuleNumber: aws.Int64(32767), Egress: aws.Bool(false), RuleAction: aws.String("deny"), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0")},
+		{RuleNumber: aws.Int64(32767), Egress: aws.Bool(true), RuleAction: aws.String("deny"), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0")},
+	}
+
+	testCases := []struct {
+		name   string
+		input  *infrav1.NetworkSpec
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "unmanaged vpc, does nothing",
+			input: &infrav1.NetworkSpec{
+				VPC:             infrav1.VPCSpec{ID: "vpc-nacl"},
+				NetworkACLRules: rules,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkAcls(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:  "no rules and no network acl, does nothing",
+			input: managedVPC(nil),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkAcls(gomock.AssignableToTypeOf(&ec2.DescribeNetworkAclsInput{})).
+					Return(&ec2.DescribeNetworkAclsOutput{
+						NetworkAcls: []*ec2.NetworkAcl{defaultACL("subnet-1", "subnet-2")},
+					}, nil)
+			},
+		},
+		{
+			name:  "no network acl, creates one with the rules and associates it with the subnets",
+			input: managedVPC(rules),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkAcls(gomock.AssignableToTypeOf(&ec2.DescribeNetworkAclsInput{})).
+					Return(&ec2.DescribeNetworkAclsOutput{
+						NetworkAcls: []*ec2.NetworkAcl{defaultACL("subnet-1", "subnet-2", "subnet-other")},
+					}, nil)
+				m.CreateNetworkAcl(gomock.AssignableToTypeOf(&ec2.CreateNetworkAclInput{})).
+					Return(&ec2.CreateNetworkAclOutput{
+						NetworkAcl: ownedACL(defaultEntries),
+					}, nil)
+				m.CreateNetworkAclEntry(gomock.Eq(&ec2.CreateNetworkAclEntryInput{
+					NetworkAclId: aws.String("acl-cluster"),
+					RuleNumber:   aws.Int64(100),
+					Egress:       aws.Bool(false),
+					RuleAction:   aws.String("allow"),
+					Protocol:     aws.String("6"),
+					CidrBlock:    aws.String("10.0.0.0/8"),
+					PortRange:    &ec2.PortRange{From: aws.Int64(443), To: aws.Int64(443)},
+				})).
+					Return(&ec2.CreateNetworkAclEntryOutput{}, nil)
+				m.CreateNetworkAclEntry(gomock.Eq(&ec2.CreateNetworkAclEntryInput{
+					NetworkAclId: aws.String("acl-cluster"),
+					RuleNumber:   aws.Int64(100),
+					Egress:       aws.Bool(true),
+					RuleAction:   aws.String("allow"),
+					Protocol:     aws.String("-1"),
+					CidrBlock:    aws.String("0.0.0.0/0"),
+				})).
+					Return(&ec2.CreateNetworkAclEntryOutput{}, nil)
+				m.ReplaceNetworkAclAssociation(gomock.Eq(&ec2.ReplaceNetworkAclAssociationInput{
+					AssociationId: aws.String("aclassoc-subnet-1"),
+					NetworkAclId:  aws.String("acl-cluster"),
+				})).
+					Return(&ec2.ReplaceNetworkAclAssociationOutput{}, nil)
+				m.ReplaceNetworkAclAssociation(gomock.Eq(&ec2.ReplaceNetworkAclAssociationInput{
+					AssociationId: aws.String("aclassoc-subnet-2"),
+					NetworkAclId:  aws.String("acl-cluster"),
+				})).
+					Return(&ec2.ReplaceNetworkAclAssociationOutput{}, nil)
+			},
+		},
+		{
+			name:  "rules changed, replaces and deletes entries of the network acl",
+			input: managedVPC(rules),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				entries := append([]*ec2.NetworkAclEntry{
+					{RuleNumber: aws.Int64(100), Egress: aws.Bool(false), RuleAction: aws.String("allow"), Protocol: aws.String("6"), CidrBlock: aws.String("10.0.0.0/8"), PortRange: &ec2.PortRange{From: aws.Int64(443), To: aws.Int64(443)}},
+					{RuleNumber: aws.Int64(100), Egress: aws.Bool(true), RuleAction: aws.String("allow"), Protocol: aws.String("-1"), CidrBlock: aws.String("10.0.0.0/8")},
+					{RuleNumber: aws.Int64(200), Egress: aws.Bool(false), RuleAction: aws.String("deny"), Protocol: aws.String("17"), CidrBlock: aws.String("0.0.0.0/0"), PortRange: &ec2.PortRange{From: aws.Int64(53), To: aws.Int64(53)}},
+				}, defaultEntries...)
+				m.DescribeNetworkAcls(gomock.AssignableToTypeOf(&ec2.DescribeNetworkAclsInput{})).
+					Return(&ec2.DescribeNetworkAclsOutput{
+						NetworkAcls: []*ec2.NetworkAcl{defaultACL(), ownedACL(entries, "subnet-1", "subnet-2")},
+					}, nil)
+				m.ReplaceNetworkAclEntry(gomock.Eq(&ec2.ReplaceNetworkAclEntryInput{
+					NetworkAclId: aws.String("acl-cluster"),
+					RuleNumber:   aws.Int64(100),
+					Egress:       aws.Bool(true),
+					RuleAction:   aws.String("allow"),
+					Protocol:     aws.String("-1"),
+					CidrBlock:    aws.String("0.0.0.0/0"),
+				})).
+					Return(&ec2.ReplaceNetworkAclEntryOutput{}, nil)
+				m.DeleteNetworkAclEntry(gomock.Eq(&ec2.DeleteNetworkAclEntryInput{
+					NetworkAclId: aws.String("acl-cluster"),
+					RuleNumber:   aws.Int64(200),
+					Egress:       aws.Bool(false),
+				})).
+					Return(&ec2.DeleteNetworkAclEntryOutput{}, nil)
+			},
+		},
+		{
+			name:  "rules removed, moves the subnets back to the default network acl and deletes the network acl",
+			input: managedVPC(nil),
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeNetworkAcls(gomock.AssignableToTypeOf(&ec2.DescribeNetworkAclsInput{})).
+					Return(&ec2.DescribeNetworkAclsOutput{
+						NetworkAcls: []*ec2.NetworkAcl{defaultACL(), ownedACL(defaultEntries, "subnet-1", "subnet-2")},
+					}, nil)
+				m.ReplaceNetworkAclAssociation(gomock.Eq(&ec2.ReplaceNetworkAclAssociationInput{
+					AssociationId: aws.String("aclassoc-subnet-1"),
+					NetworkAclId:  aws.String("acl-default"),
+				})).
+					Return(&ec2.ReplaceNetworkAclAssociationOutput{}, nil)
+				m.ReplaceNetworkAclAssociation(gomock.Eq(&ec2.ReplaceNetworkAclAssociationInput{
+					AssociationId: aws.String("aclassoc-subnet-2"),
+					NetworkAclId:  aws.String("acl-default"),
+				})).
+					Return(&ec2.ReplaceNetworkAclAssociationOutput{}, nil)
+				m.DeleteNetworkAcl(gomock.Eq(&ec2.DeleteNetworkAclInput{NetworkAclId: aws.String("acl-cluster")})).
+					Return(&ec2.DeleteNetworkAclOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						Region:      "us-east-1",
+						NetworkSpec: *tc.input,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			if err := s.reconcileNetworkACLs(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func (s *Service) reconcileVPCPeeringConnections() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC peering connections reconcile in unmanaged mode")
+		return nil
+	}
+
+	existing, err := s.describeVPCPeeringConnections()
+	if err != nil {
+		return err
+	}
+
+	if len(s.scope.PeeringConnections()) == 0 && len(existing) == 0 {
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling VPC peering connections")
+
+	routeTables, err := s.describeVpcRouteTables()
+	if err != nil {
+		return err
+	}
+
+	var notActive, conflicts []string
+	for _, peering := range s.scope.PeeringConnections() {
+		pc, ok := existing[peering.PeerVPCID]
+		delete(existing, peering.PeerVPCID)
+		if !ok {
+			pc, err = s.createVPCPeeringConnection(peering)
+			if err != nil {
+				return err
+			}
+		}
+
+		// A connection to a VPC of the same account and region can be accepted by the cluster itself.
+		if aws.StringValue(pc.Status.Code) == ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance &&
+			(peering.PeerOwnerID == "" || peering.PeerOwnerID == aws.StringValue(pc.RequesterVpcInfo.OwnerId)) &&
+			(peering.PeerRegion == "" || peering.PeerRegion == s.scope.Region()) {
+			pc, err = s.acceptVPCPeeringConnection(pc)
+			if err != nil {
+				return err
+			}
+		}
+
+		if aws.StringValue(pc.Status.Code) != ec2.VpcPeeringConnectionStateReasonCodeActive {
+			notActive = append(notActive, aws.StringValue(pc.VpcPeeringConnectionId))
+			continue
+		}
+
+		routeConflicts, err := s.reconcileVPCPeeringRoutes(pc, peerCidrBlocks(peering, pc), routeTables)
+		if err != nil {
+			return err
+		}
+		conflicts = append(conflicts, routeConflicts...)
+	}
+
+	// Connections that were removed from the spec.
+	for _, pc := range existing {
+		if err := s.deleteVPCPeeringRoutes(pc, routeTables); err != nil {
+			return err
+		}
+		if err := s.deleteVPCPeeringConnection(pc); err != nil {
+			return err
+		}
+	}
+
+	if len(notActive) > 0 {
+		conditions.MarkFalse(
+			s.scope.InfraCluster(),
+			infrav1.VPCPeeringConnectionsReadyCondition,
+			infrav1.VPCPeeringConnectionsNotActiveReason,
+			clusterv1.ConditionSeverityInfo,
+			"Waiting for VPC peering connections %s to become active", strings.Join(notActive, ", "))
+		return nil
+	}
+
+	if len(conflicts) > 0 {
+		conditions.MarkFalse(
+			s.scope.InfraCluster(),
+			infrav1.VPCPeeringConnectionsReadyCondition,
+			infrav1.VPCPeeringRoutesConflictReason,
+			clusterv1.ConditionSeverityWarning,
+			"Routes already have another target: %s", strings.Join(conflicts, ", "))
+		return nil
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.VPCPeeringConnectionsReadyCondition)
+	return nil
+}
+
+func (s *Service) deleteVPCPeeringConnections() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC peering connections deletion in unmanaged mode")
+		return nil
+	}
+
+	existing, err := s.describeVPCPeeringConnections()
+	if err != nil {
+		return err
+	}
+
+	for _, pc := range existing {
+		if err := s.deleteVPCPeeringConnection(pc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeVPCPeeringConnections returns the live VPC peering connections requested by the cluster, by peer VPC id.
+func (s *Service) describeVPCPeeringConnections() (map[string]*ec2.VpcPeeringConnection, error) {
+	input := &ec2.DescribeVpcPeeringConnectionsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("requester-vpc-info.vpc-id"),
+				Values: aws.StringSlice([]string{s.scope.VPC().ID}),
+			},
+			filter.EC2.ClusterOwned(s.scope.Name()),
+		},
+	}
+
+	connections := make(map[string]*ec2.VpcPeeringConnection)
+	err := s.EC2Client.DescribeVpcPeeringConnectionsPages(input, func(page *ec2.DescribeVpcPeeringConnectionsOutput, lastPage bool) bool {
+		for _, pc := range page.VpcPeeringConnections {
+			switch aws.StringValue(pc.Status.Code) {
+			case ec2.VpcPeeringConnectionStateReasonCodeDeleted,
+				ec2.VpcPeeringConnectionStateReasonCodeDeleting,
+				ec2.VpcPeeringConnectionStateReasonCodeExpired,
+				ec2.VpcPeeringConnectionStateReasonCodeFailed,
+				ec2.VpcPeeringConnectionStateReasonCodeRejected:
+				continue
+			}
+			connections[aws.StringValue(pc.AccepterVpcInfo.VpcId)] = pc
+		}
+		return !lastPage
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeVPCPeeringConnections", "Failed to describe VPC peering connections of vpc %q: %v", s.scope.VPC().ID, err)
+		return nil, errors.Wrapf(err, "failed to describe VPC peering connections of vpc %q", s.scope.VPC().ID)
+	}
+
+	return connections, nil
+}
+
+func (s *Service) createVPCPeeringConnection(peering infrav1.VPCPeeringConnection) (*ec2.VpcPeeringConnection, error) {
+	input := &ec2.CreateVpcPeeringConnectionInput{
+		VpcId:     aws.String(s.scope.VPC().ID),
+		PeerVpcId: aws.String(peering.PeerVPCID),
+		TagSpecifications: []*ec2.TagSpecification{
+			tags.BuildParamsToTagSpecification(ec2.ResourceTypeVpcPeeringConnection, s.getVPCPeeringConnectionTagParams(services.TemporaryResourceID, peering.PeerVPCID)),
+		},
+	}
+	if peering.PeerOwnerID != "" {
+		input.PeerOwnerId = aws.String(peering.PeerOwnerID)
+	}
+	if peering.PeerRegion != "" {
+		input.PeerRegion = aws.String(peering.PeerRegion)
+	}
+
+	out, err := s.EC2Client.CreateVpcPeeringConnection(input)
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateVPCPeeringConnection", "Failed to create managed VPC peering connection to vpc %q: %v", peering.PeerVPCID, err)
+		return nil, errors.Wrapf(err, "failed to create VPC peering connection to vpc %q", peering.PeerVPCID)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateVPCPeeringConnection", "Created managed VPC peering connection %q to vpc %q", aws.StringValue(out.VpcPeeringConnection.VpcPeeringConnectionId), peering.PeerVPCID)
+	return out.VpcPeeringConnection, nil
+}
+
+func (s *Service) acceptVPCPeeringConnection(pc *ec2.VpcPeeringConnection) (*ec2.VpcPeeringConnection, error) {
+	out, err := s.EC2Client.AcceptVpcPeeringConnection(&ec2.AcceptVpcPeeringConnectionInput{
+		VpcPeeringConnectionId: pc.VpcPeeringConnectionId,
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedAcceptVPCPeeringConnection", "Failed to accept managed VPC peering connection %q: %v", aws.StringValue(pc.VpcPeeringConnectionId), err)
+		return nil, errors.Wrapf(err, "failed to accept VPC peering connection %q", aws.StringValue(pc.VpcPeeringConnectionId))
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulAcceptVPCPeeringConnection", "Accepted managed VPC peering connection %q", aws.StringValue(pc.VpcPeeringConnectionId))
+	return out.VpcPeeringConnection, nil
+}
+
+func (s *Service) deleteVPCPeeringConnection(pc *ec2.VpcPeeringConnection) error {
+	if _, err := s.EC2Client.DeleteVpcPeeringConnection(&ec2.DeleteVpcPeeringConnectionInput{
+		VpcPeeringConnectionId: pc.VpcPeeringConnectionId,
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteVPCPeeringConnection", "Failed to delete managed VPC peering connection %q: %v", aws.StringValue(pc.VpcPeeringConnectionId), err)
+		return errors.Wrapf(err, "failed to delete VPC peering connection %q", aws.StringValue(pc.VpcPeeringConnectionId))
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteVPCPeeringConnection", "Deleted managed VPC peering connection %q", aws.StringValue(pc.VpcPeeringConnectionId))
+	return nil
+}
+
+// reconcileVPCPeeringRoutes makes sure every route table of the cluster routes the CIDR blocks of the peer
+// VPC through the peering connection, and deletes the routes through the connection to other CIDR blocks.
+// A route to one of the CIDR blocks through another target is left alone, unless the target is gone, and
+// is returned as a conflict.
+func (s *Service) reconcileVPCPeeringRoutes(pc *ec2.VpcPeeringConnection, cidrBlocks []string, routeTables []*ec2.RouteTable) ([]string, error) {
+	pcID := aws.StringValue(pc.VpcPeeringConnectionId)
+	wanted := sets.NewString(cidrBlocks...)
+
+	var conflicts []string
+	for _, rt := range routeTables {
+		for _, route := range rt.Routes {
+			if aws.StringValue(route.VpcPeeringConnectionId) != pcID || wanted.Has(aws.StringValue(route.DestinationCidrBlock)) {
+				continue
+			}
+			if err := s.deleteRoute(rt, route); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, cidr := range cidrBlocks {
+			var current *ec2.Route
+			for _, route := range rt.Routes {
+				if aws.StringValue(route.DestinationCidrBlock) == cidr {
+					current = route
+					break
+				}
+			}
+
+			if current != nil && aws.StringValue(current.VpcPeeringConnectionId) == pcID {
+				continue
+			}
+
+			if current != nil && aws.StringValue(current.State) != ec2.RouteStateBlackhole {
+				record.Warnf(s.scope.InfraCluster(), "ConflictingRoute", "Not routing %s through VPC peering connection %q in RouteTable %q, it is already routed through another target", cidr, pcID, aws.StringValue(rt.RouteTableId))
+				conflicts = append(conflicts, fmt.Sprintf("%s in route table %s", cidr, aws.StringValue(rt.RouteTableId)))
+				continue
+			}
+
+			var err error
+			if current != nil {
+				_, err = s.EC2Client.ReplaceRoute(&ec2.ReplaceRouteInput{
+					RouteTableId:           rt.RouteTableId,
+					DestinationCidrBlock:   aws.String(cidr),
+					VpcPeeringConnectionId: pc.VpcPeeringConnectionId,
+				})
+			} else {
+				_, err = s.EC2Client.CreateRoute(&ec2.CreateRouteInput{
+					RouteTableId:           rt.RouteTableId,
+					DestinationCidrBlock:   aws.String(cidr),
+					VpcPeeringConnectionId: pc.VpcPeeringConnectionId,
+				})
+			}
+			if err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedCreateRoute", "Failed to route %s through VPC peering connection %q in RouteTable %q: %v", cidr, pcID, aws.StringValue(rt.RouteTableId), err)
+				return nil, errors.Wrapf(err, "failed to route %s through VPC peering connection %q in route table %q", cidr, pcID, aws.StringValue(rt.RouteTableId))
+			}
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateRoute", "Routed %s through VPC peering connection %q in RouteTable %q", cidr, pcID, aws.StringValue(rt.RouteTableId))
+		}
+	}
+
+	return conflicts, nil
+}
+
+// deleteVPCPeeringRoutes deletes the routes through the peering connection from the route tables of the cluster.
+func (s *Service) deleteVPCPeeringRoutes(pc *ec2.VpcPeeringConnection, routeTables []*ec2.RouteTable) error {
+	for _, rt := range routeTables {
+		for _, route := range rt.Routes {
+			if aws.StringValue(route.VpcPeeringConnectionId) != aws.StringValue(pc.VpcPeeringConnectionId) {
+				continue
+			}
+			if err := s.deleteRoute(rt, route); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteRoute(rt *ec2.RouteTable, route *ec2.Route) error {
+	if _, err := s.EC2Client.DeleteRoute(&ec2.DeleteRouteInput{
+		RouteTableId:         rt.RouteTableId,
+		DestinationCidrBlock: route.DestinationCidrBlock,
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteRoute", "Failed to delete route %s from RouteTable %q: %v", aws.StringValue(route.DestinationCidrBlock), aws.StringValue(rt.RouteTableId), err)
+		return errors.Wrapf(err, "failed to delete route %s from route table %q", aws.StringValue(route.DestinationCidrBlock), aws.StringValue(rt.RouteTableId))
+	}
+
+	return nil
+}
+
+// peerCidrBlocks returns the CIDR blocks to route through the connection, defaulting to the ones of the peer VPC.
+func peerCidrBlocks(peering infrav1.VPCPeeringConnection, pc *ec2.VpcPeeringConnection) []string {
+	if len(peering.PeerCidrBlocks) > 0 {
+		return peering.PeerCidrBlocks
+	}
+
+	var cidrBlocks []string
+	for _, block := range pc.AccepterVpcInfo.CidrBlockSet {
+		cidrBlocks = append(cidrBlocks, aws.StringValue(block.CidrBlock))
+	}
+	if len(cidrBlocks) == 0 && pc.AccepterVpcInfo.CidrBlock != nil {
+		cidrBlocks = append(cidrBlocks, aws.StringValue(pc.AccepterVpcInfo.CidrBlock))
+	}
+
+	return cidrBlocks
+}
+
+func (s *Service) getVPCPeeringConnectionTagParams(id, peerVPCID string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-pcx-%s", s.scope.Name(), peerVPCID)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.CommonRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileVPCPeeringConnections(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	managedVPC := infrav1.VPCSpec{
+		ID: "vpc-peering",
+		Tags: infrav1.Tags{
+			infrav1.ClusterTagKey("test-cluster"): "owned",
+		},
+	}
+
+	routeTables := &ec2.DescribeRouteTablesOutput{
+		RouteTables: []*ec2.RouteTable{
+			{
+				RouteTableId: aws.String("rtb-1"),
+				Routes: []*ec2.Route{
+					{
+						DestinationCidrBlock: aws.String("10.0.0.0/16"),
+						GatewayId:            aws.String("local"),
+					},
+				},
+			},
+		},
+	}
+
+	connection := func(id, state string) *ec2.VpcPeeringConnection {
+		return &ec2.VpcPeeringConnection{
+			VpcPeeringConnectionId: aws.String(id),
+			Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String(state)},
+			RequesterVpcInfo: &ec2.VpcPeeringConnectionVpcInfo{
+				OwnerId: aws.String("111111111111"),
+				VpcId:   aws.String("vpc-peering"),
+			},
+			AccepterVpcInfo: &ec2.VpcPeeringConnectionVpcInfo{
+				VpcId: aws.String("vpc-shared"),
+				CidrBlockSet: []*ec2.CidrBlock{
+					{CidrBlock: aws.String("172.16.0.0/16")},
+				},
+			},
+		}
+	}
+
+	activeConnection := func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+		m.DescribeVpcPeeringConnectionsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{}), gomock.Any()).
+			Do(func(_ *ec2.DescribeVpcPeeringConnectionsInput, fn func(*ec2.DescribeVpcPeeringConnectionsOutput, bool) bool) {
+				fn(&ec2.DescribeVpcPeeringConnectionsOutput{
+					VpcPeeringConnections: []*ec2.VpcPeeringConnection{
+						connection("pcx-1", ec2.VpcPeeringConnectionStateReasonCodeActive),
+					},
+				}, true)
+			}).
+			Return(nil)
+	}
+
+	testCases := []struct {
+		name           string
+		input          *infrav1.NetworkSpec
+		expect         func(m *mock_ec2iface.MockEC2APIMockRecorder)
+		expectedReason string
+	}{
+		{
+			name: "no peering connections, does nothing",
+			input: &infrav1.NetworkSpec{
+				VPC: managedVPC,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnectionsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{}), gomock.Any()).
+					Return(nil)
+				m.CreateVpcPeeringConnection(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "peering with a vpc of the same account, creates and accepts the connection and adds routes",
+			input: &infrav1.NetworkSpec{
+				VPC: managedVPC,
+				PeeringConnections: []infrav1.VPCPeeringConnection{
+					{PeerVPCID: "vpc-shared"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnectionsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{}), gomock.Any()).
+					Return(nil)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(routeTables, nil)
+				m.CreateVpcPeeringConnection(gomock.AssignableToTypeOf(&ec2.CreateVpcPeeringConnectionInput{})).
+					Return(&ec2.CreateVpcPeeringConnectionOutput{
+						VpcPeeringConnection: connection("pcx-1", ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance),
+					}, nil)
+				m.AcceptVpcPeeringConnection(gomock.Eq(&ec2.AcceptVpcPeeringConnectionInput{
+					VpcPeeringConnectionId: aws.String("pcx-1"),
+				})).
+					Return(&ec2.AcceptVpcPeeringConnectionOutput{
+						VpcPeeringConnection: connection("pcx-1", ec2.VpcPeeringConnectionStateReasonCodeActive),
+					}, nil)
+				m.CreateRoute(gomock.Eq(&ec2.CreateRouteInput{
+					RouteTableId:           aws.String("rtb-1"),
+					DestinationCidrBlock:   aws.String("172.16.0.0/16"),
+					VpcPeeringConnectionId: aws.String("pcx-1"),
+				})).
+					Return(&ec2.CreateRouteOutput{}, nil)
+			},
+		},
+		{
+			name: "peering with a vpc of another account, waits for acceptance",
+			input: &infrav1.NetworkSpec{
+				VPC: managedVPC,
+				PeeringConnections: []infrav1.VPCPeeringConnection{
+					{PeerVPCID: "vpc-shared", PeerOwnerID: "222222222222"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnectionsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{}), gomock.Any()).
+					Do(func(_ *ec2.DescribeVpcPeeringConnectionsInput, fn func(*ec2.DescribeVpcPeeringConnectionsOutput, bool) bool) {
+						fn(&ec2.DescribeVpcPeeringConnectionsOutput{
+							VpcPeeringConnections: []*ec2.VpcPeeringConnection{
+								connection("pcx-1", ec2.VpcPeeringConnectionStateReasonCodePendingAcceptance),
+							},
+						}, true)
+					}).
+					Return(nil)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(routeTables, nil)
+				m.AcceptVpcPeeringConnection(gomock.Any()).Times(0)
+				m.CreateRoute(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "peering connection removed from the spec, deletes the connection and its routes",
+			input: &infrav1.NetworkSpec{
+				VPC: managedVPC,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcPeeringConnectionsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcPeeringConnectionsInput{}), gomock.Any()).
+					Do(func(_ *ec2.DescribeVpcPeeringConnectionsInput, fn func(*ec2.DescribeVpcPeeringConnectionsOutput, bool) bool) {
+						fn(&ec2.DescribeVpcPeeringConnectionsOutput{
+							VpcPeeringConnections: []*ec2.VpcPeeringConnection{
+								connection("pcx-1", ec2.VpcPeeringConnectionStateReasonCodeActive),
+							},
+						}, true)
+					}).
+					Return(nil)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								RouteTableId: aws.String("rtb-1"),
+								Routes: []*ec2.Route{
+									{
+										DestinationCidrBlock:   aws.String("172.16.0.0/16"),
+										VpcPeeringConnectionId: aws.String("pcx-1"),
+									},
+								},
+							},
+						},
+					}, nil)
+				m.DeleteRoute(gomock.Eq(&ec2.DeleteRouteInput{
+					RouteTableId:         aws.String("rtb-1"),
+					DestinationCidrBlock: aws.String("172.16.0.0/16"),
+				})).
+					Return(&ec2.DeleteRouteOutput{}, nil)
+				m.DeleteVpcPeeringConnection(gomock.Eq(&ec2.DeleteVpcPeeringConnectionInput{
+					VpcPeeringConnectionId: aws.String("pcx-1"),
+				})).
+					Return(&ec2.DeleteVpcPeeringConnectionOutput{}, nil)
+			},
+		},
+		{
+			name: "peer CIDR blocks changed, deletes the routes no longer listed and leaves routes through other targets alone",
+			input: &infrav1.NetworkSpec{
+				VPC: managedVPC,
+				PeeringConnections: []infrav1.VPCPeeringConnection{
+					{PeerVPCID: "vpc-shared", PeerCidrBlocks: []string{"172.16.0.0/16", "192.168.0.0/16"}},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				activeConnection(m)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								RouteTableId: aws.String("rtb-1"),
+								Routes: []*ec2.Route{
+									{
+										DestinationCidrBlock:   aws.String("172.16.0.0/16"),
+										VpcPeeringConnectionId: aws.String("pcx-1"),
+										State:                  aws.String(ec2.RouteStateActive),
+									},
+									{
+										DestinationCidrBlock:   aws.String("172.17.0.0/16"),
+										VpcPeeringConnectionId: aws.String("pcx-1"),
+										State:                  aws.String(ec2.RouteStateActive),
+									},
+									{
+										DestinationCidrBlock: aws.String("192.168.0.0/16"),
+										TransitGatewayId:     aws.String("tgw-1"),
+										State:                aws.String(ec2.RouteStateActive),
+									},
+								},
+							},
+						},
+					}, nil)
+				m.DeleteRoute(gomock.Eq(&ec2.DeleteRouteInput{
+					RouteTableId:         aws.String("rtb-1"),
+					DestinationCidrBlock: aws.String("172.17.0.0/16"),
+				})).
+					Return(&ec2.DeleteRouteOutput{}, nil)
+				m.CreateRoute(gomock.Any()).Times(0)
+				m.ReplaceRoute(gomock.Any()).Times(0)
+			},
+			expectedReason: infrav1.VPCPeeringRoutesConflictReason,
+		},
+		{
+			name: "route through a deleted target, replaces it",
+			input: &infrav1.NetworkSpec{
+				VPC: managedVPC,
+				PeeringConnections: []infrav1.VPCPeeringConnection{
+					{PeerVPCID: "vpc-shared"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				activeConnection(m)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								RouteTableId: aws.String("rtb-1"),
+								Routes: []*ec2.Route{
+									{
+										DestinationCidrBlock:   aws.String("172.16.0.0/16"),
+										VpcPeeringConnectionId: aws.String("pcx-old"),
+										State:                  aws.String(ec2.RouteStateBlackhole),
+									},
+								},
+							},
+						},
+					}, nil)
+				m.ReplaceRoute(gomock.Eq(&ec2.ReplaceRouteInput{
+					RouteTableId:           aws.String("rtb-1"),
+					DestinationCidrBlock:   aws.String("172.16.0.0/16"),
+					VpcPeeringConnectionId: aws.String("pcx-1"),
+				})).
+					Return(&ec2.ReplaceRouteOutput{}, nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						Region:      "us-east-1",
+						NetworkSpec: *tc.input,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			if err := s.reconcileVPCPeeringConnections(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+			if tc.expectedReason != "" {
+				if reason := conditions.GetReason(scope.InfraCluster(), infrav1.VPCPeeringConnectionsReadyCondition); reason != tc.expectedReason {
+					t.Fatalf("expected condition reason %q, got %q", tc.expectedReason, reason)
+				}
+			}
+		})
+	}
+}
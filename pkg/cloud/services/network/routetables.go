@@ -60,12 +60,21 @@ func (s *Service) reconcileRouteTables() error {
 				return errors.Errorf("failed to create routing tables: internet gateway for %q is nil", s.scope.VPC().ID)
 			}
 			routes = append(routes, s.getGatewayPublicRoute())
-		} else {
+			if sn.IsIPv6 {
+				routes = append(routes, s.getGatewayPublicIPv6Route())
+			}
+		} else if !s.scope.VPC().IsIsolated() {
 			natGatewayID, err := s.getNatGatewayForSubnet(&sn)
 			if err != nil {
 				return err
 			}
 			routes = append(routes, s.getNatGatewayPrivateRoute(natGatewayID))
+			if sn.IsIPv6 {
+				if !s.scope.VPC().IsIPv6Enabled() || s.scope.VPC().IPv6.EgressOnlyInternetGatewayID == nil {
+					return errors.Errorf("failed to create routing tables: egress only internet gateway for %q is nil", s.scope.VPC().ID)
+				}
+				routes = append(routes, s.getEgressOnlyInternetGatewayPrivateRoute(*s.scope.VPC().IPv6.EgressOnlyInternetGatewayID))
+			}
 		}
 
 		if rt, ok := subnetRouteMap[sn.ID]; ok {
@@ -80,15 +89,19 @@ func (s *Service) reconcileRouteTables() error {
 					// Routes destination cidr blocks must be unique within a routing table.
 					// If there is a mistmatch, we replace the routing association.
 					specRoute := routes[i]
-					if *currentRoute.DestinationCidrBlock == *specRoute.DestinationCidrBlock &&
-						((currentRoute.GatewayId != nil && *currentRoute.GatewayId != *specRoute.GatewayId) ||
-							(currentRoute.NatGatewayId != nil && *currentRoute.NatGatewayId != *specRoute.NatGatewayId)) {
+					if aws.StringValue(currentRoute.DestinationCidrBlock) == aws.StringValue(specRoute.DestinationCidrBlock) &&
+						aws.StringValue(currentRoute.DestinationIpv6CidrBlock) == aws.StringValue(specRoute.DestinationIpv6CidrBlock) &&
+						((currentRoute.GatewayId != nil && *currentRoute.GatewayId != aws.StringValue(specRoute.GatewayId)) ||
+							(currentRoute.NatGatewayId != nil && *currentRoute.NatGatewayId != aws.StringValue(specRoute.NatGatewayId)) ||
+							(currentRoute.EgressOnlyInternetGatewayId != nil && *currentRoute.EgressOnlyInternetGatewayId != aws.StringValue(specRoute.EgressOnlyInternetGatewayId))) {
 						if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
 							if _, err := s.EC2Client.ReplaceRoute(&ec2.ReplaceRouteInput{
-								RouteTableId:         rt.RouteTableId,
-								DestinationCidrBlock: specRoute.DestinationCidrBlock,
-								GatewayId:            specRoute.GatewayId,
-								NatGatewayId:         specRoute.NatGatewayId,
+								RouteTableId:                rt.RouteTableId,
+								DestinationCidrBlock:        specRoute.DestinationCidrBlock,
+								DestinationIpv6CidrBlock:    specRoute.DestinationIpv6CidrBlock,
+								EgressOnlyInternetGatewayId: specRoute.EgressOnlyInternetGatewayId,
+								GatewayId:                   specRoute.GatewayId,
+								NatGatewayId:                specRoute.NatGatewayId,
 							}); err != nil {
 								return false, err
 							}
@@ -295,6 +308,20 @@ func (s *Service) getGatewayPublicRoute() *ec2.Route {
 	}
 }
 
+func (s *Service) getGatewayPublicIPv6Route() *ec2.Route {
+	return &ec2.Route{
+		DestinationIpv6CidrBlock: aws.String(services.AnyIPv6CidrBlock),
+		GatewayId:                aws.String(*s.scope.VPC().InternetGatewayID),
+	}
+}
+
+func (s *Service) getEgressOnlyInternetGatewayPrivateRoute(egressOnlyInternetGatewayID string) *ec2.Route {
+	return &ec2.Route{
+		DestinationIpv6CidrBlock:    aws.String(services.AnyIPv6CidrBlock),
+		EgressOnlyInternetGatewayId: aws.String(egressOnlyInternetGatewayID),
+	}
+}
+
 func (s *Service) getRouteTableTagParams(id string, public bool, zone string) infrav1.BuildParams {
 	var name strings.Builder
 
@@ -36,6 +36,10 @@ type Scope interface {
 	Subnets() infrav1.Subnets
 	// SetSubnets updates the clusters subnets.
 	SetSubnets(subnets infrav1.Subnets)
+	// PeeringConnections returns the VPC peering connections of the cluster.
+	PeeringConnections() []infrav1.VPCPeeringConnection
+	// NetworkACLRules returns the rules of the network ACL of the cluster subnets.
+	NetworkACLRules() []infrav1.NetworkACLRule
 	// CNIIngressRules returns the CNI spec ingress rules.
 	CNIIngressRules() infrav1.CNIIngressRules
 	// SecurityGroups returns the cluster security groups as a map, it creates the map if empty.
@@ -45,6 +49,8 @@ type Scope interface {
 
 	// Bastion returns the bastion details for the cluster.
 	Bastion() *infrav1.Bastion
+	// ControlPlaneElasticIPs returns whether Elastic IPs are associated with control plane instances.
+	ControlPlaneElasticIPs() bool
 }
 
 // Service holds a collection of interfaces.
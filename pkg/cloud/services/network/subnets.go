@@ -17,7 +17,6 @@ limitations under the License.
 package network
 
 import (
-	"fmt"
 	"math/rand"
 	"sort"
 	"strings"
@@ -62,7 +61,7 @@ func (s *Service) reconcileSubnets() error {
 	if len(subnets) == 0 {
 		if unmanagedVPC {
 			// If we have a unmanaged VPC then subnets must be specified
-			errMsg := "no subnets specified, you must specify the subnets when using an umanaged vpc"
+			errMsg := "no subnets specified, you must specify the subnets when using an unmanaged vpc"
 			record.Warnf(s.scope.InfraCluster(), "FailedNoSubnets", errMsg)
 			return errors.New(errMsg)
 		}
@@ -132,19 +131,20 @@ func (s *Service) reconcileSubnets() error {
 			// TODO(vincepri): check if subnet needs to be updated.
 			existingSubnet.DeepCopyInto(sub)
 		} else if unmanagedVPC {
-			// If there is no existing subnet and we have an umanaged vpc report an error
-			record.Warnf(s.scope.InfraCluster(), "FailedMatchSubnet", "Using unmanaged VPC and failed to find existing subnet for specified subnet id %d, cidr %q", sub.ID, sub.CidrBlock)
-			return errors.New(fmt.Errorf("usign unmanaged vpc and subnet %s (cidr %s) specified but it doesn't exist in vpc %s", sub.ID, sub.CidrBlock, s.scope.VPC().ID).Error())
+			// If there is no existing subnet and we have an unmanaged vpc report an error
+			record.Warnf(s.scope.InfraCluster(), "FailedMatchSubnet", "Using unmanaged VPC and failed to find existing subnet for specified subnet id %q, cidr %q", sub.ID, sub.CidrBlock)
+			return errors.Errorf("using unmanaged vpc and subnet %s (cidr %s) specified but it doesn't exist in vpc %s", sub.ID, sub.CidrBlock, s.scope.VPC().ID)
 		}
 	}
 
 	if !unmanagedVPC {
-		// Check that we need at least 1 private and 1 public subnet after we have updated the metadata
+		// Check that we need at least 1 private and 1 public subnet after we have updated the metadata,
+		// an isolated VPC only has private subnets
 		if len(subnets.FilterPrivate()) < 1 {
 			record.Warnf(s.scope.InfraCluster(), "FailedNoPrivateSubnet", "Expected at least 1 private subnet but got 0")
 			return errors.New("expected at least 1 private subnet but got 0")
 		}
-		if len(subnets.FilterPublic()) < 1 {
+		if len(subnets.FilterPublic()) < 1 && !s.scope.VPC().IsIsolated() {
 			record.Warnf(s.scope.InfraCluster(), "FailedNoPublicSubnet", "Expected at least 1 public subnet but got 0")
 			return errors.New("expected at least 1 public subnet but got 0")
 		}
@@ -205,6 +205,24 @@ func (s *Service) getDefaultSubnets() (infrav1.Subnets, error) {
 		s.scope.V(2).Info("zones selected", "region", s.scope.Region(), "zones", zones)
 	}
 
+	// An isolated VPC only gets 1 private subnet for each AZ
+	if s.scope.VPC().IsIsolated() {
+		privateSubnetCIDRs, err := cidr.SplitIntoSubnetsIPv4(s.scope.VPC().CidrBlock, len(zones))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed splitting VPC CIDR %s into subnets", s.scope.VPC().CidrBlock)
+		}
+
+		subnets := infrav1.Subnets{}
+		for i, zone := range zones {
+			subnets = append(subnets, infrav1.SubnetSpec{
+				CidrBlock:        privateSubnetCIDRs[i].String(),
+				AvailabilityZone: zone,
+				IsPublic:         false,
+			})
+		}
+		return subnets, nil
+	}
+
 	// 1 private subnet for each AZ plus 1 other subnet that will be further sub-divided for the public subnets
 	numSubnets := len(zones) + 1
 	subnetCIDRs, err := cidr.SplitIntoSubnetsIPv4(s.scope.VPC().CidrBlock, numSubnets)
@@ -232,6 +250,18 @@ func (s *Service) getDefaultSubnets() (infrav1.Subnets, error) {
 		})
 	}
 
+	if s.scope.VPC().IsIPv6Enabled() {
+		ipv6CidrBlock := s.scope.VPC().IPv6.CidrBlock
+		ipv6SubnetCIDRs, err := cidr.SplitIntoSubnetsIPv6(ipv6CidrBlock, len(subnets))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed splitting VPC IPv6 CIDR %s into subnets", ipv6CidrBlock)
+		}
+		for i := range subnets {
+			subnets[i].IPv6CidrBlock = ipv6SubnetCIDRs[i].String()
+			subnets[i].IsIPv6 = true
+		}
+	}
+
 	return subnets, nil
 }
 
@@ -296,6 +326,14 @@ func (s *Service) describeVpcSubnets() (infrav1.Subnets, error) {
 			Tags:             converters.TagsToMap(ec2sn.Tags),
 		}
 
+		for _, association := range ec2sn.Ipv6CidrBlockAssociationSet {
+			if association.Ipv6CidrBlockState != nil && aws.StringValue(association.Ipv6CidrBlockState.State) == ec2.SubnetCidrBlockStateCodeAssociated {
+				spec.IPv6CidrBlock = aws.StringValue(association.Ipv6CidrBlock)
+				spec.IsIPv6 = true
+				break
+			}
+		}
+
 		// A subnet is public if it's tagged as such...
 		if spec.Tags.GetRole() == infrav1.PublicRoleTagValue {
 			spec.IsPublic = true
@@ -327,7 +365,7 @@ func (s *Service) describeVpcSubnets() (infrav1.Subnets, error) {
 }
 
 func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, error) {
-	out, err := s.EC2Client.CreateSubnet(&ec2.CreateSubnetInput{
+	input := &ec2.CreateSubnetInput{
 		VpcId:            aws.String(s.scope.VPC().ID),
 		CidrBlock:        aws.String(sn.CidrBlock),
 		AvailabilityZone: aws.String(sn.AvailabilityZone),
@@ -337,7 +375,12 @@ func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, err
 				s.getSubnetTagParams(services.TemporaryResourceID, sn.IsPublic, sn.AvailabilityZone, sn.Tags),
 			),
 		},
-	})
+	}
+	if sn.IPv6CidrBlock != "" {
+		input.Ipv6CidrBlock = aws.String(sn.IPv6CidrBlock)
+	}
+
+	out, err := s.EC2Client.CreateSubnet(input)
 	if err != nil {
 		record.Warnf(s.scope.InfraCluster(), "FailedCreateSubnet", "Failed creating new managed Subnet %v", err)
 		return nil, errors.Wrap(err, "failed to create subnet")
@@ -371,6 +414,26 @@ func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, err
 		record.Eventf(s.scope.InfraCluster(), "SuccessfulModifySubnetAttributes", "Modified managed Subnet %q attributes", *out.Subnet.SubnetId)
 	}
 
+	if sn.IPv6CidrBlock != "" {
+		attReq := &ec2.ModifySubnetAttributeInput{
+			AssignIpv6AddressOnCreation: &ec2.AttributeBooleanValue{
+				Value: aws.Bool(true),
+			},
+			SubnetId: out.Subnet.SubnetId,
+		}
+
+		if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+			if _, err := s.EC2Client.ModifySubnetAttribute(attReq); err != nil {
+				return false, err
+			}
+			return true, nil
+		}, awserrors.SubnetNotFound); err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedModifySubnetAttributes", "Failed modifying managed Subnet %q attributes: %v", *out.Subnet.SubnetId, err)
+			return nil, errors.Wrapf(err, "failed to set subnet %q attributes", *out.Subnet.SubnetId)
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulModifySubnetAttributes", "Modified managed Subnet %q attributes", *out.Subnet.SubnetId)
+	}
+
 	s.scope.V(2).Info("Created new subnet in VPC with cidr and availability zone ",
 		"subnet-id", *out.Subnet.SubnetId,
 		"vpc-id", *out.Subnet.VpcId,
@@ -381,6 +444,8 @@ func (s *Service) createSubnet(sn *infrav1.SubnetSpec) (*infrav1.SubnetSpec, err
 		ID:               *out.Subnet.SubnetId,
 		AvailabilityZone: *out.Subnet.AvailabilityZone,
 		CidrBlock:        *out.Subnet.CidrBlock,
+		IPv6CidrBlock:    sn.IPv6CidrBlock,
+		IsIPv6:           sn.IPv6CidrBlock != "",
 		IsPublic:         sn.IsPublic,
 	}, nil
 }
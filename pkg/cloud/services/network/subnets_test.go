@@ -537,6 +537,121 @@ func TestReconcileSubnets(t *testing.T) {
 					After(secondSubnet)
 			},
 		},
+		{
+			name: "Managed VPC with ipv6, no subnets exist, 1 ipv6 private and 1 public subnet in spec, create both",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: subnetsVPCID,
+					IPv6: &infrav1.IPv6{
+						CidrBlock: "2001:db8:1234:1a00::/56",
+					},
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+				Subnets: []infrav1.SubnetSpec{
+					{
+						AvailabilityZone: "us-east-1a",
+						CidrBlock:        "10.1.0.0/16",
+						IPv6CidrBlock:    "2001:db8:1234:1a01::/64",
+						IsPublic:         false,
+					},
+					{
+						AvailabilityZone: "us-east-1b",
+						CidrBlock:        "10.2.0.0/16",
+						IsPublic:         true,
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				describeCall := m.DescribeSubnets(gomock.AssignableToTypeOf(&ec2.DescribeSubnetsInput{})).
+					Return(&ec2.DescribeSubnetsOutput{}, nil)
+
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{}, nil)
+
+				m.DescribeNatGatewaysPages(gomock.AssignableToTypeOf(&ec2.DescribeNatGatewaysInput{}), gomock.Any()).
+					Return(nil)
+
+				firstSubnet := m.CreateSubnet(gomock.Eq(&ec2.CreateSubnetInput{
+					VpcId:            aws.String(subnetsVPCID),
+					CidrBlock:        aws.String("10.1.0.0/16"),
+					Ipv6CidrBlock:    aws.String("2001:db8:1234:1a01::/64"),
+					AvailabilityZone: aws.String("us-east-1a"),
+					TagSpecifications: []*ec2.TagSpecification{
+						{
+							ResourceType: aws.String("subnet"),
+							Tags: []*ec2.Tag{
+								{
+									Key:   aws.String("Name"),
+									Value: aws.String("test-cluster-subnet-private-us-east-1a"),
+								},
+								{
+									Key:   aws.String("kubernetes.io/cluster/test-cluster"),
+									Value: aws.String("shared"),
+								},
+								{
+									Key:   aws.String("kubernetes.io/role/internal-elb"),
+									Value: aws.String("1"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+									Value: aws.String("owned"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+									Value: aws.String("private"),
+								},
+							},
+						},
+					},
+				})).
+					Return(&ec2.CreateSubnetOutput{
+						Subnet: &ec2.Subnet{
+							VpcId:            aws.String(subnetsVPCID),
+							SubnetId:         aws.String("subnet-1"),
+							CidrBlock:        aws.String("10.1.0.0/16"),
+							AvailabilityZone: aws.String("us-east-1a"),
+						},
+					}, nil).
+					After(describeCall)
+
+				m.WaitUntilSubnetAvailable(gomock.Any()).
+					After(firstSubnet)
+
+				m.ModifySubnetAttribute(&ec2.ModifySubnetAttributeInput{
+					AssignIpv6AddressOnCreation: &ec2.AttributeBooleanValue{
+						Value: aws.Bool(true),
+					},
+					SubnetId: aws.String("subnet-1"),
+				}).
+					Return(&ec2.ModifySubnetAttributeOutput{}, nil).
+					After(firstSubnet)
+
+				secondSubnet := m.CreateSubnet(gomock.AssignableToTypeOf(&ec2.CreateSubnetInput{})).
+					Return(&ec2.CreateSubnetOutput{
+						Subnet: &ec2.Subnet{
+							VpcId:            aws.String(subnetsVPCID),
+							SubnetId:         aws.String("subnet-2"),
+							CidrBlock:        aws.String("10.2.0.0/16"),
+							AvailabilityZone: aws.String("us-east-1b"),
+						},
+					}, nil).
+					After(firstSubnet)
+
+				m.WaitUntilSubnetAvailable(gomock.Any()).
+					After(secondSubnet)
+
+				m.ModifySubnetAttribute(&ec2.ModifySubnetAttributeInput{
+					MapPublicIpOnLaunch: &ec2.AttributeBooleanValue{
+						Value: aws.Bool(true),
+					},
+					SubnetId: aws.String("subnet-2"),
+				}).
+					Return(&ec2.ModifySubnetAttributeOutput{}, nil).
+					After(secondSubnet)
+			},
+		},
 		{
 			name: "Managed VPC, no subnets exist, 1 private subnet in spec (no public subnet), should fail",
 			input: &infrav1.NetworkSpec{
@@ -597,7 +712,7 @@ func TestReconcileSubnets(t *testing.T) {
 					Tags: infrav1.Tags{
 						infrav1.ClusterTagKey("test-cluster"): "owned",
 					},
-					CidrBlock: defaultVPCCidr,
+					CidrBlock: DefaultVPCCidr,
 				},
 				Subnets: []infrav1.SubnetSpec{},
 			},
@@ -753,7 +868,7 @@ func TestReconcileSubnets(t *testing.T) {
 					Tags: infrav1.Tags{
 						infrav1.ClusterTagKey("test-cluster"): "owned",
 					},
-					CidrBlock: defaultVPCCidr,
+					CidrBlock: DefaultVPCCidr,
 				},
 				Subnets: []infrav1.SubnetSpec{},
 			},
@@ -1015,7 +1130,7 @@ func TestReconcileSubnets(t *testing.T) {
 					Tags: infrav1.Tags{
 						infrav1.ClusterTagKey("test-cluster"): "owned",
 					},
-					CidrBlock:                  defaultVPCCidr,
+					CidrBlock:                  DefaultVPCCidr,
 					AvailabilityZoneUsageLimit: aws.Int(1),
 					AvailabilityZoneSelection:  &infrav1.AZSelectionSchemeOrdered,
 				},
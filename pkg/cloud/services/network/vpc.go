@@ -36,7 +36,8 @@ import (
 )
 
 const (
-	defaultVPCCidr = "10.0.0.0/16"
+	// DefaultVPCCidr is the CIDR block of a managed VPC that doesn't set one.
+	DefaultVPCCidr = "10.0.0.0/16"
 )
 
 func (s *Service) reconcileVPC() error {
@@ -51,6 +52,9 @@ func (s *Service) reconcileVPC() error {
 
 		s.scope.VPC().CidrBlock = vpc.CidrBlock
 		s.scope.VPC().Tags = vpc.Tags
+		if vpc.IPv6 != nil && s.scope.VPC().IsIPv6Enabled() {
+			s.scope.VPC().IPv6.CidrBlock = vpc.IPv6.CidrBlock
+		}
 
 		// If VPC is unmanaged, return early.
 		if vpc.IsUnmanaged(s.scope.Name()) {
@@ -58,7 +62,7 @@ func (s *Service) reconcileVPC() error {
 			if err := s.scope.PatchObject(); err != nil {
 				return errors.Wrap(err, "failed to patch unmanaged VPC fields")
 			}
-			record.Eventf(s.scope.InfraCluster(), "SuccessfulSetVPCAttributes", "Set managed VPC attributes for %q", vpc.ID)
+			record.Eventf(s.scope.InfraCluster(), "SuccessfulSetVPCAttributes", "Set unmanaged VPC attributes for %q", vpc.ID)
 			return nil
 		}
 
@@ -90,6 +94,9 @@ func (s *Service) reconcileVPC() error {
 	s.scope.VPC().CidrBlock = vpc.CidrBlock
 	s.scope.VPC().Tags = vpc.Tags
 	s.scope.VPC().ID = vpc.ID
+	if vpc.IPv6 != nil {
+		s.scope.VPC().IPv6.CidrBlock = vpc.IPv6.CidrBlock
+	}
 
 	// Make sure attributes are configured
 	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
@@ -171,7 +178,7 @@ func (s *Service) ensureManagedVPCAttributes(vpc *infrav1.VPCSpec) error {
 
 func (s *Service) createVPC() (*infrav1.VPCSpec, error) {
 	if s.scope.VPC().CidrBlock == "" {
-		s.scope.VPC().CidrBlock = defaultVPCCidr
+		s.scope.VPC().CidrBlock = DefaultVPCCidr
 	}
 
 	input := &ec2.CreateVpcInput{
@@ -181,6 +188,10 @@ func (s *Service) createVPC() (*infrav1.VPCSpec, error) {
 		},
 	}
 
+	if s.scope.VPC().IsIPv6Enabled() {
+		input.AmazonProvidedIpv6CidrBlock = aws.Bool(true)
+	}
+
 	out, err := s.EC2Client.CreateVpc(input)
 	if err != nil {
 		record.Warnf(s.scope.InfraCluster(), "FailedCreateVPC", "Failed to create new managed VPC: %v", err)
@@ -190,11 +201,54 @@ func (s *Service) createVPC() (*infrav1.VPCSpec, error) {
 	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateVPC", "Created new managed VPC %q", *out.Vpc.VpcId)
 	s.scope.V(2).Info("Created new VPC with cidr", "vpc-id", *out.Vpc.VpcId, "cidr-block", *out.Vpc.CidrBlock)
 
-	return &infrav1.VPCSpec{
+	vpc := &infrav1.VPCSpec{
 		ID:        *out.Vpc.VpcId,
 		CidrBlock: *out.Vpc.CidrBlock,
 		Tags:      converters.TagsToMap(out.Vpc.Tags),
-	}, nil
+	}
+
+	if s.scope.VPC().IsIPv6Enabled() {
+		// The IPv6 CIDR block is allocated asynchronously, so wait for the association to become available.
+		ipv6CidrBlock, err := s.waitForVPCIPv6CidrBlock(vpc.ID)
+		if err != nil {
+			return nil, err
+		}
+		vpc.IPv6 = &infrav1.IPv6{CidrBlock: ipv6CidrBlock}
+	}
+
+	return vpc, nil
+}
+
+func (s *Service) waitForVPCIPv6CidrBlock(vpcID string) (string, error) {
+	var ipv6CidrBlock string
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		out, err := s.EC2Client.DescribeVpcs(&ec2.DescribeVpcsInput{
+			VpcIds: []*string{aws.String(vpcID)},
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(out.Vpcs) == 0 {
+			return false, nil
+		}
+		ipv6CidrBlock = getVPCIPv6CidrBlock(out.Vpcs[0])
+		return ipv6CidrBlock != "", nil
+	}, awserrors.VPCNotFound); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedAssociateVPCIPv6CidrBlock", "Failed waiting for IPv6 CIDR block of managed VPC %q: %v", vpcID, err)
+		return "", errors.Wrapf(err, "failed to wait for IPv6 CIDR block of vpc %q", vpcID)
+	}
+
+	return ipv6CidrBlock, nil
+}
+
+// getVPCIPv6CidrBlock returns the first associated IPv6 CIDR block of the VPC, if any.
+func getVPCIPv6CidrBlock(vpc *ec2.Vpc) string {
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState != nil && aws.StringValue(association.Ipv6CidrBlockState.State) == ec2.VpcCidrBlockStateCodeAssociated {
+			return aws.StringValue(association.Ipv6CidrBlock)
+		}
+	}
+	return ""
 }
 
 func (s *Service) deleteVPC() error {
@@ -258,11 +312,17 @@ func (s *Service) describeVPCByID() (*infrav1.VPCSpec, error) {
 		return nil, awserrors.NewNotFound("could not find available or pending vpc")
 	}
 
-	return &infrav1.VPCSpec{
+	vpc := &infrav1.VPCSpec{
 		ID:        *out.Vpcs[0].VpcId,
 		CidrBlock: *out.Vpcs[0].CidrBlock,
 		Tags:      converters.TagsToMap(out.Vpcs[0].Tags),
-	}, nil
+	}
+
+	if ipv6CidrBlock := getVPCIPv6CidrBlock(out.Vpcs[0]); ipv6CidrBlock != "" {
+		vpc.IPv6 = &infrav1.IPv6{CidrBlock: ipv6CidrBlock}
+	}
+
+	return vpc, nil
 }
 
 func (s *Service) getVPCTagParams(id string) infrav1.BuildParams {
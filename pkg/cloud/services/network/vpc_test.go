@@ -179,6 +179,109 @@ func TestReconcileVPC(t *testing.T) {
 					Return(&ec2.ModifyVpcAttributeOutput{}, nil).Times(2)
 			},
 		},
+		{
+			name:        "if managed vpc with ipv6 does not exist, creates a new VPC and waits for its IPv6 CIDR block",
+			input:       &infrav1.VPCSpec{IPv6: &infrav1.IPv6{}, AvailabilityZoneUsageLimit: &usageLimit, AvailabilityZoneSelection: &selection},
+			expectError: false,
+			expected: &infrav1.VPCSpec{
+				ID:        "vpc-new",
+				CidrBlock: "10.1.0.0/16",
+				IPv6: &infrav1.IPv6{
+					CidrBlock: "2001:db8:1234:1a00::/56",
+				},
+				Tags: map[string]string{
+					"sigs.k8s.io/cluster-api-provider-aws/role": "common",
+					"Name": "test-cluster-vpc",
+					"sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster": "owned",
+				},
+				AvailabilityZoneUsageLimit: &usageLimit,
+				AvailabilityZoneSelection:  &selection,
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.CreateVpc(gomock.Eq(&ec2.CreateVpcInput{
+					CidrBlock:                   aws.String("10.0.0.0/16"),
+					AmazonProvidedIpv6CidrBlock: aws.Bool(true),
+					TagSpecifications: []*ec2.TagSpecification{
+						{
+							ResourceType: aws.String("vpc"),
+							Tags: []*ec2.Tag{
+								{
+									Key:   aws.String("Name"),
+									Value: aws.String("test-cluster-vpc"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+									Value: aws.String("owned"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+									Value: aws.String("common"),
+								},
+							},
+						},
+					},
+				})).
+					Return(&ec2.CreateVpcOutput{
+						Vpc: &ec2.Vpc{
+							State:     aws.String("available"),
+							VpcId:     aws.String("vpc-new"),
+							CidrBlock: aws.String("10.1.0.0/16"),
+							Tags: []*ec2.Tag{
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/role"),
+									Value: aws.String("common"),
+								},
+								{
+									Key:   aws.String("Name"),
+									Value: aws.String("test-cluster-vpc"),
+								},
+								{
+									Key:   aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"),
+									Value: aws.String("owned"),
+								},
+							},
+						},
+					}, nil)
+
+				associating := m.DescribeVpcs(gomock.Eq(&ec2.DescribeVpcsInput{
+					VpcIds: []*string{aws.String("vpc-new")},
+				})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{
+							{
+								VpcId: aws.String("vpc-new"),
+								Ipv6CidrBlockAssociationSet: []*ec2.VpcIpv6CidrBlockAssociation{
+									{
+										Ipv6CidrBlock:      aws.String("2001:db8:1234:1a00::/56"),
+										Ipv6CidrBlockState: &ec2.VpcCidrBlockState{State: aws.String(ec2.VpcCidrBlockStateCodeAssociating)},
+									},
+								},
+							},
+						},
+					}, nil)
+
+				m.DescribeVpcs(gomock.Eq(&ec2.DescribeVpcsInput{
+					VpcIds: []*string{aws.String("vpc-new")},
+				})).
+					Return(&ec2.DescribeVpcsOutput{
+						Vpcs: []*ec2.Vpc{
+							{
+								VpcId: aws.String("vpc-new"),
+								Ipv6CidrBlockAssociationSet: []*ec2.VpcIpv6CidrBlockAssociation{
+									{
+										Ipv6CidrBlock:      aws.String("2001:db8:1234:1a00::/56"),
+										Ipv6CidrBlockState: &ec2.VpcCidrBlockState{State: aws.String(ec2.VpcCidrBlockStateCodeAssociated)},
+									},
+								},
+							},
+						},
+					}, nil).
+					After(associating)
+
+				m.DescribeVpcAttribute(gomock.AssignableToTypeOf(&ec2.DescribeVpcAttributeInput{})).
+					DoAndReturn(describeVpcAttributeTrue).MinTimes(1)
+			},
+		},
 		{
 			name:        "managed vpc id exists, but vpc resource is missing",
 			input:       &infrav1.VPCSpec{ID: "vpc-exists", AvailabilityZoneUsageLimit: &usageLimit, AvailabilityZoneSelection: &selection},
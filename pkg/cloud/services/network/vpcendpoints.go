@@ -0,0 +1,308 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/filter"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/wait"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/tags"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// resourceTypeVPCEndpoint is the tag specification resource type of VPC endpoints, which the
+// AWS SDK doesn't have an enum value for yet.
+const resourceTypeVPCEndpoint = "vpc-endpoint"
+
+var (
+	// interfaceEndpointServices are the AWS APIs instances in an isolated VPC depend on: EC2 and ELB
+	// for the cloud provider, STS for IAM roles, ECR for images and SSM and Secrets Manager for bootstrap data.
+	interfaceEndpointServices = []string{
+		"ec2",
+		"ecr.api",
+		"ecr.dkr",
+		"elasticloadbalancing",
+		"secretsmanager",
+		"ssm",
+		"sts",
+	}
+
+	// gatewayEndpointServices are reached through the route tables rather than a network interface.
+	// ECR stores image layers in S3.
+	gatewayEndpointServices = []string{
+		"s3",
+	}
+)
+
+func (s *Service) reconcileVPCEndpoints() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC endpoints reconcile in unmanaged mode")
+		return nil
+	}
+
+	if !s.scope.VPC().IsIsolated() {
+		s.scope.V(4).Info("Skipping VPC endpoints reconcile, VPC is not isolated")
+		return nil
+	}
+
+	s.scope.V(2).Info("Reconciling VPC endpoints")
+
+	endpoints, err := s.describeVPCEndpoints()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*ec2.VpcEndpoint)
+	for _, endpoint := range endpoints {
+		switch aws.StringValue(endpoint.State) {
+		case "deleting", "failed", "rejected", "expired":
+			continue
+		}
+		existing[aws.StringValue(endpoint.ServiceName)] = endpoint
+	}
+
+	// Interface endpoints get a network interface in one private subnet of every availability zone.
+	var subnetIDs []string
+	zones := map[string]bool{}
+	for _, sn := range s.scope.Subnets().FilterPrivate() {
+		if sn.ID == "" || zones[sn.AvailabilityZone] {
+			continue
+		}
+		zones[sn.AvailabilityZone] = true
+		subnetIDs = append(subnetIDs, sn.ID)
+	}
+
+	routeTables, err := s.describeVpcRouteTablesBySubnet()
+	if err != nil {
+		return err
+	}
+	var routeTableIDs []string
+	for _, sn := range s.scope.Subnets().FilterPrivate() {
+		if rt, ok := routeTables[sn.ID]; ok {
+			routeTableIDs = append(routeTableIDs, aws.StringValue(rt.RouteTableId))
+		}
+	}
+
+	authorized := false
+	for _, service := range interfaceEndpointServices {
+		serviceName := s.getVPCEndpointServiceName(service)
+		if _, ok := existing[serviceName]; ok {
+			continue
+		}
+		if !authorized {
+			if err := s.authorizeVPCEndpointIngress(); err != nil {
+				return err
+			}
+			authorized = true
+		}
+		if err := s.createVPCEndpoint(service, &ec2.CreateVpcEndpointInput{
+			VpcEndpointType:   aws.String(ec2.VpcEndpointTypeInterface),
+			ServiceName:       aws.String(serviceName),
+			SubnetIds:         aws.StringSlice(subnetIDs),
+			PrivateDnsEnabled: aws.Bool(true),
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, service := range gatewayEndpointServices {
+		serviceName := s.getVPCEndpointServiceName(service)
+		if _, ok := existing[serviceName]; ok {
+			continue
+		}
+		if err := s.createVPCEndpoint(service, &ec2.CreateVpcEndpointInput{
+			VpcEndpointType: aws.String(ec2.VpcEndpointTypeGateway),
+			ServiceName:     aws.String(serviceName),
+			RouteTableIds:   aws.StringSlice(routeTableIDs),
+		}); err != nil {
+			return err
+		}
+	}
+
+	conditions.MarkTrue(s.scope.InfraCluster(), infrav1.VPCEndpointsReadyCondition)
+	return nil
+}
+
+func (s *Service) deleteVPCEndpoints() error {
+	if s.scope.VPC().IsUnmanaged(s.scope.Name()) {
+		s.scope.V(4).Info("Skipping VPC endpoints deletion in unmanaged mode")
+		return nil
+	}
+
+	existing, err := s.describeVPCEndpoints()
+	if err != nil {
+		return err
+	}
+
+	var ids []*string
+	for _, endpoint := range existing {
+		if aws.StringValue(endpoint.State) != "deleting" {
+			ids = append(ids, endpoint.VpcEndpointId)
+		}
+	}
+
+	if len(ids) > 0 {
+
+		out, err := s.EC2Client.DeleteVpcEndpoints(&ec2.DeleteVpcEndpointsInput{
+			VpcEndpointIds: ids,
+		})
+		if err == nil && len(out.Unsuccessful) > 0 {
+			err = errors.New(aws.StringValue(out.Unsuccessful[0].Error.Message))
+		}
+		if err != nil {
+			record.Warnf(s.scope.InfraCluster(), "FailedDeleteVPCEndpoints", "Failed to delete managed VPC endpoints: %v", err)
+			return errors.Wrapf(err, "failed to delete VPC endpoints in vpc %q", s.scope.VPC().ID)
+		}
+		record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteVPCEndpoints", "Deleted managed VPC endpoints %v", aws.StringValueSlice(ids))
+	}
+
+	if len(existing) == 0 {
+		return nil
+	}
+
+	// The network interfaces of the interface endpoints keep the subnets in use until they are gone.
+	if err := wait.WaitForWithRetryable(wait.NewBackoff(), func() (bool, error) {
+		remaining, err := s.describeVPCEndpoints()
+		if err != nil {
+			return false, err
+		}
+		return len(remaining) == 0, nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed waiting for VPC endpoints in vpc %q to be deleted", s.scope.VPC().ID)
+	}
+
+	return nil
+}
+
+// describeVPCEndpoints returns the VPC endpoints owned by the cluster that have not been deleted yet.
+func (s *Service) describeVPCEndpoints() ([]*ec2.VpcEndpoint, error) {
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.ClusterOwned(s.scope.Name()),
+		},
+	}
+
+	var endpoints []*ec2.VpcEndpoint
+	err := s.EC2Client.DescribeVpcEndpointsPages(input, func(page *ec2.DescribeVpcEndpointsOutput, lastPage bool) bool {
+		for _, endpoint := range page.VpcEndpoints {
+			if aws.StringValue(endpoint.State) != "deleted" {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		record.Eventf(s.scope.InfraCluster(), "FailedDescribeVPCEndpoints", "Failed to describe VPC endpoints in vpc %q: %v", s.scope.VPC().ID, err)
+		return nil, errors.Wrapf(err, "failed to describe VPC endpoints in vpc %q", s.scope.VPC().ID)
+	}
+
+	return endpoints, nil
+}
+
+func (s *Service) createVPCEndpoint(service string, input *ec2.CreateVpcEndpointInput) error {
+	input.VpcId = aws.String(s.scope.VPC().ID)
+	input.TagSpecifications = []*ec2.TagSpecification{
+		tags.BuildParamsToTagSpecification(resourceTypeVPCEndpoint, s.getVPCEndpointTagParams(services.TemporaryResourceID, service)),
+	}
+
+	out, err := s.EC2Client.CreateVpcEndpoint(input)
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateVPCEndpoint", "Failed to create managed VPC endpoint for %q: %v", aws.StringValue(input.ServiceName), err)
+		return errors.Wrapf(err, "failed to create VPC endpoint for %q in vpc %q", aws.StringValue(input.ServiceName), s.scope.VPC().ID)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateVPCEndpoint", "Created managed VPC endpoint %q for %q", aws.StringValue(out.VpcEndpoint.VpcEndpointId), aws.StringValue(input.ServiceName))
+	return nil
+}
+
+// authorizeVPCEndpointIngress allows HTTPS from the whole VPC to the default security group of the VPC,
+// which interface endpoints use when no other security group is given.
+func (s *Service) authorizeVPCEndpointIngress() error {
+	out, err := s.EC2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			{Name: aws.String("group-name"), Values: aws.StringSlice([]string{"default"})},
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to describe default security group of vpc %q", s.scope.VPC().ID)
+	}
+	if len(out.SecurityGroups) == 0 {
+		return errors.Errorf("no default security group found in vpc %q", s.scope.VPC().ID)
+	}
+	groupID := out.SecurityGroups[0].GroupId
+
+	cidrBlocks := []string{s.scope.VPC().CidrBlock}
+	if s.scope.SecondaryCidrBlock() != nil {
+		cidrBlocks = append(cidrBlocks, *s.scope.SecondaryCidrBlock())
+	}
+	ipRanges := make([]*ec2.IpRange, 0, len(cidrBlocks))
+	for _, cidr := range cidrBlocks {
+		ipRanges = append(ipRanges, &ec2.IpRange{
+			CidrIp:      aws.String(cidr),
+			Description: aws.String("HTTPS to VPC endpoints"),
+		})
+	}
+
+	if _, err := s.EC2Client.AuthorizeSecurityGroupIngress(&ec2.AuthorizeSecurityGroupIngressInput{
+		GroupId: groupID,
+		IpPermissions: []*ec2.IpPermission{
+			{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int64(443),
+				ToPort:     aws.Int64(443),
+				IpRanges:   ipRanges,
+			},
+		},
+	}); err != nil {
+		if code, ok := awserrors.Code(errors.Cause(err)); ok && code == awserrors.PermissionDuplicate {
+			return nil
+		}
+		record.Warnf(s.scope.InfraCluster(), "FailedAuthorizeSecurityGroupIngressRules", "Failed to authorize HTTPS ingress for SecurityGroup %q: %v", aws.StringValue(groupID), err)
+		return errors.Wrapf(err, "failed to authorize security group %q ingress rules", aws.StringValue(groupID))
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulAuthorizeSecurityGroupIngressRules", "Authorized HTTPS ingress to VPC endpoints for SecurityGroup %q", aws.StringValue(groupID))
+	return nil
+}
+
+// getVPCEndpointServiceName returns the name of the endpoint service of an AWS API in the cluster's region.
+func (s *Service) getVPCEndpointServiceName(service string) string {
+	return fmt.Sprintf("com.amazonaws.%s.%s", s.scope.Region(), service)
+}
+
+func (s *Service) getVPCEndpointTagParams(id, service string) infrav1.BuildParams {
+	name := fmt.Sprintf("%s-vpce-%s", s.scope.Name(), service)
+
+	return infrav1.BuildParams{
+		ClusterName: s.scope.Name(),
+		ResourceID:  id,
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Name:        aws.String(name),
+		Role:        aws.String(infrav1.CommonRoleTagValue),
+		Additional:  s.scope.AdditionalTags(),
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/services/ec2/mock_ec2iface"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+func TestReconcileVPCEndpoints(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	existingEndpoints := func() []*ec2.VpcEndpoint {
+		var endpoints []*ec2.VpcEndpoint
+		for _, service := range append(interfaceEndpointServices, gatewayEndpointServices...) {
+			endpoints = append(endpoints, &ec2.VpcEndpoint{
+				VpcEndpointId: aws.String("vpce-" + service),
+				ServiceName:   aws.String("com.amazonaws.us-east-1." + service),
+				State:         aws.String("available"),
+			})
+		}
+		return endpoints
+	}
+
+	testCases := []struct {
+		name   string
+		input  *infrav1.NetworkSpec
+		expect func(m *mock_ec2iface.MockEC2APIMockRecorder)
+	}{
+		{
+			name: "vpc is not isolated, does nothing",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: "vpc-endpoints",
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcEndpointsPages(gomock.Any(), gomock.Any()).Times(0)
+				m.CreateVpcEndpoint(gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "isolated vpc without endpoints, creates them",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID:        "vpc-endpoints",
+					CidrBlock: "10.0.0.0/16",
+					Isolated:  true,
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+				Subnets: infrav1.Subnets{
+					{ID: "subnet-1", AvailabilityZone: "us-east-1a"},
+					{ID: "subnet-2", AvailabilityZone: "us-east-1a"},
+					{ID: "subnet-3", AvailabilityZone: "us-east-1b"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcEndpointsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcEndpointsInput{}), gomock.Any()).
+					Return(nil)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{
+						RouteTables: []*ec2.RouteTable{
+							{
+								RouteTableId: aws.String("rtb-1"),
+								Associations: []*ec2.RouteTableAssociation{
+									{SubnetId: aws.String("subnet-1")},
+								},
+							},
+						},
+					}, nil)
+				m.DescribeSecurityGroups(gomock.AssignableToTypeOf(&ec2.DescribeSecurityGroupsInput{})).
+					Return(&ec2.DescribeSecurityGroupsOutput{
+						SecurityGroups: []*ec2.SecurityGroup{
+							{GroupId: aws.String("sg-default")},
+						},
+					}, nil)
+				m.AuthorizeSecurityGroupIngress(gomock.AssignableToTypeOf(&ec2.AuthorizeSecurityGroupIngressInput{})).
+					Return(&ec2.AuthorizeSecurityGroupIngressOutput{}, nil)
+				m.CreateVpcEndpoint(gomock.AssignableToTypeOf(&ec2.CreateVpcEndpointInput{})).
+					DoAndReturn(func(input *ec2.CreateVpcEndpointInput) (*ec2.CreateVpcEndpointOutput, error) {
+						switch aws.StringValue(input.VpcEndpointType) {
+						case ec2.VpcEndpointTypeInterface:
+							if got := aws.StringValueSlice(input.SubnetIds); len(got) != 2 || got[0] != "subnet-1" || got[1] != "subnet-3" {
+								t.Fatalf("expected one subnet per availability zone, got %v", got)
+							}
+						case ec2.VpcEndpointTypeGateway:
+							if got := aws.StringValueSlice(input.RouteTableIds); len(got) != 1 || got[0] != "rtb-1" {
+								t.Fatalf("expected the route tables of the private subnets, got %v", got)
+							}
+						}
+						return &ec2.CreateVpcEndpointOutput{
+							VpcEndpoint: &ec2.VpcEndpoint{VpcEndpointId: aws.String("vpce-new")},
+						}, nil
+					}).
+					Times(len(interfaceEndpointServices) + len(gatewayEndpointServices))
+			},
+		},
+		{
+			name: "isolated vpc with endpoints, does not create any",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID:        "vpc-endpoints",
+					CidrBlock: "10.0.0.0/16",
+					Isolated:  true,
+					Tags: infrav1.Tags{
+						infrav1.ClusterTagKey("test-cluster"): "owned",
+					},
+				},
+				Subnets: infrav1.Subnets{
+					{ID: "subnet-1", AvailabilityZone: "us-east-1a"},
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeVpcEndpointsPages(gomock.AssignableToTypeOf(&ec2.DescribeVpcEndpointsInput{}), gomock.Any()).
+					Do(func(_ *ec2.DescribeVpcEndpointsInput, fn func(*ec2.DescribeVpcEndpointsOutput, bool) bool) {
+						fn(&ec2.DescribeVpcEndpointsOutput{VpcEndpoints: existingEndpoints()}, true)
+					}).
+					Return(nil)
+				m.DescribeRouteTables(gomock.AssignableToTypeOf(&ec2.DescribeRouteTablesInput{})).
+					Return(&ec2.DescribeRouteTablesOutput{}, nil)
+				m.AuthorizeSecurityGroupIngress(gomock.Any()).Times(0)
+				m.CreateVpcEndpoint(gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec2Mock := mock_ec2iface.NewMockEC2API(mockCtrl)
+
+			scheme := runtime.NewScheme()
+			_ = infrav1.AddToScheme(scheme)
+			client := fake.NewClientBuilder().WithScheme(scheme).Build()
+			scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+				Client: client,
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				},
+				AWSCluster: &infrav1.AWSCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "test"},
+					Spec: infrav1.AWSClusterSpec{
+						Region:      "us-east-1",
+						NetworkSpec: *tc.input,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create test context: %v", err)
+			}
+
+			tc.expect(ec2Mock.EXPECT())
+
+			s := NewService(scope)
+			s.EC2Client = ec2Mock
+
+			if err := s.reconcileVPCEndpoints(); err != nil {
+				t.Fatalf("got an unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route53
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// ReconcileControlPlaneDNS creates or updates the Route53 alias record pointing at the API server
+// load balancer and returns its name. It also returns whether the record has propagated to all
+// Route53 name servers: the ID of a pending change is kept in the cluster network status, and
+// callers should requeue until the change is in sync rather than use the name as the endpoint.
+func (s *Service) ReconcileControlPlaneDNS() (string, bool, error) {
+	spec := s.scope.ControlPlaneDNS()
+
+	hostedZoneID, zoneName, err := s.reconcileHostedZone(spec)
+	if err != nil {
+		return "", false, err
+	}
+	name := recordName(s.scope.Name(), spec, zoneName)
+
+	if changeID := s.scope.Network().ControlPlaneDNSChangeID; changeID != "" {
+		inSync, err := s.isChangeInSync(changeID)
+		if err != nil {
+			return "", false, err
+		}
+		if !inSync {
+			s.scope.V(2).Info("Waiting for control plane DNS record to propagate", "name", name, "change-id", changeID)
+			return name, false, nil
+		}
+		s.scope.Network().ControlPlaneDNSChangeID = ""
+	}
+
+	target, err := s.loadBalancerAliasTarget()
+	if err != nil {
+		return "", false, err
+	}
+
+	existing, err := s.describeRecord(hostedZoneID, name)
+	if err != nil {
+		return "", false, err
+	}
+	if existing != nil && aliasTargetMatches(existing.AliasTarget, target) {
+		return name, true, nil
+	}
+
+	s.scope.V(2).Info("Pointing control plane DNS record at API server load balancer", "name", name, "hosted-zone-id", hostedZoneID)
+	out, err := s.Route53Client.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Comment: aws.String(fmt.Sprintf("API server endpoint of cluster %s", s.scope.Name())),
+			Changes: []*route53.Change{
+				{
+					Action: aws.String(route53.ChangeActionUpsert),
+					ResourceRecordSet: &route53.ResourceRecordSet{
+						Name:        aws.String(name),
+						Type:        aws.String(route53.RRTypeA),
+						AliasTarget: target,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedUpsertDNSRecord", "Failed to point control plane DNS record %q at the API server load balancer: %v", name, err)
+		return "", false, errors.Wrapf(err, "failed to upsert control plane DNS record %q", name)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulUpsertDNSRecord", "Pointed control plane DNS record %q at the API server load balancer", name)
+	if aws.StringValue(out.ChangeInfo.Status) == route53.ChangeStatusInsync {
+		return name, true, nil
+	}
+
+	s.scope.Network().ControlPlaneDNSChangeID = aws.StringValue(out.ChangeInfo.Id)
+	return name, false, nil
+}
+
+// isChangeInSync returns true if the Route53 change with the given ID has propagated to all Route53
+// name servers. Route53 only keeps changes for a while, so a change it no longer knows about is in sync.
+func (s *Service) isChangeInSync(changeID string) (bool, error) {
+	out, err := s.Route53Client.GetChange(&route53.GetChangeInput{
+		Id: aws.String(changeID),
+	})
+	if err != nil {
+		if code, ok := awserrors.Code(errors.Cause(err)); ok && code == route53.ErrCodeNoSuchChange {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to get Route53 change %q", changeID)
+	}
+
+	return aws.StringValue(out.ChangeInfo.Status) == route53.ChangeStatusInsync, nil
+}
+
+// DeleteControlPlaneDNS deletes the Route53 alias record pointing at the API server load balancer,
+// along with the private hosted zone created for it. Records that point anywhere else are left alone.
+func (s *Service) DeleteControlPlaneDNS() error {
+	spec := s.scope.ControlPlaneDNS()
+	if spec == nil {
+		return nil
+	}
+
+	hostedZoneID, zoneName, err := s.describeHostedZone(spec)
+	if err != nil {
+		if isHostedZoneNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if hostedZoneID == "" {
+		return nil
+	}
+
+	if err := s.deleteRecord(hostedZoneID, recordName(s.scope.Name(), spec, zoneName)); err != nil {
+		return err
+	}
+
+	if spec.PrivateZoneName == "" {
+		return nil
+	}
+
+	return s.deletePrivateZone(hostedZoneID)
+}
+
+// deleteRecord deletes the A record with the given name if it is an alias of the API server load balancer.
+func (s *Service) deleteRecord(hostedZoneID, name string) error {
+	existing, err := s.describeRecord(hostedZoneID, name)
+	if err != nil {
+		return err
+	}
+
+	lbDNSName := s.scope.Network().APIServerELB.DNSName
+	if existing == nil || existing.AliasTarget == nil || lbDNSName == "" ||
+		normalizeDNSName(aws.StringValue(existing.AliasTarget.DNSName)) != normalizeDNSName(lbDNSName) {
+		return nil
+	}
+
+	s.scope.V(2).Info("Deleting control plane DNS record", "name", name, "hosted-zone-id", hostedZoneID)
+	if _, err := s.Route53Client.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{
+				{
+					Action:            aws.String(route53.ChangeActionDelete),
+					ResourceRecordSet: existing,
+				},
+			},
+		},
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteDNSRecord", "Failed to delete control plane DNS record %q: %v", name, err)
+		return errors.Wrapf(err, "failed to delete control plane DNS record %q", name)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteDNSRecord", "Deleted control plane DNS record %q", name)
+	return nil
+}
+
+// recordName returns the fully qualified name of the record, without the trailing dot.
+// It defaults to api.<cluster name>.<hosted zone domain name>.
+func recordName(clusterName string, spec *infrav1.ControlPlaneDNS, zoneName string) string {
+	if spec.Name != "" {
+		return normalizeDNSName(spec.Name)
+	}
+
+	return fmt.Sprintf("api.%s.%s", clusterName, normalizeDNSName(zoneName))
+}
+
+// describeRecord returns the A record with the given name, or nil if there is none.
+func (s *Service) describeRecord(hostedZoneID, name string) (*route53.ResourceRecordSet, error) {
+	out, err := s.Route53Client.ListResourceRecordSets(&route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(hostedZoneID),
+		StartRecordName: aws.String(name),
+		StartRecordType: aws.String(route53.RRTypeA),
+		MaxItems:        aws.String("1"),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list records of hosted zone %q", hostedZoneID)
+	}
+
+	for _, rs := range out.ResourceRecordSets {
+		if normalizeDNSName(aws.StringValue(rs.Name)) == name && aws.StringValue(rs.Type) == route53.RRTypeA {
+			return rs, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// loadBalancerAliasTarget returns the alias target of the API server load balancer.
+func (s *Service) loadBalancerAliasTarget() (*route53.AliasTarget, error) {
+	name := s.scope.Network().APIServerELB.Name
+
+	if lb := s.scope.ControlPlaneLoadBalancer(); lb != nil && lb.LoadBalancerType == infrav1.LoadBalancerTypeNLB {
+		out, err := s.ELBV2Client.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+			Names: aws.StringSlice([]string{name}),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe network load balancer %q", name)
+		}
+		if len(out.LoadBalancers) == 0 {
+			return nil, awserrors.NewNotFound(fmt.Sprintf("no network load balancer found with name %q", name))
+		}
+
+		return &route53.AliasTarget{
+			DNSName:              out.LoadBalancers[0].DNSName,
+			HostedZoneId:         out.LoadBalancers[0].CanonicalHostedZoneId,
+			EvaluateTargetHealth: aws.Bool(false),
+		}, nil
+	}
+
+	out, err := s.ELBClient.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{
+		LoadBalancerNames: aws.StringSlice([]string{name}),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe classic load balancer %q", name)
+	}
+	if len(out.LoadBalancerDescriptions) == 0 {
+		return nil, awserrors.NewNotFound(fmt.Sprintf("no classic load balancer found with name %q", name))
+	}
+
+	return &route53.AliasTarget{
+		DNSName:              out.LoadBalancerDescriptions[0].DNSName,
+		HostedZoneId:         out.LoadBalancerDescriptions[0].CanonicalHostedZoneNameID,
+		EvaluateTargetHealth: aws.Bool(false),
+	}, nil
+}
+
+// aliasTargetMatches returns true if an existing alias target points at the desired one.
+func aliasTargetMatches(existing, desired *route53.AliasTarget) bool {
+	if existing == nil || desired == nil {
+		return false
+	}
+
+	return normalizeDNSName(aws.StringValue(existing.DNSName)) == normalizeDNSName(aws.StringValue(desired.DNSName)) &&
+		aws.StringValue(existing.HostedZoneId) == aws.StringValue(desired.HostedZoneId)
+}
+
+// normalizeDNSName lowercases a DNS name and strips the trailing dot, along with the
+// dualstack prefix Route53 may add to load balancer alias targets.
+func normalizeDNSName(name string) string {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	return strings.TrimPrefix(name, "dualstack.")
+}
+
+func isHostedZoneNotFound(err error) bool {
+	code, ok := awserrors.Code(errors.Cause(err))
+	return ok && code == route53.ErrCodeNoSuchHostedZone
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route53
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/scope"
+)
+
+const (
+	testHostedZoneID = "Z0123456789"
+	testLBDNSName    = "test-cluster-apiserver-123.us-east-1.elb.amazonaws.com"
+	testLBZoneID     = "Z35SXDOTRQ7X7K"
+	testRecordName   = "api.test-cluster.example.com"
+)
+
+func TestReconcileControlPlaneDNS(t *testing.T) {
+	upToDate := &route53.ResourceRecordSet{
+		Name: aws.String(testRecordName + "."),
+		Type: aws.String(route53.RRTypeA),
+		AliasTarget: &route53.AliasTarget{
+			DNSName:      aws.String("dualstack." + testLBDNSName + "."),
+			HostedZoneId: aws.String(testLBZoneID),
+		},
+	}
+
+	tests := []struct {
+		name             string
+		changeID         string
+		record           *route53.ResourceRecordSet
+		changeStatus     string
+		getChangeErr     error
+		expectInSync     bool
+		expectUpserts    int
+		expectGetChanges int
+		expectChangeID   string
+	}{
+		{
+			name:           "creates the record and waits for it to propagate",
+			changeStatus:   route53.ChangeStatusPending,
+			expectInSync:   false,
+			expectUpserts:  1,
+			expectChangeID: "/change/C1",
+		},
+		{
+			name:          "does not wait for a change that is already in sync",
+			changeStatus:  route53.ChangeStatusInsync,
+			expectInSync:  true,
+			expectUpserts: 1,
+		},
+		{
+			name:             "keeps waiting while the change is pending",
+			changeID:         "/change/C1",
+			record:           upToDate,
+			changeStatus:     route53.ChangeStatusPending,
+			expectInSync:     false,
+			expectGetChanges: 1,
+			expectChangeID:   "/change/C1",
+		},
+		{
+			name:             "clears the change once it is in sync",
+			changeID:         "/change/C1",
+			record:           upToDate,
+			changeStatus:     route53.ChangeStatusInsync,
+			expectInSync:     true,
+			expectGetChanges: 1,
+		},
+		{
+			name:             "treats a change route53 no longer knows about as in sync",
+			changeID:         "/change/C1",
+			record:           upToDate,
+			getChangeErr:     awserr.New(route53.ErrCodeNoSuchChange, "", nil),
+			expectInSync:     true,
+			expectGetChanges: 1,
+		},
+		{
+			name:         "leaves an up to date record alone",
+			record:       upToDate,
+			expectInSync: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).NotTo(HaveOccurred())
+			clusterScope.Network().ControlPlaneDNSChangeID = tc.changeID
+
+			route53API := &fakeRoute53{record: tc.record, changeStatus: tc.changeStatus, getChangeErr: tc.getChangeErr}
+			s := &Service{
+				scope:         clusterScope,
+				Route53Client: route53API,
+				ELBClient:     &fakeELB{},
+			}
+
+			name, inSync, err := s.ReconcileControlPlaneDNS()
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(name).To(Equal(testRecordName))
+			g.Expect(inSync).To(Equal(tc.expectInSync))
+			g.Expect(route53API.upserts).To(Equal(tc.expectUpserts))
+			g.Expect(route53API.getChanges).To(Equal(tc.expectGetChanges))
+			g.Expect(clusterScope.Network().ControlPlaneDNSChangeID).To(Equal(tc.expectChangeID))
+		})
+	}
+}
+
+func TestRecordName(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *infrav1.ControlPlaneDNS
+		zoneName string
+		expected string
+	}{
+		{
+			name:     "defaults to the cluster name in the hosted zone",
+			spec:     &infrav1.ControlPlaneDNS{HostedZoneID: "Z0123456789"},
+			zoneName: "Example.com.",
+			expected: "api.test-cluster.example.com",
+		},
+		{
+			name:     "defaults to the cluster name in the private hosted zone",
+			spec:     &infrav1.ControlPlaneDNS{PrivateZoneName: "internal.example.com"},
+			zoneName: "internal.example.com",
+			expected: "api.test-cluster.internal.example.com",
+		},
+		{
+			name:     "uses the configured name",
+			spec:     &infrav1.ControlPlaneDNS{HostedZoneID: "Z0123456789", Name: "k8s.example.com."},
+			zoneName: "example.com.",
+			expected: "k8s.example.com",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(recordName("test-cluster", tc.spec, tc.zoneName)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestHostedZoneIDFromPath(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(hostedZoneIDFromPath("/hostedzone/Z0123456789")).To(Equal("Z0123456789"))
+	g.Expect(hostedZoneIDFromPath("Z0123456789")).To(Equal("Z0123456789"))
+}
+
+func TestCreatePrivateZone(t *testing.T) {
+	tests := []struct {
+		name          string
+		tagErr        error
+		expectErr     bool
+		expectDeleted []string
+	}{
+		{
+			name: "creates and tags the hosted zone",
+		},
+		{
+			name:          "deletes the hosted zone if tagging it fails",
+			tagErr:        awserr.New(route53.ErrCodeThrottlingException, "", nil),
+			expectErr:     true,
+			expectDeleted: []string{"Z9876543210"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterScope, err := setupCluster("test-cluster")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			route53API := &fakeRoute53{tagErr: tc.tagErr}
+			s := &Service{
+				scope:         clusterScope,
+				Route53Client: route53API,
+			}
+
+			hostedZoneID, err := s.createPrivateZone("internal.example.com")
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(hostedZoneID).To(Equal("Z9876543210"))
+			}
+			g.Expect(route53API.deletedZones).To(Equal(tc.expectDeleted))
+		})
+	}
+}
+
+func TestAliasTargetMatches(t *testing.T) {
+	desired := &route53.AliasTarget{
+		DNSName:      aws.String("test-cluster-apiserver-123.us-east-1.elb.amazonaws.com"),
+		HostedZoneId: aws.String("Z35SXDOTRQ7X7K"),
+	}
+
+	tests := []struct {
+		name     string
+		existing *route53.AliasTarget
+		expected bool
+	}{
+		{
+			name:     "no alias target",
+			existing: nil,
+			expected: false,
+		},
+		{
+			name: "same load balancer as returned by route53",
+			existing: &route53.AliasTarget{
+				DNSName:      aws.String("dualstack.test-cluster-apiserver-123.us-east-1.elb.amazonaws.com."),
+				HostedZoneId: aws.String("Z35SXDOTRQ7X7K"),
+			},
+			expected: true,
+		},
+		{
+			name: "different load balancer",
+			existing: &route53.AliasTarget{
+				DNSName:      aws.String("other-apiserver-456.us-east-1.elb.amazonaws.com."),
+				HostedZoneId: aws.String("Z35SXDOTRQ7X7K"),
+			},
+			expected: false,
+		},
+		{
+			name: "different hosted zone",
+			existing: &route53.AliasTarget{
+				DNSName:      aws.String("test-cluster-apiserver-123.us-east-1.elb.amazonaws.com."),
+				HostedZoneId: aws.String("Z26RNL4JYFTOTI"),
+			},
+			expected: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(aliasTargetMatches(tc.existing, desired)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func setupCluster(clusterName string) (*scope.ClusterScope, error) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	awsCluster := &infrav1.AWSCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: infrav1.AWSClusterSpec{
+			ControlPlaneDNS: &infrav1.ControlPlaneDNS{HostedZoneID: testHostedZoneID},
+		},
+		Status: infrav1.AWSClusterStatus{
+			Network: infrav1.NetworkStatus{
+				APIServerELB: infrav1.ClassicELB{Name: "test-cluster-apiserver", DNSName: testLBDNSName},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if err := client.Create(context.TODO(), awsCluster); err != nil {
+		return nil, err
+	}
+	return scope.NewClusterScope(scope.ClusterScopeParams{
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		},
+		AWSCluster: awsCluster,
+		Client:     client,
+	})
+}
+
+// fakeRoute53 stands in for the Route53 API: it holds a public hosted zone with at most one record,
+// reports every change with the configured status and records the private hosted zones deleted.
+type fakeRoute53 struct {
+	route53iface.Route53API
+
+	record       *route53.ResourceRecordSet
+	changeStatus string
+	getChangeErr error
+	tagErr       error
+
+	upserts      int
+	getChanges   int
+	deletedZones []string
+}
+
+func (f *fakeRoute53) CreateHostedZone(input *route53.CreateHostedZoneInput) (*route53.CreateHostedZoneOutput, error) {
+	return &route53.CreateHostedZoneOutput{
+		HostedZone: &route53.HostedZone{
+			Id:     aws.String("/hostedzone/Z9876543210"),
+			Name:   aws.String(aws.StringValue(input.Name) + "."),
+			Config: input.HostedZoneConfig,
+		},
+	}, nil
+}
+
+func (f *fakeRoute53) ChangeTagsForResource(_ *route53.ChangeTagsForResourceInput) (*route53.ChangeTagsForResourceOutput, error) {
+	if f.tagErr != nil {
+		return nil, f.tagErr
+	}
+	return &route53.ChangeTagsForResourceOutput{}, nil
+}
+
+func (f *fakeRoute53) DeleteHostedZone(input *route53.DeleteHostedZoneInput) (*route53.DeleteHostedZoneOutput, error) {
+	f.deletedZones = append(f.deletedZones, aws.StringValue(input.Id))
+	return &route53.DeleteHostedZoneOutput{}, nil
+}
+
+func (f *fakeRoute53) GetHostedZone(_ *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+	return &route53.GetHostedZoneOutput{
+		HostedZone: &route53.HostedZone{
+			Id:     aws.String("/hostedzone/" + testHostedZoneID),
+			Name:   aws.String("example.com."),
+			Config: &route53.HostedZoneConfig{PrivateZone: aws.Bool(false)},
+		},
+	}, nil
+}
+
+func (f *fakeRoute53) ListResourceRecordSets(_ *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
+	out := &route53.ListResourceRecordSetsOutput{}
+	if f.record != nil {
+		out.ResourceRecordSets = []*route53.ResourceRecordSet{f.record}
+	}
+	return out, nil
+}
+
+func (f *fakeRoute53) ChangeResourceRecordSets(input *route53.ChangeResourceRecordSetsInput) (*route53.ChangeResourceRecordSetsOutput, error) {
+	f.upserts++
+	f.record = input.ChangeBatch.Changes[0].ResourceRecordSet
+	return &route53.ChangeResourceRecordSetsOutput{
+		ChangeInfo: &route53.ChangeInfo{Id: aws.String("/change/C1"), Status: aws.String(f.changeStatus)},
+	}, nil
+}
+
+func (f *fakeRoute53) GetChange(input *route53.GetChangeInput) (*route53.GetChangeOutput, error) {
+	f.getChanges++
+	if f.getChangeErr != nil {
+		return nil, f.getChangeErr
+	}
+	return &route53.GetChangeOutput{
+		ChangeInfo: &route53.ChangeInfo{Id: input.Id, Status: aws.String(f.changeStatus)},
+	}, nil
+}
+
+// fakeELB stands in for the classic ELB API, holding the API server load balancer.
+type fakeELB struct {
+	elbiface.ELBAPI
+}
+
+func (f *fakeELB) DescribeLoadBalancers(_ *elb.DescribeLoadBalancersInput) (*elb.DescribeLoadBalancersOutput, error) {
+	return &elb.DescribeLoadBalancersOutput{
+		LoadBalancerDescriptions: []*elb.LoadBalancerDescription{
+			{
+				DNSName:                   aws.String(testLBDNSName),
+				CanonicalHostedZoneNameID: aws.String(testLBZoneID),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package route53
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-aws/api/v1alpha4"
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/record"
+)
+
+// reconcileHostedZone returns the ID and domain name of the hosted zone the control plane record
+// is created in. The private hosted zone named by privateZoneName is created in the cluster VPC if
+// it does not exist yet, and an existing private hosted zone is associated with the cluster VPC.
+func (s *Service) reconcileHostedZone(spec *infrav1.ControlPlaneDNS) (string, string, error) {
+	if spec.PrivateZoneName == "" {
+		zoneName, err := s.associateHostedZone(spec.HostedZoneID)
+		return spec.HostedZoneID, zoneName, err
+	}
+
+	hostedZoneID, err := s.findPrivateZone(spec.PrivateZoneName)
+	if err != nil {
+		return "", "", err
+	}
+	if hostedZoneID == "" {
+		if hostedZoneID, err = s.createPrivateZone(spec.PrivateZoneName); err != nil {
+			return "", "", err
+		}
+	}
+
+	return hostedZoneID, spec.PrivateZoneName, nil
+}
+
+// describeHostedZone is like reconcileHostedZone, without changing anything. It returns an empty
+// ID if the private hosted zone named by privateZoneName does not exist.
+func (s *Service) describeHostedZone(spec *infrav1.ControlPlaneDNS) (string, string, error) {
+	if spec.PrivateZoneName != "" {
+		hostedZoneID, err := s.findPrivateZone(spec.PrivateZoneName)
+		return hostedZoneID, spec.PrivateZoneName, err
+	}
+
+	out, err := s.Route53Client.GetHostedZone(&route53.GetHostedZoneInput{
+		Id: aws.String(spec.HostedZoneID),
+	})
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to get hosted zone %q", spec.HostedZoneID)
+	}
+
+	return spec.HostedZoneID, aws.StringValue(out.HostedZone.Name), nil
+}
+
+// associateHostedZone associates a private hosted zone with the cluster VPC, so that the record
+// resolves from within the cluster, and returns the domain name of the hosted zone.
+func (s *Service) associateHostedZone(hostedZoneID string) (string, error) {
+	out, err := s.Route53Client.GetHostedZone(&route53.GetHostedZoneInput{
+		Id: aws.String(hostedZoneID),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get hosted zone %q", hostedZoneID)
+	}
+
+	zoneName := aws.StringValue(out.HostedZone.Name)
+	if out.HostedZone.Config == nil || !aws.BoolValue(out.HostedZone.Config.PrivateZone) {
+		return zoneName, nil
+	}
+
+	vpcID := s.scope.VPC().ID
+	for _, vpc := range out.VPCs {
+		if aws.StringValue(vpc.VPCId) == vpcID {
+			return zoneName, nil
+		}
+	}
+
+	if _, err := s.Route53Client.AssociateVPCWithHostedZone(&route53.AssociateVPCWithHostedZoneInput{
+		HostedZoneId: aws.String(hostedZoneID),
+		VPC: &route53.VPC{
+			VPCId:     aws.String(vpcID),
+			VPCRegion: aws.String(s.scope.Region()),
+		},
+		Comment: aws.String(fmt.Sprintf("VPC of cluster %s", s.scope.Name())),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedAssociateHostedZone", "Failed to associate private hosted zone %q with VPC %q: %v", hostedZoneID, vpcID, err)
+		return "", errors.Wrapf(err, "failed to associate private hosted zone %q with VPC %q", hostedZoneID, vpcID)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulAssociateHostedZone", "Associated private hosted zone %q with VPC %q", hostedZoneID, vpcID)
+	return zoneName, nil
+}
+
+// findPrivateZone returns the ID of the private hosted zone with the given domain name that is
+// associated with the cluster VPC, or an empty string if there is none.
+func (s *Service) findPrivateZone(zoneName string) (string, error) {
+	vpcID := s.scope.VPC().ID
+	if vpcID == "" {
+		return "", nil
+	}
+
+	input := &route53.ListHostedZonesByVPCInput{
+		VPCId:     aws.String(vpcID),
+		VPCRegion: aws.String(s.scope.Region()),
+	}
+	for {
+		out, err := s.Route53Client.ListHostedZonesByVPC(input)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to list hosted zones of VPC %q", vpcID)
+		}
+
+		for _, summary := range out.HostedZoneSummaries {
+			if normalizeDNSName(aws.StringValue(summary.Name)) == normalizeDNSName(zoneName) {
+				return hostedZoneIDFromPath(aws.StringValue(summary.HostedZoneId)), nil
+			}
+		}
+
+		if out.NextToken == nil {
+			return "", nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
+
+// createPrivateZone creates a private hosted zone in the cluster VPC, tagged as owned by the cluster.
+func (s *Service) createPrivateZone(zoneName string) (string, error) {
+	vpcID := s.scope.VPC().ID
+
+	s.scope.V(2).Info("Creating private hosted zone", "name", zoneName, "vpc-id", vpcID)
+	out, err := s.Route53Client.CreateHostedZone(&route53.CreateHostedZoneInput{
+		Name:            aws.String(zoneName),
+		CallerReference: aws.String(string(uuid.NewUUID())),
+		VPC: &route53.VPC{
+			VPCId:     aws.String(vpcID),
+			VPCRegion: aws.String(s.scope.Region()),
+		},
+		HostedZoneConfig: &route53.HostedZoneConfig{
+			PrivateZone: aws.Bool(true),
+			Comment:     aws.String(fmt.Sprintf("Private hosted zone of cluster %s", s.scope.Name())),
+		},
+	})
+	if err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedCreateHostedZone", "Failed to create private hosted zone %q: %v", zoneName, err)
+		return "", errors.Wrapf(err, "failed to create private hosted zone %q", zoneName)
+	}
+
+	hostedZoneID := hostedZoneIDFromPath(aws.StringValue(out.HostedZone.Id))
+	if _, err := s.Route53Client.ChangeTagsForResource(&route53.ChangeTagsForResourceInput{
+		ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+		ResourceId:   aws.String(hostedZoneID),
+		AddTags: []*route53.Tag{
+			{
+				Key:   aws.String(infrav1.ClusterTagKey(s.scope.Name())),
+				Value: aws.String(string(infrav1.ResourceLifecycleOwned)),
+			},
+		},
+	}); err != nil {
+		// The cluster only deletes hosted zones tagged as owned by it, so an untagged zone would be
+		// picked up by name on the next reconcile and then leaked when the cluster is deleted.
+		record.Warnf(s.scope.InfraCluster(), "FailedTagHostedZone", "Failed to tag private hosted zone %q: %v", hostedZoneID, err)
+		if _, deleteErr := s.Route53Client.DeleteHostedZone(&route53.DeleteHostedZoneInput{
+			Id: aws.String(hostedZoneID),
+		}); deleteErr != nil {
+			return "", errors.Wrapf(deleteErr, "failed to delete private hosted zone %q after failing to tag it: %v", hostedZoneID, err)
+		}
+		return "", errors.Wrapf(err, "failed to tag private hosted zone %q", hostedZoneID)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulCreateHostedZone", "Created private hosted zone %q with ID %q", zoneName, hostedZoneID)
+	return hostedZoneID, nil
+}
+
+// deletePrivateZone deletes a private hosted zone if it is owned by the cluster.
+func (s *Service) deletePrivateZone(hostedZoneID string) error {
+	out, err := s.Route53Client.ListTagsForResource(&route53.ListTagsForResourceInput{
+		ResourceType: aws.String(route53.TagResourceTypeHostedzone),
+		ResourceId:   aws.String(hostedZoneID),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list tags of hosted zone %q", hostedZoneID)
+	}
+
+	tags := infrav1.Tags{}
+	if out.ResourceTagSet != nil {
+		for _, tag := range out.ResourceTagSet.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+	if !tags.HasOwned(s.scope.Name()) {
+		return nil
+	}
+
+	s.scope.V(2).Info("Deleting private hosted zone", "hosted-zone-id", hostedZoneID)
+	if _, err := s.Route53Client.DeleteHostedZone(&route53.DeleteHostedZoneInput{
+		Id: aws.String(hostedZoneID),
+	}); err != nil {
+		record.Warnf(s.scope.InfraCluster(), "FailedDeleteHostedZone", "Failed to delete private hosted zone %q: %v", hostedZoneID, err)
+		return errors.Wrapf(err, "failed to delete private hosted zone %q", hostedZoneID)
+	}
+
+	record.Eventf(s.scope.InfraCluster(), "SuccessfulDeleteHostedZone", "Deleted private hosted zone %q", hostedZoneID)
+	return nil
+}
+
+// hostedZoneIDFromPath strips the /hostedzone/ prefix some Route53 APIs return hosted zone IDs with.
+func hostedZoneIDFromPath(id string) string {
+	return strings.TrimPrefix(id, "/hostedzone/")
+}
@@ -48,6 +48,9 @@ const (
 
 	// IPProtocolICMPv6 is how EC2 represents the ICMPv6 protocol in ingress rules.
 	IPProtocolICMPv6 = "58"
+
+	// defaultSecurityGroupName is the name of the default security group of a VPC, which can't be deleted.
+	defaultSecurityGroupName = "default"
 )
 
 var (
@@ -282,6 +285,25 @@ func (s *Service) DeleteSecurityGroups() error {
 		return err
 	}
 
+	// The cloud provider creates a security group for the load balancer of each Service of type LoadBalancer,
+	// which outlives the load balancer and would keep the VPC from being deleted.
+	providerGroups, err := s.describeCloudProviderOwnedSecurityGroups()
+	if err != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.ClusterSecurityGroupsReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	// A security group can't be deleted while a rule of another group references it, and the groups of a
+	// cluster reference each other, e.g. the nodes allow traffic from the control plane and the load balancers.
+	ids := make(map[string]bool, len(clusterGroups)+len(providerGroups))
+	for _, sg := range append(clusterGroups, providerGroups...) {
+		ids[sg.ID] = true
+	}
+	if err := s.revokeSecurityGroupReferences(ids); awserrors.IsIgnorableSecurityGroupError(err) != nil {
+		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.ClusterSecurityGroupsReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
 	for i := range clusterGroups {
 		sg := clusterGroups[i]
 		current := sg.IngressRules
@@ -297,6 +319,18 @@ func (s *Service) DeleteSecurityGroups() error {
 		}
 	}
 
+	for i := range providerGroups {
+		sg := providerGroups[i]
+		if err := s.revokeAllSecurityGroupIngressRules(sg.ID); awserrors.IsIgnorableSecurityGroupError(err) != nil {
+			conditions.MarkFalse(s.scope.InfraCluster(), infrav1.ClusterSecurityGroupsReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
+			return err
+		}
+
+		if deleteErr := s.deleteSecurityGroup(&sg, "cloud provider managed"); deleteErr != nil {
+			err = kerrors.NewAggregate([]error{err, deleteErr})
+		}
+	}
+
 	if err != nil {
 		conditions.MarkFalse(s.scope.InfraCluster(), infrav1.ClusterSecurityGroupsReadyCondition, "DeletingFailed", clusterv1.ConditionSeverityWarning, err.Error())
 		return err
@@ -322,6 +356,77 @@ func (s *Service) deleteSecurityGroup(sg *infrav1.SecurityGroup, typ string) err
 	return nil
 }
 
+// revokeSecurityGroupReferences revokes the ingress and egress rules of the security groups of the VPC
+// that reference any of the given security groups.
+func (s *Service) revokeSecurityGroupReferences(ids map[string]bool) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+		},
+	}
+
+	groups := []*ec2.SecurityGroup{}
+	if err := s.EC2Client.DescribeSecurityGroupsPages(input, func(out *ec2.DescribeSecurityGroupsOutput, last bool) bool {
+		groups = append(groups, out.SecurityGroups...)
+		return true
+	}); err != nil {
+		return errors.Wrapf(err, "failed to describe security groups in vpc %q", s.scope.VPC().ID)
+	}
+
+	for _, sg := range groups {
+		if ingress := ipPermissionsReferencing(sg.IpPermissions, ids); len(ingress) > 0 {
+			if _, err := s.EC2Client.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+				GroupId:       sg.GroupId,
+				IpPermissions: ingress,
+			}); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedRevokeSecurityGroupIngressRules", "Failed to revoke security group ingress rules referencing cluster security groups for SecurityGroup %q: %v", *sg.GroupId, err)
+				return errors.Wrapf(err, "failed to revoke security group %q ingress rules", *sg.GroupId)
+			}
+			s.scope.V(2).Info("Revoked ingress rules referencing cluster security groups", "security-group-id", *sg.GroupId)
+		}
+
+		if egress := ipPermissionsReferencing(sg.IpPermissionsEgress, ids); len(egress) > 0 {
+			if _, err := s.EC2Client.RevokeSecurityGroupEgress(&ec2.RevokeSecurityGroupEgressInput{
+				GroupId:       sg.GroupId,
+				IpPermissions: egress,
+			}); err != nil {
+				record.Warnf(s.scope.InfraCluster(), "FailedRevokeSecurityGroupEgressRules", "Failed to revoke security group egress rules referencing cluster security groups for SecurityGroup %q: %v", *sg.GroupId, err)
+				return errors.Wrapf(err, "failed to revoke security group %q egress rules", *sg.GroupId)
+			}
+			s.scope.V(2).Info("Revoked egress rules referencing cluster security groups", "security-group-id", *sg.GroupId)
+		}
+	}
+
+	return nil
+}
+
+// ipPermissionsReferencing returns the parts of the permissions that allow traffic from or to any of the security groups.
+func ipPermissionsReferencing(permissions []*ec2.IpPermission, ids map[string]bool) []*ec2.IpPermission {
+	var res []*ec2.IpPermission
+	for _, permission := range permissions {
+		var pairs []*ec2.UserIdGroupPair
+		for _, pair := range permission.UserIdGroupPairs {
+			if ids[aws.StringValue(pair.GroupId)] {
+				pairs = append(pairs, pair)
+			}
+		}
+		if len(pairs) == 0 {
+			continue
+		}
+		res = append(res, &ec2.IpPermission{
+			IpProtocol:       permission.IpProtocol,
+			FromPort:         permission.FromPort,
+			ToPort:           permission.ToPort,
+			UserIdGroupPairs: pairs,
+		})
+	}
+	return res
+}
+
 func (s *Service) describeClusterOwnedSecurityGroups() ([]infrav1.SecurityGroup, error) {
 	input := &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
@@ -334,7 +439,8 @@ func (s *Service) describeClusterOwnedSecurityGroups() ([]infrav1.SecurityGroup,
 
 	err := s.EC2Client.DescribeSecurityGroupsPages(input, func(out *ec2.DescribeSecurityGroupsOutput, last bool) bool {
 		for _, group := range out.SecurityGroups {
-			if group != nil {
+			// The default security group of the VPC goes away with the VPC.
+			if group != nil && aws.StringValue(group.GroupName) != defaultSecurityGroupName {
 				groups = append(groups, makeInfraSecurityGroup(group))
 			}
 		}
@@ -346,6 +452,37 @@ func (s *Service) describeClusterOwnedSecurityGroups() ([]infrav1.SecurityGroup,
 	return groups, nil
 }
 
+// describeCloudProviderOwnedSecurityGroups returns the security groups of the VPC that the cloud provider
+// created for the cluster, leaving out the ones managed by the provider, EKS or the user.
+func (s *Service) describeCloudProviderOwnedSecurityGroups() ([]infrav1.SecurityGroup, error) {
+	input := &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			filter.EC2.VPC(s.scope.VPC().ID),
+			filter.EC2.ProviderOwned(s.scope.Name()),
+		},
+	}
+
+	groups := []infrav1.SecurityGroup{}
+
+	err := s.EC2Client.DescribeSecurityGroupsPages(input, func(out *ec2.DescribeSecurityGroupsOutput, last bool) bool {
+		for _, group := range out.SecurityGroups {
+			if group == nil || aws.StringValue(group.GroupName) == defaultSecurityGroupName {
+				continue
+			}
+			sg := makeInfraSecurityGroup(group)
+			if sg.Tags.HasOwned(s.scope.Name()) || s.isEKSOwned(sg) || s.securityGroupIsOverridden(sg.ID) {
+				continue
+			}
+			groups = append(groups, sg)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe cloud provider owned security groups in vpc %q", s.scope.VPC().ID)
+	}
+	return groups, nil
+}
+
 func (s *Service) describeSecurityGroupsByName() (map[string]infrav1.SecurityGroup, error) {
 	input := &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
@@ -471,7 +608,27 @@ func (s *Service) defaultSSHIngressRule(sourceSecurityGroupID string) infrav1.In
 	}
 }
 
+// efaIngressRule allows all traffic from the security group of the given role to itself,
+// which Elastic Fabric Adapters require to communicate with each other.
+func (s *Service) efaIngressRule(role infrav1.SecurityGroupRole) infrav1.IngressRule {
+	return infrav1.IngressRule{
+		Description:            "Elastic Fabric Adapter",
+		Protocol:               infrav1.SecurityGroupProtocolAll,
+		SourceSecurityGroupIDs: []string{s.scope.SecurityGroups()[role].ID},
+	}
+}
+
 func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (infrav1.IngressRules, error) {
+	rules, err := s.getDefaultSecurityGroupIngressRules(role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Append any user-defined rules for this role so they converge along with the defaults.
+	return append(rules, s.scope.AdditionalIngressRules()[role].DeepCopy()...), nil
+}
+
+func (s *Service) getDefaultSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (infrav1.IngressRules, error) {
 	// Set source of CNI ingress rules to be control plane and node security groups
 	s.scope.V(2).Info("getting security group ingress rules", "role", role)
 
@@ -529,6 +686,16 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 				SourceSecurityGroupIDs: []string{s.scope.SecurityGroups()[infrav1.SecurityGroupControlPlane].ID},
 			},
 		}
+		if lb := s.scope.ControlPlaneLoadBalancer(); lb != nil && lb.LoadBalancerType == infrav1.LoadBalancerTypeNLB {
+			// Network load balancers have no security groups and forward traffic from their private IPs within the VPC.
+			rules = append(rules, infrav1.IngressRule{
+				Description: "Kubernetes API from network load balancer",
+				Protocol:    infrav1.SecurityGroupProtocolTCP,
+				FromPort:    int64(s.scope.APIServerPort()),
+				ToPort:      int64(s.scope.APIServerPort()),
+				CidrBlocks:  []string{s.scope.VPC().CidrBlock},
+			})
+		}
 		return append(cniRules, rules...), nil
 
 	case infrav1.SecurityGroupNode:
@@ -553,11 +720,18 @@ func (s *Service) getSecurityGroupIngressRules(role infrav1.SecurityGroupRole) (
 				},
 			},
 		}
+		if s.scope.AllowEFATraffic() {
+			rules = append(rules, s.efaIngressRule(infrav1.SecurityGroupNode))
+		}
 		return append(cniRules, rules...), nil
 	case infrav1.SecurityGroupEKSNodeAdditional:
-		return infrav1.IngressRules{
+		rules := infrav1.IngressRules{
 			s.defaultSSHIngressRule(s.scope.SecurityGroups()[infrav1.SecurityGroupBastion].ID),
-		}, nil
+		}
+		if s.scope.AllowEFATraffic() {
+			rules = append(rules, s.efaIngressRule(infrav1.SecurityGroupEKSNodeAdditional))
+		}
+		return rules, nil
 	case infrav1.SecurityGroupAPIServerLB:
 		return infrav1.IngressRules{
 			{
@@ -389,6 +389,117 @@ func TestControlPlaneSecurityGroupNotOpenToAnyCIDR(t *testing.T) {
 	}
 }
 
+func TestAdditionalIngressRules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	nodePortRule := infrav1.IngressRule{
+		Description: "Corporate NodePorts",
+		Protocol:    infrav1.SecurityGroupProtocolTCP,
+		FromPort:    30000,
+		ToPort:      32767,
+		CidrBlocks:  []string{"192.168.0.0/16"},
+	}
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					AdditionalIngressRules: map[infrav1.SecurityGroupRole]infrav1.IngressRules{
+						infrav1.SecurityGroupNode: {nodePortRule},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	s := NewService(scope)
+	nodeRules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupNode)
+	if err != nil {
+		t.Fatalf("Failed to lookup node security group ingress rules: %v", err)
+	}
+	if !nodeRules[len(nodeRules)-1].Equals(&nodePortRule) {
+		t.Fatalf("Expected additional ingress rule to be appended to node rules, got %v", nodeRules)
+	}
+
+	controlPlaneRules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupControlPlane)
+	if err != nil {
+		t.Fatalf("Failed to lookup controlplane security group ingress rules: %v", err)
+	}
+	for _, r := range controlPlaneRules {
+		if r.Equals(&nodePortRule) {
+			t.Fatal("Additional node ingress rule was applied to the control plane security group")
+		}
+	}
+}
+
+func TestEFAIngressRules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	scope, err := scope.NewClusterScope(scope.ClusterScopeParams{
+		Client: client,
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+		},
+		AWSCluster: &infrav1.AWSCluster{
+			Spec: infrav1.AWSClusterSpec{
+				NetworkSpec: infrav1.NetworkSpec{
+					AllowEFATraffic: true,
+				},
+			},
+			Status: infrav1.AWSClusterStatus{
+				Network: infrav1.NetworkStatus{
+					SecurityGroups: map[infrav1.SecurityGroupRole]infrav1.SecurityGroup{
+						infrav1.SecurityGroupControlPlane: {ID: "sg-controlplane"},
+						infrav1.SecurityGroupNode:         {ID: "sg-node"},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test context: %v", err)
+	}
+
+	efaRule := infrav1.IngressRule{
+		Description:            "Elastic Fabric Adapter",
+		Protocol:               infrav1.SecurityGroupProtocolAll,
+		SourceSecurityGroupIDs: []string{"sg-node"},
+	}
+
+	s := NewService(scope)
+	nodeRules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupNode)
+	if err != nil {
+		t.Fatalf("Failed to lookup node security group ingress rules: %v", err)
+	}
+	found := false
+	for _, r := range nodeRules {
+		if r.Equals(&efaRule) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected node rules to allow all traffic from the node security group, got %v", nodeRules)
+	}
+
+	controlPlaneRules, err := s.getSecurityGroupIngressRules(infrav1.SecurityGroupControlPlane)
+	if err != nil {
+		t.Fatalf("Failed to lookup controlplane security group ingress rules: %v", err)
+	}
+	for _, r := range controlPlaneRules {
+		if r.Protocol == infrav1.SecurityGroupProtocolAll {
+			t.Fatalf("Expected control plane rules not to allow all traffic, got %v", r)
+		}
+	}
+}
+
 func TestDeleteSecurityGroups(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -427,8 +538,126 @@ func TestDeleteSecurityGroups(t *testing.T) {
 					infrav1.SecurityGroupNode:         "sg-node",
 				},
 			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+		},
+		{
+			name: "deletes the security groups the cloud provider created for load balancers",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: "vpc-securitygroups",
+				},
+			},
 			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
 				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).
+					Do(func(_ *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool) {
+						fn(&ec2.DescribeSecurityGroupsOutput{
+							SecurityGroups: []*ec2.SecurityGroup{
+								{
+									GroupId:   aws.String("sg-k8s-elb"),
+									GroupName: aws.String("k8s-elb-a1b2c3"),
+									Tags: []*ec2.Tag{
+										{Key: aws.String("kubernetes.io/cluster/test-cluster"), Value: aws.String("owned")},
+									},
+								},
+								{
+									GroupId:   aws.String("sg-lb"),
+									GroupName: aws.String("test-cluster-lb"),
+									Tags: []*ec2.Tag{
+										{Key: aws.String("kubernetes.io/cluster/test-cluster"), Value: aws.String("owned")},
+										{Key: aws.String("sigs.k8s.io/cluster-api-provider-aws/cluster/test-cluster"), Value: aws.String("owned")},
+									},
+								},
+							},
+						}, true)
+					}).Return(nil)
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+				m.DescribeSecurityGroups(gomock.Eq(&ec2.DescribeSecurityGroupsInput{GroupIds: aws.StringSlice([]string{"sg-k8s-elb"})})).
+					Return(&ec2.DescribeSecurityGroupsOutput{}, nil)
+				m.DeleteSecurityGroup(gomock.Eq(&ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-k8s-elb")})).
+					Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+			},
+		},
+		{
+			name: "revokes the rules referencing cluster security groups before deleting them",
+			input: &infrav1.NetworkSpec{
+				VPC: infrav1.VPCSpec{
+					ID: "vpc-securitygroups",
+				},
+			},
+			expect: func(m *mock_ec2iface.MockEC2APIMockRecorder) {
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).
+					Do(func(_ *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool) {
+						fn(&ec2.DescribeSecurityGroupsOutput{
+							SecurityGroups: []*ec2.SecurityGroup{
+								{GroupId: aws.String("sg-control"), GroupName: aws.String("test-cluster-controlplane")},
+								{GroupId: aws.String("sg-node"), GroupName: aws.String("test-cluster-node")},
+							},
+						}, true)
+					}).Return(nil)
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).Return(nil)
+				m.DescribeSecurityGroupsPages(gomock.Any(), gomock.Any()).
+					Do(func(_ *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool) {
+						fn(&ec2.DescribeSecurityGroupsOutput{
+							SecurityGroups: []*ec2.SecurityGroup{
+								{
+									GroupId:   aws.String("sg-node"),
+									GroupName: aws.String("test-cluster-node"),
+									IpPermissions: []*ec2.IpPermission{
+										{
+											IpProtocol:       aws.String("tcp"),
+											FromPort:         aws.Int64(10250),
+											ToPort:           aws.Int64(10250),
+											UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-control")}},
+										},
+										{
+											IpProtocol: aws.String("tcp"),
+											FromPort:   aws.Int64(22),
+											ToPort:     aws.Int64(22),
+											IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+										},
+									},
+								},
+								{
+									GroupId:   aws.String("sg-user"),
+									GroupName: aws.String("user-managed"),
+									IpPermissionsEgress: []*ec2.IpPermission{
+										{
+											IpProtocol:       aws.String("-1"),
+											UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-node")}, {GroupId: aws.String("sg-other")}},
+										},
+									},
+								},
+							},
+						}, true)
+					}).Return(nil)
+				m.RevokeSecurityGroupIngress(gomock.Eq(&ec2.RevokeSecurityGroupIngressInput{
+					GroupId: aws.String("sg-node"),
+					IpPermissions: []*ec2.IpPermission{
+						{
+							IpProtocol:       aws.String("tcp"),
+							FromPort:         aws.Int64(10250),
+							ToPort:           aws.Int64(10250),
+							UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-control")}},
+						},
+					},
+				})).Return(&ec2.RevokeSecurityGroupIngressOutput{}, nil)
+				m.RevokeSecurityGroupEgress(gomock.Eq(&ec2.RevokeSecurityGroupEgressInput{
+					GroupId: aws.String("sg-user"),
+					IpPermissions: []*ec2.IpPermission{
+						{
+							IpProtocol:       aws.String("-1"),
+							UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-node")}},
+						},
+					},
+				})).Return(&ec2.RevokeSecurityGroupEgressOutput{}, nil)
+				m.DescribeSecurityGroups(gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{}, nil).Times(2)
+				m.DeleteSecurityGroup(gomock.Eq(&ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-control")})).
+					Return(&ec2.DeleteSecurityGroupOutput{}, nil)
+				m.DeleteSecurityGroup(gomock.Eq(&ec2.DeleteSecurityGroupInput{GroupId: aws.String("sg-node")})).
+					Return(&ec2.DeleteSecurityGroupOutput{}, nil)
 			},
 		},
 	}
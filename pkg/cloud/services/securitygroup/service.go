@@ -37,6 +37,15 @@ type Scope interface {
 	// SecurityGroupOverrides returns the security groups that are overridden in the cluster spec
 	SecurityGroupOverrides() map[infrav1.SecurityGroupRole]string
 
+	// AdditionalIngressRules returns the user-defined ingress rules to add to each security group role.
+	AdditionalIngressRules() map[infrav1.SecurityGroupRole]infrav1.IngressRules
+
+	// AllowEFATraffic returns whether the node security groups allow all traffic between their instances.
+	AllowEFATraffic() bool
+
+	// ControlPlaneLoadBalancer returns the AWSLoadBalancerSpec for the API server load balancer.
+	ControlPlaneLoadBalancer() *infrav1.AWSLoadBalancerSpec
+
 	// VPC returns the cluster VPC.
 	VPC() *infrav1.VPCSpec
 
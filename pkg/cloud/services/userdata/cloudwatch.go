@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	"sigs.k8s.io/cluster-api-provider-aws/pkg/internal/mime"
+)
+
+const (
+	cloudWatchAgentBashScript = `{{.Header}}
+
+CLOUDWATCH_AGENT_DIR=/opt/aws/amazon-cloudwatch-agent
+CLOUDWATCH_AGENT_URL={{.DownloadURL}}
+
+case "$(uname -m)" in
+  aarch64) ARCH=arm64 ;;
+  *) ARCH=amd64 ;;
+esac
+
+if command -v dpkg &> /dev/null; then
+  curl -fsSL -o /tmp/amazon-cloudwatch-agent.deb "${CLOUDWATCH_AGENT_URL}/ubuntu/${ARCH}/latest/amazon-cloudwatch-agent.deb"
+  dpkg -i -E /tmp/amazon-cloudwatch-agent.deb
+  rm -f /tmp/amazon-cloudwatch-agent.deb
+else
+  rpm -U "${CLOUDWATCH_AGENT_URL}/amazon_linux/${ARCH}/latest/amazon-cloudwatch-agent.rpm"
+fi
+
+# The CloudWatch agent can't read the journal, so the kubelet and containerd logs
+# are exported to files it can tail.
+for UNIT in kubelet containerd; do
+  cat > "/etc/systemd/system/journal-export-${UNIT}.service" <<EOF
+[Unit]
+Description=Export the ${UNIT} journal to /var/log/${UNIT}.log
+After=systemd-journald.service
+
+[Service]
+ExecStart=/bin/sh -c 'exec journalctl --follow --boot --output=short-iso --unit=${UNIT}.service >> /var/log/${UNIT}.log'
+Restart=always
+
+[Install]
+WantedBy=multi-user.target
+EOF
+  systemctl enable --now "journal-export-${UNIT}.service"
+done
+
+cat > /etc/logrotate.d/journal-export <<EOF
+/var/log/kubelet.log /var/log/containerd.log {
+  daily
+  rotate 5
+  missingok
+  compress
+  copytruncate
+}
+EOF
+
+cat > "${CLOUDWATCH_AGENT_DIR}/etc/amazon-cloudwatch-agent.json" <<'EOF'
+{
+  "agent": {
+    "metrics_collection_interval": {{.MetricsCollectionInterval}},
+    "run_as_user": "root"
+  },
+  "metrics": {
+    "append_dimensions": {
+      "InstanceId": "${aws:InstanceId}"
+    },
+    "metrics_collected": {
+      "mem": {
+        "measurement": ["mem_used_percent"]
+      },
+      "disk": {
+        "measurement": ["used_percent"],
+        "resources": ["/"]
+      }
+    }
+  },
+  "logs": {
+    "logs_collected": {
+      "files": {
+        "collect_list": [
+          {
+            "file_path": "/var/log/kubelet.log",
+            "log_group_name": "{{.LogGroupName}}",
+            "log_stream_name": "{instance_id}/kubelet"
+          },
+          {
+            "file_path": "/var/log/containerd.log",
+            "log_group_name": "{{.LogGroupName}}",
+            "log_stream_name": "{instance_id}/containerd"
+          }
+        ]
+      }
+    }
+  }
+}
+EOF
+
+"${CLOUDWATCH_AGENT_DIR}/bin/amazon-cloudwatch-agent-ctl" -a fetch-config -m ec2 -s \
+  -c "file:${CLOUDWATCH_AGENT_DIR}/etc/amazon-cloudwatch-agent.json"
+`
+)
+
+// CloudWatchAgentInput defines the context to generate the script installing the CloudWatch agent.
+type CloudWatchAgentInput struct {
+	baseUserData
+
+	// LogGroupName is the CloudWatch Logs log group the kubelet and containerd logs are sent to.
+	LogGroupName string
+
+	// MetricsCollectionInterval is how often, in seconds, the agent collects metrics.
+	MetricsCollectionInterval int64
+
+	// Region is the region of the instance. The agent is downloaded from the S3 bucket of that region,
+	// which instances reach through an S3 gateway endpoint without internet access.
+	Region string
+
+	// DownloadURL is the base URL of the agent packages, set by NewCloudWatchAgent from Region.
+	DownloadURL string
+}
+
+// NewCloudWatchAgent returns the script installing and configuring the CloudWatch agent on an instance.
+func NewCloudWatchAgent(input *CloudWatchAgentInput) (string, error) {
+	input.Header = defaultHeader
+	input.DownloadURL = cloudWatchAgentDownloadURL(input.Region)
+	return generate("cloudwatch-agent", cloudWatchAgentBashScript, input)
+}
+
+// cloudWatchAgentDownloadURL returns the base URL of the agent packages in the S3 bucket of the given region.
+func cloudWatchAgentDownloadURL(region string) string {
+	dnsSuffix := "amazonaws.com"
+	if partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		dnsSuffix = partition.DNSSuffix()
+	}
+
+	return fmt.Sprintf("https://amazoncloudwatch-agent-%s.s3.%s.%s", region, region, dnsSuffix)
+}
+
+// AddCloudWatchAgent returns a multi-part document made of the given cloud-init bootstrap data
+// and of the script installing and configuring the CloudWatch agent.
+func AddCloudWatchAgent(bootstrapData []byte, input *CloudWatchAgentInput) ([]byte, error) {
+	script, err := NewCloudWatchAgent(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return mime.GenerateScriptedDocument(bootstrapData, []byte(script))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewCloudWatchAgent(t *testing.T) {
+	tests := []struct {
+		name        string
+		region      string
+		downloadURL string
+	}{
+		{
+			name:        "commercial region",
+			region:      "eu-west-2",
+			downloadURL: "https://amazoncloudwatch-agent-eu-west-2.s3.eu-west-2.amazonaws.com",
+		},
+		{
+			name:        "china region",
+			region:      "cn-north-1",
+			downloadURL: "https://amazoncloudwatch-agent-cn-north-1.s3.cn-north-1.amazonaws.com.cn",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			script, err := NewCloudWatchAgent(&CloudWatchAgentInput{
+				LogGroupName:              "/cluster-api/test-cluster",
+				MetricsCollectionInterval: 30,
+				Region:                    tc.region,
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(script).To(HavePrefix("#!/usr/bin/env bash"))
+			g.Expect(script).To(ContainSubstring("CLOUDWATCH_AGENT_URL=" + tc.downloadURL + "\n"))
+			g.Expect(script).To(ContainSubstring(`"metrics_collection_interval": 30,`))
+			g.Expect(script).To(ContainSubstring(`"log_group_name": "/cluster-api/test-cluster",`))
+		})
+	}
+}
+
+func TestAddCloudWatchAgent(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := AddCloudWatchAgent([]byte("#cloud-config\nruncmd:\n- kubeadm join\n"), &CloudWatchAgentInput{
+		LogGroupName:              "/cluster-api/test-cluster",
+		MetricsCollectionInterval: 60,
+		Region:                    "us-east-1",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(HavePrefix("MIME-Version: 1.0"))
+	g.Expect(string(data)).To(ContainSubstring("#cloud-config\nruncmd:\n- kubeadm join\n"))
+	g.Expect(string(data)).To(ContainSubstring("amazon-cloudwatch-agent-ctl"))
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// maxUserDataSize is the size limit of the user data of EC2 instances, before it is base64 encoded.
+const maxUserDataSize = 16 * 1024
+
+// ignitionConfig is the subset of an Ignition config needed to recognize one.
+type ignitionConfig struct {
+	Ignition struct {
+		Version string `json:"version"`
+	} `json:"ignition"`
+}
+
+// ValidateIgnition returns an error if the bootstrap data isn't an Ignition config, or is too large
+// to be passed to the instance as user data.
+func ValidateIgnition(data []byte) error {
+	if len(data) > maxUserDataSize {
+		return errors.Errorf("bootstrap data is %d bytes, which exceeds the %d bytes limit of EC2 user data", len(data), maxUserDataSize)
+	}
+
+	config := &ignitionConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return errors.Wrap(err, "bootstrap data is not a valid Ignition config")
+	}
+
+	if config.Ignition.Version == "" {
+		return errors.New("bootstrap data is not a valid Ignition config: ignition.version is not set")
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userdata
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValidateIgnition(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name: "ignition config",
+			data: []byte(`{"ignition":{"version":"3.2.0"},"storage":{"files":[{"path":"/etc/hostname","contents":{"source":"data:,node"}}]}}`),
+		},
+		{
+			name: "ignition config at the size limit",
+			data: []byte(fmt.Sprintf(`{"ignition":{"version":"2.3.0"},"padding":"%s"}`, strings.Repeat("a", maxUserDataSize-45))),
+		},
+		{
+			name:    "cloud-config",
+			data:    []byte("#cloud-config\nruncmd:\n- kubeadm join\n"),
+			wantErr: true,
+		},
+		{
+			name:    "json without an ignition version",
+			data:    []byte(`{"ignition":{},"storage":{}}`),
+			wantErr: true,
+		},
+		{
+			name:    "empty bootstrap data",
+			data:    []byte{},
+			wantErr: true,
+		},
+		{
+			name:    "ignition config over the size limit",
+			data:    []byte(fmt.Sprintf(`{"ignition":{"version":"2.3.0"},"padding":"%s"}`, strings.Repeat("a", maxUserDataSize))),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := ValidateIgnition(tt.data)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// DefaultMaxThrottleRetries is the number of times a throttled request is retried.
+	DefaultMaxThrottleRetries = 8
+
+	// DefaultMaxThrottleDelay caps the delay between two attempts of a throttled request.
+	DefaultMaxThrottleDelay = 20 * time.Second
+
+	// DefaultMaxThrottleElapsed caps the time spent retrying a throttled request, so that a
+	// reconcile worker isn't blocked for minutes. Once it is spent, the error is returned and
+	// the reconcile is requeued with the controller's own backoff.
+	DefaultMaxThrottleElapsed = 30 * time.Second
+)
+
+// Retryer retries requests rejected with a throttling error, such as RequestLimitExceeded
+// or Throttling, more persistently than other failures. Attempts are spaced with an
+// exponential backoff with jitter, so that throttling slows a reconcile down instead of failing it,
+// up to MaxThrottleElapsed after which the reconcile fails and is requeued.
+type Retryer struct {
+	client.DefaultRetryer
+
+	// NumMaxThrottleRetries is the number of times a throttled request is retried.
+	// Other retryable failures are retried NumMaxRetries times.
+	NumMaxThrottleRetries int
+
+	// MaxThrottleElapsed is the time after which a throttled request is no longer retried.
+	MaxThrottleElapsed time.Duration
+}
+
+// NewRetryer returns a Retryer using the SDK defaults for failures other than throttling.
+func NewRetryer() *Retryer {
+	return &Retryer{
+		DefaultRetryer: client.DefaultRetryer{
+			NumMaxRetries:    client.DefaultRetryerMaxNumRetries,
+			MinRetryDelay:    client.DefaultRetryerMinRetryDelay,
+			MaxRetryDelay:    client.DefaultRetryerMaxRetryDelay,
+			MinThrottleDelay: client.DefaultRetryerMinThrottleDelay,
+			MaxThrottleDelay: DefaultMaxThrottleDelay,
+		},
+		NumMaxThrottleRetries: DefaultMaxThrottleRetries,
+		MaxThrottleElapsed:    DefaultMaxThrottleElapsed,
+	}
+}
+
+// MaxRetries returns the upper bound of retries for any request.
+func (r *Retryer) MaxRetries() int {
+	if r.NumMaxThrottleRetries > r.NumMaxRetries {
+		return r.NumMaxThrottleRetries
+	}
+	return r.NumMaxRetries
+}
+
+// RetryRules returns the delay before the next attempt, shortened so that throttled
+// requests are not retried past MaxThrottleElapsed.
+func (r *Retryer) RetryRules(req *request.Request) time.Duration {
+	delay := r.DefaultRetryer.RetryRules(req)
+	if req.IsErrorThrottle() {
+		if remaining := r.remainingThrottleTime(req); delay > remaining {
+			delay = remaining
+		}
+	}
+	return delay
+}
+
+// ShouldRetry returns whether the request should be retried.
+func (r *Retryer) ShouldRetry(req *request.Request) bool {
+	if req.IsErrorThrottle() {
+		return req.RetryCount < r.NumMaxThrottleRetries && r.remainingThrottleTime(req) > 0
+	}
+	if req.RetryCount >= r.NumMaxRetries {
+		return false
+	}
+	return r.DefaultRetryer.ShouldRetry(req)
+}
+
+func (r *Retryer) remainingThrottleTime(req *request.Request) time.Duration {
+	if r.MaxThrottleElapsed <= 0 || req.Time.IsZero() {
+		return r.MaxThrottleDelay
+	}
+	if remaining := r.MaxThrottleElapsed - time.Since(req.Time); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// WithRetryer configures the client to retry requests with a Retryer.
+func WithRetryer(cfg *aws.Config) *aws.Config {
+	// The SDK only consults the retryer for requests whose handlers did not
+	// already decide whether they are retryable.
+	cfg.EnforceShouldRetryCheck = aws.Bool(true)
+	return request.WithRetryer(cfg, NewRetryer())
+}
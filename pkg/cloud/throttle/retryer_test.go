@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	. "github.com/onsi/gomega"
+)
+
+func TestRetryerShouldRetry(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+		retryCount int
+		age        time.Duration
+		expected   bool
+	}{
+		{
+			name:       "throttled request is retried beyond the default limit",
+			err:        awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil),
+			statusCode: http.StatusServiceUnavailable,
+			retryCount: 5,
+			expected:   true,
+		},
+		{
+			name:       "throttled request is not retried once the throttle limit is reached",
+			err:        awserr.New("Throttling", "Rate exceeded", nil),
+			statusCode: http.StatusBadRequest,
+			retryCount: DefaultMaxThrottleRetries,
+			expected:   false,
+		},
+		{
+			name:       "throttled request is not retried once the time budget is spent",
+			err:        awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil),
+			statusCode: http.StatusServiceUnavailable,
+			retryCount: 2,
+			age:        DefaultMaxThrottleElapsed + time.Second,
+			expected:   false,
+		},
+		{
+			name:       "server error is retried within the default limit",
+			err:        awserr.New("InternalError", "An internal error has occurred", nil),
+			statusCode: http.StatusInternalServerError,
+			retryCount: 1,
+			expected:   true,
+		},
+		{
+			name:       "server error is not retried beyond the default limit",
+			err:        awserr.New("InternalError", "An internal error has occurred", nil),
+			statusCode: http.StatusInternalServerError,
+			retryCount: 5,
+			expected:   false,
+		},
+		{
+			name:       "client error is not retried",
+			err:        awserr.New("InvalidParameterValue", "Invalid value", nil),
+			statusCode: http.StatusBadRequest,
+			retryCount: 0,
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			req := &request.Request{
+				Error:        tc.err,
+				HTTPResponse: &http.Response{StatusCode: tc.statusCode},
+				RetryCount:   tc.retryCount,
+				Time:         time.Now().Add(-tc.age),
+			}
+
+			g.Expect(NewRetryer().ShouldRetry(req)).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestRetryerRetryRules(t *testing.T) {
+	g := NewWithT(t)
+
+	// The last attempt is scheduled no later than the end of the time budget.
+	req := &request.Request{
+		Error:        awserr.New("Throttling", "Rate exceeded", nil),
+		HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest},
+		RetryCount:   DefaultMaxThrottleRetries - 1,
+		Time:         time.Now().Add(-(DefaultMaxThrottleElapsed - time.Second)),
+	}
+
+	g.Expect(NewRetryer().RetryRules(req)).To(BeNumerically("<=", time.Second))
+}
@@ -19,6 +19,7 @@ package throttle
 import (
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go/aws/request"
 	"sigs.k8s.io/cluster-api-provider-aws/pkg/cloud/awserrors"
@@ -37,12 +38,18 @@ func NewMultiOperationMatch(strs ...string) string {
 }
 
 // OperationLimiter defines the specs of an operation limiter.
+// An OperationLimiter is shared by all the clients created from the same session,
+// so its lazily initialized state is guarded for concurrent use.
 type OperationLimiter struct {
 	Operation  string
 	RefillRate rate.Limit
 	Burst      int
-	regexp     *regexp.Regexp
-	limiter    *rate.Limiter
+
+	regexpOnce  sync.Once
+	regexp      *regexp.Regexp
+	regexpErr   error
+	limiterOnce sync.Once
+	limiter     *rate.Limiter
 }
 
 // Wait will wait on a request.
@@ -52,12 +59,11 @@ func (o *OperationLimiter) Wait(r *request.Request) error {
 
 // Match will match a request.
 func (o *OperationLimiter) Match(r *request.Request) (bool, error) {
-	if o.regexp == nil {
-		var err error
-		o.regexp, err = regexp.Compile("^" + o.Operation)
-		if err != nil {
-			return false, err
-		}
+	o.regexpOnce.Do(func() {
+		o.regexp, o.regexpErr = regexp.Compile("^" + o.Operation)
+	})
+	if o.regexpErr != nil {
+		return false, o.regexpErr
 	}
 	return o.regexp.Match([]byte(r.Operation.Name)), nil
 }
@@ -70,9 +76,9 @@ func (s ServiceLimiter) LimitRequest(r *request.Request) {
 }
 
 func (o *OperationLimiter) getLimiter() *rate.Limiter {
-	if o.limiter == nil {
+	o.limiterOnce.Do(func() {
 		o.limiter = rate.NewLimiter(o.RefillRate, o.Burst)
-	}
+	})
 	return o.limiter
 }
 
@@ -83,7 +89,7 @@ func (s ServiceLimiter) ReviewResponse(r *request.Request) {
 			switch errorCode {
 			case "Throttling", "RequestLimitExceeded":
 				if ol, ok := s.matchRequest(r); ok {
-					ol.limiter.ResetTokens()
+					ol.getLimiter().ResetTokens()
 				}
 			}
 		}
@@ -63,3 +63,37 @@ func SplitIntoSubnetsIPv4(cidrBlock string, numSubnets int) ([]*net.IPNet, error
 
 	return subnets, nil
 }
+
+// SplitIntoSubnetsIPv6 splits an IPv6 CIDR into the specified number of /64 subnets.
+// AWS assigns a /56 IPv6 CIDR block to VPCs and requires subnets to be /64.
+func SplitIntoSubnetsIPv6(cidrBlock string, numSubnets int) ([]*net.IPNet, error) {
+	_, parent, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse CIDR")
+	}
+
+	ip6 := parent.IP.To16()
+	if ip6 == nil || parent.IP.To4() != nil {
+		return nil, errors.Errorf("unexpected IP address type: %s", parent)
+	}
+
+	const subnetLen = 64
+	networkLen, _ := parent.Mask.Size()
+	if networkLen > subnetLen || (subnetLen-networkLen < 32 && numSubnets > 1<<uint(subnetLen-networkLen)) {
+		return nil, errors.Errorf("cidr %s cannot accommodate %d subnets", cidrBlock, numSubnets)
+	}
+
+	var subnets []*net.IPNet
+	for i := 0; i < numSubnets; i++ {
+		n := binary.BigEndian.Uint64(ip6[:8])
+		n += uint64(i)
+		subnetIP := make(net.IP, net.IPv6len)
+		binary.BigEndian.PutUint64(subnetIP[:8], n)
+		subnets = append(subnets, &net.IPNet{
+			IP:   subnetIP,
+			Mask: net.CIDRMask(subnetLen, 128),
+		})
+	}
+
+	return subnets, nil
+}
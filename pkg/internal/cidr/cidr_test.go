@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cidr
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSplitIntoSubnetsIPv6(t *testing.T) {
+	tests := []struct {
+		name       string
+		cidrBlock  string
+		numSubnets int
+		expected   []string
+		expectErr  bool
+	}{
+		{
+			name:       "splits a /56 into consecutive /64s",
+			cidrBlock:  "2001:db8:1234:1a00::/56",
+			numSubnets: 3,
+			expected:   []string{"2001:db8:1234:1a00::/64", "2001:db8:1234:1a01::/64", "2001:db8:1234:1a02::/64"},
+		},
+		{
+			name:       "fills a /56 with 256 subnets",
+			cidrBlock:  "2001:db8:1234:1a00::/56",
+			numSubnets: 256,
+		},
+		{
+			name:       "a /56 cannot hold more than 256 subnets",
+			cidrBlock:  "2001:db8:1234:1a00::/56",
+			numSubnets: 257,
+			expectErr:  true,
+		},
+		{
+			name:       "a /64 is a single subnet",
+			cidrBlock:  "2001:db8:1234:1a00::/64",
+			numSubnets: 1,
+			expected:   []string{"2001:db8:1234:1a00::/64"},
+		},
+		{
+			name:       "a block smaller than a /64 cannot be split",
+			cidrBlock:  "2001:db8:1234:1a00::/72",
+			numSubnets: 1,
+			expectErr:  true,
+		},
+		{
+			name:       "rejects IPv4 CIDR blocks",
+			cidrBlock:  "10.0.0.0/16",
+			numSubnets: 2,
+			expectErr:  true,
+		},
+		{
+			name:       "rejects malformed CIDR blocks",
+			cidrBlock:  "2001:db8::/129",
+			numSubnets: 2,
+			expectErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			subnets, err := SplitIntoSubnetsIPv6(tc.cidrBlock, tc.numSubnets)
+			if tc.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(subnets).To(HaveLen(tc.numSubnets))
+			for i, expected := range tc.expected {
+				g.Expect(subnets[i].String()).To(Equal(expected))
+			}
+		})
+	}
+}
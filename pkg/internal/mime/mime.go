@@ -38,6 +38,16 @@ var (
 		"content-type": {"text/cloud-boothook"},
 	}
 
+	// cloud-init detects the type of text/plain parts from their content, which
+	// keeps the "## template: jinja" header of kubeadm bootstrap data working.
+	plainType = textproto.MIMEHeader{
+		"content-type": {"text/plain"},
+	}
+
+	shellScriptType = textproto.MIMEHeader{
+		"content-type": {"text/x-shellscript"},
+	}
+
 	multipartHeader = strings.Join([]string{
 		"MIME-Version: 1.0",
 		"Content-Type: multipart/mixed; boundary=\"%s\"",
@@ -98,3 +108,34 @@ func GenerateInitDocument(secretPrefix string, chunks int32, region string, endp
 
 	return buf.Bytes(), nil
 }
+
+// GenerateScriptedDocument returns a multi-part MIME document made of the given
+// bootstrap data followed by a shell script, which cloud-init runs once the
+// instance has booted.
+func GenerateScriptedDocument(bootstrapData []byte, script []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	mpWriter := multipart.NewWriter(&buf)
+	buf.WriteString(fmt.Sprintf(multipartHeader, mpWriter.Boundary()))
+
+	bootstrapWriter, err := mpWriter.CreatePart(plainType)
+	if err != nil {
+		return []byte{}, err
+	}
+	if _, err := bootstrapWriter.Write(bootstrapData); err != nil {
+		return []byte{}, err
+	}
+
+	scriptWriter, err := mpWriter.CreatePart(shellScriptType)
+	if err != nil {
+		return []byte{}, err
+	}
+	if _, err := scriptWriter.Write(script); err != nil {
+		return []byte{}, err
+	}
+
+	if err := mpWriter.Close(); err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
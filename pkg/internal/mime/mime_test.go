@@ -18,6 +18,9 @@ package mime
 
 import (
 	"bytes"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/mail"
 	"testing"
 )
@@ -30,3 +33,45 @@ func TestGenerateInitDocument(t *testing.T) {
 		t.Fatalf("Cannot parse MIME doc: %+v\n%s", err, string(doc))
 	}
 }
+
+func TestGenerateScriptedDocument(t *testing.T) {
+	bootstrapData := []byte("## template: jinja\n#cloud-config\n")
+	script := []byte("#!/bin/bash\necho hello\n")
+	doc, err := GenerateScriptedDocument(bootstrapData, script)
+	if err != nil {
+		t.Fatalf("Failed to generate MIME doc: %+v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewBuffer(doc))
+	if err != nil {
+		t.Fatalf("Cannot parse MIME doc: %+v\n%s", err, string(doc))
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Cannot parse MIME doc content type: %+v", err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for _, expected := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{contentType: "text/plain", content: bootstrapData},
+		{contentType: "text/x-shellscript", content: script},
+	} {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("Cannot read MIME doc part: %+v", err)
+		}
+		if contentType := part.Header.Get("Content-Type"); contentType != expected.contentType {
+			t.Fatalf("Expected part of type %q, got %q", expected.contentType, contentType)
+		}
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			t.Fatalf("Cannot read MIME doc part: %+v", err)
+		}
+		if !bytes.Equal(content, expected.content) {
+			t.Fatalf("Expected part content %q, got %q", expected.content, content)
+		}
+	}
+}